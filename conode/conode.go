@@ -21,11 +21,12 @@ import (
 	"path"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/ftcosi/check"
 	_ "github.com/dedis/cothority/ftcosi/service"
 	_ "github.com/dedis/cothority/identity"
-	_ "github.com/dedis/cothority/skipchain"
+	"github.com/dedis/cothority/skipchain"
 	_ "github.com/dedis/cothority/status/service"
 	"github.com/dedis/onet/app"
 	"github.com/dedis/onet/cfgpath"
@@ -105,13 +106,36 @@ func main() {
 	log.ErrFatal(err)
 }
 
+// serviceConfig holds the sections of the server configuration that are
+// read by the services.
+type serviceConfig struct {
+	Skipchain skipchain.Config
+}
+
 func runServer(ctx *cli.Context) error {
 	// first check the options
 	config := ctx.GlobalString("config")
+	if err := configureServices(config); err != nil {
+		return err
+	}
 	app.RunServer(config)
 	return nil
 }
 
+// configureServices reads the configuration of the services from the server
+// configuration and registers the services with it. A missing configuration
+// file is reported by app.RunServer.
+func configureServices(config string) error {
+	cfg := &serviceConfig{}
+	if _, err := toml.DecodeFile(config, cfg); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return skipchain.RegisterService(cfg.Skipchain)
+}
+
 // checkConfig contacts all servers and verifies if it receives a valid
 // signature from each.
 func checkConfig(c *cli.Context) error {
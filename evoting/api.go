@@ -4,8 +4,10 @@ package evoting
 
 import (
 	"github.com/dedis/onet"
+	"github.com/dedis/onet/network"
 
 	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/skipchain"
 )
 
 // ServiceName is the identifier of the service (application name).
@@ -39,3 +41,13 @@ func (c *Client) LookupSciper(roster *onet.Roster, sciper string) (reply *Lookup
 	err = c.SendProtobuf(roster.RandomServerIdentity(), &LookupSciper{Sciper: sciper, LookupURL: c.LookupURL}, reply)
 	return
 }
+
+// Health returns which open elections of the master skipchain the given
+// conode can still decrypt.
+func (c *Client) Health(dst *network.ServerIdentity, id skipchain.SkipBlockID) (*HealthReply, error) {
+	reply := &HealthReply{}
+	if err := c.SendProtobuf(dst, &Health{ID: id}, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
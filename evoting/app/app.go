@@ -1,15 +1,27 @@
 // This is a command line interface for communicating with the evoting service.
+//
+// Without a command, it links a new master skipchain to the roster. Voters
+// log in with a signature of the front-end, their own key from a voter roll
+// (-roll), or an ID token of an OpenID Connect issuer (-issuer).
+//
+// The command `audit <election-id>` verifies an election skipchain and prints
+// a JSON report with the tally.
 package main
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/evoting"
+	"github.com/dedis/cothority/evoting/lib"
+	"github.com/dedis/cothority/skipchain"
 	"github.com/dedis/kyber"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/app"
@@ -21,6 +33,11 @@ var (
 	argKey    = flag.String("key", "", "client-side public key")
 	argAdmins = flag.String("admins", "", "list of admin users")
 	argPin    = flag.String("pin", "", "service pin")
+
+	argRoll     = flag.String("roll", "", "path to voter roll toml file")
+	argIssuer   = flag.String("issuer", "", "OpenID Connect issuer of the voters")
+	argAudience = flag.String("audience", "", "client identifier at the issuer")
+	argClaim    = flag.String("claim", "", "claim of the ID token holding the user identifier")
 )
 
 func main() {
@@ -31,6 +48,17 @@ func main() {
 		panic(err)
 	}
 
+	if flag.Arg(0) == "audit" {
+		valid, err := audit(roster, flag.Arg(1))
+		if err != nil {
+			panic(err)
+		}
+		if !valid {
+			os.Exit(1)
+		}
+		return
+	}
+
 	key, err := parseKey(*argKey)
 	if err != nil {
 		panic(err)
@@ -41,11 +69,17 @@ func main() {
 		panic(err)
 	}
 
+	auth, err := parseAuth(*argRoll, *argIssuer, *argAudience, *argClaim)
+	if err != nil {
+		panic(err)
+	}
+
 	var client struct {
 		*onet.Client
 	}
 
-	request := &evoting.Link{Pin: *argPin, Roster: roster, Key: key, Admins: admins}
+	request := &evoting.Link{Pin: *argPin, Roster: roster, Key: key, Admins: admins,
+		Auth: auth}
 	reply := &evoting.LinkReply{}
 
 	client.Client = onet.NewClient(cothority.Suite, evoting.ServiceName)
@@ -56,6 +90,64 @@ func main() {
 	log.Info("Master ID:", reply.ID)
 }
 
+// audit verifies the election skipchain with the given hex id and prints the
+// report to the standard output. It returns false if a verification failed.
+func audit(roster *onet.Roster, id string) (bool, error) {
+	election, err := parseID(id)
+	if err != nil {
+		return false, err
+	}
+
+	report, err := lib.Audit(roster, election)
+	if err != nil {
+		return false, err
+	}
+
+	buf, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	fmt.Println(string(buf))
+	return report.Valid, nil
+}
+
+// parseID converts a skipchain id given in hexadecimal form.
+func parseID(id string) (skipchain.SkipBlockID, error) {
+	if id == "" {
+		return nil, errors.New("please give the id of the election")
+	}
+	return hex.DecodeString(id)
+}
+
+// parseAuth returns how users of the master skipchain log in: with their own
+// key from the voter roll, with an ID token of the issuer, or with a
+// signature of the front-end if neither is given.
+func parseAuth(roll, issuer, audience, claim string) (*lib.Auth, error) {
+	if roll != "" && issuer != "" {
+		return nil, errors.New("only one of roll or issuer can be given")
+	}
+	if roll != "" {
+		file, err := os.Open(roll)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		voters, err := lib.ReadVoterRoll(file)
+		if err != nil {
+			return nil, err
+		}
+		return &lib.Auth{Voters: voters}, nil
+	}
+	if issuer != "" {
+		if audience == "" {
+			return nil, errors.New("audience is needed for the issuer")
+		}
+		return &lib.Auth{Issuer: issuer, Audience: audience, Claim: claim}, nil
+	}
+	return nil, nil
+}
+
 // parseRoster reads a Dedis group toml file a converts it to a cothority roster.
 func parseRoster(path string) (*onet.Roster, error) {
 	file, err := os.Open(path)
@@ -37,3 +37,33 @@ func TestParseAdmins(t *testing.T) {
 	admins, _ = parseAdmins("1,2,3")
 	assert.Equal(t, []uint32{1, 2, 3}, admins)
 }
+
+func TestParseID(t *testing.T) {
+	_, err := parseID("")
+	assert.NotNil(t, err)
+
+	_, err = parseID("xyz")
+	assert.NotNil(t, err)
+
+	id, err := parseID("0102ff")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1, 2, 255}, []byte(id))
+}
+
+func TestParseAuth(t *testing.T) {
+	auth, err := parseAuth("", "", "", "")
+	assert.Nil(t, auth, err)
+
+	_, err = parseAuth("roll.toml", "https://issuer", "", "")
+	assert.NotNil(t, err)
+
+	_, err = parseAuth("", "https://issuer", "", "")
+	assert.NotNil(t, err)
+
+	auth, err = parseAuth("", "https://issuer", "evoting", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "evoting", auth.Audience)
+
+	_, err = parseAuth("/does/not/exist.toml", "", "", "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,223 @@
+package lib
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/kyber/share"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/network"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/skipchain"
+)
+
+/*
+This file holds the audit of an election. Anyone knowing a roster and the ID
+of an election skipchain can verify the election without credentials:
+
+ - every block is collectively signed by the roster and linked back to the
+   genesis block, so no ballot has been added or removed afterwards.
+ - every ballot belongs to a registered voter and has been casted before the
+   shuffle. Only the last ballot of each voter is counted.
+ - in elections with a voter roll, every ballot is signed by its voter.
+   Ballots with an invalid signature are not counted. Other elections rely on
+   the conodes authenticating the voter before storing the ballot.
+ - every mix holds a valid Neff shuffle proof of the previous mix, the first
+   one of the ballots.
+ - every partial holds valid decryption proofs of the last mix.
+
+Once the election is decrypted, the ballots are reconstructed from the
+verified partials and counted with CountVotes.
+*/
+
+// Report is the result of the audit of an election skipchain. It can be
+// marshalled to JSON.
+type Report struct {
+	ID       string          `json:"id"`       // ID of the election skipchain in hex.
+	Name     string          `json:"name"`     // Name of the election.
+	Blocks   int             `json:"blocks"`   // Blocks is the length of the skipchain.
+	Ballots  int             `json:"ballots"`  // Ballots is the number of counted ballots.
+	Mixes    []*MixCheck     `json:"mixes"`    // Mixes are the verified mixes.
+	Partials []*PartialCheck `json:"partials"` // Partials are the verified partials.
+	Tally    []*Tally        `json:"tally,omitempty"`
+	Errors   []string        `json:"errors,omitempty"`
+	Valid    bool            `json:"valid"` // Valid is true if no verification failed.
+}
+
+// MixCheck is the result of the verification of a mix.
+type MixCheck struct {
+	Node  string `json:"node"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// PartialCheck is the result of the verification of a partial decryption.
+type PartialCheck struct {
+	Node  string `json:"node"`
+	Index int    `json:"index"`
+	Flag  bool   `json:"flag"` // Flag is set if the node verified the mixes.
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// Audit fetches the election skipchain from the roster and verifies it.
+func Audit(roster *onet.Roster, id skipchain.SkipBlockID) (*Report, error) {
+	chain, err := chain(roster, id)
+	if err != nil {
+		return nil, err
+	}
+	return AuditChain(id, chain)
+}
+
+// AuditChain verifies the blocks of an election skipchain, starting with the
+// genesis block. An error is only returned if the blocks hold no election,
+// every failed verification is listed in the report.
+func AuditChain(id skipchain.SkipBlockID, chain []*skipchain.SkipBlock) (*Report, error) {
+	if len(chain) < 2 {
+		return nil, errors.New("skipchain holds no election")
+	}
+	_, blob, err := network.Unmarshal(chain[1].Data, cothority.Suite)
+	if err != nil {
+		return nil, err
+	}
+	election, ok := blob.(*Election)
+	if !ok {
+		return nil, errors.New("skipchain holds no election")
+	}
+
+	report := &Report{
+		ID:       hex.EncodeToString(id),
+		Name:     election.Name,
+		Blocks:   len(chain),
+		Mixes:    make([]*MixCheck, 0),
+		Partials: make([]*PartialCheck, 0),
+	}
+	if err := skipchain.Proof(chain).Verify(id); err != nil {
+		report.fail("invalid skipchain: %s", err)
+	}
+
+	ballots := make([]*Ballot, 0)
+	mixes := make([]*Mix, 0)
+	partials := make([]*Partial, 0)
+	for _, block := range chain[2:] {
+		_, blob, err := network.Unmarshal(block.Data, cothority.Suite)
+		if err != nil {
+			report.fail("unknown data in block %d", block.Index)
+			continue
+		}
+		switch data := blob.(type) {
+		case *Ballot:
+			if len(mixes) > 0 {
+				report.fail("ballot of user %d casted after the shuffle", data.User)
+				continue
+			}
+			if !election.IsUser(data.User) {
+				report.fail("ballot of unregistered user %d", data.User)
+			}
+			if err := election.VerifyBallot(data); err != nil {
+				report.fail("invalid signature of ballot of user %d: %s", data.User, err)
+				continue
+			}
+			ballots = append(ballots, data)
+		case *Mix:
+			mixes = append(mixes, data)
+		case *Partial:
+			partials = append(partials, data)
+		default:
+			report.fail("unknown data in block %d", block.Index)
+		}
+	}
+
+	box := latest(ballots)
+	report.Ballots = len(box)
+	report.auditMixes(election, box, mixes)
+	if len(partials) > 0 {
+		report.auditPartials(election, mixes, partials)
+	}
+	report.Valid = len(report.Errors) == 0
+	return report, nil
+}
+
+// auditMixes verifies the shuffle proofs of the mixes, starting with the
+// ballots.
+func (r *Report) auditMixes(election *Election, box []*Ballot, mixes []*Mix) {
+	if len(mixes) > 0 && len(mixes) != len(election.Roster.List) {
+		r.fail("%d mixes for %d nodes", len(mixes), len(election.Roster.List))
+	}
+	previous := box
+	for i, mix := range mixes {
+		check := &MixCheck{Node: mix.Node}
+		if err := VerifyShuffle(mix.Proof, election.Key, previous, mix.Ballots); err != nil {
+			check.Error = err.Error()
+			r.fail("invalid mix %d: %s", i, err)
+		} else {
+			check.Valid = true
+		}
+		r.Mixes = append(r.Mixes, check)
+		previous = mix.Ballots
+	}
+}
+
+// auditPartials verifies the proofs of the partials of the last mix and
+// counts the reconstructed ballots.
+func (r *Report) auditPartials(election *Election, mixes []*Mix, partials []*Partial) {
+	if len(mixes) == 0 {
+		r.fail("partials without mixes")
+		return
+	}
+	last := mixes[len(mixes)-1]
+	if !complete(last.Ballots) {
+		r.fail("last mix has incomplete ballots")
+		return
+	}
+
+	var poly *share.PubPoly
+	if len(election.Commits) > 0 {
+		poly = share.NewPubPoly(cothority.Suite, nil, election.Commits)
+	} else {
+		r.fail("partials cannot be verified without the commits of the DKG")
+	}
+	for _, partial := range partials {
+		check := &PartialCheck{Node: partial.Node, Index: partial.Index, Flag: partial.Flag}
+		if poly != nil {
+			if err := partial.Verify(last, poly); err != nil {
+				check.Error = err.Error()
+				r.fail("invalid partial of node %s: %s", partial.Node, err)
+			} else {
+				check.Valid = true
+			}
+		}
+		if !partial.Flag {
+			r.fail("node %s couldn't verify the mixes", partial.Node)
+		}
+		r.Partials = append(r.Partials, check)
+	}
+
+	points, err := Reconstruct(last, partials, election.Commits, election.Quorum())
+	if err != nil {
+		r.fail("couldn't reconstruct the ballots: %s", err)
+		return
+	}
+	r.Tally, err = CountVotes(election, points)
+	if err != nil {
+		r.fail("couldn't count the ballots: %s", err)
+	}
+}
+
+// fail adds an error to the report.
+func (r *Report) fail(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// complete returns true if all the ciphertexts of the ballots hold an
+// ElGamal pair.
+func complete(ballots []*Ballot) bool {
+	for _, ballot := range ballots {
+		if ballot == nil || ballot.Check() != nil {
+			return false
+		}
+	}
+	return true
+}
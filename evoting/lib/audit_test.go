@@ -0,0 +1,169 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/dedis/kyber"
+	"github.com/dedis/onet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dedis/cothority"
+)
+
+// encode returns the plaintext of a ballot choosing the given candidates.
+func encode(candidates ...uint32) []byte {
+	return EncodeAnswer(candidates)
+}
+
+func TestAudit(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	_, roster, _ := local.GenBigTree(3, 3, 1, true)
+
+	_, err := Audit(roster, []byte{})
+	assert.NotNil(t, err)
+
+	dkgs, _ := DKGSimulate(3, 2)
+	secret, _ := NewSharedSecret(dkgs[0])
+	genesis, _ := New(roster, nil)
+	election := &Election{
+		ID:         genesis.Hash,
+		Roster:     roster,
+		Key:        secret.X,
+		Threshold:  2,
+		Commits:    secret.Commits,
+		Users:      []uint32{0, 1, 2, 3},
+		Candidates: []uint32{123456, 654321},
+		MaxChoices: 1,
+	}
+	require.Nil(t, election.Store(election))
+
+	report, err := Audit(roster, election.ID)
+	require.Nil(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 0, report.Ballots)
+	assert.Nil(t, report.Tally)
+
+	votes := [][]byte{encode(123456), encode(654321), encode(654321), encode(111111)}
+	for user, vote := range votes {
+		alpha, beta := Encrypt(election.Key, vote)
+		election.Store(&Ballot{User: uint32(user), Alpha: alpha, Beta: beta})
+	}
+	// The second ballot of user 0 replaces the first one.
+	alpha, beta := Encrypt(election.Key, encode(654321))
+	election.Store(&Ballot{User: 0, Alpha: alpha, Beta: beta})
+
+	box, _ := election.Box()
+	mixes := box.genMix(election.Key, 3)
+	election.storeMixes(mixes)
+	election.storePartials(mixes[2].genPartials(dkgs))
+
+	report, err = Audit(roster, election.ID)
+	require.Nil(t, err)
+	assert.True(t, report.Valid, report.Errors)
+	assert.Equal(t, 4, report.Ballots)
+	assert.Equal(t, 3, len(report.Mixes))
+	assert.Equal(t, 3, len(report.Partials))
+	for _, partial := range report.Partials {
+		assert.True(t, partial.Valid)
+	}
+	require.NotNil(t, report.Tally)
+	assert.Equal(t, []*Count{{123456, 0}, {654321, 3}}, report.Tally[0].Votes)
+	assert.Equal(t, 1, report.Tally[0].Invalid)
+
+	// A wrong partial is reported, but the others still allow to count.
+	partial := mixes[2].genPartials(dkgs)[0]
+	partial.Points[0] = cothority.Suite.Point().Pick(cothority.Suite.RandomStream())
+	election.Store(partial)
+
+	report, _ = Audit(roster, election.ID)
+	assert.False(t, report.Valid)
+	assert.False(t, report.Partials[3].Valid)
+	assert.Equal(t, 1, len(report.Errors))
+	require.NotNil(t, report.Tally)
+	assert.Equal(t, 3, report.Tally[0].Votes[1].Votes)
+}
+
+func TestAudit_InvalidMix(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	_, roster, _ := local.GenBigTree(3, 3, 1, true)
+
+	election := &Election{Roster: roster, Users: []uint32{0, 1, 2}, Stage: Running}
+	_ = election.GenChain(3)
+
+	box, _ := election.Box()
+	mixes := box.genMix(election.Key, 3)
+	mixes[1].Ballots[0], mixes[1].Ballots[1] = mixes[1].Ballots[1], mixes[1].Ballots[0]
+	election.storeMixes(mixes)
+
+	report, err := Audit(roster, election.ID)
+	require.Nil(t, err)
+	assert.False(t, report.Valid)
+	assert.True(t, report.Mixes[0].Valid)
+	assert.False(t, report.Mixes[1].Valid)
+	assert.False(t, report.Mixes[2].Valid)
+}
+
+func TestAudit_UnregisteredUser(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	_, roster, _ := local.GenBigTree(3, 3, 1, true)
+
+	election := &Election{Roster: roster, Users: []uint32{0, 1}, Stage: Running}
+	_ = election.GenChain(3)
+
+	report, err := Audit(roster, election.ID)
+	require.Nil(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, []string{"ballot of unregistered user 2"}, report.Errors)
+}
+
+func TestAudit_BallotSignature(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	_, roster, _ := local.GenBigTree(3, 3, 1, true)
+
+	_, X := RandomKeyPair()
+	x0, X0 := RandomKeyPair()
+	x1, X1 := RandomKeyPair()
+	genesis, _ := New(roster, nil)
+	election := &Election{
+		ID:     genesis.Hash,
+		Roster: roster,
+		Key:    X,
+		Users:  []uint32{0, 1},
+		Voters: []*Voter{{User: 0, Key: X0}, {User: 1, Key: X1}},
+	}
+	require.Nil(t, election.Store(election))
+
+	ballot := func(user uint32, secret kyber.Scalar) *Ballot {
+		alpha, beta := Encrypt(X, encode(1))
+		b := &Ballot{User: user, Alpha: alpha, Beta: beta}
+		require.Nil(t, b.Sign(election.ID, secret))
+		return b
+	}
+
+	election.Store(ballot(0, x0))
+	report, err := Audit(roster, election.ID)
+	require.Nil(t, err)
+	assert.True(t, report.Valid, report.Errors)
+	assert.Equal(t, 1, report.Ballots)
+
+	// A ballot signed by another voter is reported and not counted.
+	election.Store(ballot(1, x0))
+	report, _ = Audit(roster, election.ID)
+	assert.False(t, report.Valid)
+	assert.Equal(t, 1, len(report.Errors))
+	assert.Equal(t, 1, report.Ballots)
+
+	election.Store(ballot(1, x1))
+	report, _ = Audit(roster, election.ID)
+	assert.Equal(t, 2, report.Ballots)
+}
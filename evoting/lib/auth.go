@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dedis/kyber"
+
+	"github.com/dedis/cothority"
+)
+
+// Auth defines how the users of a master skipchain log in. Without Voters
+// and Issuer, the login has to be signed with the front-end key of the master.
+type Auth struct {
+	Voters []*Voter // Voters sign their login with their own key.
+
+	Issuer   string // Issuer of the OpenID Connect tokens.
+	Audience string // Audience the tokens have to be issued for.
+	Claim    string // Claim holding the user identifier, "sub" if empty.
+}
+
+// Voter is a user with its own Ed25519 key.
+type Voter struct {
+	User uint32      // User identifier.
+	Key  kyber.Point // Key is the public key of the voter.
+}
+
+// ReadVoterRoll parses a voter roll in toml format, holding the identifier
+// and the hex-encoded public key of every voter:
+//
+//	[[Voters]]
+//	  User = 123456
+//	  Key = "3c5b..."
+func ReadVoterRoll(r io.Reader) ([]*Voter, error) {
+	roll := struct {
+		Voters []struct {
+			User uint32
+			Key  string
+		}
+	}{}
+	if _, err := toml.DecodeReader(r, &roll); err != nil {
+		return nil, err
+	}
+
+	voters := make([]*Voter, len(roll.Voters))
+	users := make(map[uint32]bool)
+	for i, v := range roll.Voters {
+		if users[v.User] {
+			return nil, fmt.Errorf("voter %d appears twice", v.User)
+		}
+		users[v.User] = true
+
+		buf, err := hex.DecodeString(v.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key of voter %d: %s", v.User, err)
+		}
+		key := cothority.Suite.Point()
+		if err := key.UnmarshalBinary(buf); err != nil {
+			return nil, fmt.Errorf("invalid key of voter %d: %s", v.User, err)
+		}
+		voters[i] = &Voter{User: v.User, Key: key}
+	}
+	return voters, nil
+}
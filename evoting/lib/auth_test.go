@@ -0,0 +1,39 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadVoterRoll(t *testing.T) {
+	_, X1 := RandomKeyPair()
+	_, X2 := RandomKeyPair()
+
+	roll := `
+[[Voters]]
+  User = 1
+  Key = "` + X1.String() + `"
+
+[[Voters]]
+  User = 2
+  Key = "` + X2.String() + `"
+`
+	voters, err := ReadVoterRoll(strings.NewReader(roll))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(voters))
+	assert.Equal(t, uint32(1), voters[0].User)
+	assert.True(t, X1.Equal(voters[0].Key))
+	assert.True(t, X2.Equal(voters[1].Key))
+
+	_, err = ReadVoterRoll(strings.NewReader(roll + roll))
+	assert.NotNil(t, err)
+
+	_, err = ReadVoterRoll(strings.NewReader("[[Voters]]\nUser = 1\nKey = \"zz\""))
+	assert.NotNil(t, err)
+
+	_, err = ReadVoterRoll(strings.NewReader("[[Voters]]\nUser = 1\nKey = \"0102\""))
+	assert.NotNil(t, err)
+}
@@ -1,13 +1,20 @@
 package lib
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
 	"github.com/dedis/kyber"
-	"github.com/dedis/kyber/proof"
+	"github.com/dedis/kyber/proof/dleq"
+	"github.com/dedis/kyber/share"
 	"github.com/dedis/kyber/share/dkg/rabin"
-	"github.com/dedis/kyber/shuffle"
+	"github.com/dedis/kyber/sign/schnorr"
 	"github.com/dedis/kyber/util/random"
 
 	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/skipchain"
 )
 
 // Ballot represents an encrypted vote.
@@ -17,6 +24,79 @@ type Ballot struct {
 	// ElGamal ciphertext pair.
 	Alpha kyber.Point
 	Beta  kyber.Point
+
+	// Extra holds the ciphertexts of the further questions of an election.
+	Extra []*Ciphertext
+
+	// Signature of the voter, needed in elections with Voters.
+	Signature []byte
+}
+
+// Ciphertext is an ElGamal ciphertext pair.
+type Ciphertext struct {
+	Alpha kyber.Point
+	Beta  kyber.Point
+}
+
+// Width returns the number of ciphertexts of the ballot.
+func (b *Ballot) Width() int {
+	return 1 + len(b.Extra)
+}
+
+// Ciphertexts returns all the ciphertexts of the ballot, starting with
+// Alpha and Beta.
+func (b *Ballot) Ciphertexts() []*Ciphertext {
+	return append([]*Ciphertext{{Alpha: b.Alpha, Beta: b.Beta}}, b.Extra...)
+}
+
+// Check returns an error if one of the ciphertexts of the ballot is
+// incomplete.
+func (b *Ballot) Check() error {
+	for _, c := range b.Ciphertexts() {
+		if c == nil || c.Alpha == nil || c.Beta == nil {
+			return errors.New("incomplete ballot")
+		}
+	}
+	return nil
+}
+
+// Digest returns the hash of the election ID, the user identifier and the
+// ciphertexts of the ballot, which is signed by the voter.
+func (b *Ballot) Digest(id skipchain.SkipBlockID) ([]byte, error) {
+	if err := b.Check(); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write(id)
+	binary.Write(h, binary.LittleEndian, b.User)
+	for _, c := range b.Ciphertexts() {
+		if _, err := c.Alpha.MarshalTo(h); err != nil {
+			return nil, err
+		}
+		if _, err := c.Beta.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// Sign creates a Schnorr signature of the ballot for the election.
+func (b *Ballot) Sign(id skipchain.SkipBlockID, secret kyber.Scalar) error {
+	digest, err := b.Digest(id)
+	if err != nil {
+		return err
+	}
+	b.Signature, err = schnorr.Sign(cothority.Suite, secret, digest)
+	return err
+}
+
+// Verify checks the Schnorr signature of the ballot for the election.
+func (b *Ballot) Verify(id skipchain.SkipBlockID, public kyber.Point) error {
+	digest, err := b.Digest(id)
+	if err != nil {
+		return err
+	}
+	return schnorr.Verify(cothority.Suite, public, digest, b.Signature)
 }
 
 // Box is a wrapper around a list of encrypted ballots.
@@ -28,12 +108,11 @@ type Box struct {
 func (b *Box) genMix(key kyber.Point, n int) []*Mix {
 	mixes := make([]*Mix, n)
 
-	x, y := Split(b.Ballots)
+	ballots := b.Ballots
 	for i := range mixes {
-		v, w, prover := shuffle.Shuffle(cothority.Suite, nil, key, x, y, random.New())
-		proof, _ := proof.HashProve(cothority.Suite, "", prover)
-		mixes[i] = &Mix{Ballots: Combine(v, w), Proof: proof, Node: string(i)}
-		x, y = v, w
+		mixed, proof, _ := Shuffle(key, ballots)
+		mixes[i] = &Mix{Ballots: mixed, Proof: proof, Node: string(i)}
+		ballots = mixed
 	}
 	return mixes
 }
@@ -46,25 +125,122 @@ type Mix struct {
 	Node string // Node signifies the creator of the mix.
 }
 
-// Partial contains the partially decrypted ballots.
+// Partial contains the partially decrypted ballots. Ballots with several
+// ciphertexts have one point and proof per ciphertext, one ballot after the
+// other.
 type Partial struct {
 	Points []kyber.Point // Points are the partially decrypted plaintexts.
+	Proofs []*dleq.Proof // Proofs of the partial decryption of each ciphertext.
+	Index  int           // Index of the DKG share used for the decryption.
 
-	Flag bool   // Flag signals if the mixes could not be verified.
+	Flag bool   // Flag signals if the mixes could be verified.
 	Node string // Node signifies the creator of this partial decryption.
 }
 
+// NewPartial partially decrypts the ballots of the mix with the secret share
+// and proves for every ballot that the same share has been used as in the
+// public share of the node.
+func NewPartial(secret *SharedSecret, mix *Mix) (*Partial, error) {
+	ciphertexts := flatten(mix.Ballots)
+	partial := &Partial{
+		Points: make([]kyber.Point, len(ciphertexts)),
+		Proofs: make([]*dleq.Proof, len(ciphertexts)),
+		Index:  secret.Index,
+	}
+	for i, c := range ciphertexts {
+		proof, _, S, err := dleq.NewDLEQProof(cothority.Suite,
+			cothority.Suite.Point().Base(), c.Alpha, secret.V)
+		if err != nil {
+			return nil, err
+		}
+		partial.Points[i] = cothority.Suite.Point().Sub(c.Beta, S)
+		partial.Proofs[i] = proof
+	}
+	return partial, nil
+}
+
+// Verify checks the proofs of the partial decryption of the mix against the
+// public share of the node, which is taken from the public polynomial.
+func (p *Partial) Verify(mix *Mix, poly *share.PubPoly) error {
+	ciphertexts := flatten(mix.Ballots)
+	if len(p.Points) != len(ciphertexts) || len(p.Proofs) != len(ciphertexts) {
+		return errors.New("wrong number of points or proofs")
+	}
+	if p.Index < 0 {
+		return errors.New("negative index")
+	}
+	public := poly.Eval(p.Index).V
+	for i, c := range ciphertexts {
+		if p.Points[i] == nil || p.Proofs[i] == nil {
+			return fmt.Errorf("missing point or proof for ciphertext %d", i)
+		}
+		S := cothority.Suite.Point().Sub(c.Beta, p.Points[i])
+		err := p.Proofs[i].Verify(cothority.Suite, cothority.Suite.Point().Base(),
+			c.Alpha, public, S)
+		if err != nil {
+			return fmt.Errorf("invalid proof for ciphertext %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// Reconstruct recovers the plaintexts of the mix from the partials using
+// Lagrange interpolation. Ballots with several ciphertexts have one
+// plaintext per ciphertext, one ballot after the other. Only partials with
+// valid proofs are used, and at least threshold of them are needed.
+// Elections opened before partials had proofs have no commits; their partials
+// cannot be verified and all of them are needed.
+func Reconstruct(mix *Mix, partials []*Partial, commits []kyber.Point, threshold int) (
+	[]kyber.Point, error) {
+
+	valid := make([]*Partial, 0)
+	if len(commits) == 0 {
+		for i, partial := range partials {
+			valid = append(valid, &Partial{Points: partial.Points, Index: i})
+		}
+		threshold = len(partials)
+	} else {
+		poly := share.NewPubPoly(cothority.Suite, nil, commits)
+		indexes := make(map[int]bool)
+		for _, partial := range partials {
+			if indexes[partial.Index] || partial.Verify(mix, poly) != nil {
+				continue
+			}
+			indexes[partial.Index] = true
+			valid = append(valid, partial)
+		}
+	}
+	if len(valid) == 0 || len(valid) < threshold {
+		return nil, errors.New("not enough valid partials")
+	}
+
+	points := make([]kyber.Point, len(flatten(mix.Ballots)))
+	for i := range points {
+		shares := make([]*share.PubShare, len(valid))
+		for j, partial := range valid {
+			if i >= len(partial.Points) {
+				return nil, errors.New("wrong number of points")
+			}
+			shares[j] = &share.PubShare{I: partial.Index, V: partial.Points[i]}
+		}
+		message, err := share.RecoverCommit(cothority.Suite, shares, threshold, len(shares))
+		if err != nil {
+			return nil, err
+		}
+		points[i] = message
+	}
+	return points, nil
+}
+
 // genPartials generates partial decryptions for a given list of shared secrets.
 func (m *Mix) genPartials(dkgs []*dkg.DistKeyGenerator) []*Partial {
 	partials := make([]*Partial, len(dkgs))
 
 	for i, gen := range dkgs {
 		secret, _ := NewSharedSecret(gen)
-		points := make([]kyber.Point, len(m.Ballots))
-		for j, ballot := range m.Ballots {
-			points[j] = Decrypt(secret.V, ballot.Alpha, ballot.Beta)
-		}
-		partials[i] = &Partial{Points: points, Node: string(i)}
+		partials[i], _ = NewPartial(secret, m)
+		partials[i].Node = string(i)
+		partials[i].Flag = true
 	}
 	return partials
 }
@@ -79,6 +255,16 @@ func genBox(key kyber.Point, n int) *Box {
 	return &Box{Ballots: ballots}
 }
 
+// flatten returns the ciphertexts of all the ballots, one ballot after the
+// other.
+func flatten(ballots []*Ballot) []*Ciphertext {
+	ciphertexts := make([]*Ciphertext, 0, len(ballots))
+	for _, ballot := range ballots {
+		ciphertexts = append(ciphertexts, ballot.Ciphertexts()...)
+	}
+	return ciphertexts
+}
+
 // Split separates the ElGamal pairs of a list of ballots into separate lists.
 func Split(ballots []*Ballot) (alpha, beta []kyber.Point) {
 	n := len(ballots)
@@ -1,11 +1,16 @@
 package lib
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/share"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dedis/cothority"
 )
 
 func TestSplit(t *testing.T) {
@@ -31,3 +36,89 @@ func TestCombine(t *testing.T) {
 	assert.Equal(t, X2, ballots[0].Beta)
 	assert.Equal(t, X2, ballots[1].Beta)
 }
+
+func TestBallot_Sign(t *testing.T) {
+	_, X := RandomKeyPair()
+	x, voter := RandomKeyPair()
+	_, other := RandomKeyPair()
+	id := []byte("election")
+
+	alpha, beta := Encrypt(X, []byte{1})
+	b := &Ballot{User: 1000, Alpha: alpha, Beta: beta}
+	require.Nil(t, b.Sign(id, x))
+	assert.Nil(t, b.Verify(id, voter))
+	assert.NotNil(t, b.Verify(id, other))
+	assert.NotNil(t, b.Verify([]byte("other election"), voter))
+
+	// The signature covers the user and all ciphertexts.
+	b.User = 1001
+	assert.NotNil(t, b.Verify(id, voter))
+	b.User = 1000
+	b.Extra = []*Ciphertext{{Alpha: alpha, Beta: beta}}
+	assert.NotNil(t, b.Verify(id, voter))
+	b.Extra = []*Ciphertext{{Alpha: alpha}}
+	assert.NotNil(t, b.Sign(id, x))
+}
+
+func TestPartial_Verify(t *testing.T) {
+	dkgs, err := DKGSimulate(5, 3)
+	require.Nil(t, err)
+	secret, _ := NewSharedSecret(dkgs[0])
+	poly := share.NewPubPoly(cothority.Suite, nil, secret.Commits)
+
+	mix := genBox(secret.X, 4).genMix(secret.X, 1)[0]
+	partial, err := NewPartial(secret, mix)
+	require.Nil(t, err)
+	assert.Nil(t, partial.Verify(mix, poly))
+
+	// Wrong index
+	partial.Index++
+	assert.NotNil(t, partial.Verify(mix, poly))
+	partial.Index--
+
+	// Tampered point
+	partial.Points[0] = cothority.Suite.Point().Add(partial.Points[0],
+		cothority.Suite.Point().Base())
+	assert.NotNil(t, partial.Verify(mix, poly))
+
+	// Missing proofs
+	partial.Proofs = partial.Proofs[1:]
+	assert.NotNil(t, partial.Verify(mix, poly))
+}
+
+func TestReconstruct(t *testing.T) {
+	dkgs, err := DKGSimulate(5, 3)
+	require.Nil(t, err)
+	secret, _ := NewSharedSecret(dkgs[0])
+	mix := genBox(secret.X, 4).genMix(secret.X, 1)[0]
+	partials := mix.genPartials(dkgs)
+
+	decoded := func(points []kyber.Point) []int {
+		messages := make([]int, len(points))
+		for i, point := range points {
+			data, _ := point.Data()
+			messages[i] = int(data[0])
+		}
+		sort.Ints(messages)
+		return messages
+	}
+
+	// Any threshold of valid partials is enough.
+	points, err := Reconstruct(mix, partials[2:], secret.Commits, 3)
+	require.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, decoded(points))
+
+	// Invalid partials are skipped.
+	partials[1].Points[0] = partials[0].Points[0]
+	points, err = Reconstruct(mix, partials, secret.Commits, 3)
+	require.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, decoded(points))
+
+	// Duplicate partials are only counted once.
+	_, err = Reconstruct(mix, []*Partial{partials[0], partials[0], partials[2]},
+		secret.Commits, 3)
+	assert.NotNil(t, err)
+
+	_, err = Reconstruct(mix, partials[:3], secret.Commits, 3)
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,9 @@
 package lib
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/dedis/kyber"
 	"github.com/dedis/kyber/share/dkg/rabin"
 	"github.com/dedis/onet"
@@ -34,6 +37,9 @@ type Election struct {
 	Key    kyber.Point           // Key is the DKG public key.
 	Stage  uint32                // Stage indicates the phase of the election.
 
+	Threshold int           // Threshold is the number of partials needed to decrypt.
+	Commits   []kyber.Point // Commits of the DKG to verify the partials.
+
 	Candidates []uint32 // Candidates is the list of candidate scipers.
 	MaxChoices int      // MaxChoices is the max votes in allowed in a ballot.
 	Subtitle   string   // Description in string format.
@@ -41,10 +47,27 @@ type Election struct {
 	Start      int64    // Start denotes the election start unix timestamp
 	End        int64    // End (termination) datetime as unix timestamp.
 
+	// Questions replace Candidates and MaxChoices in elections with several
+	// questions or ranked answers.
+	Questions []*Question
+
+	// Voters are copied from the voter roll of the master skipchain. If set,
+	// every ballot has to be signed by its voter.
+	Voters []*Voter
+
 	Theme  string // Theme denotes the CSS class for selecting background color of card title.
 	Footer footer // Footer denotes the Election footer
 }
 
+// Question is one of the independent questions of an election. The answer
+// to every question is encrypted in its own ciphertext of the ballot.
+type Question struct {
+	Title      string   // Title of the question.
+	Candidates []uint32 // Candidates is the list of candidate scipers.
+	MaxChoices int      // MaxChoices is the max candidates in an answer.
+	Ranked     bool     // Ranked answers are counted with instant-runoff.
+}
+
 // footer denotes the fields for the election footer
 type footer struct {
 	Text         string // Text is for storing footer content.
@@ -67,21 +90,25 @@ func FetchElection(roster *onet.Roster, id skipchain.SkipBlockID) (*Election, er
 	_, blob, _ := network.Unmarshal(chain[1].Data, cothority.Suite)
 	election := blob.(*Election)
 
-	n, numMixes, numPartials := len(election.Roster.List), 0, 0
+	n, numMixes := len(election.Roster.List), 0
+	nodes := make(map[string]bool)
 	for _, block := range chain {
 		_, blob, _ := network.Unmarshal(block.Data, cothority.Suite)
 		if _, ok := blob.(*Mix); ok {
 			numMixes++
-		} else if _, ok := blob.(*Partial); ok {
-			numPartials++
+		} else if partial, ok := blob.(*Partial); ok {
+			nodes[partial.Node] = true
 		}
 	}
+	numPartials := len(nodes)
 
+	// An election with less partials than the threshold is still shuffled, so
+	// that the decryption can be repeated once more nodes are reachable.
 	if numMixes == 0 && numPartials == 0 {
 		election.Stage = Running
-	} else if numMixes == n && numPartials == 0 {
+	} else if numMixes == n && numPartials < election.Quorum() {
 		election.Stage = Shuffled
-	} else if numMixes == n && numPartials == n {
+	} else if numMixes == n && numPartials <= n {
 		election.Stage = Decrypted
 	} else {
 		election.Stage = Corrupt
@@ -89,16 +116,90 @@ func FetchElection(roster *onet.Roster, id skipchain.SkipBlockID) (*Election, er
 	return election, nil
 }
 
+// Quorum returns the number of partials needed to decrypt the election. It
+// is the threshold of the DKG, or the number of nodes for elections opened
+// without a threshold.
+func (e *Election) Quorum() int {
+	if e.Threshold > 0 {
+		return e.Threshold
+	}
+	return len(e.Roster.List)
+}
+
+// AllQuestions returns the questions of the election. Elections without
+// Questions have a single question with the Candidates and MaxChoices of the
+// election.
+func (e *Election) AllQuestions() []*Question {
+	if len(e.Questions) > 0 {
+		return e.Questions
+	}
+	return []*Question{{Candidates: e.Candidates, MaxChoices: e.MaxChoices}}
+}
+
+// Width returns the number of ciphertexts of a ballot of the election.
+func (e *Election) Width() int {
+	return len(e.AllQuestions())
+}
+
+// VerifyBallot checks the signature of the ballot against the key of its
+// voter. Ballots of elections without Voters aren't signed.
+func (e *Election) VerifyBallot(ballot *Ballot) error {
+	if len(e.Voters) == 0 {
+		return nil
+	}
+	for _, voter := range e.Voters {
+		if voter.User == ballot.User {
+			return ballot.Verify(e.ID, voter.Key)
+		}
+	}
+	return fmt.Errorf("user %d is not in the voter roll", ballot.User)
+}
+
+// CheckQuestions makes sure that the answers to the questions fit into their
+// ciphertexts and can be counted.
+func (e *Election) CheckQuestions() error {
+	if len(e.Questions) > 0 && (len(e.Candidates) > 0 || e.MaxChoices > 0) {
+		return errors.New("election has candidates and questions")
+	}
+	max := cothority.Suite.Point().EmbedLen() / 3
+	for i, q := range e.AllQuestions() {
+		candidates := make(map[uint32]bool)
+		for _, c := range q.Candidates {
+			if c >= 1<<24 {
+				return fmt.Errorf("candidate %d of question %d is too big", c, i)
+			}
+			if candidates[c] {
+				return fmt.Errorf("candidate %d appears twice in question %d", c, i)
+			}
+			candidates[c] = true
+		}
+		if q.MaxChoices < 0 || q.MaxChoices > max {
+			return fmt.Errorf("question %d allows more than %d choices", i, max)
+		}
+		// A voter has to be able to rank all candidates.
+		if q.Ranked && q.MaxChoices == 0 && len(q.Candidates) > max {
+			return fmt.Errorf("ranked question %d has more than %d candidates and needs MaxChoices",
+				i, max)
+		}
+	}
+	return nil
+}
+
 // GenChain creates an election skipchain for a specific stage and a given number of ballots.
 func (e *Election) GenChain(numBallots int) []*dkg.DistKeyGenerator {
 	chain, _ := New(e.Roster, nil)
 
 	n := len(e.Roster.List)
-	dkgs, _ := DKGSimulate(n, n-1)
+	threshold := n - 1
+	if e.Threshold > 0 {
+		threshold = e.Threshold
+	}
+	dkgs, _ := DKGSimulate(n, threshold)
 	secret, _ := NewSharedSecret(dkgs[0])
 
 	e.ID = chain.Hash
 	e.Key = secret.X
+	e.Commits = secret.Commits
 
 	box := genBox(secret.X, numBallots)
 	mixes := box.genMix(secret.X, n)
@@ -143,27 +244,7 @@ func (e *Election) Box() (*Box, error) {
 			ballots = append(ballots, ballot)
 		}
 	}
-
-	// Reverse ballot list
-	for i, j := 0, len(ballots)-1; i < j; i, j = i+1, j-1 {
-		ballots[i], ballots[j] = ballots[j], ballots[i]
-	}
-
-	// Only keep last casted ballot per user
-	mapping := make(map[uint32]bool)
-	unique := make([]*Ballot, 0)
-	for _, ballot := range ballots {
-		if _, found := mapping[ballot.User]; !found {
-			unique = append(unique, ballot)
-			mapping[ballot.User] = true
-		}
-	}
-
-	// Reverse back list of unique ballots
-	for i, j := 0, len(unique)-1; i < j; i, j = i+1, j-1 {
-		unique[i], unique[j] = unique[j], unique[i]
-	}
-	return &Box{Ballots: unique}, nil
+	return &Box{Ballots: latest(ballots)}, nil
 }
 
 // Mixes returns all mixes created by the roster conodes.
@@ -246,3 +327,29 @@ func (e *Election) storePartials(partials []*Partial) error {
 	}
 	return nil
 }
+
+// latest only keeps the last casted ballot of each user, in the order they
+// have been casted.
+func latest(ballots []*Ballot) []*Ballot {
+	// Reverse ballot list
+	reversed := make([]*Ballot, len(ballots))
+	for i, ballot := range ballots {
+		reversed[len(ballots)-1-i] = ballot
+	}
+
+	// Only keep last casted ballot per user
+	mapping := make(map[uint32]bool)
+	unique := make([]*Ballot, 0)
+	for _, ballot := range reversed {
+		if _, found := mapping[ballot.User]; !found {
+			unique = append(unique, ballot)
+			mapping[ballot.User] = true
+		}
+	}
+
+	// Reverse back list of unique ballots
+	for i, j := 0, len(unique)-1; i < j; i, j = i+1, j-1 {
+		unique[i], unique[j] = unique[j], unique[i]
+	}
+	return unique
+}
@@ -113,8 +113,61 @@ func TestIsUser(t *testing.T) {
 	assert.False(t, e.IsUser(1))
 }
 
+func TestVerifyBallot(t *testing.T) {
+	_, X := RandomKeyPair()
+	x, voter := RandomKeyPair()
+	alpha, beta := Encrypt(X, []byte{1})
+	b := &Ballot{User: 0, Alpha: alpha, Beta: beta}
+
+	// Without voter roll, ballots aren't signed.
+	e := &Election{ID: []byte("election"), Users: []uint32{0, 1}}
+	assert.Nil(t, e.VerifyBallot(b))
+
+	e.Voters = []*Voter{{User: 0, Key: voter}}
+	assert.NotNil(t, e.VerifyBallot(b))
+	assert.Nil(t, b.Sign(e.ID, x))
+	assert.Nil(t, e.VerifyBallot(b))
+	b.User = 1
+	assert.Nil(t, b.Sign(e.ID, x))
+	assert.NotNil(t, e.VerifyBallot(b))
+}
+
 func TestIsCreator(t *testing.T) {
 	e := &Election{Creator: 0, Users: []uint32{0, 1}}
 	assert.True(t, e.IsCreator(0))
 	assert.False(t, e.IsCreator(1))
 }
+
+func TestCheckQuestions(t *testing.T) {
+	e := &Election{Candidates: []uint32{1, 2}, MaxChoices: 1}
+	assert.Nil(t, e.CheckQuestions())
+	assert.Equal(t, 1, e.Width())
+
+	e = &Election{Questions: []*Question{
+		{Candidates: []uint32{1, 2}, MaxChoices: 1},
+		{Candidates: []uint32{3, 4, 5}, Ranked: true},
+	}}
+	assert.Nil(t, e.CheckQuestions())
+	assert.Equal(t, 2, e.Width())
+
+	e.MaxChoices = 1
+	assert.NotNil(t, e.CheckQuestions())
+	e.MaxChoices = 0
+
+	e.Questions[1].Candidates = []uint32{3, 3}
+	assert.NotNil(t, e.CheckQuestions())
+
+	e.Questions[1].Candidates = []uint32{1 << 24}
+	assert.NotNil(t, e.CheckQuestions())
+
+	e.Questions[1].Candidates = []uint32{3}
+	e.Questions[1].MaxChoices = 10
+	assert.NotNil(t, e.CheckQuestions())
+
+	// A ranked answer with all 10 candidates doesn't fit into a ciphertext.
+	e.Questions[1].Candidates = []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	e.Questions[1].MaxChoices = 0
+	assert.NotNil(t, e.CheckQuestions())
+	e.Questions[1].MaxChoices = 9
+	assert.Nil(t, e.CheckQuestions())
+}
@@ -18,7 +18,8 @@ type Master struct {
 
 	Admins []uint32 // Admins is the list of administrators.
 
-	Key kyber.Point // Key is the front-end public key.
+	Key  kyber.Point // Key is the front-end public key.
+	Auth *Auth       // Auth defines how users log in, nil for the front-end key.
 }
 
 // Link is a wrapper around the genesis Skipblock identifier of an
@@ -0,0 +1,248 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/proof"
+	"github.com/dedis/kyber/shuffle"
+	"github.com/dedis/kyber/util/random"
+
+	"github.com/dedis/cothority"
+)
+
+/*
+This file holds the shuffle of ballots made of several ciphertexts. All the
+ciphertexts of a ballot are permuted with the same permutation, so that the
+answers of a voter stay together.
+
+It is the shuffle of sequences of ElGamal pairs of Section 5 of "Verifiable
+Mixing (Shuffling) of ElGamal Pairs" by Andrew Neff (April 2004). Every
+position j of the ballots is re-encrypted with its own random factors
+beta[j], and all positions are permuted with the same permutation pi. Once
+the shuffled ciphertexts are fixed, the verifier picks one challenge e[j] per
+position, and the prover shows with the Neff shuffle proof of ElGamal pairs
+that the pairs
+
+  XDown[i] = sum_j e[j] * Xbar[j][i], YDown[i] = sum_j e[j] * Ybar[j][i]
+
+are a shuffle of the pairs
+
+  XUp[i] = sum_j e[j] * X[j][i], YUp[i] = sum_j e[j] * Y[j][i]
+
+with the permutation pi and the re-encryption factors sum_j e[j] * beta[j].
+If one of the positions isn't a re-encryption of the inputs under the same
+permutation, this only holds for a negligible fraction of the challenges.
+The proof is made non-interactive by deriving the challenges from a hash of
+the key and all ciphertexts before and after the shuffle.
+
+Ballots with a single ciphertext are shuffled and proven with the Neff
+shuffle of ElGamal pairs.
+*/
+
+// Shuffle permutes and re-encrypts the ballots and returns the proof of the
+// shuffle. All ballots need to have the same number of ciphertexts.
+func Shuffle(key kyber.Point, ballots []*Ballot) ([]*Ballot, []byte, error) {
+	width, err := widthOf(ballots)
+	if err != nil {
+		return nil, nil, err
+	}
+	x, y := columns(ballots, width)
+	if width == 1 {
+		v, w, prover := shuffle.Shuffle(cothority.Suite, nil, key, x[0], y[0], random.New())
+		proof, err := proof.HashProve(cothority.Suite, "", prover)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Combine(v, w), proof, nil
+	}
+
+	v, w, getProver := sequencesShuffle(key, x, y)
+	e, err := challenges(key, x, y, v, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := proof.HashProve(cothority.Suite, "", getProver(e))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows(v, w), proof, nil
+}
+
+// VerifyShuffle checks the proof that mixed is a shuffle of the ballots.
+func VerifyShuffle(tag []byte, key kyber.Point, ballots, mixed []*Ballot) error {
+	if len(ballots) != len(mixed) {
+		return errors.New("wrong number of ballots")
+	}
+	if !complete(ballots) || !complete(mixed) {
+		return errors.New("incomplete ballots")
+	}
+	width, err := widthOf(append(append([]*Ballot{}, ballots...), mixed...))
+	if err != nil {
+		return err
+	}
+	x, y := columns(ballots, width)
+	v, w := columns(mixed, width)
+	if width == 1 {
+		return Verify(tag, key, x[0], y[0], v[0], w[0])
+	}
+
+	e, err := challenges(key, x, y, v, w)
+	if err != nil {
+		return err
+	}
+	XUp, YUp, XDown, YDown := sequenceVerifiable(e, x, y, v, w)
+	return Verify(tag, key, XUp, YUp, XDown, YDown)
+}
+
+// sequencesShuffle re-encrypts all positions j of the ballots, given as
+// X[j] and Y[j], and permutes them with the same permutation. It returns the
+// shuffled ciphertexts and a function returning the prover of the shuffle
+// for the challenges e.
+func sequencesShuffle(key kyber.Point, X, Y [][]kyber.Point) (Xbar, Ybar [][]kyber.Point,
+	getProver func(e []kyber.Scalar) proof.Prover) {
+	k := len(X[0])
+	pi := permutation(k)
+	beta := make([][]kyber.Scalar, len(X))
+	Xbar, Ybar = make([][]kyber.Point, len(X)), make([][]kyber.Point, len(X))
+	for j := range X {
+		beta[j] = make([]kyber.Scalar, k)
+		Xbar[j], Ybar[j] = make([]kyber.Point, k), make([]kyber.Point, k)
+		for i := range beta[j] {
+			beta[j][i] = cothority.Suite.Scalar().Pick(random.New())
+		}
+		for i := 0; i < k; i++ {
+			Xbar[j][i] = cothority.Suite.Point().Mul(beta[j][pi[i]], nil)
+			Xbar[j][i].Add(Xbar[j][i], X[j][pi[i]])
+			Ybar[j][i] = cothority.Suite.Point().Mul(beta[j][pi[i]], key)
+			Ybar[j][i].Add(Ybar[j][i], Y[j][pi[i]])
+		}
+	}
+
+	getProver = func(e []kyber.Scalar) proof.Prover {
+		XUp, YUp, _, _ := sequenceVerifiable(e, X, Y, Xbar, Ybar)
+		b := make([]kyber.Scalar, k)
+		for i := range b {
+			b[i] = cothority.Suite.Scalar().Zero()
+			for j := range e {
+				b[i].Add(b[i], cothority.Suite.Scalar().Mul(e[j], beta[j][i]))
+			}
+		}
+		return func(ctx proof.ProverContext) error {
+			ps := shuffle.PairShuffle{}
+			ps.Init(cothority.Suite, k)
+			return ps.Prove(pi, nil, key, b, XUp, YUp, random.New(), ctx)
+		}
+	}
+	return
+}
+
+// sequenceVerifiable combines the positions of the ballots before and after
+// the shuffle with the challenges e, so that the Neff shuffle proof of
+// ElGamal pairs can be used.
+func sequenceVerifiable(e []kyber.Scalar, X, Y, Xbar, Ybar [][]kyber.Point) (XUp, YUp,
+	XDown, YDown []kyber.Point) {
+	XUp, YUp = linear(e, X, Y)
+	XDown, YDown = linear(e, Xbar, Ybar)
+	return
+}
+
+// widthOf returns the number of ciphertexts of the ballots, which has to be
+// the same for all of them.
+func widthOf(ballots []*Ballot) (int, error) {
+	if len(ballots) == 0 {
+		return 1, nil
+	}
+	n := ballots[0].Width()
+	for _, ballot := range ballots {
+		if ballot.Width() != n {
+			return 0, errors.New("ballots have different numbers of ciphertexts")
+		}
+	}
+	return n, nil
+}
+
+// columns separates the ciphertexts of the ballots into one list per
+// position in the ballots.
+func columns(ballots []*Ballot, width int) (alpha, beta [][]kyber.Point) {
+	alpha, beta = make([][]kyber.Point, width), make([][]kyber.Point, width)
+	for j := 0; j < width; j++ {
+		alpha[j], beta[j] = make([]kyber.Point, len(ballots)), make([]kyber.Point, len(ballots))
+		for i, ballot := range ballots {
+			c := ballot.Ciphertexts()[j]
+			alpha[j][i], beta[j][i] = c.Alpha, c.Beta
+		}
+	}
+	return
+}
+
+// rows creates ballots out of lists of ciphertexts per position.
+func rows(alpha, beta [][]kyber.Point) []*Ballot {
+	ballots := Combine(alpha[0], beta[0])
+	for i, ballot := range ballots {
+		for j := 1; j < len(alpha); j++ {
+			ballot.Extra = append(ballot.Extra, &Ciphertext{Alpha: alpha[j][i], Beta: beta[j][i]})
+		}
+	}
+	return ballots
+}
+
+// linear returns the linear combination of the lists of points with the
+// challenges.
+func linear(e []kyber.Scalar, alpha, beta [][]kyber.Point) (a, b []kyber.Point) {
+	k := len(alpha[0])
+	a, b = make([]kyber.Point, k), make([]kyber.Point, k)
+	for i := 0; i < k; i++ {
+		a[i], b[i] = cothority.Suite.Point().Null(), cothority.Suite.Point().Null()
+		for j := range e {
+			a[i].Add(a[i], cothority.Suite.Point().Mul(e[j], alpha[j][i]))
+			b[i].Add(b[i], cothority.Suite.Point().Mul(e[j], beta[j][i]))
+		}
+	}
+	return
+}
+
+// challenges derives one challenge per position in the ballots from a hash
+// of the key and all ciphertexts before and after the shuffle.
+func challenges(key kyber.Point, x, y, v, w [][]kyber.Point) ([]kyber.Scalar, error) {
+	h := sha256.New()
+	if _, err := key.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	for _, points := range [][][]kyber.Point{x, y, v, w} {
+		for _, column := range points {
+			for _, point := range column {
+				if _, err := point.MarshalTo(h); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	seed := h.Sum(nil)
+
+	e := make([]kyber.Scalar, len(x))
+	for j := range e {
+		h := sha256.New()
+		h.Write(seed)
+		binary.Write(h, binary.LittleEndian, uint32(j))
+		e[j] = cothority.Suite.Scalar().SetBytes(h.Sum(nil))
+	}
+	return e, nil
+}
+
+// permutation returns a random permutation of k elements.
+func permutation(k int) []int {
+	pi := make([]int, k)
+	for i := range pi {
+		pi[i] = i
+	}
+	stream := random.New()
+	for i := k - 1; i > 0; i-- {
+		j := int(random.Int(big.NewInt(int64(i+1)), stream).Int64())
+		pi[i], pi[j] = pi[j], pi[i]
+	}
+	return pi
+}
@@ -0,0 +1,63 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShuffle(t *testing.T) {
+	x, X := RandomKeyPair()
+	election := &Election{Key: X, Questions: []*Question{
+		{Candidates: []uint32{1, 2}},
+		{Candidates: []uint32{3, 4}},
+		{Candidates: []uint32{5, 6}},
+	}}
+	ballots := make([]*Ballot, 4)
+	for i := range ballots {
+		ballots[i], _ = NewBallot(election, uint32(i), [][]uint32{{1}, {3 + uint32(i%2)}, {5}})
+	}
+
+	mixed, proof, err := Shuffle(X, ballots)
+	require.Nil(t, err)
+	assert.Nil(t, VerifyShuffle(proof, X, ballots, mixed))
+
+	// The answers of a ballot stay together.
+	answers := make(map[string]int)
+	for _, ballot := range mixed {
+		require.Equal(t, 3, ballot.Width())
+		answer := ""
+		for _, c := range ballot.Ciphertexts() {
+			data, _ := Decrypt(x, c.Alpha, c.Beta).Data()
+			answer += string(data)
+		}
+		answers[answer]++
+	}
+	assert.Equal(t, 2, len(answers))
+
+	// Ciphertexts moved to another ballot are detected.
+	mixed[0].Extra[0], mixed[1].Extra[0] = mixed[1].Extra[0], mixed[0].Extra[0]
+	assert.NotNil(t, VerifyShuffle(proof, X, ballots, mixed))
+	mixed[0].Extra[0], mixed[1].Extra[0] = mixed[1].Extra[0], mixed[0].Extra[0]
+
+	assert.NotNil(t, VerifyShuffle(proof, X, ballots, mixed[1:]))
+
+	// All ballots need the same number of ciphertexts.
+	ballots[0].Extra = ballots[0].Extra[1:]
+	_, _, err = Shuffle(X, ballots)
+	assert.NotNil(t, err)
+}
+
+func TestShuffle_SingleCiphertext(t *testing.T) {
+	_, X := RandomKeyPair()
+	ballots := genBox(X, 3).Ballots
+
+	mixed, proof, err := Shuffle(X, ballots)
+	require.Nil(t, err)
+	assert.Nil(t, VerifyShuffle(proof, X, ballots, mixed))
+
+	x, y := Split(ballots)
+	v, w := Split(mixed)
+	assert.Nil(t, Verify(proof, X, x, y, v, w))
+}
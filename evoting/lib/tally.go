@@ -0,0 +1,225 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/kyber"
+
+	"github.com/dedis/cothority"
+)
+
+/*
+This file holds the encoding and the counting of the answers of a ballot.
+The answer to a question is the list of the chosen candidates, each sciper in
+three bytes in little-endian order, and it is encrypted in its own ciphertext
+of the ballot. The answer to a ranked question lists the candidates from the
+most to the least preferred one.
+
+Ranked questions are counted with instant-runoff: as long as no candidate has
+a majority of the votes, the candidates with the fewest votes are eliminated
+and their votes go to the next preferred candidate of each answer.
+*/
+
+// Tally is the result of a question of a decrypted election.
+type Tally struct {
+	Title   string   `json:"title,omitempty"`
+	Votes   []*Count `json:"votes"`   // Votes of every candidate, first preferences if ranked.
+	Invalid int      `json:"invalid"` // Invalid is the number of malformed answers.
+
+	Rounds  []*Round `json:"rounds,omitempty"`  // Rounds of the instant-runoff count.
+	Winners []uint32 `json:"winners,omitempty"` // Winners of the instant-runoff count.
+}
+
+// Count is the number of votes of a candidate.
+type Count struct {
+	Candidate uint32 `json:"candidate"`
+	Votes     int    `json:"votes"`
+}
+
+// Round is a round of an instant-runoff count.
+type Round struct {
+	Votes      []*Count `json:"votes"`     // Votes of the remaining candidates.
+	Exhausted  int      `json:"exhausted"` // Exhausted answers rank no remaining candidate.
+	Eliminated []uint32 `json:"eliminated,omitempty"`
+}
+
+// EncodeAnswer returns the plaintext of an answer choosing the candidates.
+func EncodeAnswer(candidates []uint32) []byte {
+	data := make([]byte, 0, 3*len(candidates))
+	for _, c := range candidates {
+		data = append(data, byte(c), byte(c>>8), byte(c>>16))
+	}
+	return data
+}
+
+// NewBallot encrypts the answers to the questions of the election, in the
+// order of the questions.
+func NewBallot(election *Election, user uint32, answers [][]uint32) (*Ballot, error) {
+	if len(answers) != election.Width() {
+		return nil, fmt.Errorf("election has %d questions", election.Width())
+	}
+
+	ballot := &Ballot{User: user}
+	for i, answer := range answers {
+		data := EncodeAnswer(answer)
+		if len(data) > cothority.Suite.Point().EmbedLen() {
+			return nil, fmt.Errorf("answer to question %d is too long", i)
+		}
+		alpha, beta := Encrypt(election.Key, data)
+		if i == 0 {
+			ballot.Alpha, ballot.Beta = alpha, beta
+		} else {
+			ballot.Extra = append(ballot.Extra, &Ciphertext{Alpha: alpha, Beta: beta})
+		}
+	}
+	return ballot, nil
+}
+
+// CountVotes decodes the plaintexts of the ballots, as returned by
+// Reconstruct, and counts the answers to every question of the election.
+// Answers with unknown or repeated candidates, or with more than MaxChoices
+// candidates, are invalid.
+func CountVotes(election *Election, points []kyber.Point) ([]*Tally, error) {
+	questions := election.AllQuestions()
+	if len(points)%len(questions) != 0 {
+		return nil, errors.New("number of plaintexts doesn't match the questions")
+	}
+
+	tallies := make([]*Tally, len(questions))
+	for q, question := range questions {
+		tally := &Tally{Title: question.Title}
+		answers := make([][]uint32, 0)
+		for i := q; i < len(points); i += len(questions) {
+			answer, err := question.decode(points[i])
+			if err != nil {
+				tally.Invalid++
+				continue
+			}
+			answers = append(answers, answer)
+		}
+
+		if question.Ranked {
+			tally.Rounds, tally.Winners = InstantRunoff(question.Candidates, answers)
+			tally.Votes = tally.Rounds[0].Votes
+		} else {
+			tally.Votes = approvals(question.Candidates, answers)
+		}
+		tallies[q] = tally
+	}
+	return tallies, nil
+}
+
+// InstantRunoff counts ranked answers. In every round, each answer counts for
+// its most preferred candidate still in the race. A candidate with more than
+// half of these votes wins, else the candidates with the fewest votes are
+// eliminated. If all remaining candidates have the same number of votes,
+// they are all returned as winners. Without any votes there is no winner.
+func InstantRunoff(candidates []uint32, answers [][]uint32) ([]*Round, []uint32) {
+	remaining := make(map[uint32]bool)
+	for _, c := range candidates {
+		remaining[c] = true
+	}
+
+	rounds := make([]*Round, 0)
+	for {
+		round := &Round{Votes: make([]*Count, 0)}
+		votes := make(map[uint32]int)
+		for _, answer := range answers {
+			exhausted := true
+			for _, c := range answer {
+				if remaining[c] {
+					votes[c]++
+					exhausted = false
+					break
+				}
+			}
+			if exhausted {
+				round.Exhausted++
+			}
+		}
+
+		fewest := -1
+		for _, c := range candidates {
+			if !remaining[c] {
+				continue
+			}
+			round.Votes = append(round.Votes, &Count{Candidate: c, Votes: votes[c]})
+			if fewest < 0 || votes[c] < fewest {
+				fewest = votes[c]
+			}
+		}
+		rounds = append(rounds, round)
+
+		active := len(answers) - round.Exhausted
+		if active == 0 {
+			return rounds, nil
+		}
+		for _, count := range round.Votes {
+			if 2*count.Votes > active {
+				return rounds, []uint32{count.Candidate}
+			}
+		}
+
+		eliminated := make([]uint32, 0)
+		for _, count := range round.Votes {
+			if count.Votes == fewest {
+				eliminated = append(eliminated, count.Candidate)
+			}
+		}
+		if len(eliminated) == len(round.Votes) {
+			return rounds, eliminated
+		}
+		round.Eliminated = eliminated
+		for _, c := range eliminated {
+			delete(remaining, c)
+		}
+	}
+}
+
+// approvals counts the votes of every candidate.
+func approvals(candidates []uint32, answers [][]uint32) []*Count {
+	votes := make(map[uint32]int)
+	for _, answer := range answers {
+		for _, c := range answer {
+			votes[c]++
+		}
+	}
+
+	counts := make([]*Count, len(candidates))
+	for i, c := range candidates {
+		counts[i] = &Count{Candidate: c, Votes: votes[c]}
+	}
+	return counts
+}
+
+// decode returns the candidates of the plaintext of an answer to the
+// question.
+func (q *Question) decode(point kyber.Point) ([]uint32, error) {
+	data, err := point.Data()
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%3 != 0 {
+		return nil, errors.New("malformed answer")
+	}
+	if q.MaxChoices > 0 && len(data)/3 > q.MaxChoices {
+		return nil, errors.New("too many candidates")
+	}
+
+	candidates := make(map[uint32]bool)
+	for _, c := range q.Candidates {
+		candidates[c] = true
+	}
+	answer := make([]uint32, 0, len(data)/3)
+	for i := 0; i < len(data); i += 3 {
+		c := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16
+		if !candidates[c] {
+			return nil, fmt.Errorf("unknown candidate %d", c)
+		}
+		// Chosen candidates are removed, so that repetitions are unknown.
+		delete(candidates, c)
+		answer = append(answer, c)
+	}
+	return answer, nil
+}
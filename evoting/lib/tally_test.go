@@ -0,0 +1,121 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/dedis/kyber"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dedis/cothority"
+)
+
+func TestInstantRunoff(t *testing.T) {
+	// 3 wins in the second round with the votes of 1.
+	rounds, winners := InstantRunoff([]uint32{1, 2, 3}, [][]uint32{
+		{1, 3}, {2}, {2}, {3}, {3, 2},
+	})
+	require.Equal(t, 2, len(rounds))
+	assert.Equal(t, []*Count{{1, 1}, {2, 2}, {3, 2}}, rounds[0].Votes)
+	assert.Equal(t, []uint32{1}, rounds[0].Eliminated)
+	assert.Equal(t, []*Count{{2, 2}, {3, 3}}, rounds[1].Votes)
+	assert.Equal(t, []uint32{3}, winners)
+
+	// Answers without remaining candidates are exhausted and the majority
+	// is taken among the other ones.
+	rounds, winners = InstantRunoff([]uint32{1, 2, 3}, [][]uint32{
+		{1}, {2}, {2}, {3, 1}, {3}, {3},
+	})
+	require.Equal(t, 2, len(rounds))
+	assert.Equal(t, []uint32{1}, rounds[0].Eliminated)
+	assert.Equal(t, 1, rounds[1].Exhausted)
+	assert.Equal(t, []uint32{3}, winners)
+
+	// Candidates tied for the fewest votes are eliminated together.
+	rounds, winners = InstantRunoff([]uint32{1, 2, 3, 4}, [][]uint32{
+		{1, 4}, {2, 4}, {3}, {3}, {4}, {4},
+	})
+	require.Equal(t, 2, len(rounds))
+	assert.Equal(t, []uint32{1, 2}, rounds[0].Eliminated)
+	assert.Equal(t, []*Count{{3, 2}, {4, 4}}, rounds[1].Votes)
+	assert.Equal(t, []uint32{4}, winners)
+
+	// A tie between all remaining candidates has several winners.
+	_, winners = InstantRunoff([]uint32{1, 2, 3}, [][]uint32{{1}, {2}})
+	assert.Equal(t, []uint32{1, 2}, winners)
+
+	// Without votes there is no winner.
+	rounds, winners = InstantRunoff([]uint32{1, 2}, [][]uint32{})
+	assert.Equal(t, 1, len(rounds))
+	assert.Nil(t, winners)
+}
+
+func TestCountVotes(t *testing.T) {
+	embed := func(data ...[]byte) []kyber.Point {
+		points := make([]kyber.Point, len(data))
+		for i := range data {
+			points[i] = cothority.Suite.Point().Embed(data[i], cothority.Suite.RandomStream())
+		}
+		return points
+	}
+
+	election := &Election{Candidates: []uint32{1, 2, 3}, MaxChoices: 2}
+	tallies, err := CountVotes(election, embed(
+		encode(1, 2), encode(2), encode(), encode(1, 2, 3), encode(2, 2), encode(4),
+		[]byte{1, 0},
+	))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tallies))
+	assert.Equal(t, []*Count{{1, 1}, {2, 2}, {3, 0}}, tallies[0].Votes)
+	assert.Equal(t, 4, tallies[0].Invalid)
+	assert.Nil(t, tallies[0].Rounds)
+
+	election = &Election{Questions: []*Question{
+		{Title: "president", Candidates: []uint32{1, 2}, MaxChoices: 1},
+		{Title: "board", Candidates: []uint32{3, 4, 5}, Ranked: true},
+	}}
+	tallies, err = CountVotes(election, embed(
+		encode(1), encode(3, 5),
+		encode(2), encode(4, 5),
+		encode(1), encode(5, 4),
+		encode(1, 2), encode(5, 5),
+	))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(tallies))
+	assert.Equal(t, "president", tallies[0].Title)
+	assert.Equal(t, []*Count{{1, 2}, {2, 1}}, tallies[0].Votes)
+	assert.Equal(t, 1, tallies[0].Invalid)
+	assert.Equal(t, "board", tallies[1].Title)
+	assert.Equal(t, []*Count{{3, 1}, {4, 1}, {5, 1}}, tallies[1].Votes)
+	assert.Equal(t, 1, tallies[1].Invalid)
+	assert.Equal(t, []uint32{3, 4, 5}, tallies[1].Winners)
+
+	_, err = CountVotes(election, embed(encode(1)))
+	assert.NotNil(t, err)
+}
+
+func TestNewBallot(t *testing.T) {
+	x, X := RandomKeyPair()
+	election := &Election{Key: X, Questions: []*Question{
+		{Candidates: []uint32{1, 2}},
+		{Candidates: []uint32{3, 4}, Ranked: true},
+	}}
+
+	ballot, err := NewBallot(election, 7, [][]uint32{{1, 2}, {4, 3}})
+	require.Nil(t, err)
+	assert.Equal(t, uint32(7), ballot.User)
+	require.Equal(t, 2, ballot.Width())
+	for i, answer := range [][]byte{encode(1, 2), encode(4, 3)} {
+		c := ballot.Ciphertexts()[i]
+		data, err := Decrypt(x, c.Alpha, c.Beta).Data()
+		require.Nil(t, err)
+		assert.Equal(t, answer, data)
+	}
+
+	_, err = NewBallot(election, 7, [][]uint32{{1}})
+	assert.NotNil(t, err)
+
+	_, err = NewBallot(election, 7, [][]uint32{{1}, make([]uint32, 10)})
+	assert.NotNil(t, err)
+}
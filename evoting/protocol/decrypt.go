@@ -1,27 +1,38 @@
 package protocol
 
 import (
+	"errors"
+	"time"
+
 	"github.com/dedis/kyber"
 	"github.com/dedis/onet"
+	"github.com/dedis/onet/log"
 	"github.com/dedis/onet/network"
 
 	"github.com/dedis/cothority/evoting/lib"
 )
 
 /*
-Each participating node begins with verifying the integrity of each mix. If
-If all mixes are correct a partial decryption of the last mix is performed using
-the node's shared secret from the DKG. The result is the appended to the election
-skipchain before prompting the next node. A node sets a flag in its partial if it
-cannot verify all the mixes. The leaf node notifies the root upon completing its turn, which
-terminates the protocol.
+The root node performs its own partial decryption and then prompts the other
+nodes one after the other to perform theirs. Each node begins with verifying
+the integrity of each mix. It then performs a verifiable partial decryption of
+the last mix using the node's shared secret from the DKG and appends the
+partial to the election skipchain before replying to the root. A node sets a flag in its
+partial if it cannot verify all the mixes. The root skips nodes that are
+unreachable, don't reply in time or cannot decrypt, and terminates the protocol
+as soon as the election holds the partials of a threshold of nodes, or when
+all nodes have been prompted.
 
 Schema:
 
-        [Prompt]            [Prompt]            [Prompt]         [Terminate]
-  Root ------------> Node1 ------------> Node2 --> ... --> Leaf ------------> Root
+          [Prompt]
+  Root ------------> Node_i    for i = 1, 2, ... until enough partials are stored
+       <------------
+          [Reply]
 
-The protocol can only be started by the election's creator and is non-repeatable.
+The protocol can only be started by the election's creator. Nodes that already
+stored a partial are not prompted again, so it can be repeated if not enough
+nodes were reachable.
 */
 
 // NameDecrypt is the protocol identifier string.
@@ -33,29 +44,114 @@ type Decrypt struct {
 
 	Secret   *lib.SharedSecret // Secret is the private key share from the DKG.
 	Election *lib.Election     // Election to be decrypted.
-	Finished chan bool         // Flag to signal protocol termination.
+	Timeout  time.Duration     // Timeout to wait for the reply of a node.
+	Finished chan bool         // Flag to signal protocol termination, true if decrypted.
+
+	replies chan MessageReplyDecrypt
 }
 
 func init() {
-	network.RegisterMessages(PromptDecrypt{}, TerminateDecrypt{})
+	network.RegisterMessages(PromptDecrypt{}, ReplyDecrypt{})
 	onet.GlobalProtocolRegister(NameDecrypt, NewDecrypt)
 }
 
 // NewDecrypt initializes the protocol object and registers all the handlers.
 func NewDecrypt(node *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
-	decrypt := &Decrypt{TreeNodeInstance: node, Finished: make(chan bool)}
-	decrypt.RegisterHandlers(decrypt.HandlePrompt, decrypt.HandleTerminate)
+	decrypt := &Decrypt{
+		TreeNodeInstance: node,
+		Timeout:          20 * time.Second,
+		Finished:         make(chan bool, 1),
+		replies:          make(chan MessageReplyDecrypt, len(node.Children())),
+	}
+	decrypt.RegisterHandlers(decrypt.HandlePrompt, decrypt.HandleReply)
 	return decrypt, nil
 }
 
-// Start is called on the root node prompting it to send itself a Prompt message.
+// Start is called on the root node. It stores the partial of the root and
+// then prompts the other nodes.
 func (d *Decrypt) Start() error {
-	return d.HandlePrompt(MessagePromptDecrypt{d.TreeNode(), PromptDecrypt{}})
+	partials, err := d.Election.Partials()
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]bool)
+	for _, partial := range partials {
+		stored[partial.Node] = true
+	}
+
+	if !stored[d.Name()] {
+		if err := d.decrypt(); err != nil {
+			log.Error(d.Name(), "couldn't decrypt:", err)
+		} else {
+			stored[d.Name()] = true
+		}
+	}
+	go d.prompt(stored)
+	return nil
 }
 
-// HandlePrompt retrieves the mixes, verifies them and performs a partial decryption
-// on the last mix before appending it to the election skipchain.
+// HandlePrompt performs the partial decryption and replies to the root.
 func (d *Decrypt) HandlePrompt(prompt MessagePromptDecrypt) error {
+	defer d.Done()
+	err := d.decrypt()
+	if err != nil {
+		log.Error(d.Name(), "couldn't decrypt:", err)
+	}
+	return d.SendToParent(&ReplyDecrypt{Stored: err == nil})
+}
+
+// HandleReply passes the reply of a node to the prompting root.
+func (d *Decrypt) HandleReply(reply MessageReplyDecrypt) error {
+	d.replies <- reply
+	return nil
+}
+
+// prompt asks one node after the other to store its partial, until enough
+// partials are stored.
+func (d *Decrypt) prompt(stored map[string]bool) {
+	defer d.Done()
+	for _, child := range d.Children() {
+		if len(stored) >= d.Election.Quorum() {
+			break
+		}
+		name := child.ServerIdentity.Address.String()
+		if stored[name] {
+			continue
+		}
+		if err := d.SendTo(child, &PromptDecrypt{}); err != nil {
+			log.Lvl2("Skipping unreachable node", name, ":", err)
+			continue
+		}
+		if d.waitReply(child) {
+			stored[name] = true
+		}
+	}
+	d.Finished <- len(stored) >= d.Election.Quorum()
+}
+
+// waitReply returns true if the node replied in time that it stored its
+// partial. Late replies of previously prompted nodes are ignored.
+func (d *Decrypt) waitReply(child *onet.TreeNode) bool {
+	timeout := time.After(d.Timeout)
+	for {
+		select {
+		case reply := <-d.replies:
+			if reply.TreeNode.ID.Equal(child.ID) {
+				return reply.Stored
+			}
+		case <-timeout:
+			log.Lvl2("Skipping node", child.ServerIdentity, "that didn't reply in time")
+			return false
+		}
+	}
+}
+
+// decrypt verifies the mixes, performs the partial decryption of the last
+// mix and stores it on the election skipchain.
+func (d *Decrypt) decrypt() error {
+	if d.Secret == nil {
+		return errors.New("no share of the secret")
+	}
 	box, err := d.Election.Box()
 	if err != nil {
 		return err
@@ -64,43 +160,27 @@ func (d *Decrypt) HandlePrompt(prompt MessagePromptDecrypt) error {
 	if err != nil {
 		return err
 	}
-
-	last := mixes[len(mixes)-1].Ballots
-	points := make([]kyber.Point, len(box.Ballots))
-	for i := range points {
-		points[i] = lib.Decrypt(d.Secret.V, last[i].Alpha, last[i].Beta)
+	if len(mixes) == 0 {
+		return errors.New("no mixes to decrypt")
 	}
-	flag := Verify(d.Election.Key, box, mixes)
-	partial := &lib.Partial{Points: points, Flag: flag, Node: d.Name()}
 
-	if err = d.Election.Store(partial); err != nil {
+	partial, err := lib.NewPartial(d.Secret, mixes[len(mixes)-1])
+	if err != nil {
 		return err
 	}
-
-	if d.IsLeaf() {
-		return d.SendTo(d.Root(), &TerminateDecrypt{})
-	}
-	return d.SendToChildren(&PromptDecrypt{})
-}
-
-// HandleTerminate concludes to the protocol.
-func (d *Decrypt) HandleTerminate(terminate MessageTerminateDecrypt) error {
-	d.Finished <- true
-	return nil
+	partial.Flag = Verify(d.Election.Key, box, mixes)
+	partial.Node = d.Name()
+	return d.Election.Store(partial)
 }
 
 // Verify iteratively checks the integrity of each mix.
 func Verify(key kyber.Point, box *lib.Box, mixes []*lib.Mix) bool {
-	x, y := lib.Split(box.Ballots)
-	v, w := lib.Split(mixes[0].Ballots)
-	if lib.Verify(mixes[0].Proof, key, x, y, v, w) != nil {
+	if lib.VerifyShuffle(mixes[0].Proof, key, box.Ballots, mixes[0].Ballots) != nil {
 		return false
 	}
 
 	for i := 0; i < len(mixes)-1; i++ {
-		x, y = lib.Split(mixes[i].Ballots)
-		v, w = lib.Split(mixes[i+1].Ballots)
-		if lib.Verify(mixes[i+1].Proof, key, x, y, v, w) != nil {
+		if lib.VerifyShuffle(mixes[i+1].Proof, key, mixes[i].Ballots, mixes[i+1].Ballots) != nil {
 			return false
 		}
 	}
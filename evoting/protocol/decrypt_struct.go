@@ -4,8 +4,8 @@ import (
 	"github.com/dedis/onet"
 )
 
-// PromptDecrypt is sent from node to node prompting the receiver to perform
-// their respective partial decryption of the last mix.
+// PromptDecrypt is sent from the root to a node prompting the receiver to
+// perform their respective partial decryption of the last mix.
 type PromptDecrypt struct{}
 
 // MessagePromptDecrypt is a wrapper around PromptDecrypt.
@@ -14,12 +14,14 @@ type MessagePromptDecrypt struct {
 	PromptDecrypt
 }
 
-// TerminateDecrypt is sent by the leaf node to the root node upon completion of
-// the last partial decryption, which terminates the protocol.
-type TerminateDecrypt struct{}
+// ReplyDecrypt is sent back to the root by a prompted node. Stored is true
+// if the node stored its partial decryption.
+type ReplyDecrypt struct {
+	Stored bool
+}
 
-// MessageTerminateDecrypt is a wrapper around TerminateDecrypt.
-type MessageTerminateDecrypt struct {
+// MessageReplyDecrypt is a wrapper around ReplyDecrypt.
+type MessageReplyDecrypt struct {
 	*onet.TreeNode
-	TerminateDecrypt
+	ReplyDecrypt
 }
@@ -46,22 +46,34 @@ func (s *decryptService) NewProtocol(node *onet.TreeNodeInstance, conf *onet.Gen
 
 func TestDecryptProtocol(t *testing.T) {
 	for _, nodes := range []int{3, 5, 7} {
-		runDecrypt(t, nodes)
+		runDecrypt(t, nodes, 0, 0)
 	}
 }
 
-func runDecrypt(t *testing.T, n int) {
+func TestDecryptProtocol_Threshold(t *testing.T) {
+	// One node lost its secret, but the others are enough.
+	runDecrypt(t, 5, 4, 1)
+	// Two nodes lost their secret, which is too many.
+	runDecrypt(t, 5, 4, 2)
+}
+
+// runDecrypt runs the decrypt protocol on n nodes, of which the last missing
+// nodes don't have a secret.
+func runDecrypt(t *testing.T, n, threshold, missing int) {
 	local := onet.NewLocalTest(cothority.Suite)
 	defer local.CloseAll()
 
-	nodes, roster, tree := local.GenBigTree(n, n, 1, true)
+	nodes, roster, _ := local.GenBigTree(n, n, 1, true)
+	tree := roster.GenerateNaryTree(n)
 
-	election := &lib.Election{Roster: roster, Stage: lib.Shuffled}
+	election := &lib.Election{Roster: roster, Stage: lib.Shuffled, Threshold: threshold}
 	dkgs := election.GenChain(n)
 
 	services := local.GetServices(nodes, decryptServiceID)
 	for i := range services {
-		services[i].(*decryptService).secret, _ = lib.NewSharedSecret(dkgs[i])
+		if i < n-missing {
+			services[i].(*decryptService).secret, _ = lib.NewSharedSecret(dkgs[i])
+		}
 		services[i].(*decryptService).election = election
 	}
 
@@ -69,15 +81,32 @@ func runDecrypt(t *testing.T, n int) {
 	decrypt := instance.(*Decrypt)
 	decrypt.Secret, _ = lib.NewSharedSecret(dkgs[0])
 	decrypt.Election = election
-	decrypt.Start()
+	require.Nil(t, decrypt.Start())
 
 	select {
-	case <-decrypt.Finished:
+	case decrypted := <-decrypt.Finished:
+		require.Equal(t, n-missing >= election.Quorum(), decrypted)
 		partials, _ := election.Partials()
+		require.Equal(t, min(n-missing, election.Quorum()), len(partials))
 		for _, partial := range partials {
 			require.True(t, partial.Flag)
 		}
+		if !decrypted {
+			return
+		}
+		mixes, _ := election.Mixes()
+		points, err := lib.Reconstruct(mixes[len(mixes)-1], partials, election.Commits,
+			election.Quorum())
+		require.Nil(t, err)
+		require.Equal(t, n, len(points))
 	case <-time.After(60 * time.Second):
 		assert.True(t, false)
 	}
 }
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
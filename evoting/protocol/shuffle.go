@@ -3,10 +3,6 @@ package protocol
 import (
 	"errors"
 
-	"github.com/dedis/cothority"
-	"github.com/dedis/kyber/proof"
-	"github.com/dedis/kyber/shuffle"
-	"github.com/dedis/kyber/util/random"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/network"
 
@@ -80,14 +76,12 @@ func (s *Shuffle) HandlePrompt(prompt MessagePrompt) error {
 
 	}
 
-	a, b := lib.Split(ballots)
-	g, d, prover := shuffle.Shuffle(cothority.Suite, nil, s.Election.Key, a, b, random.New())
-	proof, err := proof.HashProve(cothority.Suite, "", prover)
+	mixed, proof, err := lib.Shuffle(s.Election.Key, ballots)
 	if err != nil {
 		return err
 	}
 
-	mix := &lib.Mix{Ballots: lib.Combine(g, d), Proof: proof, Node: s.Name()}
+	mix := &lib.Mix{Ballots: mixed, Proof: proof, Node: s.Name()}
 	if err := s.Election.Store(mix); err != nil {
 		return err
 	}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dedis/kyber"
+
+	"github.com/dedis/cothority/evoting"
+	"github.com/dedis/cothority/evoting/lib"
+)
+
+/*
+This file holds the authentication of the users logging in to a master
+skipchain. The Auth of the master, set when it is linked, defines how users
+authenticate:
+
+ - without Auth, the front-end signs the login with its key, after having
+   authenticated the user itself, e.g. with Tequila at EPFL.
+ - with Voters, every voter signs the login and its timestamp with its own
+   key.
+ - with an Issuer, the login holds an ID token of an OpenID Connect issuer.
+*/
+
+// Authenticator verifies a login to a master skipchain and returns the
+// identifier of the user.
+type Authenticator interface {
+	Authenticate(req *evoting.Login) (uint32, error)
+}
+
+// loginValidity is how far the timestamp of a login signed by a voter may be
+// from the time of the conode.
+const loginValidity = time.Minute
+
+// newAuthenticator returns the authenticator of the master skipchain. The
+// keys of OpenID Connect issuers are taken from the cache.
+func newAuthenticator(master *lib.Master, keys *keyCache) (Authenticator, error) {
+	auth := master.Auth
+	if auth == nil || (len(auth.Voters) == 0 && auth.Issuer == "") {
+		return &signedLogin{key: master.Key}, nil
+	}
+	if len(auth.Voters) > 0 && auth.Issuer != "" {
+		return nil, errors.New("only one of voters or issuer can be set")
+	}
+	if len(auth.Voters) > 0 {
+		return newVoterRoll(auth.Voters), nil
+	}
+	if auth.Audience == "" {
+		return nil, errors.New("audience of the issuer is missing")
+	}
+	return newOIDC(auth.Issuer, auth.Audience, auth.Claim, keys), nil
+}
+
+// signedLogin accepts logins signed by the front-end.
+type signedLogin struct {
+	key kyber.Point
+}
+
+// Authenticate checks the signature of the front-end.
+func (a *signedLogin) Authenticate(req *evoting.Login) (uint32, error) {
+	if a.key == nil || req.Verify(a.key) != nil {
+		return 0, errInvalidSignature
+	}
+	return req.User, nil
+}
+
+// voterRoll accepts logins signed by the key of the voter.
+type voterRoll struct {
+	keys map[uint32]kyber.Point
+}
+
+// newVoterRoll maps the voters to their keys.
+func newVoterRoll(voters []*lib.Voter) *voterRoll {
+	roll := &voterRoll{keys: make(map[uint32]kyber.Point)}
+	for _, voter := range voters {
+		roll.keys[voter.User] = voter.Key
+	}
+	return roll
+}
+
+// Authenticate checks the signature of the voter, which has to include a
+// timestamp close to the current time, so that an intercepted login cannot
+// be replayed later.
+func (a *voterRoll) Authenticate(req *evoting.Login) (uint32, error) {
+	key, ok := a.keys[req.User]
+	if !ok {
+		return 0, errors.New("user is not in the voter roll")
+	}
+	login := time.Unix(req.Timestamp, 0)
+	if req.Timestamp == 0 || time.Since(login) > loginValidity ||
+		time.Until(login) > loginValidity {
+		return 0, errors.New("login is expired or from the future")
+	}
+	if key == nil || req.Verify(key) != nil {
+		return 0, errInvalidSignature
+	}
+	return req.User, nil
+}
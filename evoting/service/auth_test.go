@@ -0,0 +1,305 @@
+package service
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dedis/onet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/evoting"
+	"github.com/dedis/cothority/evoting/lib"
+)
+
+// newIssuer starts a stand-in OpenID Connect issuer publishing the public
+// key of the returned private key with the key id "test".
+func newIssuer(t *testing.T) (*httptest.Server, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	server, _ := serveKey(key)
+	return server, key
+}
+
+// serveKey starts a stand-in OpenID Connect issuer publishing the public key
+// with the key id "test". The returned counter holds the number of times the
+// keys have been fetched.
+func serveKey(key *rsa.PrivateKey) (*httptest.Server, *int32) {
+	fetched := new(int32)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   server.URL,
+			"jwks_uri": server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fetched, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	return server, fetched
+}
+
+// newToken returns an ID token with the claims signed by the key.
+func newToken(key *rsa.PrivateKey, alg, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDC_Authenticate(t *testing.T) {
+	server, key := newIssuer(t)
+	defer server.Close()
+
+	auth := newOIDC(server.URL, "evoting", "", newKeyCache())
+	claims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss": server.URL,
+			"aud": "evoting",
+			"sub": "123456",
+			"exp": time.Now().Unix() + 60,
+		}
+	}
+	login := func(token string) (uint32, error) {
+		return auth.Authenticate(&evoting.Login{IDToken: token})
+	}
+
+	user, err := login(newToken(key, "RS256", "test", claims()))
+	require.Nil(t, err)
+	assert.Equal(t, uint32(123456), user)
+
+	c := claims()
+	c["aud"] = []string{"other", "evoting"}
+	user, err = login(newToken(key, "RS256", "test", c))
+	require.Nil(t, err)
+	assert.Equal(t, uint32(123456), user)
+
+	c = claims()
+	c["aud"] = "other"
+	_, err = login(newToken(key, "RS256", "test", c))
+	assert.NotNil(t, err)
+
+	c = claims()
+	c["iss"] = "https://other.issuer"
+	_, err = login(newToken(key, "RS256", "test", c))
+	assert.NotNil(t, err)
+
+	c = claims()
+	c["exp"] = time.Now().Unix() - 1
+	_, err = login(newToken(key, "RS256", "test", c))
+	assert.NotNil(t, err)
+
+	c = claims()
+	delete(c, "exp")
+	_, err = login(newToken(key, "RS256", "test", c))
+	assert.NotNil(t, err)
+
+	c = claims()
+	c["nbf"] = time.Now().Unix() + 60
+	_, err = login(newToken(key, "RS256", "test", c))
+	assert.NotNil(t, err)
+
+	c = claims()
+	c["sub"] = "alice"
+	_, err = login(newToken(key, "RS256", "test", c))
+	assert.NotNil(t, err)
+
+	_, err = login(newToken(key, "HS256", "test", claims()))
+	assert.NotNil(t, err)
+
+	_, err = login(newToken(key, "RS256", "unknown", claims()))
+	assert.NotNil(t, err)
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, err = login(newToken(other, "RS256", "test", claims()))
+	assert.Equal(t, errInvalidSignature, err)
+
+	_, err = login("not a token")
+	assert.NotNil(t, err)
+
+	// The user identifier can be taken from another claim.
+	auth = newOIDC(server.URL, "evoting", "sciper", newKeyCache())
+	c = claims()
+	c["sciper"] = 654321
+	user, err = login(newToken(key, "RS256", "", c))
+	require.Nil(t, err)
+	assert.Equal(t, uint32(654321), user)
+}
+
+func TestOIDC_KeyCache(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	server, fetched := serveKey(key)
+	defer server.Close()
+
+	keys := newKeyCache()
+	login := func(kid string) error {
+		token := newToken(key, "RS256", kid, map[string]interface{}{
+			"iss": server.URL,
+			"aud": "evoting",
+			"sub": "123456",
+			"exp": time.Now().Unix() + 60,
+		})
+		_, err := newOIDC(server.URL, "evoting", "", keys).Authenticate(
+			&evoting.Login{IDToken: token})
+		return err
+	}
+	require.Nil(t, login("test"))
+	require.Nil(t, login("test"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(fetched))
+
+	// Unknown key ids only fetch the keys again after keyRefresh.
+	assert.NotNil(t, login("unknown"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(fetched))
+	keys.issuers[server.URL].fetched = time.Now().Add(-keyRefresh - time.Second)
+	assert.NotNil(t, login("unknown"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(fetched))
+
+	keys.issuers[server.URL].fetched = time.Now().Add(-keyTTL - time.Second)
+	require.Nil(t, login("test"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(fetched))
+}
+
+func TestOIDC_SmallKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.Nil(t, err)
+	server, _ := serveKey(key)
+	defer server.Close()
+
+	token := newToken(key, "RS256", "test", map[string]interface{}{
+		"iss": server.URL,
+		"aud": "evoting",
+		"sub": "123456",
+		"exp": time.Now().Unix() + 60,
+	})
+	_, err = newOIDC(server.URL, "evoting", "", newKeyCache()).Authenticate(
+		&evoting.Login{IDToken: token})
+	assert.NotNil(t, err)
+}
+
+func TestLink_InvalidAuth(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+
+	_, err := s.Link(&evoting.Link{Pin: s.pin, Roster: roster,
+		Auth: &lib.Auth{Issuer: "https://issuer"}})
+	assert.NotNil(t, err)
+
+	_, X := lib.RandomKeyPair()
+	_, err = s.Link(&evoting.Link{Pin: s.pin, Roster: roster,
+		Auth: &lib.Auth{Issuer: "https://issuer", Audience: "evoting",
+			Voters: []*lib.Voter{{User: 0, Key: X}}}})
+	assert.NotNil(t, err)
+}
+
+func TestLogin_VoterRoll(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+
+	election := &lib.Election{
+		Roster:  roster,
+		Creator: 0,
+		Users:   []uint32{7},
+		Stage:   lib.Running,
+	}
+	_ = election.GenChain(3)
+
+	x, X := lib.RandomKeyPair()
+	x7, X7 := lib.RandomKeyPair()
+	master := &lib.Master{Roster: roster, Key: X,
+		Auth: &lib.Auth{Voters: []*lib.Voter{{User: 7, Key: X7}}}}
+	master.GenChain(election.ID)
+
+	l := &evoting.Login{User: 7, ID: master.ID, Timestamp: time.Now().Unix()}
+	l.Sign(x7)
+	r, err := s.Login(l)
+	require.Nil(t, err)
+	assert.Equal(t, election.ID, r.Elections[0].ID)
+
+	// Logins without a recent timestamp are refused.
+	for _, ts := range []int64{0, time.Now().Add(-2 * loginValidity).Unix(),
+		time.Now().Add(2 * loginValidity).Unix()} {
+		old := &evoting.Login{User: 7, ID: master.ID, Timestamp: ts}
+		old.Sign(x7)
+		_, err = s.Login(old)
+		assert.NotNil(t, err)
+	}
+
+	// The front-end key is not accepted anymore.
+	l.Sign(x)
+	_, err = s.Login(l)
+	assert.Equal(t, errInvalidSignature, err)
+
+	l = &evoting.Login{User: 8, ID: master.ID, Timestamp: time.Now().Unix()}
+	l.Sign(x7)
+	_, err = s.Login(l)
+	assert.NotNil(t, err)
+}
+
+func TestLogin_OIDC(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+
+	server, key := newIssuer(t)
+	defer server.Close()
+
+	election := &lib.Election{
+		Roster:  roster,
+		Creator: 0,
+		Users:   []uint32{123456},
+		Stage:   lib.Running,
+	}
+	_ = election.GenChain(3)
+
+	_, X := lib.RandomKeyPair()
+	master := &lib.Master{Roster: roster, Key: X,
+		Auth: &lib.Auth{Issuer: server.URL, Audience: "evoting"}}
+	master.GenChain(election.ID)
+
+	token := newToken(key, "RS256", "test", map[string]interface{}{
+		"iss": server.URL,
+		"aud": "evoting",
+		"sub": "123456",
+		"exp": time.Now().Unix() + 60,
+	})
+	r, err := s.Login(&evoting.Login{ID: master.ID, IDToken: token})
+	require.Nil(t, err)
+	assert.Equal(t, election.ID, r.Elections[0].ID)
+	assert.Equal(t, uint32(123456), s.state.get(r.Token).user)
+
+	_, err = s.Login(&evoting.Login{ID: master.ID, IDToken: "a.b.c"})
+	assert.NotNil(t, err)
+}
@@ -140,7 +140,8 @@ func TestCast_Full(t *testing.T) {
 	}
 	_ = election.GenChain(3)
 
-	ballot := &lib.Ballot{User: 1000}
+	alpha, beta := lib.Encrypt(election.Key, lib.EncodeAnswer(nil))
+	ballot := &lib.Ballot{User: 1000, Alpha: alpha, Beta: beta}
 	r, _ := s.Cast(&evoting.Cast{Token: token, ID: election.ID, Ballot: ballot})
 	assert.NotNil(t, r)
 
@@ -149,3 +150,91 @@ func TestCast_Full(t *testing.T) {
 	_, blob, _ := network.Unmarshal(chain.Update[len(chain.Update)-1].Data, cothority.Suite)
 	assert.Equal(t, ballot.User, blob.(*lib.Ballot).User)
 }
+
+func TestCast_WrongWidth(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+	token := s.state.register(1000, false)
+
+	election := &lib.Election{
+		Roster:  roster,
+		Creator: 0,
+		Users:   []uint32{1000},
+		Stage:   lib.Running,
+		End:     time.Now().Unix() + 3600,
+		Questions: []*lib.Question{
+			{Candidates: []uint32{1, 2}},
+			{Candidates: []uint32{3, 4}, Ranked: true},
+		},
+	}
+	_ = election.GenChain(3)
+
+	_, err := s.Cast(&evoting.Cast{Token: token, ID: election.ID,
+		Ballot: &lib.Ballot{User: 1000}})
+	assert.NotNil(t, err)
+}
+
+func TestCast_IncompleteBallot(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+	token := s.state.register(1000, false)
+
+	election := &lib.Election{
+		Roster:  roster,
+		Creator: 0,
+		Users:   []uint32{1000},
+		Stage:   lib.Running,
+		End:     time.Now().Unix() + 3600,
+		Questions: []*lib.Question{
+			{Candidates: []uint32{1, 2}},
+			{Candidates: []uint32{3, 4}},
+		},
+	}
+	_ = election.GenChain(3)
+
+	alpha, beta := lib.Encrypt(election.Key, lib.EncodeAnswer(nil))
+	ballot := &lib.Ballot{User: 1000, Alpha: alpha, Beta: beta,
+		Extra: []*lib.Ciphertext{{}}}
+	_, err := s.Cast(&evoting.Cast{Token: token, ID: election.ID, Ballot: ballot})
+	assert.NotNil(t, err)
+
+	ballot.Extra[0].Alpha, ballot.Extra[0].Beta = lib.Encrypt(election.Key,
+		lib.EncodeAnswer(nil))
+	_, err = s.Cast(&evoting.Cast{Token: token, ID: election.ID, Ballot: ballot})
+	assert.Nil(t, err)
+}
+
+func TestCast_VoterSignature(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+	token := s.state.register(1000, false)
+
+	x, X := lib.RandomKeyPair()
+	election := &lib.Election{
+		Roster:  roster,
+		Creator: 0,
+		Users:   []uint32{1000},
+		Stage:   lib.Running,
+		End:     time.Now().Unix() + 3600,
+		Voters:  []*lib.Voter{{User: 1000, Key: X}},
+	}
+	_ = election.GenChain(3)
+
+	alpha, beta := lib.Encrypt(election.Key, lib.EncodeAnswer(nil))
+	ballot := &lib.Ballot{User: 1000, Alpha: alpha, Beta: beta}
+	_, err := s.Cast(&evoting.Cast{Token: token, ID: election.ID, Ballot: ballot})
+	assert.NotNil(t, err)
+
+	ballot.Sign(election.ID, x)
+	_, err = s.Cast(&evoting.Cast{Token: token, ID: election.ID, Ballot: ballot})
+	assert.Nil(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/dedis/onet"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/evoting"
@@ -123,7 +124,7 @@ func TestDecrypt_Full(t *testing.T) {
 	election := &lib.Election{
 		Roster:  roster,
 		Creator: 0,
-		Users:   []uint32{0},
+		Users:   []uint32{0, 1, 2},
 		Stage:   lib.Shuffled,
 	}
 	dkgs := election.GenChain(3)
@@ -133,4 +134,13 @@ func TestDecrypt_Full(t *testing.T) {
 
 	r, _ := s0.Decrypt(&evoting.Decrypt{Token: token, ID: election.ID})
 	assert.NotNil(t, r)
+
+	// The partials of the protocol pass the audit.
+	report, err := lib.Audit(roster, election.ID)
+	require.Nil(t, err)
+	assert.True(t, report.Valid, report.Errors)
+	assert.Equal(t, 3, len(report.Partials))
+	for _, partial := range report.Partials {
+		assert.True(t, partial.Flag)
+	}
 }
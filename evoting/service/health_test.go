@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dedis/onet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/evoting"
+	"github.com/dedis/cothority/evoting/lib"
+	"github.com/dedis/cothority/skipchain"
+)
+
+func TestHealth_InvalidMasterID(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, _, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+
+	_, err := s.Health(&evoting.Health{ID: []byte{}})
+	assert.NotNil(t, err)
+}
+
+func TestHealth_Full(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	services := local.GetServices(nodes, serviceID)
+	s := services[0].(*Service)
+	token := s.state.register(0, true)
+
+	election := &lib.Election{End: time.Now().Unix() + 3600}
+	master := &lib.Master{Roster: roster}
+	master.GenChain(nil)
+	r, err := s.Open(&evoting.Open{Token: token, ID: master.ID, Election: election})
+	require.Nil(t, err)
+
+	// Wait for the other nodes to store their share.
+	deadline := time.Now().Add(10 * time.Second)
+	for _, srv := range services {
+		for srv.(*Service).getSecret(r.ID) == nil {
+			require.True(t, time.Now().Before(deadline), "share was not stored in time")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, srv := range services {
+		reply, err := srv.(*Service).Health(&evoting.Health{ID: master.ID})
+		require.Nil(t, err)
+		assert.Equal(t, []skipchain.SkipBlockID{r.ID}, reply.Decryptable)
+		assert.Empty(t, reply.Undecryptable)
+	}
+
+	// Simulate a restart of the first node.
+	s.secrets = make(map[string]*lib.SharedSecret)
+	reply, err := s.Health(&evoting.Health{ID: master.ID})
+	require.Nil(t, err)
+	assert.Empty(t, reply.Decryptable)
+	assert.Equal(t, []skipchain.SkipBlockID{r.ID}, reply.Undecryptable)
+
+	require.Nil(t, s.load())
+	assert.Equal(t, r.Key, s.getSecret(r.ID).X)
+	reply, err = s.Health(&evoting.Health{ID: master.ID})
+	require.Nil(t, err)
+	assert.Equal(t, []skipchain.SkipBlockID{r.ID}, reply.Decryptable)
+
+	// The stored secret doesn't hold the share in clear.
+	share, _ := s.getSecret(r.ID).V.MarshalBinary()
+	sealed := s.storage.Secrets[r.ID.Short()]
+	assert.NotContains(t, string(sealed.Cipher), string(share))
+
+	// A secret sealed by another node cannot be opened.
+	_, err = services[1].(*Service).open(sealed)
+	assert.NotNil(t, err)
+}
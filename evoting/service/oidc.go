@@ -0,0 +1,274 @@
+package service
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dedis/cothority/evoting"
+)
+
+// keyTTL is how long the discovery document and the keys of an issuer are
+// cached.
+const keyTTL = 10 * time.Minute
+
+// keyRefresh is how long cached keys are used before they are fetched again
+// for a token with an unknown key id, e.g. after the issuer rotated its keys.
+const keyRefresh = time.Minute
+
+// minKeyBits is the minimal size of the RSA keys of an issuer.
+const minKeyBits = 2048
+
+// oidc accepts logins holding an ID token of an OpenID Connect issuer. The
+// token has to be signed with RS256 by one of the keys published at the
+// jwks_uri of the discovery document of the issuer.
+type oidc struct {
+	issuer   string    // issuer as it appears in the iss claim.
+	audience string    // audience is the client identifier of the front-end.
+	claim    string    // claim holding the user identifier.
+	keys     *keyCache // keys caches the keys of the issuer.
+}
+
+// newOIDC creates an authenticator for the given issuer. If claim is empty,
+// the user identifier is taken from the subject of the token.
+func newOIDC(issuer, audience, claim string, keys *keyCache) *oidc {
+	if claim == "" {
+		claim = "sub"
+	}
+	return &oidc{
+		issuer:   issuer,
+		audience: audience,
+		claim:    claim,
+		keys:     keys,
+	}
+}
+
+// Authenticate verifies the ID token of the login and returns the user
+// identifier from its claim.
+func (a *oidc) Authenticate(req *evoting.Login) (uint32, error) {
+	parts := strings.Split(req.IDToken, ".")
+	if len(parts) != 3 {
+		return 0, errors.New("malformed ID token")
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return 0, err
+	}
+	if header.Alg != "RS256" {
+		return 0, errors.New("unsupported signature algorithm: " + header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return 0, errors.New("malformed ID token")
+	}
+	key, err := a.keys.key(a.issuer, header.Kid)
+	if err != nil {
+		return 0, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+		return 0, errInvalidSignature
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return 0, err
+	}
+	if iss, _ := claims["iss"].(string); iss != a.issuer {
+		return 0, errors.New("ID token of another issuer")
+	}
+	if !hasAudience(claims["aud"], a.audience) {
+		return 0, errors.New("ID token for another audience")
+	}
+	now := time.Now().Unix()
+	if exp, err := claimInt(claims["exp"]); err != nil || now >= exp {
+		return 0, errors.New("ID token has expired")
+	}
+	if _, ok := claims["nbf"]; ok {
+		if nbf, err := claimInt(claims["nbf"]); err != nil || now < nbf {
+			return 0, errors.New("ID token is not valid yet")
+		}
+	}
+
+	user, err := claimInt(claims[a.claim])
+	if err != nil || user < 0 || user > 1<<32-1 {
+		return 0, fmt.Errorf("claim %s is not a user identifier", a.claim)
+	}
+	return uint32(user), nil
+}
+
+// jwk is a key published by an issuer.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// issuerKeys are the keys of an issuer and the time they were fetched.
+type issuerKeys struct {
+	keys    []jwk
+	fetched time.Time
+}
+
+// keyCache fetches the keys of issuers and keeps them for keyTTL.
+type keyCache struct {
+	client  *http.Client
+	issuers map[string]*issuerKeys
+	sync.Mutex
+}
+
+// newKeyCache returns an empty cache.
+func newKeyCache() *keyCache {
+	return &keyCache{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		issuers: make(map[string]*issuerKeys),
+	}
+}
+
+// key returns the public key of the issuer with the given key id. If the
+// token has no key id, the first RSA key of the issuer is used. The keys are
+// fetched again if they are older than keyTTL, or older than keyRefresh and
+// the key id is unknown.
+func (c *keyCache) key(issuer, kid string) (*rsa.PublicKey, error) {
+	c.Lock()
+	defer c.Unlock()
+	cached := c.issuers[issuer]
+	if cached == nil || time.Since(cached.fetched) > keyTTL ||
+		(time.Since(cached.fetched) > keyRefresh && findKey(cached.keys, kid) == nil) {
+		keys, err := c.fetch(issuer)
+		if err != nil {
+			return nil, err
+		}
+		cached = &issuerKeys{keys: keys, fetched: time.Now()}
+		c.issuers[issuer] = cached
+	}
+	k := findKey(cached.keys, kid)
+	if k == nil {
+		return nil, errors.New("issuer has no key for the ID token")
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	modulus := new(big.Int).SetBytes(n)
+	if modulus.BitLen() < minKeyBits {
+		return nil, fmt.Errorf("issuer key has less than %d bits", minKeyBits)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent of issuer key")
+	}
+	return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+}
+
+// fetch gets the keys published at the jwks_uri of the discovery document of
+// the issuer.
+func (c *keyCache) fetch(issuer string) ([]jwk, error) {
+	discovery := struct {
+		JWKSURI string `json:"jwks_uri"`
+	}{}
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if err := c.get(url, &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("issuer has no jwks_uri")
+	}
+
+	jwks := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := c.get(discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+	return jwks.Keys, nil
+}
+
+// findKey returns the RSA key with the given key id, or the first RSA key if
+// kid is empty.
+func findKey(keys []jwk, kid string) *jwk {
+	for i, k := range keys {
+		if k.Kty == "RSA" && (kid == "" || k.Kid == kid) {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+// get fetches and decodes a json document from the issuer.
+func (c *keyCache) get(url string, v interface{}) error {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("issuer replied with status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<17)).Decode(v)
+}
+
+// decodeSegment decodes a base64url-encoded json segment of a token. Numbers
+// are kept as json.Number to not lose precision.
+func decodeSegment(segment string, v interface{}) error {
+	buf, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("malformed ID token")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return errors.New("malformed ID token")
+	}
+	return nil
+}
+
+// hasAudience checks if the aud claim, a string or a list of strings,
+// holds the audience.
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimInt converts a numeric claim, or a string holding a number, to an
+// integer.
+func claimInt(claim interface{}) (int64, error) {
+	switch claim := claim.(type) {
+	case json.Number:
+		return claim.Int64()
+	case string:
+		return strconv.ParseInt(claim, 10, 64)
+	}
+	return 0, errors.New("claim is not a number")
+}
@@ -83,6 +83,24 @@ func TestOpen_EndLessThanNow(t *testing.T) {
 	assert.Equal(t, "election cannot end before current time", err.Error())
 }
 
+func TestOpen_InvalidThreshold(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer local.CloseAll()
+
+	nodes, roster, _ := local.GenBigTree(3, 3, 1, true)
+	s := local.GetServices(nodes, serviceID)[0].(*Service)
+	token := s.state.register(0, true)
+
+	master := &lib.Master{Roster: roster}
+	master.GenChain(nil)
+
+	for _, threshold := range []int{-1, 1, 4} {
+		election := &lib.Election{End: time.Now().Unix() + 3600, Threshold: threshold}
+		_, err := s.Open(&evoting.Open{Token: token, ID: master.ID, Election: election})
+		assert.NotNil(t, err)
+	}
+}
+
 func TestOpen_Full(t *testing.T) {
 	local := onet.NewLocalTest(cothority.Suite)
 	defer local.CloseAll()
@@ -104,4 +122,6 @@ func TestOpen_Full(t *testing.T) {
 	assert.Equal(t, r.ID, blob.(*lib.Election).ID)
 
 	assert.Equal(t, r.Key, s.secrets[r.ID.Short()].X)
+	assert.Equal(t, 3, blob.(*lib.Election).Threshold)
+	assert.Equal(t, 3, len(blob.(*lib.Election).Commits))
 }
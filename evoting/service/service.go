@@ -10,10 +10,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/dedis/kyber"
-	"github.com/dedis/kyber/share"
+	"github.com/dedis/kyber/share/vss/rabin"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
 	"github.com/dedis/onet/network"
@@ -45,6 +45,8 @@ var (
 	errAlreadyEnded     = errors.New("election has ended")
 	errCorrupt          = errors.New("election skipchain is corrupt")
 
+	errNotEnoughPartials = errors.New("not enough nodes could decrypt the election")
+
 	errProtocolUnknown = errors.New("protocol unknown")
 	errProtocolTimeout = errors.New("protocol timeout")
 )
@@ -57,8 +59,11 @@ type Service struct {
 	*onet.ServiceProcessor
 
 	secrets map[string]*lib.SharedSecret // secrets is map a of DKG products.
+	storage *storage                     // storage holds the encrypted secrets.
+	mutex   sync.Mutex                   // mutex protects secrets and storage.
 
 	state *state       // state is the log of currently logged in users.
+	keys  *keyCache    // keys caches the keys of OpenID Connect issuers.
 	node  *onet.Roster // nodes is a unitary roster.
 	pin   string       // pin is the current service number.
 }
@@ -94,6 +99,10 @@ func (s *Service) Link(req *evoting.Link) (*evoting.LinkReply, error) {
 		Roster: req.Roster,
 		Admins: req.Admins,
 		Key:    req.Key,
+		Auth:   req.Auth,
+	}
+	if _, err := newAuthenticator(master, s.keys); err != nil {
+		return nil, err
 	}
 	if err := master.Store(master); err != nil {
 		return nil, err
@@ -117,12 +126,25 @@ func (s *Service) Open(req *evoting.Open) (*evoting.OpenReply, error) {
 		return nil, errors.New("election cannot end before current time")
 	}
 
+	if err = req.Election.CheckQuestions(); err != nil {
+		return nil, err
+	}
+
 	genesis, err := lib.New(master.Roster, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	size := len(master.Roster.List)
+	threshold := req.Election.Threshold
+	if threshold == 0 {
+		threshold = size - (size-1)/3
+	}
+	if threshold < vss.MinimumT(size) || threshold > size {
+		return nil, fmt.Errorf("threshold must be between %d and %d",
+			vss.MinimumT(size), size)
+	}
+
 	rooted := master.Roster.NewRosterWithRoot(s.ServerIdentity())
 	if rooted == nil {
 		return nil, errors.New("we're not in the roster")
@@ -133,6 +155,7 @@ func (s *Service) Open(req *evoting.Open) (*evoting.OpenReply, error) {
 	}
 	instance, err := s.CreateProtocol(protocol.NameDKG, tree)
 	protocol := instance.(*protocol.SetupDKG)
+	protocol.Threshold = uint32(threshold)
 
 	config, _ := network.Marshal(&synchronizer{genesis.Hash})
 	protocol.SetConfig(&onet.GenericConfig{Data: config})
@@ -147,7 +170,14 @@ func (s *Service) Open(req *evoting.Open) (*evoting.OpenReply, error) {
 		req.Election.ID = genesis.Hash
 		req.Election.Roster = master.Roster
 		req.Election.Key = secret.X
-		s.secrets[genesis.Short()] = secret
+		req.Election.Threshold = threshold
+		req.Election.Commits = secret.Commits
+		if master.Auth != nil {
+			req.Election.Voters = master.Auth.Voters
+		}
+		if err := s.storeSecret(genesis.Hash, secret); err != nil {
+			return nil, err
+		}
 
 		if err := req.Election.Store(req.Election); err != nil {
 			return nil, err
@@ -163,15 +193,21 @@ func (s *Service) Open(req *evoting.Open) (*evoting.OpenReply, error) {
 	}
 }
 
-// Login message handler. Log potential user in state.
+// Login message handler. Authenticates the user as defined by the master
+// skipchain and logs the user in state.
 func (s *Service) Login(req *evoting.Login) (*evoting.LoginReply, error) {
 	master, err := lib.FetchMaster(s.node, req.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Verify(master.Key) != nil {
-		return nil, errInvalidSignature
+	auth, err := newAuthenticator(master, s.keys)
+	if err != nil {
+		return nil, err
+	}
+	user, err := auth.Authenticate(req)
+	if err != nil {
+		return nil, err
 	}
 
 	links, err := master.Links()
@@ -186,14 +222,14 @@ func (s *Service) Login(req *evoting.Login) (*evoting.LoginReply, error) {
 			return nil, err
 		}
 
-		if (election.IsUser(req.User) && time.Now().Unix() >= election.Start) ||
-			election.IsCreator(req.User) {
+		if (election.IsUser(user) && time.Now().Unix() >= election.Start) ||
+			election.IsCreator(user) {
 			elections = append(elections, election)
 		}
 	}
 
-	admin := master.IsAdmin(req.User)
-	token := s.state.register(req.User, admin)
+	admin := master.IsAdmin(user)
+	token := s.state.register(user, admin)
 	return &evoting.LoginReply{Token: token, Admin: admin, Elections: elections}, nil
 }
 
@@ -274,6 +310,18 @@ func (s *Service) Cast(req *evoting.Cast) (*evoting.CastReply, error) {
 		return nil, errAlreadyEnded
 	}
 
+	if req.Ballot == nil || req.Ballot.Width() != election.Width() {
+		return nil, errors.New("ballot has the wrong number of ciphertexts")
+	}
+
+	if err = req.Ballot.Check(); err != nil {
+		return nil, err
+	}
+
+	if err = election.VerifyBallot(req.Ballot); err != nil {
+		return nil, err
+	}
+
 	if err = election.Store(req.Ballot); err != nil {
 		return nil, err
 	}
@@ -389,13 +437,13 @@ func (s *Service) Decrypt(req *evoting.Decrypt) (*evoting.DecryptReply, error) {
 	if rooted == nil {
 		return nil, errors.New("we're not in the roster")
 	}
-	tree := rooted.GenerateNaryTree(1)
+	tree := rooted.GenerateNaryTree(len(rooted.List))
 	if tree == nil {
 		return nil, errors.New("error while generating tree")
 	}
 	instance, _ := s.CreateProtocol(protocol.NameDecrypt, tree)
 	protocol := instance.(*protocol.Decrypt)
-	protocol.Secret = s.secrets[skipchain.SkipBlockID(election.ID).Short()]
+	protocol.Secret = s.getSecret(election.ID)
 	protocol.Election = election
 
 	config, _ := network.Marshal(&synchronizer{election.ID})
@@ -405,10 +453,17 @@ func (s *Service) Decrypt(req *evoting.Decrypt) (*evoting.DecryptReply, error) {
 		return nil, err
 	}
 
+	// The protocol prompts the other nodes one after the other and skips
+	// those that don't reply within its timeout, so the service waits for
+	// each of them plus a margin to store the last partial.
+	wait := protocol.Timeout*time.Duration(len(rooted.List)-1) + protocol.Timeout
 	select {
-	case <-protocol.Finished:
+	case decrypted := <-protocol.Finished:
+		if !decrypted {
+			return nil, errNotEnoughPartials
+		}
 		return &evoting.DecryptReply{}, nil
-	case <-time.After(timeout):
+	case <-time.After(wait):
 		return nil, errProtocolTimeout
 	}
 }
@@ -424,25 +479,53 @@ func (s *Service) Reconstruct(req *evoting.Reconstruct) (*evoting.ReconstructRep
 		return nil, errNotDecrypted
 	}
 
+	mixes, err := election.Mixes()
+	if err != nil {
+		return nil, err
+	}
 	partials, err := election.Partials()
 	if err != nil {
 		return nil, err
 	}
 
-	points := make([]kyber.Point, 0)
+	points, err := lib.Reconstruct(mixes[len(mixes)-1], partials, election.Commits,
+		election.Quorum())
+	if err != nil {
+		return nil, err
+	}
+	return &evoting.ReconstructReply{Points: points}, nil
+}
 
-	n := len(election.Roster.List)
-	for i := 0; i < len(partials[0].Points); i++ {
-		shares := make([]*share.PubShare, n)
-		for j, partial := range partials {
-			shares[j] = &share.PubShare{I: j, V: partial.Points[i]}
-		}
+// Health message handler. Reports which open elections of the master
+// skipchain this node can still decrypt.
+func (s *Service) Health(req *evoting.Health) (*evoting.HealthReply, error) {
+	master, err := lib.FetchMaster(s.node, req.ID)
+	if err != nil {
+		return nil, err
+	}
 
-		message, _ := share.RecoverCommit(cothority.Suite, shares, n, n)
-		points = append(points, message)
+	links, err := master.Links()
+	if err != nil {
+		return nil, err
 	}
 
-	return &evoting.ReconstructReply{Points: points}, nil
+	reply := &evoting.HealthReply{}
+	for _, link := range links {
+		election, err := lib.FetchElection(s.node, link.ID)
+		if err != nil {
+			return nil, err
+		}
+		if election.Stage >= lib.Decrypted {
+			continue
+		}
+
+		if canDecrypt(s.getSecret(election.ID), election.Key) {
+			reply.Decryptable = append(reply.Decryptable, election.ID)
+		} else {
+			reply.Undecryptable = append(reply.Undecryptable, election.ID)
+		}
+	}
+	return reply, nil
 }
 
 // NewProtocol hooks non-root nodes into created protocols.
@@ -458,8 +541,14 @@ func (s *Service) NewProtocol(node *onet.TreeNodeInstance, conf *onet.GenericCon
 		protocol := instance.(*protocol.SetupDKG)
 		go func() {
 			<-protocol.Done
-			secret, _ := lib.NewSharedSecret(protocol.DKG)
-			s.secrets[id.Short()] = secret
+			secret, err := lib.NewSharedSecret(protocol.DKG)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err := s.storeSecret(id, secret); err != nil {
+				log.Error("couldn't store secret:", err)
+			}
 		}()
 		return protocol, nil
 	case protocol.NameShuffle:
@@ -484,7 +573,7 @@ func (s *Service) NewProtocol(node *onet.TreeNodeInstance, conf *onet.GenericCon
 
 		instance, _ := protocol.NewDecrypt(node)
 		protocol := instance.(*protocol.Decrypt)
-		protocol.Secret = s.secrets[id.Short()]
+		protocol.Secret = s.getSecret(id)
 		protocol.Election = election
 
 		config, _ := network.Marshal(&synchronizer{election.ID})
@@ -537,17 +626,23 @@ func new(context *onet.Context) (onet.Service, error) {
 		ServiceProcessor: onet.NewServiceProcessor(context),
 		secrets:          make(map[string]*lib.SharedSecret),
 		state:            &state{log: make(map[string]*stamp)},
+		keys:             newKeyCache(),
 		pin:              nonce(48),
 	}
 
 	err := service.RegisterHandlers(service.Ping, service.Link, service.Open, service.Login,
 		service.Cast, service.GetBox, service.GetMixes, service.Shuffle,
 		service.GetPartials, service.Decrypt, service.Reconstruct, service.LookupSciper,
+		service.Health,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := service.load(); err != nil {
+		return nil, err
+	}
+
 	service.node = onet.NewRoster([]*network.ServerIdentity{service.ServerIdentity()})
 
 	log.Lvl1("Pin:", service.pin)
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/share"
+	"github.com/dedis/kyber/util/random"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/evoting/lib"
+	"github.com/dedis/cothority/skipchain"
+)
+
+// storageKey identifies the persisted secrets in the onet database.
+var storageKey = []byte("secrets")
+
+// storage holds the DKG products of all elections, encrypted with a key
+// derived from the private key of the conode.
+type storage struct {
+	Secrets map[string]*sealedSecret
+}
+
+// sealedSecret is an AES-GCM encrypted lib.SharedSecret.
+type sealedSecret struct {
+	Nonce  []byte
+	Cipher []byte
+}
+
+func init() {
+	network.RegisterMessages(storage{}, lib.SharedSecret{})
+}
+
+// storeSecret keeps the secret of an election in memory and persists it.
+func (s *Service) storeSecret(id skipchain.SkipBlockID, secret *lib.SharedSecret) error {
+	sealed, err := s.seal(secret)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.secrets[id.Short()] = secret
+	s.storage.Secrets[id.Short()] = sealed
+	return s.Save(storageKey, s.storage)
+}
+
+// getSecret returns the secret of an election or nil if the node doesn't
+// hold one.
+func (s *Service) getSecret(id skipchain.SkipBlockID) *lib.SharedSecret {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.secrets[id.Short()]
+}
+
+// load restores the secrets persisted before a restart of the conode.
+// Secrets that cannot be decrypted are skipped.
+func (s *Service) load() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.storage = &storage{Secrets: make(map[string]*sealedSecret)}
+	msg, err := s.Load(storageKey)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return nil
+	}
+	stored, ok := msg.(*storage)
+	if !ok {
+		return errors.New("data of wrong type")
+	}
+	if stored.Secrets != nil {
+		s.storage = stored
+	}
+	for id, sealed := range s.storage.Secrets {
+		secret, err := s.open(sealed)
+		if err != nil {
+			log.Error("couldn't decrypt secret of election", id, ":", err)
+			continue
+		}
+		s.secrets[id] = secret
+	}
+	log.Lvl2("Loaded", len(s.secrets), "secrets")
+	return nil
+}
+
+// seal encrypts the secret with the storage key of the conode.
+func (s *Service) seal(secret *lib.SharedSecret) (*sealedSecret, error) {
+	buf, err := network.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := s.storageCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := random.Bits(uint(aead.NonceSize()*8), false, random.New())
+	return &sealedSecret{Nonce: nonce, Cipher: aead.Seal(nil, nonce, buf, nil)}, nil
+}
+
+// open decrypts a secret encrypted by seal.
+func (s *Service) open(sealed *sealedSecret) (*lib.SharedSecret, error) {
+	aead, err := s.storageCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed.Nonce) != aead.NonceSize() {
+		return nil, errors.New("wrong nonce size")
+	}
+	buf, err := aead.Open(nil, sealed.Nonce, sealed.Cipher, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, blob, err := network.Unmarshal(buf, cothority.Suite)
+	if err != nil {
+		return nil, err
+	}
+	secret, ok := blob.(*lib.SharedSecret)
+	if !ok {
+		return nil, errors.New("data of wrong type")
+	}
+	return secret, nil
+}
+
+// storageCipher returns the symmetric cipher derived from the private key of
+// the conode.
+func (s *Service) storageCipher() (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write([]byte("evoting-secrets"))
+	if _, err := s.ServerIdentity().GetPrivate().MarshalTo(h); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// canDecrypt checks that the secret is a valid share of the election key.
+func canDecrypt(secret *lib.SharedSecret, key kyber.Point) bool {
+	if secret == nil || key == nil || len(secret.Commits) == 0 || !secret.X.Equal(key) {
+		return false
+	}
+	poly := share.NewPubPoly(cothority.Suite, nil, secret.Commits)
+	if !poly.Commit().Equal(key) {
+		return false
+	}
+	public := cothority.Suite.Point().Mul(secret.V, nil)
+	return poly.Eval(secret.Index).V.Equal(public)
+}
@@ -1,6 +1,7 @@
 package evoting
 
 import (
+	"encoding/binary"
 	"strconv"
 
 	"github.com/dedis/kyber"
@@ -25,6 +26,7 @@ func init() {
 		GetMixes{}, GetMixesReply{},
 		GetPartials{}, GetPartialsReply{},
 		Reconstruct{}, ReconstructReply{},
+		Health{}, HealthReply{},
 		Ping{},
 	)
 }
@@ -33,16 +35,24 @@ func init() {
 type Login struct {
 	ID        skipchain.SkipBlockID // ID of the master skipchain.
 	User      uint32                // User identifier.
-	Signature []byte                // Signature from the front-end.
+	Timestamp int64                 // Timestamp of the login, signed by voters.
+	Signature []byte                // Signature from the front-end or the voter.
+	IDToken   string                // IDToken from an OpenID Connect issuer.
 }
 
-// Digest appends the digits of the user identifier to the skipblock ID.
+// Digest appends the digits of the user identifier to the skipblock ID. If
+// the login has a timestamp, it is appended, too.
 func (l *Login) Digest() []byte {
-	message := l.ID
+	message := append([]byte{}, l.ID...)
 	for _, c := range strconv.Itoa(int(l.User)) {
 		d, _ := strconv.Atoi(string(c))
 		message = append(message, byte(d))
 	}
+	if l.Timestamp != 0 {
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, uint64(l.Timestamp))
+		message = append(message, buf...)
+	}
 	return message
 }
 
@@ -87,6 +97,7 @@ type Link struct {
 	Roster *onet.Roster // Roster that handles elections.
 	Key    kyber.Point  // Key is a front-end public key.
 	Admins []uint32     // Admins is a list of election administrators.
+	Auth   *lib.Auth    // Auth defines how users log in, optional.
 }
 
 // LinkReply message.
@@ -179,6 +190,17 @@ type ReconstructReply struct {
 	Points []kyber.Point // Points are the decrypted plaintexts.
 }
 
+// Health message.
+type Health struct {
+	ID skipchain.SkipBlockID // ID of the master skipchain.
+}
+
+// HealthReply message.
+type HealthReply struct {
+	Decryptable   []skipchain.SkipBlockID // Open elections the node can decrypt.
+	Undecryptable []skipchain.SkipBlockID // Open elections the node lost its share of.
+}
+
 // Ping message.
 type Ping struct {
 	Nonce uint32 // Nonce can be any integer.
@@ -11,6 +11,10 @@ import (
 func TestDigest(t *testing.T) {
 	login := &Login{ID: []byte{0, 1, 2}, User: 3}
 	assert.Equal(t, []byte{0, 1, 2, 3}, login.Digest())
+
+	login.Timestamp = 0x0102
+	assert.Equal(t, []byte{0, 1, 2, 3, 2, 1, 0, 0, 0, 0, 0, 0}, login.Digest())
+	assert.Equal(t, []byte{0, 1, 2}, []byte(login.ID))
 }
 
 func TestSchnorr(t *testing.T) {
@@ -14,6 +14,8 @@ public class IdentityFactory {
             return new IdentityDarc(proto.getDarc());
         } else if (proto.hasX509Ec()) {
             return new IdentityX509EC(proto.getX509Ec());
+        } else if (proto.hasX509Chain()) {
+            return new IdentityX509Chain(proto.getX509Chain());
         } else {
             throw new CothorityCryptoException("No identity present");
         }
@@ -29,6 +31,8 @@ public class IdentityFactory {
             return new IdentityEd25519(signer);
         } else if (SignerX509EC.class.isInstance(signer)) {
             return new IdentityX509EC(signer);
+        } else if (SignerX509Chain.class.isInstance(signer)) {
+            return new IdentityX509Chain(signer);
         } else {
             throw new CothorityCryptoException("Cannot make Identity out of " + signer.toString());
         }
@@ -0,0 +1,163 @@
+package ch.epfl.dedis.lib.darc;
+
+import ch.epfl.dedis.lib.exception.CothorityCryptoException;
+import ch.epfl.dedis.proto.DarcProto;
+import com.google.protobuf.ByteString;
+import com.google.protobuf.InvalidProtocolBufferException;
+
+import java.io.ByteArrayInputStream;
+import java.security.GeneralSecurityException;
+import java.security.PublicKey;
+import java.security.Signature;
+import java.security.cert.CertPath;
+import java.security.cert.CertPathValidator;
+import java.security.cert.CertificateEncodingException;
+import java.security.cert.CertificateException;
+import java.security.cert.CertificateFactory;
+import java.security.cert.PKIXParameters;
+import java.security.cert.TrustAnchor;
+import java.security.cert.X509Certificate;
+import java.security.interfaces.ECPublicKey;
+import java.security.interfaces.RSAPublicKey;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.Date;
+import java.util.List;
+
+/**
+ * IdentityX509Chain represents a trusted root certificate. Every key holding a valid
+ * certificate chaining to this root can sign for this identity.
+ */
+public class IdentityX509Chain implements Identity {
+    private final X509Certificate root;
+
+    /**
+     * Creates an IdentityX509Chain from a protobuf representation.
+     * @param proto
+     */
+    public IdentityX509Chain(DarcProto.IdentityX509Chain proto) throws CothorityCryptoException {
+        root = parseCertificate(proto.getRoot().toByteArray());
+    }
+
+    /**
+     * Creates an IdentityX509Chain from a SignerX509Chain.
+     * @param signer
+     */
+    public IdentityX509Chain(Signer signer) throws CothorityCryptoException {
+        if (SignerX509Chain.class.isInstance(signer)) {
+            root = ((SignerX509Chain) signer).getRoot();
+        } else {
+            throw new CothorityCryptoException("Wrong signer type: " + signer.toString());
+        }
+    }
+
+    /**
+     * Returns true if the certificate chain of the signature is valid now and chains to
+     * the root, the leaf certificate allows digital signatures and the signature of msg by
+     * the key of the leaf certificate is correct, or false if not.
+     * @param msg
+     * @param signature
+     * @return
+     */
+    public boolean verify(byte[] msg, byte[] signature) {
+        return verify(msg, signature, new Date());
+    }
+
+    /**
+     * Works like verify, but the certificates need to be valid at the time when. The
+     * conodes use the time of the transaction holding the signature.
+     * @param msg
+     * @param signature
+     * @param when
+     * @return
+     */
+    public boolean verify(byte[] msg, byte[] signature, Date when) {
+        try {
+            DarcProto.X509ChainSignature sig = DarcProto.X509ChainSignature.parseFrom(signature);
+            if (sig.getCertificatesCount() == 0) {
+                return false;
+            }
+            List<X509Certificate> chain = new ArrayList<>();
+            for (ByteString der : sig.getCertificatesList()) {
+                chain.add(parseCertificate(der.toByteArray()));
+            }
+            X509Certificate leaf = chain.get(0);
+            boolean[] usage = leaf.getKeyUsage();
+            if (usage != null && !usage[0]) {
+                return false;
+            }
+            CertificateFactory cf = CertificateFactory.getInstance("X.509");
+            CertPath path = cf.generateCertPath(chain);
+            PKIXParameters params = new PKIXParameters(Collections.singleton(new TrustAnchor(root, null)));
+            params.setRevocationEnabled(false);
+            params.setDate(when);
+            CertPathValidator.getInstance("PKIX").validate(path, params);
+
+            final Signature signature2 = Signature.getInstance(algorithm(leaf.getPublicKey()));
+            signature2.initVerify(leaf.getPublicKey());
+            signature2.update(msg);
+            return signature2.verify(sig.getSignature().toByteArray());
+        } catch (InvalidProtocolBufferException | GeneralSecurityException | CothorityCryptoException e) {
+            return false;
+        }
+    }
+
+    /**
+     * Creates a protobuf-representation of the implementation. The protobuf
+     * representation has to hold all necessary fields to represent any of the
+     * identity implementations.
+     * @return
+     */
+    public DarcProto.Identity toProto() {
+        DarcProto.Identity.Builder bid = DarcProto.Identity.newBuilder();
+        DarcProto.IdentityX509Chain.Builder bch = DarcProto.IdentityX509Chain.newBuilder();
+        try {
+            bch.setRoot(ByteString.copyFrom(root.getEncoded()));
+        } catch (CertificateEncodingException e) {
+            throw new IllegalStateException("Unable to encode root certificate", e);
+        }
+        bid.setX509Chain(bch);
+        return bid.build();
+    }
+
+    public String toString() {
+        return root.getSubjectX500Principal().getName();
+    }
+
+    @Override
+    public boolean equals(Object other) {
+        if (other == null) return false;
+        if (other == this) return true;
+        if (!(other instanceof IdentityX509Chain)) return false;
+        IdentityX509Chain otherChain = (IdentityX509Chain) other;
+        try {
+            return Arrays.equals(root.getEncoded(), otherChain.root.getEncoded());
+        } catch (CertificateEncodingException e) {
+            return false;
+        }
+    }
+
+    /**
+     * Returns the name of the signature algorithm used with the given key.
+     * @param key
+     * @return
+     */
+    static String algorithm(PublicKey key) throws CothorityCryptoException {
+        if (key instanceof ECPublicKey) {
+            return "SHA384withECDSA";
+        } else if (key instanceof RSAPublicKey) {
+            return "SHA256withRSA";
+        }
+        throw new CothorityCryptoException("Unsupported key type: " + key.getAlgorithm());
+    }
+
+    private static X509Certificate parseCertificate(byte[] der) throws CothorityCryptoException {
+        try {
+            CertificateFactory cf = CertificateFactory.getInstance("X.509");
+            return (X509Certificate) cf.generateCertificate(new ByteArrayInputStream(der));
+        } catch (CertificateException e) {
+            throw new CothorityCryptoException("Unable to parse certificate", e);
+        }
+    }
+}
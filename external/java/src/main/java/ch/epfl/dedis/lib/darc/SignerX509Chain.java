@@ -0,0 +1,101 @@
+package ch.epfl.dedis.lib.darc;
+
+import ch.epfl.dedis.lib.crypto.Point;
+import ch.epfl.dedis.lib.crypto.Scalar;
+import ch.epfl.dedis.lib.exception.CothorityCryptoException;
+import ch.epfl.dedis.proto.DarcProto;
+import com.google.protobuf.ByteString;
+
+import java.io.IOException;
+import java.security.GeneralSecurityException;
+import java.security.PrivateKey;
+import java.security.Signature;
+import java.security.cert.X509Certificate;
+import java.util.List;
+
+/**
+ * SignerX509Chain holds a certificate chain to a trusted root and signs with the key of
+ * the leaf certificate. Implementations only need to give the certificates and the
+ * private key, which may be kept in a keycard.
+ */
+public abstract class SignerX509Chain implements Signer {
+    /**
+     * Signs the message with the key of the leaf certificate and returns a
+     * X509ChainSignature holding the certificate chain.
+     *
+     * @param msg
+     * @return
+     */
+    public byte[] sign(byte[] msg) throws SignRequestRejectedException {
+        try {
+            List<X509Certificate> chain = getCertificateChain();
+            final Signature signature = Signature.getInstance(
+                    IdentityX509Chain.algorithm(chain.get(0).getPublicKey()));
+            signature.initSign(getPrivateKey());
+            signature.update(msg);
+            DarcProto.X509ChainSignature.Builder sig = DarcProto.X509ChainSignature.newBuilder();
+            for (X509Certificate cert : chain) {
+                sig.addCertificates(ByteString.copyFrom(cert.getEncoded()));
+            }
+            sig.setSignature(ByteString.copyFrom(signature.sign()));
+            return sig.build().toByteArray();
+        } catch (GeneralSecurityException | CothorityCryptoException e) {
+            throw new SignRequestRejectedException("Unable to sign with certificate chain", e);
+        }
+    }
+
+    /**
+     * Returns the private key of the signer, or throws a CothorityCryptoException.
+     *
+     * @return
+     */
+    public Scalar getPrivate() throws CothorityCryptoException {
+        throw new CothorityCryptoException("cannot reveal private key");
+    }
+
+    /**
+     * Returns the public key of the signer, or throws a CothorityCryptoException.
+     *
+     * @return
+     */
+    public Point getPublic() throws CothorityCryptoException {
+        throw new CothorityCryptoException("non-ed25519 public keys not yet implemented");
+    }
+
+    /**
+     * Creates an identity of the signer.
+     *
+     * @return an identity
+     * @throws CothorityCryptoException
+     */
+    public Identity getIdentity() throws CothorityCryptoException {
+        return IdentityFactory.New(this);
+    }
+
+    /**
+     * Certificate-chain signers cannot be serialised, as the private key might be in a card.
+     *
+     * @return
+     */
+    public byte[] serialize() throws IOException {
+        throw new IllegalStateException("It is not possible to serialise certificate chain signer");
+    }
+
+    /**
+     * Returns the trusted root certificate of the chain.
+     * @return
+     */
+    public abstract X509Certificate getRoot();
+
+    /**
+     * Returns the leaf certificate, followed by eventual intermediate certificates.
+     * @return
+     */
+    public abstract List<X509Certificate> getCertificateChain();
+
+    /**
+     * Returns the private key of the leaf certificate.
+     * @return
+     */
+    protected abstract PrivateKey getPrivateKey();
+}
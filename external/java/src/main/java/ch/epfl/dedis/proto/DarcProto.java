@@ -2135,6 +2135,31 @@ public final class DarcProto {
      * <code>optional .IdentityX509EC x509ec = 3;</code>
      */
     ch.epfl.dedis.proto.DarcProto.IdentityX509ECOrBuilder getX509EcOrBuilder();
+
+    /**
+     * <pre>
+     * 	 Certificate-chain identity
+     * </pre>
+     *
+     * <code>optional .IdentityX509Chain x509chain = 4;</code>
+     */
+    boolean hasX509Chain();
+    /**
+     * <pre>
+     * 	 Certificate-chain identity
+     * </pre>
+     *
+     * <code>optional .IdentityX509Chain x509chain = 4;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.IdentityX509Chain getX509Chain();
+    /**
+     * <pre>
+     * 	 Certificate-chain identity
+     * </pre>
+     *
+     * <code>optional .IdentityX509Chain x509chain = 4;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder getX509ChainOrBuilder();
   }
   /**
    * <pre>
@@ -2222,6 +2247,19 @@ public final class DarcProto {
               bitField0_ |= 0x00000004;
               break;
             }
+            case 34: {
+              ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder subBuilder = null;
+              if (((bitField0_ & 0x00000008) == 0x00000008)) {
+                subBuilder = x509Chain_.toBuilder();
+              }
+              x509Chain_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.PARSER, extensionRegistry);
+              if (subBuilder != null) {
+                subBuilder.mergeFrom(x509Chain_);
+                x509Chain_ = subBuilder.buildPartial();
+              }
+              bitField0_ |= 0x00000008;
+              break;
+            }
           }
         }
       } catch (com.google.protobuf.InvalidProtocolBufferException e) {
@@ -2346,6 +2384,39 @@ public final class DarcProto {
       return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.IdentityX509EC.getDefaultInstance() : x509Ec_;
     }
 
+    public static final int X509CHAIN_FIELD_NUMBER = 4;
+    private ch.epfl.dedis.proto.DarcProto.IdentityX509Chain x509Chain_;
+    /**
+     * <pre>
+     * 	 Certificate-chain identity
+     * </pre>
+     *
+     * <code>optional .IdentityX509Chain x509chain = 4;</code>
+     */
+    public boolean hasX509Chain() {
+      return ((bitField0_ & 0x00000008) == 0x00000008);
+    }
+    /**
+     * <pre>
+     * 	 Certificate-chain identity
+     * </pre>
+     *
+     * <code>optional .IdentityX509Chain x509chain = 4;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain getX509Chain() {
+      return x509Chain_ == null ? ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance() : x509Chain_;
+    }
+    /**
+     * <pre>
+     * 	 Certificate-chain identity
+     * </pre>
+     *
+     * <code>optional .IdentityX509Chain x509chain = 4;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder getX509ChainOrBuilder() {
+      return x509Chain_ == null ? ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance() : x509Chain_;
+    }
+
     private byte memoizedIsInitialized = -1;
     public final boolean isInitialized() {
       byte isInitialized = memoizedIsInitialized;
@@ -2370,6 +2441,12 @@ public final class DarcProto {
           return false;
         }
       }
+      if (hasX509Chain()) {
+        if (!getX509Chain().isInitialized()) {
+          memoizedIsInitialized = 0;
+          return false;
+        }
+      }
       memoizedIsInitialized = 1;
       return true;
     }
@@ -2385,6 +2462,9 @@ public final class DarcProto {
       if (((bitField0_ & 0x00000004) == 0x00000004)) {
         output.writeMessage(3, getX509Ec());
       }
+      if (((bitField0_ & 0x00000008) == 0x00000008)) {
+        output.writeMessage(4, getX509Chain());
+      }
       unknownFields.writeTo(output);
     }
 
@@ -2405,6 +2485,10 @@ public final class DarcProto {
         size += com.google.protobuf.CodedOutputStream
           .computeMessageSize(3, getX509Ec());
       }
+      if (((bitField0_ & 0x00000008) == 0x00000008)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(4, getX509Chain());
+      }
       size += unknownFields.getSerializedSize();
       memoizedSize = size;
       return size;
@@ -2436,6 +2520,11 @@ public final class DarcProto {
         result = result && getX509Ec()
             .equals(other.getX509Ec());
       }
+      result = result && (hasX509Chain() == other.hasX509Chain());
+      if (hasX509Chain()) {
+        result = result && getX509Chain()
+            .equals(other.getX509Chain());
+      }
       result = result && unknownFields.equals(other.unknownFields);
       return result;
     }
@@ -2459,6 +2548,10 @@ public final class DarcProto {
         hash = (37 * hash) + X509EC_FIELD_NUMBER;
         hash = (53 * hash) + getX509Ec().hashCode();
       }
+      if (hasX509Chain()) {
+        hash = (37 * hash) + X509CHAIN_FIELD_NUMBER;
+        hash = (53 * hash) + getX509Chain().hashCode();
+      }
       hash = (29 * hash) + unknownFields.hashCode();
       memoizedHashCode = hash;
       return hash;
@@ -2591,6 +2684,7 @@ public final class DarcProto {
           getDarcFieldBuilder();
           getEd25519FieldBuilder();
           getX509EcFieldBuilder();
+          getX509ChainFieldBuilder();
         }
       }
       public Builder clear() {
@@ -2613,6 +2707,12 @@ public final class DarcProto {
           x509EcBuilder_.clear();
         }
         bitField0_ = (bitField0_ & ~0x00000004);
+        if (x509ChainBuilder_ == null) {
+          x509Chain_ = null;
+        } else {
+          x509ChainBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000008);
         return this;
       }
 
@@ -2661,6 +2761,14 @@ public final class DarcProto {
         } else {
           result.x509Ec_ = x509EcBuilder_.build();
         }
+        if (((from_bitField0_ & 0x00000008) == 0x00000008)) {
+          to_bitField0_ |= 0x00000008;
+        }
+        if (x509ChainBuilder_ == null) {
+          result.x509Chain_ = x509Chain_;
+        } else {
+          result.x509Chain_ = x509ChainBuilder_.build();
+        }
         result.bitField0_ = to_bitField0_;
         onBuilt();
         return result;
@@ -2712,6 +2820,9 @@ public final class DarcProto {
         if (other.hasX509Ec()) {
           mergeX509Ec(other.getX509Ec());
         }
+        if (other.hasX509Chain()) {
+          mergeX509Chain(other.getX509Chain());
+        }
         this.mergeUnknownFields(other.unknownFields);
         onChanged();
         return this;
@@ -2733,6 +2844,11 @@ public final class DarcProto {
             return false;
           }
         }
+        if (hasX509Chain()) {
+          if (!getX509Chain().isInitialized()) {
+            return false;
+          }
+        }
         return true;
       }
 
@@ -3216,6 +3332,160 @@ public final class DarcProto {
         }
         return x509EcBuilder_;
       }
+
+      private ch.epfl.dedis.proto.DarcProto.IdentityX509Chain x509Chain_ = null;
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.IdentityX509Chain, ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder, ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder> x509ChainBuilder_;
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public boolean hasX509Chain() {
+        return ((bitField0_ & 0x00000008) == 0x00000008);
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain getX509Chain() {
+        if (x509ChainBuilder_ == null) {
+          return x509Chain_ == null ? ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance() : x509Chain_;
+        } else {
+          return x509ChainBuilder_.getMessage();
+        }
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public Builder setX509Chain(ch.epfl.dedis.proto.DarcProto.IdentityX509Chain value) {
+        if (x509ChainBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          x509Chain_ = value;
+          onChanged();
+        } else {
+          x509ChainBuilder_.setMessage(value);
+        }
+        bitField0_ |= 0x00000008;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public Builder setX509Chain(
+          ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder builderForValue) {
+        if (x509ChainBuilder_ == null) {
+          x509Chain_ = builderForValue.build();
+          onChanged();
+        } else {
+          x509ChainBuilder_.setMessage(builderForValue.build());
+        }
+        bitField0_ |= 0x00000008;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public Builder mergeX509Chain(ch.epfl.dedis.proto.DarcProto.IdentityX509Chain value) {
+        if (x509ChainBuilder_ == null) {
+          if (((bitField0_ & 0x00000008) == 0x00000008) &&
+              x509Chain_ != null &&
+              x509Chain_ != ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance()) {
+            x509Chain_ =
+              ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.newBuilder(x509Chain_).mergeFrom(value).buildPartial();
+          } else {
+            x509Chain_ = value;
+          }
+          onChanged();
+        } else {
+          x509ChainBuilder_.mergeFrom(value);
+        }
+        bitField0_ |= 0x00000008;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public Builder clearX509Chain() {
+        if (x509ChainBuilder_ == null) {
+          x509Chain_ = null;
+          onChanged();
+        } else {
+          x509ChainBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000008);
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder getX509ChainBuilder() {
+        bitField0_ |= 0x00000008;
+        onChanged();
+        return getX509ChainFieldBuilder().getBuilder();
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder getX509ChainOrBuilder() {
+        if (x509ChainBuilder_ != null) {
+          return x509ChainBuilder_.getMessageOrBuilder();
+        } else {
+          return x509Chain_ == null ?
+              ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance() : x509Chain_;
+        }
+      }
+      /**
+       * <pre>
+       * 	 Certificate-chain identity
+       * </pre>
+       *
+       * <code>optional .IdentityX509Chain x509chain = 4;</code>
+       */
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.IdentityX509Chain, ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder, ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder> 
+          getX509ChainFieldBuilder() {
+        if (x509ChainBuilder_ == null) {
+          x509ChainBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.IdentityX509Chain, ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder, ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder>(
+                  getX509Chain(),
+                  getParentForChildren(),
+                  isClean());
+          x509Chain_ = null;
+        }
+        return x509ChainBuilder_;
+      }
       public final Builder setUnknownFields(
           final com.google.protobuf.UnknownFieldSet unknownFields) {
         return super.setUnknownFields(unknownFields);
@@ -4255,37 +4525,39 @@ public final class DarcProto {
 
   }
 
-  public interface IdentityDarcOrBuilder extends
-      // @@protoc_insertion_point(interface_extends:IdentityDarc)
+  public interface IdentityX509ChainOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:IdentityX509Chain)
       com.google.protobuf.MessageOrBuilder {
 
     /**
-     * <code>required bytes id = 1;</code>
+     * <code>required bytes root = 1;</code>
      */
-    boolean hasId();
+    boolean hasRoot();
     /**
-     * <code>required bytes id = 1;</code>
+     * <code>required bytes root = 1;</code>
      */
-    com.google.protobuf.ByteString getId();
+    com.google.protobuf.ByteString getRoot();
   }
   /**
    * <pre>
-   * IdentityDarc is a structure that points to a Darc with a given DarcID on a skipchain
+   * IdentityX509Chain holds the DER-encoding of a trusted root certificate.
+   * Every key with a valid certificate chaining to the root can sign for this
+   * identity.
    * </pre>
    *
-   * Protobuf type {@code IdentityDarc}
+   * Protobuf type {@code IdentityX509Chain}
    */
-  public  static final class IdentityDarc extends
+  public  static final class IdentityX509Chain extends
       com.google.protobuf.GeneratedMessageV3 implements
-      // @@protoc_insertion_point(message_implements:IdentityDarc)
-      IdentityDarcOrBuilder {
+      // @@protoc_insertion_point(message_implements:IdentityX509Chain)
+      IdentityX509ChainOrBuilder {
   private static final long serialVersionUID = 0L;
-    // Use IdentityDarc.newBuilder() to construct.
-    private IdentityDarc(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+    // Use IdentityX509Chain.newBuilder() to construct.
+    private IdentityX509Chain(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
       super(builder);
     }
-    private IdentityDarc() {
-      id_ = com.google.protobuf.ByteString.EMPTY;
+    private IdentityX509Chain() {
+      root_ = com.google.protobuf.ByteString.EMPTY;
     }
 
     @java.lang.Override
@@ -4293,7 +4565,7 @@ public final class DarcProto {
     getUnknownFields() {
       return this.unknownFields;
     }
-    private IdentityDarc(
+    private IdentityX509Chain(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
@@ -4318,7 +4590,7 @@ public final class DarcProto {
             }
             case 10: {
               bitField0_ |= 0x00000001;
-              id_ = input.readBytes();
+              root_ = input.readBytes();
               break;
             }
           }
@@ -4335,30 +4607,30 @@ public final class DarcProto {
     }
     public static final com.google.protobuf.Descriptors.Descriptor
         getDescriptor() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_descriptor;
+      return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityX509Chain_descriptor;
     }
 
     protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
         internalGetFieldAccessorTable() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_fieldAccessorTable
+      return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityX509Chain_fieldAccessorTable
           .ensureFieldAccessorsInitialized(
-              ch.epfl.dedis.proto.DarcProto.IdentityDarc.class, ch.epfl.dedis.proto.DarcProto.IdentityDarc.Builder.class);
+              ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.class, ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder.class);
     }
 
     private int bitField0_;
-    public static final int ID_FIELD_NUMBER = 1;
-    private com.google.protobuf.ByteString id_;
+    public static final int ROOT_FIELD_NUMBER = 1;
+    private com.google.protobuf.ByteString root_;
     /**
-     * <code>required bytes id = 1;</code>
+     * <code>required bytes root = 1;</code>
      */
-    public boolean hasId() {
+    public boolean hasRoot() {
       return ((bitField0_ & 0x00000001) == 0x00000001);
     }
     /**
-     * <code>required bytes id = 1;</code>
+     * <code>required bytes root = 1;</code>
      */
-    public com.google.protobuf.ByteString getId() {
-      return id_;
+    public com.google.protobuf.ByteString getRoot() {
+      return root_;
     }
 
     private byte memoizedIsInitialized = -1;
@@ -4367,7 +4639,7 @@ public final class DarcProto {
       if (isInitialized == 1) return true;
       if (isInitialized == 0) return false;
 
-      if (!hasId()) {
+      if (!hasRoot()) {
         memoizedIsInitialized = 0;
         return false;
       }
@@ -4378,7 +4650,7 @@ public final class DarcProto {
     public void writeTo(com.google.protobuf.CodedOutputStream output)
                         throws java.io.IOException {
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
-        output.writeBytes(1, id_);
+        output.writeBytes(1, root_);
       }
       unknownFields.writeTo(output);
     }
@@ -4390,7 +4662,7 @@ public final class DarcProto {
       size = 0;
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
         size += com.google.protobuf.CodedOutputStream
-          .computeBytesSize(1, id_);
+          .computeBytesSize(1, root_);
       }
       size += unknownFields.getSerializedSize();
       memoizedSize = size;
@@ -4402,16 +4674,16 @@ public final class DarcProto {
       if (obj == this) {
        return true;
       }
-      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.IdentityDarc)) {
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.IdentityX509Chain)) {
         return super.equals(obj);
       }
-      ch.epfl.dedis.proto.DarcProto.IdentityDarc other = (ch.epfl.dedis.proto.DarcProto.IdentityDarc) obj;
+      ch.epfl.dedis.proto.DarcProto.IdentityX509Chain other = (ch.epfl.dedis.proto.DarcProto.IdentityX509Chain) obj;
 
       boolean result = true;
-      result = result && (hasId() == other.hasId());
-      if (hasId()) {
-        result = result && getId()
-            .equals(other.getId());
+      result = result && (hasRoot() == other.hasRoot());
+      if (hasRoot()) {
+        result = result && getRoot()
+            .equals(other.getRoot());
       }
       result = result && unknownFields.equals(other.unknownFields);
       return result;
@@ -4424,78 +4696,78 @@ public final class DarcProto {
       }
       int hash = 41;
       hash = (19 * hash) + getDescriptor().hashCode();
-      if (hasId()) {
-        hash = (37 * hash) + ID_FIELD_NUMBER;
-        hash = (53 * hash) + getId().hashCode();
+      if (hasRoot()) {
+        hash = (37 * hash) + ROOT_FIELD_NUMBER;
+        hash = (53 * hash) + getRoot().hashCode();
       }
       hash = (29 * hash) + unknownFields.hashCode();
       memoizedHashCode = hash;
       return hash;
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         java.nio.ByteBuffer data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         java.nio.ByteBuffer data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         com.google.protobuf.ByteString data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         com.google.protobuf.ByteString data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(byte[] data)
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(byte[] data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         byte[] data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseDelimitedFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseDelimitedFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseDelimitedFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseDelimitedFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         com.google.protobuf.CodedInputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parseFrom(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
@@ -4507,7 +4779,7 @@ public final class DarcProto {
     public static Builder newBuilder() {
       return DEFAULT_INSTANCE.toBuilder();
     }
-    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.IdentityDarc prototype) {
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.IdentityX509Chain prototype) {
       return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
     }
     public Builder toBuilder() {
@@ -4523,28 +4795,30 @@ public final class DarcProto {
     }
     /**
      * <pre>
-     * IdentityDarc is a structure that points to a Darc with a given DarcID on a skipchain
+     * IdentityX509Chain holds the DER-encoding of a trusted root certificate.
+     * Every key with a valid certificate chaining to the root can sign for this
+     * identity.
      * </pre>
      *
-     * Protobuf type {@code IdentityDarc}
+     * Protobuf type {@code IdentityX509Chain}
      */
     public static final class Builder extends
         com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
-        // @@protoc_insertion_point(builder_implements:IdentityDarc)
-        ch.epfl.dedis.proto.DarcProto.IdentityDarcOrBuilder {
+        // @@protoc_insertion_point(builder_implements:IdentityX509Chain)
+        ch.epfl.dedis.proto.DarcProto.IdentityX509ChainOrBuilder {
       public static final com.google.protobuf.Descriptors.Descriptor
           getDescriptor() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityX509Chain_descriptor;
       }
 
       protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
           internalGetFieldAccessorTable() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_fieldAccessorTable
+        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityX509Chain_fieldAccessorTable
             .ensureFieldAccessorsInitialized(
-                ch.epfl.dedis.proto.DarcProto.IdentityDarc.class, ch.epfl.dedis.proto.DarcProto.IdentityDarc.Builder.class);
+                ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.class, ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.Builder.class);
       }
 
-      // Construct using ch.epfl.dedis.proto.DarcProto.IdentityDarc.newBuilder()
+      // Construct using ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.newBuilder()
       private Builder() {
         maybeForceBuilderInitialization();
       }
@@ -4561,36 +4835,36 @@ public final class DarcProto {
       }
       public Builder clear() {
         super.clear();
-        id_ = com.google.protobuf.ByteString.EMPTY;
+        root_ = com.google.protobuf.ByteString.EMPTY;
         bitField0_ = (bitField0_ & ~0x00000001);
         return this;
       }
 
       public com.google.protobuf.Descriptors.Descriptor
           getDescriptorForType() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityX509Chain_descriptor;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.IdentityDarc getDefaultInstanceForType() {
-        return ch.epfl.dedis.proto.DarcProto.IdentityDarc.getDefaultInstance();
+      public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance();
       }
 
-      public ch.epfl.dedis.proto.DarcProto.IdentityDarc build() {
-        ch.epfl.dedis.proto.DarcProto.IdentityDarc result = buildPartial();
+      public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain build() {
+        ch.epfl.dedis.proto.DarcProto.IdentityX509Chain result = buildPartial();
         if (!result.isInitialized()) {
           throw newUninitializedMessageException(result);
         }
         return result;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.IdentityDarc buildPartial() {
-        ch.epfl.dedis.proto.DarcProto.IdentityDarc result = new ch.epfl.dedis.proto.DarcProto.IdentityDarc(this);
+      public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.IdentityX509Chain result = new ch.epfl.dedis.proto.DarcProto.IdentityX509Chain(this);
         int from_bitField0_ = bitField0_;
         int to_bitField0_ = 0;
         if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
           to_bitField0_ |= 0x00000001;
         }
-        result.id_ = id_;
+        result.root_ = root_;
         result.bitField0_ = to_bitField0_;
         onBuilt();
         return result;
@@ -4623,18 +4897,18 @@ public final class DarcProto {
         return (Builder) super.addRepeatedField(field, value);
       }
       public Builder mergeFrom(com.google.protobuf.Message other) {
-        if (other instanceof ch.epfl.dedis.proto.DarcProto.IdentityDarc) {
-          return mergeFrom((ch.epfl.dedis.proto.DarcProto.IdentityDarc)other);
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.IdentityX509Chain) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.IdentityX509Chain)other);
         } else {
           super.mergeFrom(other);
           return this;
         }
       }
 
-      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.IdentityDarc other) {
-        if (other == ch.epfl.dedis.proto.DarcProto.IdentityDarc.getDefaultInstance()) return this;
-        if (other.hasId()) {
-          setId(other.getId());
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.IdentityX509Chain other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.IdentityX509Chain.getDefaultInstance()) return this;
+        if (other.hasRoot()) {
+          setRoot(other.getRoot());
         }
         this.mergeUnknownFields(other.unknownFields);
         onChanged();
@@ -4642,7 +4916,7 @@ public final class DarcProto {
       }
 
       public final boolean isInitialized() {
-        if (!hasId()) {
+        if (!hasRoot()) {
           return false;
         }
         return true;
@@ -4652,11 +4926,11 @@ public final class DarcProto {
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws java.io.IOException {
-        ch.epfl.dedis.proto.DarcProto.IdentityDarc parsedMessage = null;
+        ch.epfl.dedis.proto.DarcProto.IdentityX509Chain parsedMessage = null;
         try {
           parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
         } catch (com.google.protobuf.InvalidProtocolBufferException e) {
-          parsedMessage = (ch.epfl.dedis.proto.DarcProto.IdentityDarc) e.getUnfinishedMessage();
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.IdentityX509Chain) e.getUnfinishedMessage();
           throw e.unwrapIOException();
         } finally {
           if (parsedMessage != null) {
@@ -4667,37 +4941,37 @@ public final class DarcProto {
       }
       private int bitField0_;
 
-      private com.google.protobuf.ByteString id_ = com.google.protobuf.ByteString.EMPTY;
+      private com.google.protobuf.ByteString root_ = com.google.protobuf.ByteString.EMPTY;
       /**
-       * <code>required bytes id = 1;</code>
+       * <code>required bytes root = 1;</code>
        */
-      public boolean hasId() {
+      public boolean hasRoot() {
         return ((bitField0_ & 0x00000001) == 0x00000001);
       }
       /**
-       * <code>required bytes id = 1;</code>
+       * <code>required bytes root = 1;</code>
        */
-      public com.google.protobuf.ByteString getId() {
-        return id_;
+      public com.google.protobuf.ByteString getRoot() {
+        return root_;
       }
       /**
-       * <code>required bytes id = 1;</code>
+       * <code>required bytes root = 1;</code>
        */
-      public Builder setId(com.google.protobuf.ByteString value) {
+      public Builder setRoot(com.google.protobuf.ByteString value) {
         if (value == null) {
     throw new NullPointerException();
   }
   bitField0_ |= 0x00000001;
-        id_ = value;
+        root_ = value;
         onChanged();
         return this;
       }
       /**
-       * <code>required bytes id = 1;</code>
+       * <code>required bytes root = 1;</code>
        */
-      public Builder clearId() {
+      public Builder clearRoot() {
         bitField0_ = (bitField0_ & ~0x00000001);
-        id_ = getDefaultInstance().getId();
+        root_ = getDefaultInstance().getRoot();
         onChanged();
         return this;
       }
@@ -4712,108 +4986,90 @@ public final class DarcProto {
       }
 
 
-      // @@protoc_insertion_point(builder_scope:IdentityDarc)
+      // @@protoc_insertion_point(builder_scope:IdentityX509Chain)
     }
 
-    // @@protoc_insertion_point(class_scope:IdentityDarc)
-    private static final ch.epfl.dedis.proto.DarcProto.IdentityDarc DEFAULT_INSTANCE;
+    // @@protoc_insertion_point(class_scope:IdentityX509Chain)
+    private static final ch.epfl.dedis.proto.DarcProto.IdentityX509Chain DEFAULT_INSTANCE;
     static {
-      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.IdentityDarc();
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.IdentityX509Chain();
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc getDefaultInstance() {
+    public static ch.epfl.dedis.proto.DarcProto.IdentityX509Chain getDefaultInstance() {
       return DEFAULT_INSTANCE;
     }
 
-    @java.lang.Deprecated public static final com.google.protobuf.Parser<IdentityDarc>
-        PARSER = new com.google.protobuf.AbstractParser<IdentityDarc>() {
-      public IdentityDarc parsePartialFrom(
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<IdentityX509Chain>
+        PARSER = new com.google.protobuf.AbstractParser<IdentityX509Chain>() {
+      public IdentityX509Chain parsePartialFrom(
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws com.google.protobuf.InvalidProtocolBufferException {
-          return new IdentityDarc(input, extensionRegistry);
+          return new IdentityX509Chain(input, extensionRegistry);
       }
     };
 
-    public static com.google.protobuf.Parser<IdentityDarc> parser() {
+    public static com.google.protobuf.Parser<IdentityX509Chain> parser() {
       return PARSER;
     }
 
     @java.lang.Override
-    public com.google.protobuf.Parser<IdentityDarc> getParserForType() {
+    public com.google.protobuf.Parser<IdentityX509Chain> getParserForType() {
       return PARSER;
     }
 
-    public ch.epfl.dedis.proto.DarcProto.IdentityDarc getDefaultInstanceForType() {
+    public ch.epfl.dedis.proto.DarcProto.IdentityX509Chain getDefaultInstanceForType() {
       return DEFAULT_INSTANCE;
     }
 
   }
 
-  public interface SignatureOrBuilder extends
-      // @@protoc_insertion_point(interface_extends:Signature)
+  public interface X509ChainSignatureOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:X509ChainSignature)
       com.google.protobuf.MessageOrBuilder {
 
     /**
-     * <pre>
-     * 	 The signature itself
-     * </pre>
-     *
-     * <code>required bytes signature = 1;</code>
+     * <code>repeated bytes certificates = 1;</code>
      */
-    boolean hasSignature();
+    java.util.List<com.google.protobuf.ByteString> getCertificatesList();
     /**
-     * <pre>
-     * 	 The signature itself
-     * </pre>
-     *
-     * <code>required bytes signature = 1;</code>
+     * <code>repeated bytes certificates = 1;</code>
      */
-    com.google.protobuf.ByteString getSignature();
-
+    int getCertificatesCount();
     /**
-     * <pre>
-     * 	 Represents the path to get up to information to be able to verify this signature
-     * </pre>
-     *
-     * <code>required .SignaturePath signaturepath = 2;</code>
+     * <code>repeated bytes certificates = 1;</code>
      */
-    boolean hasSignaturepath();
+    com.google.protobuf.ByteString getCertificates(int index);
+
     /**
-     * <pre>
-     * 	 Represents the path to get up to information to be able to verify this signature
-     * </pre>
-     *
-     * <code>required .SignaturePath signaturepath = 2;</code>
+     * <code>required bytes signature = 2;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.SignaturePath getSignaturepath();
+    boolean hasSignature();
     /**
-     * <pre>
-     * 	 Represents the path to get up to information to be able to verify this signature
-     * </pre>
-     *
-     * <code>required .SignaturePath signaturepath = 2;</code>
+     * <code>required bytes signature = 2;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder getSignaturepathOrBuilder();
+    com.google.protobuf.ByteString getSignature();
   }
   /**
    * <pre>
-   * Signature is a signature on a Darc to accept a given decision.
-   * can be verified using the appropriate identity.
+   * X509ChainSignature is the signature of an IdentityX509Chain. It holds the
+   * leaf certificate, followed by eventual intermediate certificates, and the
+   * signature of the message by the key of the leaf certificate.
    * </pre>
    *
-   * Protobuf type {@code Signature}
+   * Protobuf type {@code X509ChainSignature}
    */
-  public  static final class Signature extends
+  public  static final class X509ChainSignature extends
       com.google.protobuf.GeneratedMessageV3 implements
-      // @@protoc_insertion_point(message_implements:Signature)
-      SignatureOrBuilder {
+      // @@protoc_insertion_point(message_implements:X509ChainSignature)
+      X509ChainSignatureOrBuilder {
   private static final long serialVersionUID = 0L;
-    // Use Signature.newBuilder() to construct.
-    private Signature(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+    // Use X509ChainSignature.newBuilder() to construct.
+    private X509ChainSignature(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
       super(builder);
     }
-    private Signature() {
+    private X509ChainSignature() {
+      certificates_ = java.util.Collections.emptyList();
       signature_ = com.google.protobuf.ByteString.EMPTY;
     }
 
@@ -4822,7 +5078,7 @@ public final class DarcProto {
     getUnknownFields() {
       return this.unknownFields;
     }
-    private Signature(
+    private X509ChainSignature(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
@@ -4846,21 +5102,16 @@ public final class DarcProto {
               break;
             }
             case 10: {
-              bitField0_ |= 0x00000001;
-              signature_ = input.readBytes();
+              if (!((mutable_bitField0_ & 0x00000001) == 0x00000001)) {
+                certificates_ = new java.util.ArrayList<com.google.protobuf.ByteString>();
+                mutable_bitField0_ |= 0x00000001;
+              }
+              certificates_.add(input.readBytes());
               break;
             }
             case 18: {
-              ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder subBuilder = null;
-              if (((bitField0_ & 0x00000002) == 0x00000002)) {
-                subBuilder = signaturepath_.toBuilder();
-              }
-              signaturepath_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignaturePath.PARSER, extensionRegistry);
-              if (subBuilder != null) {
-                subBuilder.mergeFrom(signaturepath_);
-                signaturepath_ = subBuilder.buildPartial();
-              }
-              bitField0_ |= 0x00000002;
+              bitField0_ |= 0x00000001;
+              signature_ = input.readBytes();
               break;
             }
           }
@@ -4871,77 +5122,61 @@ public final class DarcProto {
         throw new com.google.protobuf.InvalidProtocolBufferException(
             e).setUnfinishedMessage(this);
       } finally {
+        if (((mutable_bitField0_ & 0x00000001) == 0x00000001)) {
+          certificates_ = java.util.Collections.unmodifiableList(certificates_);
+        }
         this.unknownFields = unknownFields.build();
         makeExtensionsImmutable();
       }
     }
     public static final com.google.protobuf.Descriptors.Descriptor
         getDescriptor() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_descriptor;
+      return ch.epfl.dedis.proto.DarcProto.internal_static_X509ChainSignature_descriptor;
     }
 
     protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
         internalGetFieldAccessorTable() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_fieldAccessorTable
+      return ch.epfl.dedis.proto.DarcProto.internal_static_X509ChainSignature_fieldAccessorTable
           .ensureFieldAccessorsInitialized(
-              ch.epfl.dedis.proto.DarcProto.Signature.class, ch.epfl.dedis.proto.DarcProto.Signature.Builder.class);
+              ch.epfl.dedis.proto.DarcProto.X509ChainSignature.class, ch.epfl.dedis.proto.DarcProto.X509ChainSignature.Builder.class);
     }
 
     private int bitField0_;
-    public static final int SIGNATURE_FIELD_NUMBER = 1;
-    private com.google.protobuf.ByteString signature_;
+    public static final int CERTIFICATES_FIELD_NUMBER = 1;
+    private java.util.List<com.google.protobuf.ByteString> certificates_;
     /**
-     * <pre>
-     * 	 The signature itself
-     * </pre>
-     *
-     * <code>required bytes signature = 1;</code>
+     * <code>repeated bytes certificates = 1;</code>
      */
-    public boolean hasSignature() {
-      return ((bitField0_ & 0x00000001) == 0x00000001);
+    public java.util.List<com.google.protobuf.ByteString>
+        getCertificatesList() {
+      return certificates_;
     }
     /**
-     * <pre>
-     * 	 The signature itself
-     * </pre>
-     *
-     * <code>required bytes signature = 1;</code>
+     * <code>repeated bytes certificates = 1;</code>
      */
-    public com.google.protobuf.ByteString getSignature() {
-      return signature_;
+    public int getCertificatesCount() {
+      return certificates_.size();
     }
-
-    public static final int SIGNATUREPATH_FIELD_NUMBER = 2;
-    private ch.epfl.dedis.proto.DarcProto.SignaturePath signaturepath_;
     /**
-     * <pre>
-     * 	 Represents the path to get up to information to be able to verify this signature
-     * </pre>
-     *
-     * <code>required .SignaturePath signaturepath = 2;</code>
+     * <code>repeated bytes certificates = 1;</code>
      */
-    public boolean hasSignaturepath() {
-      return ((bitField0_ & 0x00000002) == 0x00000002);
+    public com.google.protobuf.ByteString getCertificates(int index) {
+      return certificates_.get(index);
     }
+
+    public static final int SIGNATURE_FIELD_NUMBER = 2;
+    private com.google.protobuf.ByteString signature_;
     /**
-     * <pre>
-     * 	 Represents the path to get up to information to be able to verify this signature
-     * </pre>
-     *
-     * <code>required .SignaturePath signaturepath = 2;</code>
+     * <code>required bytes signature = 2;</code>
      */
-    public ch.epfl.dedis.proto.DarcProto.SignaturePath getSignaturepath() {
-      return signaturepath_ == null ? ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
+    public boolean hasSignature() {
+      return ((bitField0_ & 0x00000001) == 0x00000001);
     }
     /**
-     * <pre>
-     * 	 Represents the path to get up to information to be able to verify this signature
-     * </pre>
-     *
-     * <code>required .SignaturePath signaturepath = 2;</code>
+     * <code>required bytes signature = 2;</code>
      */
-    public ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder getSignaturepathOrBuilder() {
-      return signaturepath_ == null ? ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
+    public com.google.protobuf.ByteString getSignature() {
+      return signature_;
     }
 
     private byte memoizedIsInitialized = -1;
@@ -4954,25 +5189,17 @@ public final class DarcProto {
         memoizedIsInitialized = 0;
         return false;
       }
-      if (!hasSignaturepath()) {
-        memoizedIsInitialized = 0;
-        return false;
-      }
-      if (!getSignaturepath().isInitialized()) {
-        memoizedIsInitialized = 0;
-        return false;
-      }
       memoizedIsInitialized = 1;
       return true;
     }
 
     public void writeTo(com.google.protobuf.CodedOutputStream output)
                         throws java.io.IOException {
-      if (((bitField0_ & 0x00000001) == 0x00000001)) {
-        output.writeBytes(1, signature_);
+      for (int i = 0; i < certificates_.size(); i++) {
+        output.writeBytes(1, certificates_.get(i));
       }
-      if (((bitField0_ & 0x00000002) == 0x00000002)) {
-        output.writeMessage(2, getSignaturepath());
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        output.writeBytes(2, signature_);
       }
       unknownFields.writeTo(output);
     }
@@ -4982,13 +5209,18 @@ public final class DarcProto {
       if (size != -1) return size;
 
       size = 0;
-      if (((bitField0_ & 0x00000001) == 0x00000001)) {
-        size += com.google.protobuf.CodedOutputStream
-          .computeBytesSize(1, signature_);
+      {
+        int dataSize = 0;
+        for (int i = 0; i < certificates_.size(); i++) {
+          dataSize += com.google.protobuf.CodedOutputStream
+            .computeBytesSizeNoTag(certificates_.get(i));
+        }
+        size += dataSize;
+        size += 1 * getCertificatesList().size();
       }
-      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
         size += com.google.protobuf.CodedOutputStream
-          .computeMessageSize(2, getSignaturepath());
+          .computeBytesSize(2, signature_);
       }
       size += unknownFields.getSerializedSize();
       memoizedSize = size;
@@ -5000,22 +5232,19 @@ public final class DarcProto {
       if (obj == this) {
        return true;
       }
-      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.Signature)) {
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.X509ChainSignature)) {
         return super.equals(obj);
       }
-      ch.epfl.dedis.proto.DarcProto.Signature other = (ch.epfl.dedis.proto.DarcProto.Signature) obj;
+      ch.epfl.dedis.proto.DarcProto.X509ChainSignature other = (ch.epfl.dedis.proto.DarcProto.X509ChainSignature) obj;
 
       boolean result = true;
+      result = result && getCertificatesList()
+          .equals(other.getCertificatesList());
       result = result && (hasSignature() == other.hasSignature());
       if (hasSignature()) {
         result = result && getSignature()
             .equals(other.getSignature());
       }
-      result = result && (hasSignaturepath() == other.hasSignaturepath());
-      if (hasSignaturepath()) {
-        result = result && getSignaturepath()
-            .equals(other.getSignaturepath());
-      }
       result = result && unknownFields.equals(other.unknownFields);
       return result;
     }
@@ -5027,82 +5256,82 @@ public final class DarcProto {
       }
       int hash = 41;
       hash = (19 * hash) + getDescriptor().hashCode();
+      if (getCertificatesCount() > 0) {
+        hash = (37 * hash) + CERTIFICATES_FIELD_NUMBER;
+        hash = (53 * hash) + getCertificatesList().hashCode();
+      }
       if (hasSignature()) {
         hash = (37 * hash) + SIGNATURE_FIELD_NUMBER;
         hash = (53 * hash) + getSignature().hashCode();
       }
-      if (hasSignaturepath()) {
-        hash = (37 * hash) + SIGNATUREPATH_FIELD_NUMBER;
-        hash = (53 * hash) + getSignaturepath().hashCode();
-      }
       hash = (29 * hash) + unknownFields.hashCode();
       memoizedHashCode = hash;
       return hash;
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         java.nio.ByteBuffer data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         java.nio.ByteBuffer data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         com.google.protobuf.ByteString data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         com.google.protobuf.ByteString data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(byte[] data)
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(byte[] data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         byte[] data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseDelimitedFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseDelimitedFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseDelimitedFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseDelimitedFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         com.google.protobuf.CodedInputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature parseFrom(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
@@ -5114,7 +5343,7 @@ public final class DarcProto {
     public static Builder newBuilder() {
       return DEFAULT_INSTANCE.toBuilder();
     }
-    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.Signature prototype) {
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.X509ChainSignature prototype) {
       return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
     }
     public Builder toBuilder() {
@@ -5130,29 +5359,30 @@ public final class DarcProto {
     }
     /**
      * <pre>
-     * Signature is a signature on a Darc to accept a given decision.
-     * can be verified using the appropriate identity.
+     * X509ChainSignature is the signature of an IdentityX509Chain. It holds the
+     * leaf certificate, followed by eventual intermediate certificates, and the
+     * signature of the message by the key of the leaf certificate.
      * </pre>
      *
-     * Protobuf type {@code Signature}
+     * Protobuf type {@code X509ChainSignature}
      */
     public static final class Builder extends
         com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
-        // @@protoc_insertion_point(builder_implements:Signature)
-        ch.epfl.dedis.proto.DarcProto.SignatureOrBuilder {
+        // @@protoc_insertion_point(builder_implements:X509ChainSignature)
+        ch.epfl.dedis.proto.DarcProto.X509ChainSignatureOrBuilder {
       public static final com.google.protobuf.Descriptors.Descriptor
           getDescriptor() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_X509ChainSignature_descriptor;
       }
 
       protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
           internalGetFieldAccessorTable() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_fieldAccessorTable
+        return ch.epfl.dedis.proto.DarcProto.internal_static_X509ChainSignature_fieldAccessorTable
             .ensureFieldAccessorsInitialized(
-                ch.epfl.dedis.proto.DarcProto.Signature.class, ch.epfl.dedis.proto.DarcProto.Signature.Builder.class);
+                ch.epfl.dedis.proto.DarcProto.X509ChainSignature.class, ch.epfl.dedis.proto.DarcProto.X509ChainSignature.Builder.class);
       }
 
-      // Construct using ch.epfl.dedis.proto.DarcProto.Signature.newBuilder()
+      // Construct using ch.epfl.dedis.proto.DarcProto.X509ChainSignature.newBuilder()
       private Builder() {
         maybeForceBuilderInitialization();
       }
@@ -5165,55 +5395,47 @@ public final class DarcProto {
       private void maybeForceBuilderInitialization() {
         if (com.google.protobuf.GeneratedMessageV3
                 .alwaysUseFieldBuilders) {
-          getSignaturepathFieldBuilder();
         }
       }
       public Builder clear() {
         super.clear();
-        signature_ = com.google.protobuf.ByteString.EMPTY;
+        certificates_ = java.util.Collections.emptyList();
         bitField0_ = (bitField0_ & ~0x00000001);
-        if (signaturepathBuilder_ == null) {
-          signaturepath_ = null;
-        } else {
-          signaturepathBuilder_.clear();
-        }
+        signature_ = com.google.protobuf.ByteString.EMPTY;
         bitField0_ = (bitField0_ & ~0x00000002);
         return this;
       }
 
       public com.google.protobuf.Descriptors.Descriptor
           getDescriptorForType() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_X509ChainSignature_descriptor;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.Signature getDefaultInstanceForType() {
-        return ch.epfl.dedis.proto.DarcProto.Signature.getDefaultInstance();
+      public ch.epfl.dedis.proto.DarcProto.X509ChainSignature getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.X509ChainSignature.getDefaultInstance();
       }
 
-      public ch.epfl.dedis.proto.DarcProto.Signature build() {
-        ch.epfl.dedis.proto.DarcProto.Signature result = buildPartial();
+      public ch.epfl.dedis.proto.DarcProto.X509ChainSignature build() {
+        ch.epfl.dedis.proto.DarcProto.X509ChainSignature result = buildPartial();
         if (!result.isInitialized()) {
           throw newUninitializedMessageException(result);
         }
         return result;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.Signature buildPartial() {
-        ch.epfl.dedis.proto.DarcProto.Signature result = new ch.epfl.dedis.proto.DarcProto.Signature(this);
+      public ch.epfl.dedis.proto.DarcProto.X509ChainSignature buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.X509ChainSignature result = new ch.epfl.dedis.proto.DarcProto.X509ChainSignature(this);
         int from_bitField0_ = bitField0_;
         int to_bitField0_ = 0;
-        if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
-          to_bitField0_ |= 0x00000001;
+        if (((bitField0_ & 0x00000001) == 0x00000001)) {
+          certificates_ = java.util.Collections.unmodifiableList(certificates_);
+          bitField0_ = (bitField0_ & ~0x00000001);
         }
-        result.signature_ = signature_;
+        result.certificates_ = certificates_;
         if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
-          to_bitField0_ |= 0x00000002;
-        }
-        if (signaturepathBuilder_ == null) {
-          result.signaturepath_ = signaturepath_;
-        } else {
-          result.signaturepath_ = signaturepathBuilder_.build();
+          to_bitField0_ |= 0x00000001;
         }
+        result.signature_ = signature_;
         result.bitField0_ = to_bitField0_;
         onBuilt();
         return result;
@@ -5246,22 +5468,29 @@ public final class DarcProto {
         return (Builder) super.addRepeatedField(field, value);
       }
       public Builder mergeFrom(com.google.protobuf.Message other) {
-        if (other instanceof ch.epfl.dedis.proto.DarcProto.Signature) {
-          return mergeFrom((ch.epfl.dedis.proto.DarcProto.Signature)other);
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.X509ChainSignature) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.X509ChainSignature)other);
         } else {
           super.mergeFrom(other);
           return this;
         }
       }
 
-      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.Signature other) {
-        if (other == ch.epfl.dedis.proto.DarcProto.Signature.getDefaultInstance()) return this;
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.X509ChainSignature other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.X509ChainSignature.getDefaultInstance()) return this;
+        if (!other.certificates_.isEmpty()) {
+          if (certificates_.isEmpty()) {
+            certificates_ = other.certificates_;
+            bitField0_ = (bitField0_ & ~0x00000001);
+          } else {
+            ensureCertificatesIsMutable();
+            certificates_.addAll(other.certificates_);
+          }
+          onChanged();
+        }
         if (other.hasSignature()) {
           setSignature(other.getSignature());
         }
-        if (other.hasSignaturepath()) {
-          mergeSignaturepath(other.getSignaturepath());
-        }
         this.mergeUnknownFields(other.unknownFields);
         onChanged();
         return this;
@@ -5271,12 +5500,6 @@ public final class DarcProto {
         if (!hasSignature()) {
           return false;
         }
-        if (!hasSignaturepath()) {
-          return false;
-        }
-        if (!getSignaturepath().isInitialized()) {
-          return false;
-        }
         return true;
       }
 
@@ -5284,11 +5507,11 @@ public final class DarcProto {
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws java.io.IOException {
-        ch.epfl.dedis.proto.DarcProto.Signature parsedMessage = null;
+        ch.epfl.dedis.proto.DarcProto.X509ChainSignature parsedMessage = null;
         try {
           parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
         } catch (com.google.protobuf.InvalidProtocolBufferException e) {
-          parsedMessage = (ch.epfl.dedis.proto.DarcProto.Signature) e.getUnfinishedMessage();
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.X509ChainSignature) e.getUnfinishedMessage();
           throw e.unwrapIOException();
         } finally {
           if (parsedMessage != null) {
@@ -5299,209 +5522,111 @@ public final class DarcProto {
       }
       private int bitField0_;
 
-      private com.google.protobuf.ByteString signature_ = com.google.protobuf.ByteString.EMPTY;
+      private java.util.List<com.google.protobuf.ByteString> certificates_ = java.util.Collections.emptyList();
+      private void ensureCertificatesIsMutable() {
+        if (!((bitField0_ & 0x00000001) == 0x00000001)) {
+          certificates_ = new java.util.ArrayList<com.google.protobuf.ByteString>(certificates_);
+          bitField0_ |= 0x00000001;
+         }
+      }
       /**
-       * <pre>
-       * 	 The signature itself
-       * </pre>
-       *
-       * <code>required bytes signature = 1;</code>
+       * <code>repeated bytes certificates = 1;</code>
        */
-      public boolean hasSignature() {
-        return ((bitField0_ & 0x00000001) == 0x00000001);
+      public java.util.List<com.google.protobuf.ByteString>
+          getCertificatesList() {
+        return java.util.Collections.unmodifiableList(certificates_);
       }
       /**
-       * <pre>
-       * 	 The signature itself
-       * </pre>
-       *
-       * <code>required bytes signature = 1;</code>
+       * <code>repeated bytes certificates = 1;</code>
        */
-      public com.google.protobuf.ByteString getSignature() {
-        return signature_;
+      public int getCertificatesCount() {
+        return certificates_.size();
       }
       /**
-       * <pre>
-       * 	 The signature itself
-       * </pre>
-       *
-       * <code>required bytes signature = 1;</code>
+       * <code>repeated bytes certificates = 1;</code>
        */
-      public Builder setSignature(com.google.protobuf.ByteString value) {
+      public com.google.protobuf.ByteString getCertificates(int index) {
+        return certificates_.get(index);
+      }
+      /**
+       * <code>repeated bytes certificates = 1;</code>
+       */
+      public Builder setCertificates(
+          int index, com.google.protobuf.ByteString value) {
         if (value == null) {
     throw new NullPointerException();
   }
-  bitField0_ |= 0x00000001;
-        signature_ = value;
+  ensureCertificatesIsMutable();
+        certificates_.set(index, value);
         onChanged();
         return this;
       }
       /**
-       * <pre>
-       * 	 The signature itself
-       * </pre>
-       *
-       * <code>required bytes signature = 1;</code>
+       * <code>repeated bytes certificates = 1;</code>
        */
-      public Builder clearSignature() {
-        bitField0_ = (bitField0_ & ~0x00000001);
-        signature_ = getDefaultInstance().getSignature();
-        onChanged();
-        return this;
-      }
-
-      private ch.epfl.dedis.proto.DarcProto.SignaturePath signaturepath_ = null;
-      private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.SignaturePath, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder, ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder> signaturepathBuilder_;
-      /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
-       */
-      public boolean hasSignaturepath() {
-        return ((bitField0_ & 0x00000002) == 0x00000002);
-      }
-      /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
-       */
-      public ch.epfl.dedis.proto.DarcProto.SignaturePath getSignaturepath() {
-        if (signaturepathBuilder_ == null) {
-          return signaturepath_ == null ? ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
-        } else {
-          return signaturepathBuilder_.getMessage();
-        }
-      }
-      /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
-       */
-      public Builder setSignaturepath(ch.epfl.dedis.proto.DarcProto.SignaturePath value) {
-        if (signaturepathBuilder_ == null) {
-          if (value == null) {
-            throw new NullPointerException();
-          }
-          signaturepath_ = value;
-          onChanged();
-        } else {
-          signaturepathBuilder_.setMessage(value);
-        }
-        bitField0_ |= 0x00000002;
+      public Builder addCertificates(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  ensureCertificatesIsMutable();
+        certificates_.add(value);
+        onChanged();
         return this;
       }
       /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
+       * <code>repeated bytes certificates = 1;</code>
        */
-      public Builder setSignaturepath(
-          ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder builderForValue) {
-        if (signaturepathBuilder_ == null) {
-          signaturepath_ = builderForValue.build();
-          onChanged();
-        } else {
-          signaturepathBuilder_.setMessage(builderForValue.build());
-        }
-        bitField0_ |= 0x00000002;
+      public Builder addAllCertificates(
+          java.lang.Iterable<? extends com.google.protobuf.ByteString> values) {
+        ensureCertificatesIsMutable();
+        com.google.protobuf.AbstractMessageLite.Builder.addAll(
+            values, certificates_);
+        onChanged();
         return this;
       }
       /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
+       * <code>repeated bytes certificates = 1;</code>
        */
-      public Builder mergeSignaturepath(ch.epfl.dedis.proto.DarcProto.SignaturePath value) {
-        if (signaturepathBuilder_ == null) {
-          if (((bitField0_ & 0x00000002) == 0x00000002) &&
-              signaturepath_ != null &&
-              signaturepath_ != ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance()) {
-            signaturepath_ =
-              ch.epfl.dedis.proto.DarcProto.SignaturePath.newBuilder(signaturepath_).mergeFrom(value).buildPartial();
-          } else {
-            signaturepath_ = value;
-          }
-          onChanged();
-        } else {
-          signaturepathBuilder_.mergeFrom(value);
-        }
-        bitField0_ |= 0x00000002;
+      public Builder clearCertificates() {
+        certificates_ = java.util.Collections.emptyList();
+        bitField0_ = (bitField0_ & ~0x00000001);
+        onChanged();
         return this;
       }
+
+      private com.google.protobuf.ByteString signature_ = com.google.protobuf.ByteString.EMPTY;
       /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
+       * <code>required bytes signature = 2;</code>
        */
-      public Builder clearSignaturepath() {
-        if (signaturepathBuilder_ == null) {
-          signaturepath_ = null;
-          onChanged();
-        } else {
-          signaturepathBuilder_.clear();
-        }
-        bitField0_ = (bitField0_ & ~0x00000002);
-        return this;
+      public boolean hasSignature() {
+        return ((bitField0_ & 0x00000002) == 0x00000002);
       }
       /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
+       * <code>required bytes signature = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder getSignaturepathBuilder() {
-        bitField0_ |= 0x00000002;
-        onChanged();
-        return getSignaturepathFieldBuilder().getBuilder();
+      public com.google.protobuf.ByteString getSignature() {
+        return signature_;
       }
       /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
+       * <code>required bytes signature = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder getSignaturepathOrBuilder() {
-        if (signaturepathBuilder_ != null) {
-          return signaturepathBuilder_.getMessageOrBuilder();
-        } else {
-          return signaturepath_ == null ?
-              ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
-        }
+      public Builder setSignature(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  bitField0_ |= 0x00000002;
+        signature_ = value;
+        onChanged();
+        return this;
       }
       /**
-       * <pre>
-       * 	 Represents the path to get up to information to be able to verify this signature
-       * </pre>
-       *
-       * <code>required .SignaturePath signaturepath = 2;</code>
+       * <code>required bytes signature = 2;</code>
        */
-      private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.SignaturePath, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder, ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder> 
-          getSignaturepathFieldBuilder() {
-        if (signaturepathBuilder_ == null) {
-          signaturepathBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
-              ch.epfl.dedis.proto.DarcProto.SignaturePath, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder, ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder>(
-                  getSignaturepath(),
-                  getParentForChildren(),
-                  isClean());
-          signaturepath_ = null;
-        }
-        return signaturepathBuilder_;
+      public Builder clearSignature() {
+        bitField0_ = (bitField0_ & ~0x00000002);
+        signature_ = getDefaultInstance().getSignature();
+        onChanged();
+        return this;
       }
       public final Builder setUnknownFields(
           final com.google.protobuf.UnknownFieldSet unknownFields) {
@@ -5514,161 +5639,83 @@ public final class DarcProto {
       }
 
 
-      // @@protoc_insertion_point(builder_scope:Signature)
+      // @@protoc_insertion_point(builder_scope:X509ChainSignature)
     }
 
-    // @@protoc_insertion_point(class_scope:Signature)
-    private static final ch.epfl.dedis.proto.DarcProto.Signature DEFAULT_INSTANCE;
+    // @@protoc_insertion_point(class_scope:X509ChainSignature)
+    private static final ch.epfl.dedis.proto.DarcProto.X509ChainSignature DEFAULT_INSTANCE;
     static {
-      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.Signature();
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.X509ChainSignature();
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.Signature getDefaultInstance() {
+    public static ch.epfl.dedis.proto.DarcProto.X509ChainSignature getDefaultInstance() {
       return DEFAULT_INSTANCE;
     }
 
-    @java.lang.Deprecated public static final com.google.protobuf.Parser<Signature>
-        PARSER = new com.google.protobuf.AbstractParser<Signature>() {
-      public Signature parsePartialFrom(
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<X509ChainSignature>
+        PARSER = new com.google.protobuf.AbstractParser<X509ChainSignature>() {
+      public X509ChainSignature parsePartialFrom(
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws com.google.protobuf.InvalidProtocolBufferException {
-          return new Signature(input, extensionRegistry);
+          return new X509ChainSignature(input, extensionRegistry);
       }
     };
 
-    public static com.google.protobuf.Parser<Signature> parser() {
+    public static com.google.protobuf.Parser<X509ChainSignature> parser() {
       return PARSER;
     }
 
     @java.lang.Override
-    public com.google.protobuf.Parser<Signature> getParserForType() {
+    public com.google.protobuf.Parser<X509ChainSignature> getParserForType() {
       return PARSER;
     }
 
-    public ch.epfl.dedis.proto.DarcProto.Signature getDefaultInstanceForType() {
+    public ch.epfl.dedis.proto.DarcProto.X509ChainSignature getDefaultInstanceForType() {
       return DEFAULT_INSTANCE;
     }
 
   }
 
-  public interface SignaturePathOrBuilder extends
-      // @@protoc_insertion_point(interface_extends:SignaturePath)
+  public interface IdentityDarcOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:IdentityDarc)
       com.google.protobuf.MessageOrBuilder {
 
     /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> 
-        getDarcsList();
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    ch.epfl.dedis.proto.DarcProto.Darc getDarcs(int index);
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    int getDarcsCount();
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
+     * <code>required bytes id = 1;</code>
      */
-    java.util.List<? extends ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
-        getDarcsOrBuilderList();
+    boolean hasId();
     /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
+     * <code>required bytes id = 1;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.DarcOrBuilder getDarcsOrBuilder(
-        int index);
-
-    /**
-     * <pre>
-     * 	 the Idenity (public key or another Darc) of the signer
-     * </pre>
-     *
-     * <code>required .Identity signer = 2;</code>
-     */
-    boolean hasSigner();
-    /**
-     * <pre>
-     * 	 the Idenity (public key or another Darc) of the signer
-     * </pre>
-     *
-     * <code>required .Identity signer = 2;</code>
-     */
-    ch.epfl.dedis.proto.DarcProto.Identity getSigner();
-    /**
-     * <pre>
-     * 	 the Idenity (public key or another Darc) of the signer
-     * </pre>
-     *
-     * <code>required .Identity signer = 2;</code>
-     */
-    ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder getSignerOrBuilder();
-
-    /**
-     * <pre>
-     * 	 Is the signer Owner of a Darc or an user
-     * </pre>
-     *
-     * <code>required sint32 role = 3;</code>
-     */
-    boolean hasRole();
-    /**
-     * <pre>
-     * 	 Is the signer Owner of a Darc or an user
-     * </pre>
-     *
-     * <code>required sint32 role = 3;</code>
-     */
-    int getRole();
-  }
-  /**
-   * <pre>
-   * SignaturePath is a struct that holds information necessary for signature verification
-   * </pre>
-   *
-   * Protobuf type {@code SignaturePath}
-   */
-  public  static final class SignaturePath extends
-      com.google.protobuf.GeneratedMessageV3 implements
-      // @@protoc_insertion_point(message_implements:SignaturePath)
-      SignaturePathOrBuilder {
-  private static final long serialVersionUID = 0L;
-    // Use SignaturePath.newBuilder() to construct.
-    private SignaturePath(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
-      super(builder);
-    }
-    private SignaturePath() {
-      darcs_ = java.util.Collections.emptyList();
-      role_ = 0;
-    }
+    com.google.protobuf.ByteString getId();
+  }
+  /**
+   * <pre>
+   * IdentityDarc is a structure that points to a Darc with a given DarcID on a skipchain
+   * </pre>
+   *
+   * Protobuf type {@code IdentityDarc}
+   */
+  public  static final class IdentityDarc extends
+      com.google.protobuf.GeneratedMessageV3 implements
+      // @@protoc_insertion_point(message_implements:IdentityDarc)
+      IdentityDarcOrBuilder {
+  private static final long serialVersionUID = 0L;
+    // Use IdentityDarc.newBuilder() to construct.
+    private IdentityDarc(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+      super(builder);
+    }
+    private IdentityDarc() {
+      id_ = com.google.protobuf.ByteString.EMPTY;
+    }
 
     @java.lang.Override
     public final com.google.protobuf.UnknownFieldSet
     getUnknownFields() {
       return this.unknownFields;
     }
-    private SignaturePath(
+    private IdentityDarc(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
@@ -5692,30 +5739,8 @@ public final class DarcProto {
               break;
             }
             case 10: {
-              if (!((mutable_bitField0_ & 0x00000001) == 0x00000001)) {
-                darcs_ = new java.util.ArrayList<ch.epfl.dedis.proto.DarcProto.Darc>();
-                mutable_bitField0_ |= 0x00000001;
-              }
-              darcs_.add(
-                  input.readMessage(ch.epfl.dedis.proto.DarcProto.Darc.PARSER, extensionRegistry));
-              break;
-            }
-            case 18: {
-              ch.epfl.dedis.proto.DarcProto.Identity.Builder subBuilder = null;
-              if (((bitField0_ & 0x00000001) == 0x00000001)) {
-                subBuilder = signer_.toBuilder();
-              }
-              signer_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.Identity.PARSER, extensionRegistry);
-              if (subBuilder != null) {
-                subBuilder.mergeFrom(signer_);
-                signer_ = subBuilder.buildPartial();
-              }
               bitField0_ |= 0x00000001;
-              break;
-            }
-            case 24: {
-              bitField0_ |= 0x00000002;
-              role_ = input.readSInt32();
+              id_ = input.readBytes();
               break;
             }
           }
@@ -5726,135 +5751,36 @@ public final class DarcProto {
         throw new com.google.protobuf.InvalidProtocolBufferException(
             e).setUnfinishedMessage(this);
       } finally {
-        if (((mutable_bitField0_ & 0x00000001) == 0x00000001)) {
-          darcs_ = java.util.Collections.unmodifiableList(darcs_);
-        }
         this.unknownFields = unknownFields.build();
         makeExtensionsImmutable();
       }
     }
     public static final com.google.protobuf.Descriptors.Descriptor
         getDescriptor() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_descriptor;
+      return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_descriptor;
     }
 
     protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
         internalGetFieldAccessorTable() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_fieldAccessorTable
+      return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_fieldAccessorTable
           .ensureFieldAccessorsInitialized(
-              ch.epfl.dedis.proto.DarcProto.SignaturePath.class, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder.class);
+              ch.epfl.dedis.proto.DarcProto.IdentityDarc.class, ch.epfl.dedis.proto.DarcProto.IdentityDarc.Builder.class);
     }
 
     private int bitField0_;
-    public static final int DARCS_FIELD_NUMBER = 1;
-    private java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> darcs_;
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    public java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> getDarcsList() {
-      return darcs_;
-    }
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    public java.util.List<? extends ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
-        getDarcsOrBuilderList() {
-      return darcs_;
-    }
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    public int getDarcsCount() {
-      return darcs_.size();
-    }
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    public ch.epfl.dedis.proto.DarcProto.Darc getDarcs(int index) {
-      return darcs_.get(index);
-    }
-    /**
-     * <pre>
-     * 	 Darc(s) that justify the right of the signer to push a new Darc
-     * </pre>
-     *
-     * <code>repeated .Darc darcs = 1;</code>
-     */
-    public ch.epfl.dedis.proto.DarcProto.DarcOrBuilder getDarcsOrBuilder(
-        int index) {
-      return darcs_.get(index);
-    }
-
-    public static final int SIGNER_FIELD_NUMBER = 2;
-    private ch.epfl.dedis.proto.DarcProto.Identity signer_;
+    public static final int ID_FIELD_NUMBER = 1;
+    private com.google.protobuf.ByteString id_;
     /**
-     * <pre>
-     * 	 the Idenity (public key or another Darc) of the signer
-     * </pre>
-     *
-     * <code>required .Identity signer = 2;</code>
+     * <code>required bytes id = 1;</code>
      */
-    public boolean hasSigner() {
+    public boolean hasId() {
       return ((bitField0_ & 0x00000001) == 0x00000001);
     }
     /**
-     * <pre>
-     * 	 the Idenity (public key or another Darc) of the signer
-     * </pre>
-     *
-     * <code>required .Identity signer = 2;</code>
-     */
-    public ch.epfl.dedis.proto.DarcProto.Identity getSigner() {
-      return signer_ == null ? ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
-    }
-    /**
-     * <pre>
-     * 	 the Idenity (public key or another Darc) of the signer
-     * </pre>
-     *
-     * <code>required .Identity signer = 2;</code>
-     */
-    public ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder getSignerOrBuilder() {
-      return signer_ == null ? ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
-    }
-
-    public static final int ROLE_FIELD_NUMBER = 3;
-    private int role_;
-    /**
-     * <pre>
-     * 	 Is the signer Owner of a Darc or an user
-     * </pre>
-     *
-     * <code>required sint32 role = 3;</code>
-     */
-    public boolean hasRole() {
-      return ((bitField0_ & 0x00000002) == 0x00000002);
-    }
-    /**
-     * <pre>
-     * 	 Is the signer Owner of a Darc or an user
-     * </pre>
-     *
-     * <code>required sint32 role = 3;</code>
+     * <code>required bytes id = 1;</code>
      */
-    public int getRole() {
-      return role_;
+    public com.google.protobuf.ByteString getId() {
+      return id_;
     }
 
     private byte memoizedIsInitialized = -1;
@@ -5863,21 +5789,7 @@ public final class DarcProto {
       if (isInitialized == 1) return true;
       if (isInitialized == 0) return false;
 
-      if (!hasSigner()) {
-        memoizedIsInitialized = 0;
-        return false;
-      }
-      if (!hasRole()) {
-        memoizedIsInitialized = 0;
-        return false;
-      }
-      for (int i = 0; i < getDarcsCount(); i++) {
-        if (!getDarcs(i).isInitialized()) {
-          memoizedIsInitialized = 0;
-          return false;
-        }
-      }
-      if (!getSigner().isInitialized()) {
+      if (!hasId()) {
         memoizedIsInitialized = 0;
         return false;
       }
@@ -5887,14 +5799,8 @@ public final class DarcProto {
 
     public void writeTo(com.google.protobuf.CodedOutputStream output)
                         throws java.io.IOException {
-      for (int i = 0; i < darcs_.size(); i++) {
-        output.writeMessage(1, darcs_.get(i));
-      }
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
-        output.writeMessage(2, getSigner());
-      }
-      if (((bitField0_ & 0x00000002) == 0x00000002)) {
-        output.writeSInt32(3, role_);
+        output.writeBytes(1, id_);
       }
       unknownFields.writeTo(output);
     }
@@ -5904,17 +5810,9 @@ public final class DarcProto {
       if (size != -1) return size;
 
       size = 0;
-      for (int i = 0; i < darcs_.size(); i++) {
-        size += com.google.protobuf.CodedOutputStream
-          .computeMessageSize(1, darcs_.get(i));
-      }
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
         size += com.google.protobuf.CodedOutputStream
-          .computeMessageSize(2, getSigner());
-      }
-      if (((bitField0_ & 0x00000002) == 0x00000002)) {
-        size += com.google.protobuf.CodedOutputStream
-          .computeSInt32Size(3, role_);
+          .computeBytesSize(1, id_);
       }
       size += unknownFields.getSerializedSize();
       memoizedSize = size;
@@ -5926,23 +5824,16 @@ public final class DarcProto {
       if (obj == this) {
        return true;
       }
-      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignaturePath)) {
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.IdentityDarc)) {
         return super.equals(obj);
       }
-      ch.epfl.dedis.proto.DarcProto.SignaturePath other = (ch.epfl.dedis.proto.DarcProto.SignaturePath) obj;
+      ch.epfl.dedis.proto.DarcProto.IdentityDarc other = (ch.epfl.dedis.proto.DarcProto.IdentityDarc) obj;
 
       boolean result = true;
-      result = result && getDarcsList()
-          .equals(other.getDarcsList());
-      result = result && (hasSigner() == other.hasSigner());
-      if (hasSigner()) {
-        result = result && getSigner()
-            .equals(other.getSigner());
-      }
-      result = result && (hasRole() == other.hasRole());
-      if (hasRole()) {
-        result = result && (getRole()
-            == other.getRole());
+      result = result && (hasId() == other.hasId());
+      if (hasId()) {
+        result = result && getId()
+            .equals(other.getId());
       }
       result = result && unknownFields.equals(other.unknownFields);
       return result;
@@ -5955,86 +5846,78 @@ public final class DarcProto {
       }
       int hash = 41;
       hash = (19 * hash) + getDescriptor().hashCode();
-      if (getDarcsCount() > 0) {
-        hash = (37 * hash) + DARCS_FIELD_NUMBER;
-        hash = (53 * hash) + getDarcsList().hashCode();
-      }
-      if (hasSigner()) {
-        hash = (37 * hash) + SIGNER_FIELD_NUMBER;
-        hash = (53 * hash) + getSigner().hashCode();
-      }
-      if (hasRole()) {
-        hash = (37 * hash) + ROLE_FIELD_NUMBER;
-        hash = (53 * hash) + getRole();
+      if (hasId()) {
+        hash = (37 * hash) + ID_FIELD_NUMBER;
+        hash = (53 * hash) + getId().hashCode();
       }
       hash = (29 * hash) + unknownFields.hashCode();
       memoizedHashCode = hash;
       return hash;
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         java.nio.ByteBuffer data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         java.nio.ByteBuffer data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         com.google.protobuf.ByteString data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         com.google.protobuf.ByteString data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(byte[] data)
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(byte[] data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         byte[] data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseDelimitedFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseDelimitedFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseDelimitedFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseDelimitedFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         com.google.protobuf.CodedInputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc parseFrom(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
@@ -6046,7 +5929,7 @@ public final class DarcProto {
     public static Builder newBuilder() {
       return DEFAULT_INSTANCE.toBuilder();
     }
-    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignaturePath prototype) {
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.IdentityDarc prototype) {
       return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
     }
     public Builder toBuilder() {
@@ -6062,28 +5945,28 @@ public final class DarcProto {
     }
     /**
      * <pre>
-     * SignaturePath is a struct that holds information necessary for signature verification
+     * IdentityDarc is a structure that points to a Darc with a given DarcID on a skipchain
      * </pre>
      *
-     * Protobuf type {@code SignaturePath}
+     * Protobuf type {@code IdentityDarc}
      */
     public static final class Builder extends
         com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
-        // @@protoc_insertion_point(builder_implements:SignaturePath)
-        ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder {
+        // @@protoc_insertion_point(builder_implements:IdentityDarc)
+        ch.epfl.dedis.proto.DarcProto.IdentityDarcOrBuilder {
       public static final com.google.protobuf.Descriptors.Descriptor
           getDescriptor() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_descriptor;
       }
 
       protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
           internalGetFieldAccessorTable() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_fieldAccessorTable
+        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_fieldAccessorTable
             .ensureFieldAccessorsInitialized(
-                ch.epfl.dedis.proto.DarcProto.SignaturePath.class, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder.class);
+                ch.epfl.dedis.proto.DarcProto.IdentityDarc.class, ch.epfl.dedis.proto.DarcProto.IdentityDarc.Builder.class);
       }
 
-      // Construct using ch.epfl.dedis.proto.DarcProto.SignaturePath.newBuilder()
+      // Construct using ch.epfl.dedis.proto.DarcProto.IdentityDarc.newBuilder()
       private Builder() {
         maybeForceBuilderInitialization();
       }
@@ -6096,71 +5979,40 @@ public final class DarcProto {
       private void maybeForceBuilderInitialization() {
         if (com.google.protobuf.GeneratedMessageV3
                 .alwaysUseFieldBuilders) {
-          getDarcsFieldBuilder();
-          getSignerFieldBuilder();
         }
       }
       public Builder clear() {
         super.clear();
-        if (darcsBuilder_ == null) {
-          darcs_ = java.util.Collections.emptyList();
-          bitField0_ = (bitField0_ & ~0x00000001);
-        } else {
-          darcsBuilder_.clear();
-        }
-        if (signerBuilder_ == null) {
-          signer_ = null;
-        } else {
-          signerBuilder_.clear();
-        }
-        bitField0_ = (bitField0_ & ~0x00000002);
-        role_ = 0;
-        bitField0_ = (bitField0_ & ~0x00000004);
+        id_ = com.google.protobuf.ByteString.EMPTY;
+        bitField0_ = (bitField0_ & ~0x00000001);
         return this;
       }
 
       public com.google.protobuf.Descriptors.Descriptor
           getDescriptorForType() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_IdentityDarc_descriptor;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignaturePath getDefaultInstanceForType() {
-        return ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance();
+      public ch.epfl.dedis.proto.DarcProto.IdentityDarc getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.IdentityDarc.getDefaultInstance();
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignaturePath build() {
-        ch.epfl.dedis.proto.DarcProto.SignaturePath result = buildPartial();
+      public ch.epfl.dedis.proto.DarcProto.IdentityDarc build() {
+        ch.epfl.dedis.proto.DarcProto.IdentityDarc result = buildPartial();
         if (!result.isInitialized()) {
           throw newUninitializedMessageException(result);
         }
         return result;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignaturePath buildPartial() {
-        ch.epfl.dedis.proto.DarcProto.SignaturePath result = new ch.epfl.dedis.proto.DarcProto.SignaturePath(this);
+      public ch.epfl.dedis.proto.DarcProto.IdentityDarc buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.IdentityDarc result = new ch.epfl.dedis.proto.DarcProto.IdentityDarc(this);
         int from_bitField0_ = bitField0_;
         int to_bitField0_ = 0;
-        if (darcsBuilder_ == null) {
-          if (((bitField0_ & 0x00000001) == 0x00000001)) {
-            darcs_ = java.util.Collections.unmodifiableList(darcs_);
-            bitField0_ = (bitField0_ & ~0x00000001);
-          }
-          result.darcs_ = darcs_;
-        } else {
-          result.darcs_ = darcsBuilder_.build();
-        }
-        if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
+        if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
           to_bitField0_ |= 0x00000001;
         }
-        if (signerBuilder_ == null) {
-          result.signer_ = signer_;
-        } else {
-          result.signer_ = signerBuilder_.build();
-        }
-        if (((from_bitField0_ & 0x00000004) == 0x00000004)) {
-          to_bitField0_ |= 0x00000002;
-        }
-        result.role_ = role_;
+        result.id_ = id_;
         result.bitField0_ = to_bitField0_;
         onBuilt();
         return result;
@@ -6193,47 +6045,18 @@ public final class DarcProto {
         return (Builder) super.addRepeatedField(field, value);
       }
       public Builder mergeFrom(com.google.protobuf.Message other) {
-        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignaturePath) {
-          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignaturePath)other);
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.IdentityDarc) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.IdentityDarc)other);
         } else {
           super.mergeFrom(other);
           return this;
         }
       }
 
-      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignaturePath other) {
-        if (other == ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance()) return this;
-        if (darcsBuilder_ == null) {
-          if (!other.darcs_.isEmpty()) {
-            if (darcs_.isEmpty()) {
-              darcs_ = other.darcs_;
-              bitField0_ = (bitField0_ & ~0x00000001);
-            } else {
-              ensureDarcsIsMutable();
-              darcs_.addAll(other.darcs_);
-            }
-            onChanged();
-          }
-        } else {
-          if (!other.darcs_.isEmpty()) {
-            if (darcsBuilder_.isEmpty()) {
-              darcsBuilder_.dispose();
-              darcsBuilder_ = null;
-              darcs_ = other.darcs_;
-              bitField0_ = (bitField0_ & ~0x00000001);
-              darcsBuilder_ = 
-                com.google.protobuf.GeneratedMessageV3.alwaysUseFieldBuilders ?
-                   getDarcsFieldBuilder() : null;
-            } else {
-              darcsBuilder_.addAllMessages(other.darcs_);
-            }
-          }
-        }
-        if (other.hasSigner()) {
-          mergeSigner(other.getSigner());
-        }
-        if (other.hasRole()) {
-          setRole(other.getRole());
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.IdentityDarc other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.IdentityDarc.getDefaultInstance()) return this;
+        if (other.hasId()) {
+          setId(other.getId());
         }
         this.mergeUnknownFields(other.unknownFields);
         onChanged();
@@ -6241,18 +6064,7 @@ public final class DarcProto {
       }
 
       public final boolean isInitialized() {
-        if (!hasSigner()) {
-          return false;
-        }
-        if (!hasRole()) {
-          return false;
-        }
-        for (int i = 0; i < getDarcsCount(); i++) {
-          if (!getDarcs(i).isInitialized()) {
-            return false;
-          }
-        }
-        if (!getSigner().isInitialized()) {
+        if (!hasId()) {
           return false;
         }
         return true;
@@ -6262,11 +6074,11 @@ public final class DarcProto {
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws java.io.IOException {
-        ch.epfl.dedis.proto.DarcProto.SignaturePath parsedMessage = null;
+        ch.epfl.dedis.proto.DarcProto.IdentityDarc parsedMessage = null;
         try {
           parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
         } catch (com.google.protobuf.InvalidProtocolBufferException e) {
-          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignaturePath) e.getUnfinishedMessage();
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.IdentityDarc) e.getUnfinishedMessage();
           throw e.unwrapIOException();
         } finally {
           if (parsedMessage != null) {
@@ -6277,518 +6089,3156 @@ public final class DarcProto {
       }
       private int bitField0_;
 
-      private java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> darcs_ =
-        java.util.Collections.emptyList();
-      private void ensureDarcsIsMutable() {
-        if (!((bitField0_ & 0x00000001) == 0x00000001)) {
-          darcs_ = new java.util.ArrayList<ch.epfl.dedis.proto.DarcProto.Darc>(darcs_);
-          bitField0_ |= 0x00000001;
-         }
-      }
-
-      private com.google.protobuf.RepeatedFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.Darc, ch.epfl.dedis.proto.DarcProto.Darc.Builder, ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> darcsBuilder_;
-
-      /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
-       */
-      public java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> getDarcsList() {
-        if (darcsBuilder_ == null) {
-          return java.util.Collections.unmodifiableList(darcs_);
-        } else {
-          return darcsBuilder_.getMessageList();
-        }
-      }
+      private com.google.protobuf.ByteString id_ = com.google.protobuf.ByteString.EMPTY;
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>required bytes id = 1;</code>
        */
-      public int getDarcsCount() {
-        if (darcsBuilder_ == null) {
-          return darcs_.size();
-        } else {
-          return darcsBuilder_.getCount();
-        }
+      public boolean hasId() {
+        return ((bitField0_ & 0x00000001) == 0x00000001);
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>required bytes id = 1;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.Darc getDarcs(int index) {
-        if (darcsBuilder_ == null) {
-          return darcs_.get(index);
-        } else {
-          return darcsBuilder_.getMessage(index);
-        }
+      public com.google.protobuf.ByteString getId() {
+        return id_;
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>required bytes id = 1;</code>
        */
-      public Builder setDarcs(
-          int index, ch.epfl.dedis.proto.DarcProto.Darc value) {
-        if (darcsBuilder_ == null) {
-          if (value == null) {
-            throw new NullPointerException();
-          }
-          ensureDarcsIsMutable();
-          darcs_.set(index, value);
-          onChanged();
-        } else {
-          darcsBuilder_.setMessage(index, value);
-        }
+      public Builder setId(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  bitField0_ |= 0x00000001;
+        id_ = value;
+        onChanged();
         return this;
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>required bytes id = 1;</code>
        */
-      public Builder setDarcs(
-          int index, ch.epfl.dedis.proto.DarcProto.Darc.Builder builderForValue) {
-        if (darcsBuilder_ == null) {
-          ensureDarcsIsMutable();
-          darcs_.set(index, builderForValue.build());
-          onChanged();
-        } else {
-          darcsBuilder_.setMessage(index, builderForValue.build());
-        }
+      public Builder clearId() {
+        bitField0_ = (bitField0_ & ~0x00000001);
+        id_ = getDefaultInstance().getId();
+        onChanged();
         return this;
       }
-      /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
-       */
-      public Builder addDarcs(ch.epfl.dedis.proto.DarcProto.Darc value) {
-        if (darcsBuilder_ == null) {
-          if (value == null) {
-            throw new NullPointerException();
+      public final Builder setUnknownFields(
+          final com.google.protobuf.UnknownFieldSet unknownFields) {
+        return super.setUnknownFields(unknownFields);
+      }
+
+      public final Builder mergeUnknownFields(
+          final com.google.protobuf.UnknownFieldSet unknownFields) {
+        return super.mergeUnknownFields(unknownFields);
+      }
+
+
+      // @@protoc_insertion_point(builder_scope:IdentityDarc)
+    }
+
+    // @@protoc_insertion_point(class_scope:IdentityDarc)
+    private static final ch.epfl.dedis.proto.DarcProto.IdentityDarc DEFAULT_INSTANCE;
+    static {
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.IdentityDarc();
+    }
+
+    public static ch.epfl.dedis.proto.DarcProto.IdentityDarc getDefaultInstance() {
+      return DEFAULT_INSTANCE;
+    }
+
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<IdentityDarc>
+        PARSER = new com.google.protobuf.AbstractParser<IdentityDarc>() {
+      public IdentityDarc parsePartialFrom(
+          com.google.protobuf.CodedInputStream input,
+          com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+          throws com.google.protobuf.InvalidProtocolBufferException {
+          return new IdentityDarc(input, extensionRegistry);
+      }
+    };
+
+    public static com.google.protobuf.Parser<IdentityDarc> parser() {
+      return PARSER;
+    }
+
+    @java.lang.Override
+    public com.google.protobuf.Parser<IdentityDarc> getParserForType() {
+      return PARSER;
+    }
+
+    public ch.epfl.dedis.proto.DarcProto.IdentityDarc getDefaultInstanceForType() {
+      return DEFAULT_INSTANCE;
+    }
+
+  }
+
+  public interface SignatureOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:Signature)
+      com.google.protobuf.MessageOrBuilder {
+
+    /**
+     * <pre>
+     * 	 The signature itself
+     * </pre>
+     *
+     * <code>required bytes signature = 1;</code>
+     */
+    boolean hasSignature();
+    /**
+     * <pre>
+     * 	 The signature itself
+     * </pre>
+     *
+     * <code>required bytes signature = 1;</code>
+     */
+    com.google.protobuf.ByteString getSignature();
+
+    /**
+     * <pre>
+     * 	 Represents the path to get up to information to be able to verify this signature
+     * </pre>
+     *
+     * <code>required .SignaturePath signaturepath = 2;</code>
+     */
+    boolean hasSignaturepath();
+    /**
+     * <pre>
+     * 	 Represents the path to get up to information to be able to verify this signature
+     * </pre>
+     *
+     * <code>required .SignaturePath signaturepath = 2;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignaturePath getSignaturepath();
+    /**
+     * <pre>
+     * 	 Represents the path to get up to information to be able to verify this signature
+     * </pre>
+     *
+     * <code>required .SignaturePath signaturepath = 2;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder getSignaturepathOrBuilder();
+  }
+  /**
+   * <pre>
+   * Signature is a signature on a Darc to accept a given decision.
+   * can be verified using the appropriate identity.
+   * </pre>
+   *
+   * Protobuf type {@code Signature}
+   */
+  public  static final class Signature extends
+      com.google.protobuf.GeneratedMessageV3 implements
+      // @@protoc_insertion_point(message_implements:Signature)
+      SignatureOrBuilder {
+  private static final long serialVersionUID = 0L;
+    // Use Signature.newBuilder() to construct.
+    private Signature(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+      super(builder);
+    }
+    private Signature() {
+      signature_ = com.google.protobuf.ByteString.EMPTY;
+    }
+
+    @java.lang.Override
+    public final com.google.protobuf.UnknownFieldSet
+    getUnknownFields() {
+      return this.unknownFields;
+    }
+    private Signature(
+        com.google.protobuf.CodedInputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      this();
+      int mutable_bitField0_ = 0;
+      com.google.protobuf.UnknownFieldSet.Builder unknownFields =
+          com.google.protobuf.UnknownFieldSet.newBuilder();
+      try {
+        boolean done = false;
+        while (!done) {
+          int tag = input.readTag();
+          switch (tag) {
+            case 0:
+              done = true;
+              break;
+            default: {
+              if (!parseUnknownField(
+                  input, unknownFields, extensionRegistry, tag)) {
+                done = true;
+              }
+              break;
+            }
+            case 10: {
+              bitField0_ |= 0x00000001;
+              signature_ = input.readBytes();
+              break;
+            }
+            case 18: {
+              ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder subBuilder = null;
+              if (((bitField0_ & 0x00000002) == 0x00000002)) {
+                subBuilder = signaturepath_.toBuilder();
+              }
+              signaturepath_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignaturePath.PARSER, extensionRegistry);
+              if (subBuilder != null) {
+                subBuilder.mergeFrom(signaturepath_);
+                signaturepath_ = subBuilder.buildPartial();
+              }
+              bitField0_ |= 0x00000002;
+              break;
+            }
           }
-          ensureDarcsIsMutable();
-          darcs_.add(value);
-          onChanged();
+        }
+      } catch (com.google.protobuf.InvalidProtocolBufferException e) {
+        throw e.setUnfinishedMessage(this);
+      } catch (java.io.IOException e) {
+        throw new com.google.protobuf.InvalidProtocolBufferException(
+            e).setUnfinishedMessage(this);
+      } finally {
+        this.unknownFields = unknownFields.build();
+        makeExtensionsImmutable();
+      }
+    }
+    public static final com.google.protobuf.Descriptors.Descriptor
+        getDescriptor() {
+      return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_descriptor;
+    }
+
+    protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+        internalGetFieldAccessorTable() {
+      return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_fieldAccessorTable
+          .ensureFieldAccessorsInitialized(
+              ch.epfl.dedis.proto.DarcProto.Signature.class, ch.epfl.dedis.proto.DarcProto.Signature.Builder.class);
+    }
+
+    private int bitField0_;
+    public static final int SIGNATURE_FIELD_NUMBER = 1;
+    private com.google.protobuf.ByteString signature_;
+    /**
+     * <pre>
+     * 	 The signature itself
+     * </pre>
+     *
+     * <code>required bytes signature = 1;</code>
+     */
+    public boolean hasSignature() {
+      return ((bitField0_ & 0x00000001) == 0x00000001);
+    }
+    /**
+     * <pre>
+     * 	 The signature itself
+     * </pre>
+     *
+     * <code>required bytes signature = 1;</code>
+     */
+    public com.google.protobuf.ByteString getSignature() {
+      return signature_;
+    }
+
+    public static final int SIGNATUREPATH_FIELD_NUMBER = 2;
+    private ch.epfl.dedis.proto.DarcProto.SignaturePath signaturepath_;
+    /**
+     * <pre>
+     * 	 Represents the path to get up to information to be able to verify this signature
+     * </pre>
+     *
+     * <code>required .SignaturePath signaturepath = 2;</code>
+     */
+    public boolean hasSignaturepath() {
+      return ((bitField0_ & 0x00000002) == 0x00000002);
+    }
+    /**
+     * <pre>
+     * 	 Represents the path to get up to information to be able to verify this signature
+     * </pre>
+     *
+     * <code>required .SignaturePath signaturepath = 2;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignaturePath getSignaturepath() {
+      return signaturepath_ == null ? ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
+    }
+    /**
+     * <pre>
+     * 	 Represents the path to get up to information to be able to verify this signature
+     * </pre>
+     *
+     * <code>required .SignaturePath signaturepath = 2;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder getSignaturepathOrBuilder() {
+      return signaturepath_ == null ? ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
+    }
+
+    private byte memoizedIsInitialized = -1;
+    public final boolean isInitialized() {
+      byte isInitialized = memoizedIsInitialized;
+      if (isInitialized == 1) return true;
+      if (isInitialized == 0) return false;
+
+      if (!hasSignature()) {
+        memoizedIsInitialized = 0;
+        return false;
+      }
+      if (!hasSignaturepath()) {
+        memoizedIsInitialized = 0;
+        return false;
+      }
+      if (!getSignaturepath().isInitialized()) {
+        memoizedIsInitialized = 0;
+        return false;
+      }
+      memoizedIsInitialized = 1;
+      return true;
+    }
+
+    public void writeTo(com.google.protobuf.CodedOutputStream output)
+                        throws java.io.IOException {
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        output.writeBytes(1, signature_);
+      }
+      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+        output.writeMessage(2, getSignaturepath());
+      }
+      unknownFields.writeTo(output);
+    }
+
+    public int getSerializedSize() {
+      int size = memoizedSize;
+      if (size != -1) return size;
+
+      size = 0;
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeBytesSize(1, signature_);
+      }
+      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(2, getSignaturepath());
+      }
+      size += unknownFields.getSerializedSize();
+      memoizedSize = size;
+      return size;
+    }
+
+    @java.lang.Override
+    public boolean equals(final java.lang.Object obj) {
+      if (obj == this) {
+       return true;
+      }
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.Signature)) {
+        return super.equals(obj);
+      }
+      ch.epfl.dedis.proto.DarcProto.Signature other = (ch.epfl.dedis.proto.DarcProto.Signature) obj;
+
+      boolean result = true;
+      result = result && (hasSignature() == other.hasSignature());
+      if (hasSignature()) {
+        result = result && getSignature()
+            .equals(other.getSignature());
+      }
+      result = result && (hasSignaturepath() == other.hasSignaturepath());
+      if (hasSignaturepath()) {
+        result = result && getSignaturepath()
+            .equals(other.getSignaturepath());
+      }
+      result = result && unknownFields.equals(other.unknownFields);
+      return result;
+    }
+
+    @java.lang.Override
+    public int hashCode() {
+      if (memoizedHashCode != 0) {
+        return memoizedHashCode;
+      }
+      int hash = 41;
+      hash = (19 * hash) + getDescriptor().hashCode();
+      if (hasSignature()) {
+        hash = (37 * hash) + SIGNATURE_FIELD_NUMBER;
+        hash = (53 * hash) + getSignature().hashCode();
+      }
+      if (hasSignaturepath()) {
+        hash = (37 * hash) + SIGNATUREPATH_FIELD_NUMBER;
+        hash = (53 * hash) + getSignaturepath().hashCode();
+      }
+      hash = (29 * hash) + unknownFields.hashCode();
+      memoizedHashCode = hash;
+      return hash;
+    }
+
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        java.nio.ByteBuffer data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        java.nio.ByteBuffer data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        com.google.protobuf.ByteString data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        com.google.protobuf.ByteString data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(byte[] data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        byte[] data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(java.io.InputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        java.io.InputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseDelimitedFrom(java.io.InputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseDelimitedWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseDelimitedFrom(
+        java.io.InputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        com.google.protobuf.CodedInputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signature parseFrom(
+        com.google.protobuf.CodedInputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input, extensionRegistry);
+    }
+
+    public Builder newBuilderForType() { return newBuilder(); }
+    public static Builder newBuilder() {
+      return DEFAULT_INSTANCE.toBuilder();
+    }
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.Signature prototype) {
+      return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
+    }
+    public Builder toBuilder() {
+      return this == DEFAULT_INSTANCE
+          ? new Builder() : new Builder().mergeFrom(this);
+    }
+
+    @java.lang.Override
+    protected Builder newBuilderForType(
+        com.google.protobuf.GeneratedMessageV3.BuilderParent parent) {
+      Builder builder = new Builder(parent);
+      return builder;
+    }
+    /**
+     * <pre>
+     * Signature is a signature on a Darc to accept a given decision.
+     * can be verified using the appropriate identity.
+     * </pre>
+     *
+     * Protobuf type {@code Signature}
+     */
+    public static final class Builder extends
+        com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
+        // @@protoc_insertion_point(builder_implements:Signature)
+        ch.epfl.dedis.proto.DarcProto.SignatureOrBuilder {
+      public static final com.google.protobuf.Descriptors.Descriptor
+          getDescriptor() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_descriptor;
+      }
+
+      protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+          internalGetFieldAccessorTable() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_fieldAccessorTable
+            .ensureFieldAccessorsInitialized(
+                ch.epfl.dedis.proto.DarcProto.Signature.class, ch.epfl.dedis.proto.DarcProto.Signature.Builder.class);
+      }
+
+      // Construct using ch.epfl.dedis.proto.DarcProto.Signature.newBuilder()
+      private Builder() {
+        maybeForceBuilderInitialization();
+      }
+
+      private Builder(
+          com.google.protobuf.GeneratedMessageV3.BuilderParent parent) {
+        super(parent);
+        maybeForceBuilderInitialization();
+      }
+      private void maybeForceBuilderInitialization() {
+        if (com.google.protobuf.GeneratedMessageV3
+                .alwaysUseFieldBuilders) {
+          getSignaturepathFieldBuilder();
+        }
+      }
+      public Builder clear() {
+        super.clear();
+        signature_ = com.google.protobuf.ByteString.EMPTY;
+        bitField0_ = (bitField0_ & ~0x00000001);
+        if (signaturepathBuilder_ == null) {
+          signaturepath_ = null;
+        } else {
+          signaturepathBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000002);
+        return this;
+      }
+
+      public com.google.protobuf.Descriptors.Descriptor
+          getDescriptorForType() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_Signature_descriptor;
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.Signature getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.Signature.getDefaultInstance();
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.Signature build() {
+        ch.epfl.dedis.proto.DarcProto.Signature result = buildPartial();
+        if (!result.isInitialized()) {
+          throw newUninitializedMessageException(result);
+        }
+        return result;
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.Signature buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.Signature result = new ch.epfl.dedis.proto.DarcProto.Signature(this);
+        int from_bitField0_ = bitField0_;
+        int to_bitField0_ = 0;
+        if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
+          to_bitField0_ |= 0x00000001;
+        }
+        result.signature_ = signature_;
+        if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
+          to_bitField0_ |= 0x00000002;
+        }
+        if (signaturepathBuilder_ == null) {
+          result.signaturepath_ = signaturepath_;
+        } else {
+          result.signaturepath_ = signaturepathBuilder_.build();
+        }
+        result.bitField0_ = to_bitField0_;
+        onBuilt();
+        return result;
+      }
+
+      public Builder clone() {
+        return (Builder) super.clone();
+      }
+      public Builder setField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          java.lang.Object value) {
+        return (Builder) super.setField(field, value);
+      }
+      public Builder clearField(
+          com.google.protobuf.Descriptors.FieldDescriptor field) {
+        return (Builder) super.clearField(field);
+      }
+      public Builder clearOneof(
+          com.google.protobuf.Descriptors.OneofDescriptor oneof) {
+        return (Builder) super.clearOneof(oneof);
+      }
+      public Builder setRepeatedField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          int index, java.lang.Object value) {
+        return (Builder) super.setRepeatedField(field, index, value);
+      }
+      public Builder addRepeatedField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          java.lang.Object value) {
+        return (Builder) super.addRepeatedField(field, value);
+      }
+      public Builder mergeFrom(com.google.protobuf.Message other) {
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.Signature) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.Signature)other);
+        } else {
+          super.mergeFrom(other);
+          return this;
+        }
+      }
+
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.Signature other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.Signature.getDefaultInstance()) return this;
+        if (other.hasSignature()) {
+          setSignature(other.getSignature());
+        }
+        if (other.hasSignaturepath()) {
+          mergeSignaturepath(other.getSignaturepath());
+        }
+        this.mergeUnknownFields(other.unknownFields);
+        onChanged();
+        return this;
+      }
+
+      public final boolean isInitialized() {
+        if (!hasSignature()) {
+          return false;
+        }
+        if (!hasSignaturepath()) {
+          return false;
+        }
+        if (!getSignaturepath().isInitialized()) {
+          return false;
+        }
+        return true;
+      }
+
+      public Builder mergeFrom(
+          com.google.protobuf.CodedInputStream input,
+          com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+          throws java.io.IOException {
+        ch.epfl.dedis.proto.DarcProto.Signature parsedMessage = null;
+        try {
+          parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
+        } catch (com.google.protobuf.InvalidProtocolBufferException e) {
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.Signature) e.getUnfinishedMessage();
+          throw e.unwrapIOException();
+        } finally {
+          if (parsedMessage != null) {
+            mergeFrom(parsedMessage);
+          }
+        }
+        return this;
+      }
+      private int bitField0_;
+
+      private com.google.protobuf.ByteString signature_ = com.google.protobuf.ByteString.EMPTY;
+      /**
+       * <pre>
+       * 	 The signature itself
+       * </pre>
+       *
+       * <code>required bytes signature = 1;</code>
+       */
+      public boolean hasSignature() {
+        return ((bitField0_ & 0x00000001) == 0x00000001);
+      }
+      /**
+       * <pre>
+       * 	 The signature itself
+       * </pre>
+       *
+       * <code>required bytes signature = 1;</code>
+       */
+      public com.google.protobuf.ByteString getSignature() {
+        return signature_;
+      }
+      /**
+       * <pre>
+       * 	 The signature itself
+       * </pre>
+       *
+       * <code>required bytes signature = 1;</code>
+       */
+      public Builder setSignature(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  bitField0_ |= 0x00000001;
+        signature_ = value;
+        onChanged();
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 The signature itself
+       * </pre>
+       *
+       * <code>required bytes signature = 1;</code>
+       */
+      public Builder clearSignature() {
+        bitField0_ = (bitField0_ & ~0x00000001);
+        signature_ = getDefaultInstance().getSignature();
+        onChanged();
+        return this;
+      }
+
+      private ch.epfl.dedis.proto.DarcProto.SignaturePath signaturepath_ = null;
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.SignaturePath, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder, ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder> signaturepathBuilder_;
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public boolean hasSignaturepath() {
+        return ((bitField0_ & 0x00000002) == 0x00000002);
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.SignaturePath getSignaturepath() {
+        if (signaturepathBuilder_ == null) {
+          return signaturepath_ == null ? ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
+        } else {
+          return signaturepathBuilder_.getMessage();
+        }
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public Builder setSignaturepath(ch.epfl.dedis.proto.DarcProto.SignaturePath value) {
+        if (signaturepathBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          signaturepath_ = value;
+          onChanged();
+        } else {
+          signaturepathBuilder_.setMessage(value);
+        }
+        bitField0_ |= 0x00000002;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public Builder setSignaturepath(
+          ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder builderForValue) {
+        if (signaturepathBuilder_ == null) {
+          signaturepath_ = builderForValue.build();
+          onChanged();
+        } else {
+          signaturepathBuilder_.setMessage(builderForValue.build());
+        }
+        bitField0_ |= 0x00000002;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public Builder mergeSignaturepath(ch.epfl.dedis.proto.DarcProto.SignaturePath value) {
+        if (signaturepathBuilder_ == null) {
+          if (((bitField0_ & 0x00000002) == 0x00000002) &&
+              signaturepath_ != null &&
+              signaturepath_ != ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance()) {
+            signaturepath_ =
+              ch.epfl.dedis.proto.DarcProto.SignaturePath.newBuilder(signaturepath_).mergeFrom(value).buildPartial();
+          } else {
+            signaturepath_ = value;
+          }
+          onChanged();
+        } else {
+          signaturepathBuilder_.mergeFrom(value);
+        }
+        bitField0_ |= 0x00000002;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public Builder clearSignaturepath() {
+        if (signaturepathBuilder_ == null) {
+          signaturepath_ = null;
+          onChanged();
+        } else {
+          signaturepathBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000002);
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder getSignaturepathBuilder() {
+        bitField0_ |= 0x00000002;
+        onChanged();
+        return getSignaturepathFieldBuilder().getBuilder();
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder getSignaturepathOrBuilder() {
+        if (signaturepathBuilder_ != null) {
+          return signaturepathBuilder_.getMessageOrBuilder();
+        } else {
+          return signaturepath_ == null ?
+              ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance() : signaturepath_;
+        }
+      }
+      /**
+       * <pre>
+       * 	 Represents the path to get up to information to be able to verify this signature
+       * </pre>
+       *
+       * <code>required .SignaturePath signaturepath = 2;</code>
+       */
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.SignaturePath, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder, ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder> 
+          getSignaturepathFieldBuilder() {
+        if (signaturepathBuilder_ == null) {
+          signaturepathBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.SignaturePath, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder, ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder>(
+                  getSignaturepath(),
+                  getParentForChildren(),
+                  isClean());
+          signaturepath_ = null;
+        }
+        return signaturepathBuilder_;
+      }
+      public final Builder setUnknownFields(
+          final com.google.protobuf.UnknownFieldSet unknownFields) {
+        return super.setUnknownFields(unknownFields);
+      }
+
+      public final Builder mergeUnknownFields(
+          final com.google.protobuf.UnknownFieldSet unknownFields) {
+        return super.mergeUnknownFields(unknownFields);
+      }
+
+
+      // @@protoc_insertion_point(builder_scope:Signature)
+    }
+
+    // @@protoc_insertion_point(class_scope:Signature)
+    private static final ch.epfl.dedis.proto.DarcProto.Signature DEFAULT_INSTANCE;
+    static {
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.Signature();
+    }
+
+    public static ch.epfl.dedis.proto.DarcProto.Signature getDefaultInstance() {
+      return DEFAULT_INSTANCE;
+    }
+
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<Signature>
+        PARSER = new com.google.protobuf.AbstractParser<Signature>() {
+      public Signature parsePartialFrom(
+          com.google.protobuf.CodedInputStream input,
+          com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+          throws com.google.protobuf.InvalidProtocolBufferException {
+          return new Signature(input, extensionRegistry);
+      }
+    };
+
+    public static com.google.protobuf.Parser<Signature> parser() {
+      return PARSER;
+    }
+
+    @java.lang.Override
+    public com.google.protobuf.Parser<Signature> getParserForType() {
+      return PARSER;
+    }
+
+    public ch.epfl.dedis.proto.DarcProto.Signature getDefaultInstanceForType() {
+      return DEFAULT_INSTANCE;
+    }
+
+  }
+
+  public interface SignaturePathOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:SignaturePath)
+      com.google.protobuf.MessageOrBuilder {
+
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> 
+        getDarcsList();
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.Darc getDarcs(int index);
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    int getDarcsCount();
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    java.util.List<? extends ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
+        getDarcsOrBuilderList();
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.DarcOrBuilder getDarcsOrBuilder(
+        int index);
+
+    /**
+     * <pre>
+     * 	 the Idenity (public key or another Darc) of the signer
+     * </pre>
+     *
+     * <code>required .Identity signer = 2;</code>
+     */
+    boolean hasSigner();
+    /**
+     * <pre>
+     * 	 the Idenity (public key or another Darc) of the signer
+     * </pre>
+     *
+     * <code>required .Identity signer = 2;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.Identity getSigner();
+    /**
+     * <pre>
+     * 	 the Idenity (public key or another Darc) of the signer
+     * </pre>
+     *
+     * <code>required .Identity signer = 2;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder getSignerOrBuilder();
+
+    /**
+     * <pre>
+     * 	 Is the signer Owner of a Darc or an user
+     * </pre>
+     *
+     * <code>required sint32 role = 3;</code>
+     */
+    boolean hasRole();
+    /**
+     * <pre>
+     * 	 Is the signer Owner of a Darc or an user
+     * </pre>
+     *
+     * <code>required sint32 role = 3;</code>
+     */
+    int getRole();
+  }
+  /**
+   * <pre>
+   * SignaturePath is a struct that holds information necessary for signature verification
+   * </pre>
+   *
+   * Protobuf type {@code SignaturePath}
+   */
+  public  static final class SignaturePath extends
+      com.google.protobuf.GeneratedMessageV3 implements
+      // @@protoc_insertion_point(message_implements:SignaturePath)
+      SignaturePathOrBuilder {
+  private static final long serialVersionUID = 0L;
+    // Use SignaturePath.newBuilder() to construct.
+    private SignaturePath(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+      super(builder);
+    }
+    private SignaturePath() {
+      darcs_ = java.util.Collections.emptyList();
+      role_ = 0;
+    }
+
+    @java.lang.Override
+    public final com.google.protobuf.UnknownFieldSet
+    getUnknownFields() {
+      return this.unknownFields;
+    }
+    private SignaturePath(
+        com.google.protobuf.CodedInputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      this();
+      int mutable_bitField0_ = 0;
+      com.google.protobuf.UnknownFieldSet.Builder unknownFields =
+          com.google.protobuf.UnknownFieldSet.newBuilder();
+      try {
+        boolean done = false;
+        while (!done) {
+          int tag = input.readTag();
+          switch (tag) {
+            case 0:
+              done = true;
+              break;
+            default: {
+              if (!parseUnknownField(
+                  input, unknownFields, extensionRegistry, tag)) {
+                done = true;
+              }
+              break;
+            }
+            case 10: {
+              if (!((mutable_bitField0_ & 0x00000001) == 0x00000001)) {
+                darcs_ = new java.util.ArrayList<ch.epfl.dedis.proto.DarcProto.Darc>();
+                mutable_bitField0_ |= 0x00000001;
+              }
+              darcs_.add(
+                  input.readMessage(ch.epfl.dedis.proto.DarcProto.Darc.PARSER, extensionRegistry));
+              break;
+            }
+            case 18: {
+              ch.epfl.dedis.proto.DarcProto.Identity.Builder subBuilder = null;
+              if (((bitField0_ & 0x00000001) == 0x00000001)) {
+                subBuilder = signer_.toBuilder();
+              }
+              signer_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.Identity.PARSER, extensionRegistry);
+              if (subBuilder != null) {
+                subBuilder.mergeFrom(signer_);
+                signer_ = subBuilder.buildPartial();
+              }
+              bitField0_ |= 0x00000001;
+              break;
+            }
+            case 24: {
+              bitField0_ |= 0x00000002;
+              role_ = input.readSInt32();
+              break;
+            }
+          }
+        }
+      } catch (com.google.protobuf.InvalidProtocolBufferException e) {
+        throw e.setUnfinishedMessage(this);
+      } catch (java.io.IOException e) {
+        throw new com.google.protobuf.InvalidProtocolBufferException(
+            e).setUnfinishedMessage(this);
+      } finally {
+        if (((mutable_bitField0_ & 0x00000001) == 0x00000001)) {
+          darcs_ = java.util.Collections.unmodifiableList(darcs_);
+        }
+        this.unknownFields = unknownFields.build();
+        makeExtensionsImmutable();
+      }
+    }
+    public static final com.google.protobuf.Descriptors.Descriptor
+        getDescriptor() {
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_descriptor;
+    }
+
+    protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+        internalGetFieldAccessorTable() {
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_fieldAccessorTable
+          .ensureFieldAccessorsInitialized(
+              ch.epfl.dedis.proto.DarcProto.SignaturePath.class, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder.class);
+    }
+
+    private int bitField0_;
+    public static final int DARCS_FIELD_NUMBER = 1;
+    private java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> darcs_;
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    public java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> getDarcsList() {
+      return darcs_;
+    }
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    public java.util.List<? extends ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
+        getDarcsOrBuilderList() {
+      return darcs_;
+    }
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    public int getDarcsCount() {
+      return darcs_.size();
+    }
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.Darc getDarcs(int index) {
+      return darcs_.get(index);
+    }
+    /**
+     * <pre>
+     * 	 Darc(s) that justify the right of the signer to push a new Darc
+     * </pre>
+     *
+     * <code>repeated .Darc darcs = 1;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.DarcOrBuilder getDarcsOrBuilder(
+        int index) {
+      return darcs_.get(index);
+    }
+
+    public static final int SIGNER_FIELD_NUMBER = 2;
+    private ch.epfl.dedis.proto.DarcProto.Identity signer_;
+    /**
+     * <pre>
+     * 	 the Idenity (public key or another Darc) of the signer
+     * </pre>
+     *
+     * <code>required .Identity signer = 2;</code>
+     */
+    public boolean hasSigner() {
+      return ((bitField0_ & 0x00000001) == 0x00000001);
+    }
+    /**
+     * <pre>
+     * 	 the Idenity (public key or another Darc) of the signer
+     * </pre>
+     *
+     * <code>required .Identity signer = 2;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.Identity getSigner() {
+      return signer_ == null ? ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
+    }
+    /**
+     * <pre>
+     * 	 the Idenity (public key or another Darc) of the signer
+     * </pre>
+     *
+     * <code>required .Identity signer = 2;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder getSignerOrBuilder() {
+      return signer_ == null ? ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
+    }
+
+    public static final int ROLE_FIELD_NUMBER = 3;
+    private int role_;
+    /**
+     * <pre>
+     * 	 Is the signer Owner of a Darc or an user
+     * </pre>
+     *
+     * <code>required sint32 role = 3;</code>
+     */
+    public boolean hasRole() {
+      return ((bitField0_ & 0x00000002) == 0x00000002);
+    }
+    /**
+     * <pre>
+     * 	 Is the signer Owner of a Darc or an user
+     * </pre>
+     *
+     * <code>required sint32 role = 3;</code>
+     */
+    public int getRole() {
+      return role_;
+    }
+
+    private byte memoizedIsInitialized = -1;
+    public final boolean isInitialized() {
+      byte isInitialized = memoizedIsInitialized;
+      if (isInitialized == 1) return true;
+      if (isInitialized == 0) return false;
+
+      if (!hasSigner()) {
+        memoizedIsInitialized = 0;
+        return false;
+      }
+      if (!hasRole()) {
+        memoizedIsInitialized = 0;
+        return false;
+      }
+      for (int i = 0; i < getDarcsCount(); i++) {
+        if (!getDarcs(i).isInitialized()) {
+          memoizedIsInitialized = 0;
+          return false;
+        }
+      }
+      if (!getSigner().isInitialized()) {
+        memoizedIsInitialized = 0;
+        return false;
+      }
+      memoizedIsInitialized = 1;
+      return true;
+    }
+
+    public void writeTo(com.google.protobuf.CodedOutputStream output)
+                        throws java.io.IOException {
+      for (int i = 0; i < darcs_.size(); i++) {
+        output.writeMessage(1, darcs_.get(i));
+      }
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        output.writeMessage(2, getSigner());
+      }
+      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+        output.writeSInt32(3, role_);
+      }
+      unknownFields.writeTo(output);
+    }
+
+    public int getSerializedSize() {
+      int size = memoizedSize;
+      if (size != -1) return size;
+
+      size = 0;
+      for (int i = 0; i < darcs_.size(); i++) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(1, darcs_.get(i));
+      }
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(2, getSigner());
+      }
+      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeSInt32Size(3, role_);
+      }
+      size += unknownFields.getSerializedSize();
+      memoizedSize = size;
+      return size;
+    }
+
+    @java.lang.Override
+    public boolean equals(final java.lang.Object obj) {
+      if (obj == this) {
+       return true;
+      }
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignaturePath)) {
+        return super.equals(obj);
+      }
+      ch.epfl.dedis.proto.DarcProto.SignaturePath other = (ch.epfl.dedis.proto.DarcProto.SignaturePath) obj;
+
+      boolean result = true;
+      result = result && getDarcsList()
+          .equals(other.getDarcsList());
+      result = result && (hasSigner() == other.hasSigner());
+      if (hasSigner()) {
+        result = result && getSigner()
+            .equals(other.getSigner());
+      }
+      result = result && (hasRole() == other.hasRole());
+      if (hasRole()) {
+        result = result && (getRole()
+            == other.getRole());
+      }
+      result = result && unknownFields.equals(other.unknownFields);
+      return result;
+    }
+
+    @java.lang.Override
+    public int hashCode() {
+      if (memoizedHashCode != 0) {
+        return memoizedHashCode;
+      }
+      int hash = 41;
+      hash = (19 * hash) + getDescriptor().hashCode();
+      if (getDarcsCount() > 0) {
+        hash = (37 * hash) + DARCS_FIELD_NUMBER;
+        hash = (53 * hash) + getDarcsList().hashCode();
+      }
+      if (hasSigner()) {
+        hash = (37 * hash) + SIGNER_FIELD_NUMBER;
+        hash = (53 * hash) + getSigner().hashCode();
+      }
+      if (hasRole()) {
+        hash = (37 * hash) + ROLE_FIELD_NUMBER;
+        hash = (53 * hash) + getRole();
+      }
+      hash = (29 * hash) + unknownFields.hashCode();
+      memoizedHashCode = hash;
+      return hash;
+    }
+
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        java.nio.ByteBuffer data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        java.nio.ByteBuffer data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        com.google.protobuf.ByteString data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        com.google.protobuf.ByteString data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(byte[] data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        byte[] data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(java.io.InputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        java.io.InputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseDelimitedFrom(java.io.InputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseDelimitedWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseDelimitedFrom(
+        java.io.InputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        com.google.protobuf.CodedInputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath parseFrom(
+        com.google.protobuf.CodedInputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input, extensionRegistry);
+    }
+
+    public Builder newBuilderForType() { return newBuilder(); }
+    public static Builder newBuilder() {
+      return DEFAULT_INSTANCE.toBuilder();
+    }
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignaturePath prototype) {
+      return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
+    }
+    public Builder toBuilder() {
+      return this == DEFAULT_INSTANCE
+          ? new Builder() : new Builder().mergeFrom(this);
+    }
+
+    @java.lang.Override
+    protected Builder newBuilderForType(
+        com.google.protobuf.GeneratedMessageV3.BuilderParent parent) {
+      Builder builder = new Builder(parent);
+      return builder;
+    }
+    /**
+     * <pre>
+     * SignaturePath is a struct that holds information necessary for signature verification
+     * </pre>
+     *
+     * Protobuf type {@code SignaturePath}
+     */
+    public static final class Builder extends
+        com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
+        // @@protoc_insertion_point(builder_implements:SignaturePath)
+        ch.epfl.dedis.proto.DarcProto.SignaturePathOrBuilder {
+      public static final com.google.protobuf.Descriptors.Descriptor
+          getDescriptor() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_descriptor;
+      }
+
+      protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+          internalGetFieldAccessorTable() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_fieldAccessorTable
+            .ensureFieldAccessorsInitialized(
+                ch.epfl.dedis.proto.DarcProto.SignaturePath.class, ch.epfl.dedis.proto.DarcProto.SignaturePath.Builder.class);
+      }
+
+      // Construct using ch.epfl.dedis.proto.DarcProto.SignaturePath.newBuilder()
+      private Builder() {
+        maybeForceBuilderInitialization();
+      }
+
+      private Builder(
+          com.google.protobuf.GeneratedMessageV3.BuilderParent parent) {
+        super(parent);
+        maybeForceBuilderInitialization();
+      }
+      private void maybeForceBuilderInitialization() {
+        if (com.google.protobuf.GeneratedMessageV3
+                .alwaysUseFieldBuilders) {
+          getDarcsFieldBuilder();
+          getSignerFieldBuilder();
+        }
+      }
+      public Builder clear() {
+        super.clear();
+        if (darcsBuilder_ == null) {
+          darcs_ = java.util.Collections.emptyList();
+          bitField0_ = (bitField0_ & ~0x00000001);
+        } else {
+          darcsBuilder_.clear();
+        }
+        if (signerBuilder_ == null) {
+          signer_ = null;
+        } else {
+          signerBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000002);
+        role_ = 0;
+        bitField0_ = (bitField0_ & ~0x00000004);
+        return this;
+      }
+
+      public com.google.protobuf.Descriptors.Descriptor
+          getDescriptorForType() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignaturePath_descriptor;
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.SignaturePath getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance();
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.SignaturePath build() {
+        ch.epfl.dedis.proto.DarcProto.SignaturePath result = buildPartial();
+        if (!result.isInitialized()) {
+          throw newUninitializedMessageException(result);
+        }
+        return result;
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.SignaturePath buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.SignaturePath result = new ch.epfl.dedis.proto.DarcProto.SignaturePath(this);
+        int from_bitField0_ = bitField0_;
+        int to_bitField0_ = 0;
+        if (darcsBuilder_ == null) {
+          if (((bitField0_ & 0x00000001) == 0x00000001)) {
+            darcs_ = java.util.Collections.unmodifiableList(darcs_);
+            bitField0_ = (bitField0_ & ~0x00000001);
+          }
+          result.darcs_ = darcs_;
+        } else {
+          result.darcs_ = darcsBuilder_.build();
+        }
+        if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
+          to_bitField0_ |= 0x00000001;
+        }
+        if (signerBuilder_ == null) {
+          result.signer_ = signer_;
+        } else {
+          result.signer_ = signerBuilder_.build();
+        }
+        if (((from_bitField0_ & 0x00000004) == 0x00000004)) {
+          to_bitField0_ |= 0x00000002;
+        }
+        result.role_ = role_;
+        result.bitField0_ = to_bitField0_;
+        onBuilt();
+        return result;
+      }
+
+      public Builder clone() {
+        return (Builder) super.clone();
+      }
+      public Builder setField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          java.lang.Object value) {
+        return (Builder) super.setField(field, value);
+      }
+      public Builder clearField(
+          com.google.protobuf.Descriptors.FieldDescriptor field) {
+        return (Builder) super.clearField(field);
+      }
+      public Builder clearOneof(
+          com.google.protobuf.Descriptors.OneofDescriptor oneof) {
+        return (Builder) super.clearOneof(oneof);
+      }
+      public Builder setRepeatedField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          int index, java.lang.Object value) {
+        return (Builder) super.setRepeatedField(field, index, value);
+      }
+      public Builder addRepeatedField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          java.lang.Object value) {
+        return (Builder) super.addRepeatedField(field, value);
+      }
+      public Builder mergeFrom(com.google.protobuf.Message other) {
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignaturePath) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignaturePath)other);
+        } else {
+          super.mergeFrom(other);
+          return this;
+        }
+      }
+
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignaturePath other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.SignaturePath.getDefaultInstance()) return this;
+        if (darcsBuilder_ == null) {
+          if (!other.darcs_.isEmpty()) {
+            if (darcs_.isEmpty()) {
+              darcs_ = other.darcs_;
+              bitField0_ = (bitField0_ & ~0x00000001);
+            } else {
+              ensureDarcsIsMutable();
+              darcs_.addAll(other.darcs_);
+            }
+            onChanged();
+          }
+        } else {
+          if (!other.darcs_.isEmpty()) {
+            if (darcsBuilder_.isEmpty()) {
+              darcsBuilder_.dispose();
+              darcsBuilder_ = null;
+              darcs_ = other.darcs_;
+              bitField0_ = (bitField0_ & ~0x00000001);
+              darcsBuilder_ = 
+                com.google.protobuf.GeneratedMessageV3.alwaysUseFieldBuilders ?
+                   getDarcsFieldBuilder() : null;
+            } else {
+              darcsBuilder_.addAllMessages(other.darcs_);
+            }
+          }
+        }
+        if (other.hasSigner()) {
+          mergeSigner(other.getSigner());
+        }
+        if (other.hasRole()) {
+          setRole(other.getRole());
+        }
+        this.mergeUnknownFields(other.unknownFields);
+        onChanged();
+        return this;
+      }
+
+      public final boolean isInitialized() {
+        if (!hasSigner()) {
+          return false;
+        }
+        if (!hasRole()) {
+          return false;
+        }
+        for (int i = 0; i < getDarcsCount(); i++) {
+          if (!getDarcs(i).isInitialized()) {
+            return false;
+          }
+        }
+        if (!getSigner().isInitialized()) {
+          return false;
+        }
+        return true;
+      }
+
+      public Builder mergeFrom(
+          com.google.protobuf.CodedInputStream input,
+          com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+          throws java.io.IOException {
+        ch.epfl.dedis.proto.DarcProto.SignaturePath parsedMessage = null;
+        try {
+          parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
+        } catch (com.google.protobuf.InvalidProtocolBufferException e) {
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignaturePath) e.getUnfinishedMessage();
+          throw e.unwrapIOException();
+        } finally {
+          if (parsedMessage != null) {
+            mergeFrom(parsedMessage);
+          }
+        }
+        return this;
+      }
+      private int bitField0_;
+
+      private java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> darcs_ =
+        java.util.Collections.emptyList();
+      private void ensureDarcsIsMutable() {
+        if (!((bitField0_ & 0x00000001) == 0x00000001)) {
+          darcs_ = new java.util.ArrayList<ch.epfl.dedis.proto.DarcProto.Darc>(darcs_);
+          bitField0_ |= 0x00000001;
+         }
+      }
+
+      private com.google.protobuf.RepeatedFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.Darc, ch.epfl.dedis.proto.DarcProto.Darc.Builder, ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> darcsBuilder_;
+
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public java.util.List<ch.epfl.dedis.proto.DarcProto.Darc> getDarcsList() {
+        if (darcsBuilder_ == null) {
+          return java.util.Collections.unmodifiableList(darcs_);
+        } else {
+          return darcsBuilder_.getMessageList();
+        }
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public int getDarcsCount() {
+        if (darcsBuilder_ == null) {
+          return darcs_.size();
+        } else {
+          return darcsBuilder_.getCount();
+        }
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.Darc getDarcs(int index) {
+        if (darcsBuilder_ == null) {
+          return darcs_.get(index);
+        } else {
+          return darcsBuilder_.getMessage(index);
+        }
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder setDarcs(
+          int index, ch.epfl.dedis.proto.DarcProto.Darc value) {
+        if (darcsBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          ensureDarcsIsMutable();
+          darcs_.set(index, value);
+          onChanged();
+        } else {
+          darcsBuilder_.setMessage(index, value);
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder setDarcs(
+          int index, ch.epfl.dedis.proto.DarcProto.Darc.Builder builderForValue) {
+        if (darcsBuilder_ == null) {
+          ensureDarcsIsMutable();
+          darcs_.set(index, builderForValue.build());
+          onChanged();
+        } else {
+          darcsBuilder_.setMessage(index, builderForValue.build());
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder addDarcs(ch.epfl.dedis.proto.DarcProto.Darc value) {
+        if (darcsBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          ensureDarcsIsMutable();
+          darcs_.add(value);
+          onChanged();
+        } else {
+          darcsBuilder_.addMessage(value);
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder addDarcs(
+          int index, ch.epfl.dedis.proto.DarcProto.Darc value) {
+        if (darcsBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          ensureDarcsIsMutable();
+          darcs_.add(index, value);
+          onChanged();
+        } else {
+          darcsBuilder_.addMessage(index, value);
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder addDarcs(
+          ch.epfl.dedis.proto.DarcProto.Darc.Builder builderForValue) {
+        if (darcsBuilder_ == null) {
+          ensureDarcsIsMutable();
+          darcs_.add(builderForValue.build());
+          onChanged();
+        } else {
+          darcsBuilder_.addMessage(builderForValue.build());
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder addDarcs(
+          int index, ch.epfl.dedis.proto.DarcProto.Darc.Builder builderForValue) {
+        if (darcsBuilder_ == null) {
+          ensureDarcsIsMutable();
+          darcs_.add(index, builderForValue.build());
+          onChanged();
+        } else {
+          darcsBuilder_.addMessage(index, builderForValue.build());
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder addAllDarcs(
+          java.lang.Iterable<? extends ch.epfl.dedis.proto.DarcProto.Darc> values) {
+        if (darcsBuilder_ == null) {
+          ensureDarcsIsMutable();
+          com.google.protobuf.AbstractMessageLite.Builder.addAll(
+              values, darcs_);
+          onChanged();
+        } else {
+          darcsBuilder_.addAllMessages(values);
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder clearDarcs() {
+        if (darcsBuilder_ == null) {
+          darcs_ = java.util.Collections.emptyList();
+          bitField0_ = (bitField0_ & ~0x00000001);
+          onChanged();
+        } else {
+          darcsBuilder_.clear();
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public Builder removeDarcs(int index) {
+        if (darcsBuilder_ == null) {
+          ensureDarcsIsMutable();
+          darcs_.remove(index);
+          onChanged();
+        } else {
+          darcsBuilder_.remove(index);
+        }
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.Darc.Builder getDarcsBuilder(
+          int index) {
+        return getDarcsFieldBuilder().getBuilder(index);
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.DarcOrBuilder getDarcsOrBuilder(
+          int index) {
+        if (darcsBuilder_ == null) {
+          return darcs_.get(index);  } else {
+          return darcsBuilder_.getMessageOrBuilder(index);
+        }
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public java.util.List<? extends ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
+           getDarcsOrBuilderList() {
+        if (darcsBuilder_ != null) {
+          return darcsBuilder_.getMessageOrBuilderList();
+        } else {
+          return java.util.Collections.unmodifiableList(darcs_);
+        }
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.Darc.Builder addDarcsBuilder() {
+        return getDarcsFieldBuilder().addBuilder(
+            ch.epfl.dedis.proto.DarcProto.Darc.getDefaultInstance());
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.Darc.Builder addDarcsBuilder(
+          int index) {
+        return getDarcsFieldBuilder().addBuilder(
+            index, ch.epfl.dedis.proto.DarcProto.Darc.getDefaultInstance());
+      }
+      /**
+       * <pre>
+       * 	 Darc(s) that justify the right of the signer to push a new Darc
+       * </pre>
+       *
+       * <code>repeated .Darc darcs = 1;</code>
+       */
+      public java.util.List<ch.epfl.dedis.proto.DarcProto.Darc.Builder> 
+           getDarcsBuilderList() {
+        return getDarcsFieldBuilder().getBuilderList();
+      }
+      private com.google.protobuf.RepeatedFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.Darc, ch.epfl.dedis.proto.DarcProto.Darc.Builder, ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
+          getDarcsFieldBuilder() {
+        if (darcsBuilder_ == null) {
+          darcsBuilder_ = new com.google.protobuf.RepeatedFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.Darc, ch.epfl.dedis.proto.DarcProto.Darc.Builder, ch.epfl.dedis.proto.DarcProto.DarcOrBuilder>(
+                  darcs_,
+                  ((bitField0_ & 0x00000001) == 0x00000001),
+                  getParentForChildren(),
+                  isClean());
+          darcs_ = null;
+        }
+        return darcsBuilder_;
+      }
+
+      private ch.epfl.dedis.proto.DarcProto.Identity signer_ = null;
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.Identity, ch.epfl.dedis.proto.DarcProto.Identity.Builder, ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder> signerBuilder_;
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public boolean hasSigner() {
+        return ((bitField0_ & 0x00000002) == 0x00000002);
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.Identity getSigner() {
+        if (signerBuilder_ == null) {
+          return signer_ == null ? ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
+        } else {
+          return signerBuilder_.getMessage();
+        }
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public Builder setSigner(ch.epfl.dedis.proto.DarcProto.Identity value) {
+        if (signerBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          signer_ = value;
+          onChanged();
+        } else {
+          signerBuilder_.setMessage(value);
+        }
+        bitField0_ |= 0x00000002;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public Builder setSigner(
+          ch.epfl.dedis.proto.DarcProto.Identity.Builder builderForValue) {
+        if (signerBuilder_ == null) {
+          signer_ = builderForValue.build();
+          onChanged();
+        } else {
+          signerBuilder_.setMessage(builderForValue.build());
+        }
+        bitField0_ |= 0x00000002;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public Builder mergeSigner(ch.epfl.dedis.proto.DarcProto.Identity value) {
+        if (signerBuilder_ == null) {
+          if (((bitField0_ & 0x00000002) == 0x00000002) &&
+              signer_ != null &&
+              signer_ != ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance()) {
+            signer_ =
+              ch.epfl.dedis.proto.DarcProto.Identity.newBuilder(signer_).mergeFrom(value).buildPartial();
+          } else {
+            signer_ = value;
+          }
+          onChanged();
+        } else {
+          signerBuilder_.mergeFrom(value);
+        }
+        bitField0_ |= 0x00000002;
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public Builder clearSigner() {
+        if (signerBuilder_ == null) {
+          signer_ = null;
+          onChanged();
+        } else {
+          signerBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000002);
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.Identity.Builder getSignerBuilder() {
+        bitField0_ |= 0x00000002;
+        onChanged();
+        return getSignerFieldBuilder().getBuilder();
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder getSignerOrBuilder() {
+        if (signerBuilder_ != null) {
+          return signerBuilder_.getMessageOrBuilder();
+        } else {
+          return signer_ == null ?
+              ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
+        }
+      }
+      /**
+       * <pre>
+       * 	 the Idenity (public key or another Darc) of the signer
+       * </pre>
+       *
+       * <code>required .Identity signer = 2;</code>
+       */
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.Identity, ch.epfl.dedis.proto.DarcProto.Identity.Builder, ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder> 
+          getSignerFieldBuilder() {
+        if (signerBuilder_ == null) {
+          signerBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.Identity, ch.epfl.dedis.proto.DarcProto.Identity.Builder, ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder>(
+                  getSigner(),
+                  getParentForChildren(),
+                  isClean());
+          signer_ = null;
+        }
+        return signerBuilder_;
+      }
+
+      private int role_ ;
+      /**
+       * <pre>
+       * 	 Is the signer Owner of a Darc or an user
+       * </pre>
+       *
+       * <code>required sint32 role = 3;</code>
+       */
+      public boolean hasRole() {
+        return ((bitField0_ & 0x00000004) == 0x00000004);
+      }
+      /**
+       * <pre>
+       * 	 Is the signer Owner of a Darc or an user
+       * </pre>
+       *
+       * <code>required sint32 role = 3;</code>
+       */
+      public int getRole() {
+        return role_;
+      }
+      /**
+       * <pre>
+       * 	 Is the signer Owner of a Darc or an user
+       * </pre>
+       *
+       * <code>required sint32 role = 3;</code>
+       */
+      public Builder setRole(int value) {
+        bitField0_ |= 0x00000004;
+        role_ = value;
+        onChanged();
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Is the signer Owner of a Darc or an user
+       * </pre>
+       *
+       * <code>required sint32 role = 3;</code>
+       */
+      public Builder clearRole() {
+        bitField0_ = (bitField0_ & ~0x00000004);
+        role_ = 0;
+        onChanged();
+        return this;
+      }
+      public final Builder setUnknownFields(
+          final com.google.protobuf.UnknownFieldSet unknownFields) {
+        return super.setUnknownFields(unknownFields);
+      }
+
+      public final Builder mergeUnknownFields(
+          final com.google.protobuf.UnknownFieldSet unknownFields) {
+        return super.mergeUnknownFields(unknownFields);
+      }
+
+
+      // @@protoc_insertion_point(builder_scope:SignaturePath)
+    }
+
+    // @@protoc_insertion_point(class_scope:SignaturePath)
+    private static final ch.epfl.dedis.proto.DarcProto.SignaturePath DEFAULT_INSTANCE;
+    static {
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignaturePath();
+    }
+
+    public static ch.epfl.dedis.proto.DarcProto.SignaturePath getDefaultInstance() {
+      return DEFAULT_INSTANCE;
+    }
+
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignaturePath>
+        PARSER = new com.google.protobuf.AbstractParser<SignaturePath>() {
+      public SignaturePath parsePartialFrom(
+          com.google.protobuf.CodedInputStream input,
+          com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+          throws com.google.protobuf.InvalidProtocolBufferException {
+          return new SignaturePath(input, extensionRegistry);
+      }
+    };
+
+    public static com.google.protobuf.Parser<SignaturePath> parser() {
+      return PARSER;
+    }
+
+    @java.lang.Override
+    public com.google.protobuf.Parser<SignaturePath> getParserForType() {
+      return PARSER;
+    }
+
+    public ch.epfl.dedis.proto.DarcProto.SignaturePath getDefaultInstanceForType() {
+      return DEFAULT_INSTANCE;
+    }
+
+  }
+
+  public interface SignerOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:Signer)
+      com.google.protobuf.MessageOrBuilder {
+
+    /**
+     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     */
+    boolean hasEd25519();
+    /**
+     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignerEd25519 getEd25519();
+    /**
+     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder getEd25519OrBuilder();
+
+    /**
+     * <code>optional .SignerX509EC x509ec = 2;</code>
+     */
+    boolean hasX509Ec();
+    /**
+     * <code>optional .SignerX509EC x509ec = 2;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignerX509EC getX509Ec();
+    /**
+     * <code>optional .SignerX509EC x509ec = 2;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder getX509EcOrBuilder();
+
+    /**
+     * <code>optional .SignerX509Chain x509chain = 3;</code>
+     */
+    boolean hasX509Chain();
+    /**
+     * <code>optional .SignerX509Chain x509chain = 3;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignerX509Chain getX509Chain();
+    /**
+     * <code>optional .SignerX509Chain x509chain = 3;</code>
+     */
+    ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder getX509ChainOrBuilder();
+  }
+  /**
+   * <pre>
+   * Signer is a generic structure that can hold different types of signers
+   * </pre>
+   *
+   * Protobuf type {@code Signer}
+   */
+  public  static final class Signer extends
+      com.google.protobuf.GeneratedMessageV3 implements
+      // @@protoc_insertion_point(message_implements:Signer)
+      SignerOrBuilder {
+  private static final long serialVersionUID = 0L;
+    // Use Signer.newBuilder() to construct.
+    private Signer(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+      super(builder);
+    }
+    private Signer() {
+    }
+
+    @java.lang.Override
+    public final com.google.protobuf.UnknownFieldSet
+    getUnknownFields() {
+      return this.unknownFields;
+    }
+    private Signer(
+        com.google.protobuf.CodedInputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      this();
+      int mutable_bitField0_ = 0;
+      com.google.protobuf.UnknownFieldSet.Builder unknownFields =
+          com.google.protobuf.UnknownFieldSet.newBuilder();
+      try {
+        boolean done = false;
+        while (!done) {
+          int tag = input.readTag();
+          switch (tag) {
+            case 0:
+              done = true;
+              break;
+            default: {
+              if (!parseUnknownField(
+                  input, unknownFields, extensionRegistry, tag)) {
+                done = true;
+              }
+              break;
+            }
+            case 10: {
+              ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder subBuilder = null;
+              if (((bitField0_ & 0x00000001) == 0x00000001)) {
+                subBuilder = ed25519_.toBuilder();
+              }
+              ed25519_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignerEd25519.PARSER, extensionRegistry);
+              if (subBuilder != null) {
+                subBuilder.mergeFrom(ed25519_);
+                ed25519_ = subBuilder.buildPartial();
+              }
+              bitField0_ |= 0x00000001;
+              break;
+            }
+            case 18: {
+              ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder subBuilder = null;
+              if (((bitField0_ & 0x00000002) == 0x00000002)) {
+                subBuilder = x509Ec_.toBuilder();
+              }
+              x509Ec_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignerX509EC.PARSER, extensionRegistry);
+              if (subBuilder != null) {
+                subBuilder.mergeFrom(x509Ec_);
+                x509Ec_ = subBuilder.buildPartial();
+              }
+              bitField0_ |= 0x00000002;
+              break;
+            }
+            case 26: {
+              ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder subBuilder = null;
+              if (((bitField0_ & 0x00000004) == 0x00000004)) {
+                subBuilder = x509Chain_.toBuilder();
+              }
+              x509Chain_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignerX509Chain.PARSER, extensionRegistry);
+              if (subBuilder != null) {
+                subBuilder.mergeFrom(x509Chain_);
+                x509Chain_ = subBuilder.buildPartial();
+              }
+              bitField0_ |= 0x00000004;
+              break;
+            }
+          }
+        }
+      } catch (com.google.protobuf.InvalidProtocolBufferException e) {
+        throw e.setUnfinishedMessage(this);
+      } catch (java.io.IOException e) {
+        throw new com.google.protobuf.InvalidProtocolBufferException(
+            e).setUnfinishedMessage(this);
+      } finally {
+        this.unknownFields = unknownFields.build();
+        makeExtensionsImmutable();
+      }
+    }
+    public static final com.google.protobuf.Descriptors.Descriptor
+        getDescriptor() {
+      return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_descriptor;
+    }
+
+    protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+        internalGetFieldAccessorTable() {
+      return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_fieldAccessorTable
+          .ensureFieldAccessorsInitialized(
+              ch.epfl.dedis.proto.DarcProto.Signer.class, ch.epfl.dedis.proto.DarcProto.Signer.Builder.class);
+    }
+
+    private int bitField0_;
+    public static final int ED25519_FIELD_NUMBER = 1;
+    private ch.epfl.dedis.proto.DarcProto.SignerEd25519 ed25519_;
+    /**
+     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     */
+    public boolean hasEd25519() {
+      return ((bitField0_ & 0x00000001) == 0x00000001);
+    }
+    /**
+     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getEd25519() {
+      return ed25519_ == null ? ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
+    }
+    /**
+     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder getEd25519OrBuilder() {
+      return ed25519_ == null ? ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
+    }
+
+    public static final int X509EC_FIELD_NUMBER = 2;
+    private ch.epfl.dedis.proto.DarcProto.SignerX509EC x509Ec_;
+    /**
+     * <code>optional .SignerX509EC x509ec = 2;</code>
+     */
+    public boolean hasX509Ec() {
+      return ((bitField0_ & 0x00000002) == 0x00000002);
+    }
+    /**
+     * <code>optional .SignerX509EC x509ec = 2;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignerX509EC getX509Ec() {
+      return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
+    }
+    /**
+     * <code>optional .SignerX509EC x509ec = 2;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder getX509EcOrBuilder() {
+      return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
+    }
+
+    public static final int X509CHAIN_FIELD_NUMBER = 3;
+    private ch.epfl.dedis.proto.DarcProto.SignerX509Chain x509Chain_;
+    /**
+     * <code>optional .SignerX509Chain x509chain = 3;</code>
+     */
+    public boolean hasX509Chain() {
+      return ((bitField0_ & 0x00000004) == 0x00000004);
+    }
+    /**
+     * <code>optional .SignerX509Chain x509chain = 3;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignerX509Chain getX509Chain() {
+      return x509Chain_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance() : x509Chain_;
+    }
+    /**
+     * <code>optional .SignerX509Chain x509chain = 3;</code>
+     */
+    public ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder getX509ChainOrBuilder() {
+      return x509Chain_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance() : x509Chain_;
+    }
+
+    private byte memoizedIsInitialized = -1;
+    public final boolean isInitialized() {
+      byte isInitialized = memoizedIsInitialized;
+      if (isInitialized == 1) return true;
+      if (isInitialized == 0) return false;
+
+      if (hasEd25519()) {
+        if (!getEd25519().isInitialized()) {
+          memoizedIsInitialized = 0;
+          return false;
+        }
+      }
+      if (hasX509Ec()) {
+        if (!getX509Ec().isInitialized()) {
+          memoizedIsInitialized = 0;
+          return false;
+        }
+      }
+      if (hasX509Chain()) {
+        if (!getX509Chain().isInitialized()) {
+          memoizedIsInitialized = 0;
+          return false;
+        }
+      }
+      memoizedIsInitialized = 1;
+      return true;
+    }
+
+    public void writeTo(com.google.protobuf.CodedOutputStream output)
+                        throws java.io.IOException {
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        output.writeMessage(1, getEd25519());
+      }
+      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+        output.writeMessage(2, getX509Ec());
+      }
+      if (((bitField0_ & 0x00000004) == 0x00000004)) {
+        output.writeMessage(3, getX509Chain());
+      }
+      unknownFields.writeTo(output);
+    }
+
+    public int getSerializedSize() {
+      int size = memoizedSize;
+      if (size != -1) return size;
+
+      size = 0;
+      if (((bitField0_ & 0x00000001) == 0x00000001)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(1, getEd25519());
+      }
+      if (((bitField0_ & 0x00000002) == 0x00000002)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(2, getX509Ec());
+      }
+      if (((bitField0_ & 0x00000004) == 0x00000004)) {
+        size += com.google.protobuf.CodedOutputStream
+          .computeMessageSize(3, getX509Chain());
+      }
+      size += unknownFields.getSerializedSize();
+      memoizedSize = size;
+      return size;
+    }
+
+    @java.lang.Override
+    public boolean equals(final java.lang.Object obj) {
+      if (obj == this) {
+       return true;
+      }
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.Signer)) {
+        return super.equals(obj);
+      }
+      ch.epfl.dedis.proto.DarcProto.Signer other = (ch.epfl.dedis.proto.DarcProto.Signer) obj;
+
+      boolean result = true;
+      result = result && (hasEd25519() == other.hasEd25519());
+      if (hasEd25519()) {
+        result = result && getEd25519()
+            .equals(other.getEd25519());
+      }
+      result = result && (hasX509Ec() == other.hasX509Ec());
+      if (hasX509Ec()) {
+        result = result && getX509Ec()
+            .equals(other.getX509Ec());
+      }
+      result = result && (hasX509Chain() == other.hasX509Chain());
+      if (hasX509Chain()) {
+        result = result && getX509Chain()
+            .equals(other.getX509Chain());
+      }
+      result = result && unknownFields.equals(other.unknownFields);
+      return result;
+    }
+
+    @java.lang.Override
+    public int hashCode() {
+      if (memoizedHashCode != 0) {
+        return memoizedHashCode;
+      }
+      int hash = 41;
+      hash = (19 * hash) + getDescriptor().hashCode();
+      if (hasEd25519()) {
+        hash = (37 * hash) + ED25519_FIELD_NUMBER;
+        hash = (53 * hash) + getEd25519().hashCode();
+      }
+      if (hasX509Ec()) {
+        hash = (37 * hash) + X509EC_FIELD_NUMBER;
+        hash = (53 * hash) + getX509Ec().hashCode();
+      }
+      if (hasX509Chain()) {
+        hash = (37 * hash) + X509CHAIN_FIELD_NUMBER;
+        hash = (53 * hash) + getX509Chain().hashCode();
+      }
+      hash = (29 * hash) + unknownFields.hashCode();
+      memoizedHashCode = hash;
+      return hash;
+    }
+
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        java.nio.ByteBuffer data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        java.nio.ByteBuffer data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        com.google.protobuf.ByteString data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        com.google.protobuf.ByteString data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(byte[] data)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        byte[] data,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws com.google.protobuf.InvalidProtocolBufferException {
+      return PARSER.parseFrom(data, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(java.io.InputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        java.io.InputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseDelimitedFrom(java.io.InputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseDelimitedWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseDelimitedFrom(
+        java.io.InputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        com.google.protobuf.CodedInputStream input)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input);
+    }
+    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+        com.google.protobuf.CodedInputStream input,
+        com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+        throws java.io.IOException {
+      return com.google.protobuf.GeneratedMessageV3
+          .parseWithIOException(PARSER, input, extensionRegistry);
+    }
+
+    public Builder newBuilderForType() { return newBuilder(); }
+    public static Builder newBuilder() {
+      return DEFAULT_INSTANCE.toBuilder();
+    }
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.Signer prototype) {
+      return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
+    }
+    public Builder toBuilder() {
+      return this == DEFAULT_INSTANCE
+          ? new Builder() : new Builder().mergeFrom(this);
+    }
+
+    @java.lang.Override
+    protected Builder newBuilderForType(
+        com.google.protobuf.GeneratedMessageV3.BuilderParent parent) {
+      Builder builder = new Builder(parent);
+      return builder;
+    }
+    /**
+     * <pre>
+     * Signer is a generic structure that can hold different types of signers
+     * </pre>
+     *
+     * Protobuf type {@code Signer}
+     */
+    public static final class Builder extends
+        com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
+        // @@protoc_insertion_point(builder_implements:Signer)
+        ch.epfl.dedis.proto.DarcProto.SignerOrBuilder {
+      public static final com.google.protobuf.Descriptors.Descriptor
+          getDescriptor() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_descriptor;
+      }
+
+      protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+          internalGetFieldAccessorTable() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_fieldAccessorTable
+            .ensureFieldAccessorsInitialized(
+                ch.epfl.dedis.proto.DarcProto.Signer.class, ch.epfl.dedis.proto.DarcProto.Signer.Builder.class);
+      }
+
+      // Construct using ch.epfl.dedis.proto.DarcProto.Signer.newBuilder()
+      private Builder() {
+        maybeForceBuilderInitialization();
+      }
+
+      private Builder(
+          com.google.protobuf.GeneratedMessageV3.BuilderParent parent) {
+        super(parent);
+        maybeForceBuilderInitialization();
+      }
+      private void maybeForceBuilderInitialization() {
+        if (com.google.protobuf.GeneratedMessageV3
+                .alwaysUseFieldBuilders) {
+          getEd25519FieldBuilder();
+          getX509EcFieldBuilder();
+          getX509ChainFieldBuilder();
+        }
+      }
+      public Builder clear() {
+        super.clear();
+        if (ed25519Builder_ == null) {
+          ed25519_ = null;
+        } else {
+          ed25519Builder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000001);
+        if (x509EcBuilder_ == null) {
+          x509Ec_ = null;
+        } else {
+          x509EcBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000002);
+        if (x509ChainBuilder_ == null) {
+          x509Chain_ = null;
+        } else {
+          x509ChainBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000004);
+        return this;
+      }
+
+      public com.google.protobuf.Descriptors.Descriptor
+          getDescriptorForType() {
+        return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_descriptor;
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.Signer getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.Signer.getDefaultInstance();
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.Signer build() {
+        ch.epfl.dedis.proto.DarcProto.Signer result = buildPartial();
+        if (!result.isInitialized()) {
+          throw newUninitializedMessageException(result);
+        }
+        return result;
+      }
+
+      public ch.epfl.dedis.proto.DarcProto.Signer buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.Signer result = new ch.epfl.dedis.proto.DarcProto.Signer(this);
+        int from_bitField0_ = bitField0_;
+        int to_bitField0_ = 0;
+        if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
+          to_bitField0_ |= 0x00000001;
+        }
+        if (ed25519Builder_ == null) {
+          result.ed25519_ = ed25519_;
         } else {
-          darcsBuilder_.addMessage(value);
+          result.ed25519_ = ed25519Builder_.build();
+        }
+        if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
+          to_bitField0_ |= 0x00000002;
+        }
+        if (x509EcBuilder_ == null) {
+          result.x509Ec_ = x509Ec_;
+        } else {
+          result.x509Ec_ = x509EcBuilder_.build();
+        }
+        if (((from_bitField0_ & 0x00000004) == 0x00000004)) {
+          to_bitField0_ |= 0x00000004;
+        }
+        if (x509ChainBuilder_ == null) {
+          result.x509Chain_ = x509Chain_;
+        } else {
+          result.x509Chain_ = x509ChainBuilder_.build();
+        }
+        result.bitField0_ = to_bitField0_;
+        onBuilt();
+        return result;
+      }
+
+      public Builder clone() {
+        return (Builder) super.clone();
+      }
+      public Builder setField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          java.lang.Object value) {
+        return (Builder) super.setField(field, value);
+      }
+      public Builder clearField(
+          com.google.protobuf.Descriptors.FieldDescriptor field) {
+        return (Builder) super.clearField(field);
+      }
+      public Builder clearOneof(
+          com.google.protobuf.Descriptors.OneofDescriptor oneof) {
+        return (Builder) super.clearOneof(oneof);
+      }
+      public Builder setRepeatedField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          int index, java.lang.Object value) {
+        return (Builder) super.setRepeatedField(field, index, value);
+      }
+      public Builder addRepeatedField(
+          com.google.protobuf.Descriptors.FieldDescriptor field,
+          java.lang.Object value) {
+        return (Builder) super.addRepeatedField(field, value);
+      }
+      public Builder mergeFrom(com.google.protobuf.Message other) {
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.Signer) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.Signer)other);
+        } else {
+          super.mergeFrom(other);
+          return this;
+        }
+      }
+
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.Signer other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.Signer.getDefaultInstance()) return this;
+        if (other.hasEd25519()) {
+          mergeEd25519(other.getEd25519());
         }
+        if (other.hasX509Ec()) {
+          mergeX509Ec(other.getX509Ec());
+        }
+        if (other.hasX509Chain()) {
+          mergeX509Chain(other.getX509Chain());
+        }
+        this.mergeUnknownFields(other.unknownFields);
+        onChanged();
         return this;
       }
-      /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
-       */
-      public Builder addDarcs(
-          int index, ch.epfl.dedis.proto.DarcProto.Darc value) {
-        if (darcsBuilder_ == null) {
-          if (value == null) {
-            throw new NullPointerException();
+
+      public final boolean isInitialized() {
+        if (hasEd25519()) {
+          if (!getEd25519().isInitialized()) {
+            return false;
+          }
+        }
+        if (hasX509Ec()) {
+          if (!getX509Ec().isInitialized()) {
+            return false;
+          }
+        }
+        if (hasX509Chain()) {
+          if (!getX509Chain().isInitialized()) {
+            return false;
+          }
+        }
+        return true;
+      }
+
+      public Builder mergeFrom(
+          com.google.protobuf.CodedInputStream input,
+          com.google.protobuf.ExtensionRegistryLite extensionRegistry)
+          throws java.io.IOException {
+        ch.epfl.dedis.proto.DarcProto.Signer parsedMessage = null;
+        try {
+          parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
+        } catch (com.google.protobuf.InvalidProtocolBufferException e) {
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.Signer) e.getUnfinishedMessage();
+          throw e.unwrapIOException();
+        } finally {
+          if (parsedMessage != null) {
+            mergeFrom(parsedMessage);
           }
-          ensureDarcsIsMutable();
-          darcs_.add(index, value);
-          onChanged();
-        } else {
-          darcsBuilder_.addMessage(index, value);
         }
         return this;
       }
+      private int bitField0_;
+
+      private ch.epfl.dedis.proto.DarcProto.SignerEd25519 ed25519_ = null;
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.SignerEd25519, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder, ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder> ed25519Builder_;
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public Builder addDarcs(
-          ch.epfl.dedis.proto.DarcProto.Darc.Builder builderForValue) {
-        if (darcsBuilder_ == null) {
-          ensureDarcsIsMutable();
-          darcs_.add(builderForValue.build());
-          onChanged();
-        } else {
-          darcsBuilder_.addMessage(builderForValue.build());
-        }
-        return this;
+      public boolean hasEd25519() {
+        return ((bitField0_ & 0x00000001) == 0x00000001);
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public Builder addDarcs(
-          int index, ch.epfl.dedis.proto.DarcProto.Darc.Builder builderForValue) {
-        if (darcsBuilder_ == null) {
-          ensureDarcsIsMutable();
-          darcs_.add(index, builderForValue.build());
-          onChanged();
+      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getEd25519() {
+        if (ed25519Builder_ == null) {
+          return ed25519_ == null ? ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
         } else {
-          darcsBuilder_.addMessage(index, builderForValue.build());
+          return ed25519Builder_.getMessage();
         }
-        return this;
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public Builder addAllDarcs(
-          java.lang.Iterable<? extends ch.epfl.dedis.proto.DarcProto.Darc> values) {
-        if (darcsBuilder_ == null) {
-          ensureDarcsIsMutable();
-          com.google.protobuf.AbstractMessageLite.Builder.addAll(
-              values, darcs_);
+      public Builder setEd25519(ch.epfl.dedis.proto.DarcProto.SignerEd25519 value) {
+        if (ed25519Builder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          ed25519_ = value;
           onChanged();
         } else {
-          darcsBuilder_.addAllMessages(values);
+          ed25519Builder_.setMessage(value);
         }
+        bitField0_ |= 0x00000001;
         return this;
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public Builder clearDarcs() {
-        if (darcsBuilder_ == null) {
-          darcs_ = java.util.Collections.emptyList();
-          bitField0_ = (bitField0_ & ~0x00000001);
+      public Builder setEd25519(
+          ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder builderForValue) {
+        if (ed25519Builder_ == null) {
+          ed25519_ = builderForValue.build();
           onChanged();
         } else {
-          darcsBuilder_.clear();
+          ed25519Builder_.setMessage(builderForValue.build());
         }
+        bitField0_ |= 0x00000001;
         return this;
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public Builder removeDarcs(int index) {
-        if (darcsBuilder_ == null) {
-          ensureDarcsIsMutable();
-          darcs_.remove(index);
+      public Builder mergeEd25519(ch.epfl.dedis.proto.DarcProto.SignerEd25519 value) {
+        if (ed25519Builder_ == null) {
+          if (((bitField0_ & 0x00000001) == 0x00000001) &&
+              ed25519_ != null &&
+              ed25519_ != ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance()) {
+            ed25519_ =
+              ch.epfl.dedis.proto.DarcProto.SignerEd25519.newBuilder(ed25519_).mergeFrom(value).buildPartial();
+          } else {
+            ed25519_ = value;
+          }
           onChanged();
         } else {
-          darcsBuilder_.remove(index);
+          ed25519Builder_.mergeFrom(value);
         }
+        bitField0_ |= 0x00000001;
         return this;
       }
-      /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
-       */
-      public ch.epfl.dedis.proto.DarcProto.Darc.Builder getDarcsBuilder(
-          int index) {
-        return getDarcsFieldBuilder().getBuilder(index);
-      }
-      /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
-       */
-      public ch.epfl.dedis.proto.DarcProto.DarcOrBuilder getDarcsOrBuilder(
-          int index) {
-        if (darcsBuilder_ == null) {
-          return darcs_.get(index);  } else {
-          return darcsBuilder_.getMessageOrBuilder(index);
-        }
-      }
-      /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
-       */
-      public java.util.List<? extends ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
-           getDarcsOrBuilderList() {
-        if (darcsBuilder_ != null) {
-          return darcsBuilder_.getMessageOrBuilderList();
+      /**
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
+       */
+      public Builder clearEd25519() {
+        if (ed25519Builder_ == null) {
+          ed25519_ = null;
+          onChanged();
         } else {
-          return java.util.Collections.unmodifiableList(darcs_);
+          ed25519Builder_.clear();
         }
+        bitField0_ = (bitField0_ & ~0x00000001);
+        return this;
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.Darc.Builder addDarcsBuilder() {
-        return getDarcsFieldBuilder().addBuilder(
-            ch.epfl.dedis.proto.DarcProto.Darc.getDefaultInstance());
+      public ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder getEd25519Builder() {
+        bitField0_ |= 0x00000001;
+        onChanged();
+        return getEd25519FieldBuilder().getBuilder();
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.Darc.Builder addDarcsBuilder(
-          int index) {
-        return getDarcsFieldBuilder().addBuilder(
-            index, ch.epfl.dedis.proto.DarcProto.Darc.getDefaultInstance());
+      public ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder getEd25519OrBuilder() {
+        if (ed25519Builder_ != null) {
+          return ed25519Builder_.getMessageOrBuilder();
+        } else {
+          return ed25519_ == null ?
+              ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
+        }
       }
       /**
-       * <pre>
-       * 	 Darc(s) that justify the right of the signer to push a new Darc
-       * </pre>
-       *
-       * <code>repeated .Darc darcs = 1;</code>
+       * <code>optional .SignerEd25519 ed25519 = 1;</code>
        */
-      public java.util.List<ch.epfl.dedis.proto.DarcProto.Darc.Builder> 
-           getDarcsBuilderList() {
-        return getDarcsFieldBuilder().getBuilderList();
-      }
-      private com.google.protobuf.RepeatedFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.Darc, ch.epfl.dedis.proto.DarcProto.Darc.Builder, ch.epfl.dedis.proto.DarcProto.DarcOrBuilder> 
-          getDarcsFieldBuilder() {
-        if (darcsBuilder_ == null) {
-          darcsBuilder_ = new com.google.protobuf.RepeatedFieldBuilderV3<
-              ch.epfl.dedis.proto.DarcProto.Darc, ch.epfl.dedis.proto.DarcProto.Darc.Builder, ch.epfl.dedis.proto.DarcProto.DarcOrBuilder>(
-                  darcs_,
-                  ((bitField0_ & 0x00000001) == 0x00000001),
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.SignerEd25519, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder, ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder> 
+          getEd25519FieldBuilder() {
+        if (ed25519Builder_ == null) {
+          ed25519Builder_ = new com.google.protobuf.SingleFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.SignerEd25519, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder, ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder>(
+                  getEd25519(),
                   getParentForChildren(),
                   isClean());
-          darcs_ = null;
+          ed25519_ = null;
         }
-        return darcsBuilder_;
+        return ed25519Builder_;
       }
 
-      private ch.epfl.dedis.proto.DarcProto.Identity signer_ = null;
+      private ch.epfl.dedis.proto.DarcProto.SignerX509EC x509Ec_ = null;
       private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.Identity, ch.epfl.dedis.proto.DarcProto.Identity.Builder, ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder> signerBuilder_;
+          ch.epfl.dedis.proto.DarcProto.SignerX509EC, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder> x509EcBuilder_;
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public boolean hasSigner() {
+      public boolean hasX509Ec() {
         return ((bitField0_ & 0x00000002) == 0x00000002);
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.Identity getSigner() {
-        if (signerBuilder_ == null) {
-          return signer_ == null ? ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
+      public ch.epfl.dedis.proto.DarcProto.SignerX509EC getX509Ec() {
+        if (x509EcBuilder_ == null) {
+          return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
         } else {
-          return signerBuilder_.getMessage();
+          return x509EcBuilder_.getMessage();
         }
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public Builder setSigner(ch.epfl.dedis.proto.DarcProto.Identity value) {
-        if (signerBuilder_ == null) {
+      public Builder setX509Ec(ch.epfl.dedis.proto.DarcProto.SignerX509EC value) {
+        if (x509EcBuilder_ == null) {
           if (value == null) {
             throw new NullPointerException();
           }
-          signer_ = value;
+          x509Ec_ = value;
           onChanged();
         } else {
-          signerBuilder_.setMessage(value);
+          x509EcBuilder_.setMessage(value);
         }
         bitField0_ |= 0x00000002;
         return this;
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public Builder setSigner(
-          ch.epfl.dedis.proto.DarcProto.Identity.Builder builderForValue) {
-        if (signerBuilder_ == null) {
-          signer_ = builderForValue.build();
+      public Builder setX509Ec(
+          ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder builderForValue) {
+        if (x509EcBuilder_ == null) {
+          x509Ec_ = builderForValue.build();
           onChanged();
         } else {
-          signerBuilder_.setMessage(builderForValue.build());
+          x509EcBuilder_.setMessage(builderForValue.build());
         }
         bitField0_ |= 0x00000002;
         return this;
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public Builder mergeSigner(ch.epfl.dedis.proto.DarcProto.Identity value) {
-        if (signerBuilder_ == null) {
+      public Builder mergeX509Ec(ch.epfl.dedis.proto.DarcProto.SignerX509EC value) {
+        if (x509EcBuilder_ == null) {
           if (((bitField0_ & 0x00000002) == 0x00000002) &&
-              signer_ != null &&
-              signer_ != ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance()) {
-            signer_ =
-              ch.epfl.dedis.proto.DarcProto.Identity.newBuilder(signer_).mergeFrom(value).buildPartial();
+              x509Ec_ != null &&
+              x509Ec_ != ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance()) {
+            x509Ec_ =
+              ch.epfl.dedis.proto.DarcProto.SignerX509EC.newBuilder(x509Ec_).mergeFrom(value).buildPartial();
           } else {
-            signer_ = value;
+            x509Ec_ = value;
           }
           onChanged();
         } else {
-          signerBuilder_.mergeFrom(value);
+          x509EcBuilder_.mergeFrom(value);
         }
         bitField0_ |= 0x00000002;
         return this;
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public Builder clearSigner() {
-        if (signerBuilder_ == null) {
-          signer_ = null;
+      public Builder clearX509Ec() {
+        if (x509EcBuilder_ == null) {
+          x509Ec_ = null;
           onChanged();
         } else {
-          signerBuilder_.clear();
+          x509EcBuilder_.clear();
         }
         bitField0_ = (bitField0_ & ~0x00000002);
         return this;
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.Identity.Builder getSignerBuilder() {
+      public ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder getX509EcBuilder() {
         bitField0_ |= 0x00000002;
         onChanged();
-        return getSignerFieldBuilder().getBuilder();
+        return getX509EcFieldBuilder().getBuilder();
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder getSignerOrBuilder() {
-        if (signerBuilder_ != null) {
-          return signerBuilder_.getMessageOrBuilder();
+      public ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder getX509EcOrBuilder() {
+        if (x509EcBuilder_ != null) {
+          return x509EcBuilder_.getMessageOrBuilder();
         } else {
-          return signer_ == null ?
-              ch.epfl.dedis.proto.DarcProto.Identity.getDefaultInstance() : signer_;
+          return x509Ec_ == null ?
+              ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
         }
       }
       /**
-       * <pre>
-       * 	 the Idenity (public key or another Darc) of the signer
-       * </pre>
-       *
-       * <code>required .Identity signer = 2;</code>
+       * <code>optional .SignerX509EC x509ec = 2;</code>
        */
       private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.Identity, ch.epfl.dedis.proto.DarcProto.Identity.Builder, ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder> 
-          getSignerFieldBuilder() {
-        if (signerBuilder_ == null) {
-          signerBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
-              ch.epfl.dedis.proto.DarcProto.Identity, ch.epfl.dedis.proto.DarcProto.Identity.Builder, ch.epfl.dedis.proto.DarcProto.IdentityOrBuilder>(
-                  getSigner(),
+          ch.epfl.dedis.proto.DarcProto.SignerX509EC, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder> 
+          getX509EcFieldBuilder() {
+        if (x509EcBuilder_ == null) {
+          x509EcBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.SignerX509EC, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder>(
+                  getX509Ec(),
                   getParentForChildren(),
                   isClean());
-          signer_ = null;
+          x509Ec_ = null;
         }
-        return signerBuilder_;
+        return x509EcBuilder_;
       }
 
-      private int role_ ;
+      private ch.epfl.dedis.proto.DarcProto.SignerX509Chain x509Chain_ = null;
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.SignerX509Chain, ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder> x509ChainBuilder_;
       /**
-       * <pre>
-       * 	 Is the signer Owner of a Darc or an user
-       * </pre>
-       *
-       * <code>required sint32 role = 3;</code>
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
        */
-      public boolean hasRole() {
+      public boolean hasX509Chain() {
         return ((bitField0_ & 0x00000004) == 0x00000004);
       }
       /**
-       * <pre>
-       * 	 Is the signer Owner of a Darc or an user
-       * </pre>
-       *
-       * <code>required sint32 role = 3;</code>
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
+       */
+      public ch.epfl.dedis.proto.DarcProto.SignerX509Chain getX509Chain() {
+        if (x509ChainBuilder_ == null) {
+          return x509Chain_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance() : x509Chain_;
+        } else {
+          return x509ChainBuilder_.getMessage();
+        }
+      }
+      /**
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
+       */
+      public Builder setX509Chain(ch.epfl.dedis.proto.DarcProto.SignerX509Chain value) {
+        if (x509ChainBuilder_ == null) {
+          if (value == null) {
+            throw new NullPointerException();
+          }
+          x509Chain_ = value;
+          onChanged();
+        } else {
+          x509ChainBuilder_.setMessage(value);
+        }
+        bitField0_ |= 0x00000004;
+        return this;
+      }
+      /**
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
+       */
+      public Builder setX509Chain(
+          ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder builderForValue) {
+        if (x509ChainBuilder_ == null) {
+          x509Chain_ = builderForValue.build();
+          onChanged();
+        } else {
+          x509ChainBuilder_.setMessage(builderForValue.build());
+        }
+        bitField0_ |= 0x00000004;
+        return this;
+      }
+      /**
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
+       */
+      public Builder mergeX509Chain(ch.epfl.dedis.proto.DarcProto.SignerX509Chain value) {
+        if (x509ChainBuilder_ == null) {
+          if (((bitField0_ & 0x00000004) == 0x00000004) &&
+              x509Chain_ != null &&
+              x509Chain_ != ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance()) {
+            x509Chain_ =
+              ch.epfl.dedis.proto.DarcProto.SignerX509Chain.newBuilder(x509Chain_).mergeFrom(value).buildPartial();
+          } else {
+            x509Chain_ = value;
+          }
+          onChanged();
+        } else {
+          x509ChainBuilder_.mergeFrom(value);
+        }
+        bitField0_ |= 0x00000004;
+        return this;
+      }
+      /**
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
        */
-      public int getRole() {
-        return role_;
+      public Builder clearX509Chain() {
+        if (x509ChainBuilder_ == null) {
+          x509Chain_ = null;
+          onChanged();
+        } else {
+          x509ChainBuilder_.clear();
+        }
+        bitField0_ = (bitField0_ & ~0x00000004);
+        return this;
       }
       /**
-       * <pre>
-       * 	 Is the signer Owner of a Darc or an user
-       * </pre>
-       *
-       * <code>required sint32 role = 3;</code>
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
        */
-      public Builder setRole(int value) {
+      public ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder getX509ChainBuilder() {
         bitField0_ |= 0x00000004;
-        role_ = value;
         onChanged();
-        return this;
+        return getX509ChainFieldBuilder().getBuilder();
       }
       /**
-       * <pre>
-       * 	 Is the signer Owner of a Darc or an user
-       * </pre>
-       *
-       * <code>required sint32 role = 3;</code>
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
        */
-      public Builder clearRole() {
-        bitField0_ = (bitField0_ & ~0x00000004);
-        role_ = 0;
-        onChanged();
-        return this;
+      public ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder getX509ChainOrBuilder() {
+        if (x509ChainBuilder_ != null) {
+          return x509ChainBuilder_.getMessageOrBuilder();
+        } else {
+          return x509Chain_ == null ?
+              ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance() : x509Chain_;
+        }
+      }
+      /**
+       * <code>optional .SignerX509Chain x509chain = 3;</code>
+       */
+      private com.google.protobuf.SingleFieldBuilderV3<
+          ch.epfl.dedis.proto.DarcProto.SignerX509Chain, ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder> 
+          getX509ChainFieldBuilder() {
+        if (x509ChainBuilder_ == null) {
+          x509ChainBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
+              ch.epfl.dedis.proto.DarcProto.SignerX509Chain, ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder>(
+                  getX509Chain(),
+                  getParentForChildren(),
+                  isClean());
+          x509Chain_ = null;
+        }
+        return x509ChainBuilder_;
       }
       public final Builder setUnknownFields(
           final com.google.protobuf.UnknownFieldSet unknownFields) {
@@ -6801,91 +9251,85 @@ public final class DarcProto {
       }
 
 
-      // @@protoc_insertion_point(builder_scope:SignaturePath)
+      // @@protoc_insertion_point(builder_scope:Signer)
     }
 
-    // @@protoc_insertion_point(class_scope:SignaturePath)
-    private static final ch.epfl.dedis.proto.DarcProto.SignaturePath DEFAULT_INSTANCE;
+    // @@protoc_insertion_point(class_scope:Signer)
+    private static final ch.epfl.dedis.proto.DarcProto.Signer DEFAULT_INSTANCE;
     static {
-      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignaturePath();
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.Signer();
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.SignaturePath getDefaultInstance() {
+    public static ch.epfl.dedis.proto.DarcProto.Signer getDefaultInstance() {
       return DEFAULT_INSTANCE;
     }
 
-    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignaturePath>
-        PARSER = new com.google.protobuf.AbstractParser<SignaturePath>() {
-      public SignaturePath parsePartialFrom(
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<Signer>
+        PARSER = new com.google.protobuf.AbstractParser<Signer>() {
+      public Signer parsePartialFrom(
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws com.google.protobuf.InvalidProtocolBufferException {
-          return new SignaturePath(input, extensionRegistry);
+          return new Signer(input, extensionRegistry);
       }
     };
 
-    public static com.google.protobuf.Parser<SignaturePath> parser() {
+    public static com.google.protobuf.Parser<Signer> parser() {
       return PARSER;
     }
 
     @java.lang.Override
-    public com.google.protobuf.Parser<SignaturePath> getParserForType() {
+    public com.google.protobuf.Parser<Signer> getParserForType() {
       return PARSER;
     }
 
-    public ch.epfl.dedis.proto.DarcProto.SignaturePath getDefaultInstanceForType() {
+    public ch.epfl.dedis.proto.DarcProto.Signer getDefaultInstanceForType() {
       return DEFAULT_INSTANCE;
     }
 
   }
 
-  public interface SignerOrBuilder extends
-      // @@protoc_insertion_point(interface_extends:Signer)
+  public interface SignerEd25519OrBuilder extends
+      // @@protoc_insertion_point(interface_extends:SignerEd25519)
       com.google.protobuf.MessageOrBuilder {
 
     /**
-     * <code>optional .SignerEd25519 ed25519 = 1;</code>
-     */
-    boolean hasEd25519();
-    /**
-     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     * <code>required bytes point = 1;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.SignerEd25519 getEd25519();
+    boolean hasPoint();
     /**
-     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     * <code>required bytes point = 1;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder getEd25519OrBuilder();
+    com.google.protobuf.ByteString getPoint();
 
     /**
-     * <code>optional .SignerX509EC x509ec = 2;</code>
-     */
-    boolean hasX509Ec();
-    /**
-     * <code>optional .SignerX509EC x509ec = 2;</code>
+     * <code>required bytes secret = 2;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.SignerX509EC getX509Ec();
+    boolean hasSecret();
     /**
-     * <code>optional .SignerX509EC x509ec = 2;</code>
+     * <code>required bytes secret = 2;</code>
      */
-    ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder getX509EcOrBuilder();
+    com.google.protobuf.ByteString getSecret();
   }
   /**
    * <pre>
-   * Signer is a generic structure that can hold different types of signers
+   * SignerEd25519 holds a public and private keys necessary to sign Darcs
    * </pre>
    *
-   * Protobuf type {@code Signer}
+   * Protobuf type {@code SignerEd25519}
    */
-  public  static final class Signer extends
+  public  static final class SignerEd25519 extends
       com.google.protobuf.GeneratedMessageV3 implements
-      // @@protoc_insertion_point(message_implements:Signer)
-      SignerOrBuilder {
+      // @@protoc_insertion_point(message_implements:SignerEd25519)
+      SignerEd25519OrBuilder {
   private static final long serialVersionUID = 0L;
-    // Use Signer.newBuilder() to construct.
-    private Signer(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+    // Use SignerEd25519.newBuilder() to construct.
+    private SignerEd25519(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
       super(builder);
     }
-    private Signer() {
+    private SignerEd25519() {
+      point_ = com.google.protobuf.ByteString.EMPTY;
+      secret_ = com.google.protobuf.ByteString.EMPTY;
     }
 
     @java.lang.Override
@@ -6893,7 +9337,7 @@ public final class DarcProto {
     getUnknownFields() {
       return this.unknownFields;
     }
-    private Signer(
+    private SignerEd25519(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
@@ -6917,29 +9361,13 @@ public final class DarcProto {
               break;
             }
             case 10: {
-              ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder subBuilder = null;
-              if (((bitField0_ & 0x00000001) == 0x00000001)) {
-                subBuilder = ed25519_.toBuilder();
-              }
-              ed25519_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignerEd25519.PARSER, extensionRegistry);
-              if (subBuilder != null) {
-                subBuilder.mergeFrom(ed25519_);
-                ed25519_ = subBuilder.buildPartial();
-              }
               bitField0_ |= 0x00000001;
+              point_ = input.readBytes();
               break;
             }
             case 18: {
-              ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder subBuilder = null;
-              if (((bitField0_ & 0x00000002) == 0x00000002)) {
-                subBuilder = x509Ec_.toBuilder();
-              }
-              x509Ec_ = input.readMessage(ch.epfl.dedis.proto.DarcProto.SignerX509EC.PARSER, extensionRegistry);
-              if (subBuilder != null) {
-                subBuilder.mergeFrom(x509Ec_);
-                x509Ec_ = subBuilder.buildPartial();
-              }
               bitField0_ |= 0x00000002;
+              secret_ = input.readBytes();
               break;
             }
           }
@@ -6956,57 +9384,45 @@ public final class DarcProto {
     }
     public static final com.google.protobuf.Descriptors.Descriptor
         getDescriptor() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_descriptor;
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_descriptor;
     }
 
     protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
         internalGetFieldAccessorTable() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_fieldAccessorTable
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_fieldAccessorTable
           .ensureFieldAccessorsInitialized(
-              ch.epfl.dedis.proto.DarcProto.Signer.class, ch.epfl.dedis.proto.DarcProto.Signer.Builder.class);
+              ch.epfl.dedis.proto.DarcProto.SignerEd25519.class, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder.class);
     }
 
     private int bitField0_;
-    public static final int ED25519_FIELD_NUMBER = 1;
-    private ch.epfl.dedis.proto.DarcProto.SignerEd25519 ed25519_;
+    public static final int POINT_FIELD_NUMBER = 1;
+    private com.google.protobuf.ByteString point_;
     /**
-     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     * <code>required bytes point = 1;</code>
      */
-    public boolean hasEd25519() {
+    public boolean hasPoint() {
       return ((bitField0_ & 0x00000001) == 0x00000001);
     }
     /**
-     * <code>optional .SignerEd25519 ed25519 = 1;</code>
-     */
-    public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getEd25519() {
-      return ed25519_ == null ? ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
-    }
-    /**
-     * <code>optional .SignerEd25519 ed25519 = 1;</code>
+     * <code>required bytes point = 1;</code>
      */
-    public ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder getEd25519OrBuilder() {
-      return ed25519_ == null ? ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
+    public com.google.protobuf.ByteString getPoint() {
+      return point_;
     }
 
-    public static final int X509EC_FIELD_NUMBER = 2;
-    private ch.epfl.dedis.proto.DarcProto.SignerX509EC x509Ec_;
+    public static final int SECRET_FIELD_NUMBER = 2;
+    private com.google.protobuf.ByteString secret_;
     /**
-     * <code>optional .SignerX509EC x509ec = 2;</code>
+     * <code>required bytes secret = 2;</code>
      */
-    public boolean hasX509Ec() {
+    public boolean hasSecret() {
       return ((bitField0_ & 0x00000002) == 0x00000002);
     }
     /**
-     * <code>optional .SignerX509EC x509ec = 2;</code>
-     */
-    public ch.epfl.dedis.proto.DarcProto.SignerX509EC getX509Ec() {
-      return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
-    }
-    /**
-     * <code>optional .SignerX509EC x509ec = 2;</code>
+     * <code>required bytes secret = 2;</code>
      */
-    public ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder getX509EcOrBuilder() {
-      return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
+    public com.google.protobuf.ByteString getSecret() {
+      return secret_;
     }
 
     private byte memoizedIsInitialized = -1;
@@ -7015,17 +9431,13 @@ public final class DarcProto {
       if (isInitialized == 1) return true;
       if (isInitialized == 0) return false;
 
-      if (hasEd25519()) {
-        if (!getEd25519().isInitialized()) {
-          memoizedIsInitialized = 0;
-          return false;
-        }
+      if (!hasPoint()) {
+        memoizedIsInitialized = 0;
+        return false;
       }
-      if (hasX509Ec()) {
-        if (!getX509Ec().isInitialized()) {
-          memoizedIsInitialized = 0;
-          return false;
-        }
+      if (!hasSecret()) {
+        memoizedIsInitialized = 0;
+        return false;
       }
       memoizedIsInitialized = 1;
       return true;
@@ -7034,10 +9446,10 @@ public final class DarcProto {
     public void writeTo(com.google.protobuf.CodedOutputStream output)
                         throws java.io.IOException {
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
-        output.writeMessage(1, getEd25519());
+        output.writeBytes(1, point_);
       }
       if (((bitField0_ & 0x00000002) == 0x00000002)) {
-        output.writeMessage(2, getX509Ec());
+        output.writeBytes(2, secret_);
       }
       unknownFields.writeTo(output);
     }
@@ -7049,11 +9461,11 @@ public final class DarcProto {
       size = 0;
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
         size += com.google.protobuf.CodedOutputStream
-          .computeMessageSize(1, getEd25519());
+          .computeBytesSize(1, point_);
       }
       if (((bitField0_ & 0x00000002) == 0x00000002)) {
         size += com.google.protobuf.CodedOutputStream
-          .computeMessageSize(2, getX509Ec());
+          .computeBytesSize(2, secret_);
       }
       size += unknownFields.getSerializedSize();
       memoizedSize = size;
@@ -7065,21 +9477,21 @@ public final class DarcProto {
       if (obj == this) {
        return true;
       }
-      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.Signer)) {
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignerEd25519)) {
         return super.equals(obj);
       }
-      ch.epfl.dedis.proto.DarcProto.Signer other = (ch.epfl.dedis.proto.DarcProto.Signer) obj;
+      ch.epfl.dedis.proto.DarcProto.SignerEd25519 other = (ch.epfl.dedis.proto.DarcProto.SignerEd25519) obj;
 
       boolean result = true;
-      result = result && (hasEd25519() == other.hasEd25519());
-      if (hasEd25519()) {
-        result = result && getEd25519()
-            .equals(other.getEd25519());
+      result = result && (hasPoint() == other.hasPoint());
+      if (hasPoint()) {
+        result = result && getPoint()
+            .equals(other.getPoint());
       }
-      result = result && (hasX509Ec() == other.hasX509Ec());
-      if (hasX509Ec()) {
-        result = result && getX509Ec()
-            .equals(other.getX509Ec());
+      result = result && (hasSecret() == other.hasSecret());
+      if (hasSecret()) {
+        result = result && getSecret()
+            .equals(other.getSecret());
       }
       result = result && unknownFields.equals(other.unknownFields);
       return result;
@@ -7092,82 +9504,82 @@ public final class DarcProto {
       }
       int hash = 41;
       hash = (19 * hash) + getDescriptor().hashCode();
-      if (hasEd25519()) {
-        hash = (37 * hash) + ED25519_FIELD_NUMBER;
-        hash = (53 * hash) + getEd25519().hashCode();
+      if (hasPoint()) {
+        hash = (37 * hash) + POINT_FIELD_NUMBER;
+        hash = (53 * hash) + getPoint().hashCode();
       }
-      if (hasX509Ec()) {
-        hash = (37 * hash) + X509EC_FIELD_NUMBER;
-        hash = (53 * hash) + getX509Ec().hashCode();
+      if (hasSecret()) {
+        hash = (37 * hash) + SECRET_FIELD_NUMBER;
+        hash = (53 * hash) + getSecret().hashCode();
       }
       hash = (29 * hash) + unknownFields.hashCode();
       memoizedHashCode = hash;
       return hash;
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         java.nio.ByteBuffer data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         java.nio.ByteBuffer data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         com.google.protobuf.ByteString data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         com.google.protobuf.ByteString data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(byte[] data)
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(byte[] data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         byte[] data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseDelimitedFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseDelimitedFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseDelimitedFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseDelimitedFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         com.google.protobuf.CodedInputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.Signer parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
@@ -7179,7 +9591,7 @@ public final class DarcProto {
     public static Builder newBuilder() {
       return DEFAULT_INSTANCE.toBuilder();
     }
-    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.Signer prototype) {
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignerEd25519 prototype) {
       return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
     }
     public Builder toBuilder() {
@@ -7195,28 +9607,28 @@ public final class DarcProto {
     }
     /**
      * <pre>
-     * Signer is a generic structure that can hold different types of signers
+     * SignerEd25519 holds a public and private keys necessary to sign Darcs
      * </pre>
      *
-     * Protobuf type {@code Signer}
+     * Protobuf type {@code SignerEd25519}
      */
     public static final class Builder extends
         com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
-        // @@protoc_insertion_point(builder_implements:Signer)
-        ch.epfl.dedis.proto.DarcProto.SignerOrBuilder {
+        // @@protoc_insertion_point(builder_implements:SignerEd25519)
+        ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder {
       public static final com.google.protobuf.Descriptors.Descriptor
           getDescriptor() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_descriptor;
       }
 
       protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
           internalGetFieldAccessorTable() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_fieldAccessorTable
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_fieldAccessorTable
             .ensureFieldAccessorsInitialized(
-                ch.epfl.dedis.proto.DarcProto.Signer.class, ch.epfl.dedis.proto.DarcProto.Signer.Builder.class);
+                ch.epfl.dedis.proto.DarcProto.SignerEd25519.class, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder.class);
       }
 
-      // Construct using ch.epfl.dedis.proto.DarcProto.Signer.newBuilder()
+      // Construct using ch.epfl.dedis.proto.DarcProto.SignerEd25519.newBuilder()
       private Builder() {
         maybeForceBuilderInitialization();
       }
@@ -7229,64 +9641,46 @@ public final class DarcProto {
       private void maybeForceBuilderInitialization() {
         if (com.google.protobuf.GeneratedMessageV3
                 .alwaysUseFieldBuilders) {
-          getEd25519FieldBuilder();
-          getX509EcFieldBuilder();
         }
       }
       public Builder clear() {
         super.clear();
-        if (ed25519Builder_ == null) {
-          ed25519_ = null;
-        } else {
-          ed25519Builder_.clear();
-        }
+        point_ = com.google.protobuf.ByteString.EMPTY;
         bitField0_ = (bitField0_ & ~0x00000001);
-        if (x509EcBuilder_ == null) {
-          x509Ec_ = null;
-        } else {
-          x509EcBuilder_.clear();
-        }
+        secret_ = com.google.protobuf.ByteString.EMPTY;
         bitField0_ = (bitField0_ & ~0x00000002);
         return this;
       }
 
       public com.google.protobuf.Descriptors.Descriptor
           getDescriptorForType() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_Signer_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_descriptor;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.Signer getDefaultInstanceForType() {
-        return ch.epfl.dedis.proto.DarcProto.Signer.getDefaultInstance();
+      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance();
       }
 
-      public ch.epfl.dedis.proto.DarcProto.Signer build() {
-        ch.epfl.dedis.proto.DarcProto.Signer result = buildPartial();
+      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 build() {
+        ch.epfl.dedis.proto.DarcProto.SignerEd25519 result = buildPartial();
         if (!result.isInitialized()) {
           throw newUninitializedMessageException(result);
         }
         return result;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.Signer buildPartial() {
-        ch.epfl.dedis.proto.DarcProto.Signer result = new ch.epfl.dedis.proto.DarcProto.Signer(this);
+      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.SignerEd25519 result = new ch.epfl.dedis.proto.DarcProto.SignerEd25519(this);
         int from_bitField0_ = bitField0_;
         int to_bitField0_ = 0;
         if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
           to_bitField0_ |= 0x00000001;
         }
-        if (ed25519Builder_ == null) {
-          result.ed25519_ = ed25519_;
-        } else {
-          result.ed25519_ = ed25519Builder_.build();
-        }
+        result.point_ = point_;
         if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
           to_bitField0_ |= 0x00000002;
         }
-        if (x509EcBuilder_ == null) {
-          result.x509Ec_ = x509Ec_;
-        } else {
-          result.x509Ec_ = x509EcBuilder_.build();
-        }
+        result.secret_ = secret_;
         result.bitField0_ = to_bitField0_;
         onBuilt();
         return result;
@@ -7319,21 +9713,21 @@ public final class DarcProto {
         return (Builder) super.addRepeatedField(field, value);
       }
       public Builder mergeFrom(com.google.protobuf.Message other) {
-        if (other instanceof ch.epfl.dedis.proto.DarcProto.Signer) {
-          return mergeFrom((ch.epfl.dedis.proto.DarcProto.Signer)other);
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignerEd25519) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignerEd25519)other);
         } else {
           super.mergeFrom(other);
           return this;
         }
       }
 
-      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.Signer other) {
-        if (other == ch.epfl.dedis.proto.DarcProto.Signer.getDefaultInstance()) return this;
-        if (other.hasEd25519()) {
-          mergeEd25519(other.getEd25519());
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignerEd25519 other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance()) return this;
+        if (other.hasPoint()) {
+          setPoint(other.getPoint());
         }
-        if (other.hasX509Ec()) {
-          mergeX509Ec(other.getX509Ec());
+        if (other.hasSecret()) {
+          setSecret(other.getSecret());
         }
         this.mergeUnknownFields(other.unknownFields);
         onChanged();
@@ -7341,15 +9735,11 @@ public final class DarcProto {
       }
 
       public final boolean isInitialized() {
-        if (hasEd25519()) {
-          if (!getEd25519().isInitialized()) {
-            return false;
-          }
+        if (!hasPoint()) {
+          return false;
         }
-        if (hasX509Ec()) {
-          if (!getX509Ec().isInitialized()) {
-            return false;
-          }
+        if (!hasSecret()) {
+          return false;
         }
         return true;
       }
@@ -7358,11 +9748,11 @@ public final class DarcProto {
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws java.io.IOException {
-        ch.epfl.dedis.proto.DarcProto.Signer parsedMessage = null;
+        ch.epfl.dedis.proto.DarcProto.SignerEd25519 parsedMessage = null;
         try {
           parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
         } catch (com.google.protobuf.InvalidProtocolBufferException e) {
-          parsedMessage = (ch.epfl.dedis.proto.DarcProto.Signer) e.getUnfinishedMessage();
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignerEd25519) e.getUnfinishedMessage();
           throw e.unwrapIOException();
         } finally {
           if (parsedMessage != null) {
@@ -7373,240 +9763,74 @@ public final class DarcProto {
       }
       private int bitField0_;
 
-      private ch.epfl.dedis.proto.DarcProto.SignerEd25519 ed25519_ = null;
-      private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.SignerEd25519, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder, ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder> ed25519Builder_;
+      private com.google.protobuf.ByteString point_ = com.google.protobuf.ByteString.EMPTY;
       /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
+       * <code>required bytes point = 1;</code>
        */
-      public boolean hasEd25519() {
+      public boolean hasPoint() {
         return ((bitField0_ & 0x00000001) == 0x00000001);
       }
       /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
-       */
-      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getEd25519() {
-        if (ed25519Builder_ == null) {
-          return ed25519_ == null ? ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
-        } else {
-          return ed25519Builder_.getMessage();
-        }
-      }
-      /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
-       */
-      public Builder setEd25519(ch.epfl.dedis.proto.DarcProto.SignerEd25519 value) {
-        if (ed25519Builder_ == null) {
-          if (value == null) {
-            throw new NullPointerException();
-          }
-          ed25519_ = value;
-          onChanged();
-        } else {
-          ed25519Builder_.setMessage(value);
-        }
-        bitField0_ |= 0x00000001;
-        return this;
-      }
-      /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
-       */
-      public Builder setEd25519(
-          ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder builderForValue) {
-        if (ed25519Builder_ == null) {
-          ed25519_ = builderForValue.build();
-          onChanged();
-        } else {
-          ed25519Builder_.setMessage(builderForValue.build());
-        }
-        bitField0_ |= 0x00000001;
-        return this;
-      }
-      /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
-       */
-      public Builder mergeEd25519(ch.epfl.dedis.proto.DarcProto.SignerEd25519 value) {
-        if (ed25519Builder_ == null) {
-          if (((bitField0_ & 0x00000001) == 0x00000001) &&
-              ed25519_ != null &&
-              ed25519_ != ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance()) {
-            ed25519_ =
-              ch.epfl.dedis.proto.DarcProto.SignerEd25519.newBuilder(ed25519_).mergeFrom(value).buildPartial();
-          } else {
-            ed25519_ = value;
-          }
-          onChanged();
-        } else {
-          ed25519Builder_.mergeFrom(value);
-        }
-        bitField0_ |= 0x00000001;
-        return this;
-      }
-      /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
+       * <code>required bytes point = 1;</code>
        */
-      public Builder clearEd25519() {
-        if (ed25519Builder_ == null) {
-          ed25519_ = null;
-          onChanged();
-        } else {
-          ed25519Builder_.clear();
-        }
-        bitField0_ = (bitField0_ & ~0x00000001);
-        return this;
+      public com.google.protobuf.ByteString getPoint() {
+        return point_;
       }
       /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
+       * <code>required bytes point = 1;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder getEd25519Builder() {
-        bitField0_ |= 0x00000001;
+      public Builder setPoint(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  bitField0_ |= 0x00000001;
+        point_ = value;
         onChanged();
-        return getEd25519FieldBuilder().getBuilder();
-      }
-      /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
-       */
-      public ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder getEd25519OrBuilder() {
-        if (ed25519Builder_ != null) {
-          return ed25519Builder_.getMessageOrBuilder();
-        } else {
-          return ed25519_ == null ?
-              ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance() : ed25519_;
-        }
-      }
-      /**
-       * <code>optional .SignerEd25519 ed25519 = 1;</code>
-       */
-      private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.SignerEd25519, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder, ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder> 
-          getEd25519FieldBuilder() {
-        if (ed25519Builder_ == null) {
-          ed25519Builder_ = new com.google.protobuf.SingleFieldBuilderV3<
-              ch.epfl.dedis.proto.DarcProto.SignerEd25519, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder, ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder>(
-                  getEd25519(),
-                  getParentForChildren(),
-                  isClean());
-          ed25519_ = null;
-        }
-        return ed25519Builder_;
-      }
-
-      private ch.epfl.dedis.proto.DarcProto.SignerX509EC x509Ec_ = null;
-      private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.SignerX509EC, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder> x509EcBuilder_;
-      /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
-       */
-      public boolean hasX509Ec() {
-        return ((bitField0_ & 0x00000002) == 0x00000002);
-      }
-      /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
-       */
-      public ch.epfl.dedis.proto.DarcProto.SignerX509EC getX509Ec() {
-        if (x509EcBuilder_ == null) {
-          return x509Ec_ == null ? ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
-        } else {
-          return x509EcBuilder_.getMessage();
-        }
-      }
-      /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
-       */
-      public Builder setX509Ec(ch.epfl.dedis.proto.DarcProto.SignerX509EC value) {
-        if (x509EcBuilder_ == null) {
-          if (value == null) {
-            throw new NullPointerException();
-          }
-          x509Ec_ = value;
-          onChanged();
-        } else {
-          x509EcBuilder_.setMessage(value);
-        }
-        bitField0_ |= 0x00000002;
-        return this;
-      }
-      /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
-       */
-      public Builder setX509Ec(
-          ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder builderForValue) {
-        if (x509EcBuilder_ == null) {
-          x509Ec_ = builderForValue.build();
-          onChanged();
-        } else {
-          x509EcBuilder_.setMessage(builderForValue.build());
-        }
-        bitField0_ |= 0x00000002;
         return this;
       }
       /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
+       * <code>required bytes point = 1;</code>
        */
-      public Builder mergeX509Ec(ch.epfl.dedis.proto.DarcProto.SignerX509EC value) {
-        if (x509EcBuilder_ == null) {
-          if (((bitField0_ & 0x00000002) == 0x00000002) &&
-              x509Ec_ != null &&
-              x509Ec_ != ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance()) {
-            x509Ec_ =
-              ch.epfl.dedis.proto.DarcProto.SignerX509EC.newBuilder(x509Ec_).mergeFrom(value).buildPartial();
-          } else {
-            x509Ec_ = value;
-          }
-          onChanged();
-        } else {
-          x509EcBuilder_.mergeFrom(value);
-        }
-        bitField0_ |= 0x00000002;
+      public Builder clearPoint() {
+        bitField0_ = (bitField0_ & ~0x00000001);
+        point_ = getDefaultInstance().getPoint();
+        onChanged();
         return this;
       }
+
+      private com.google.protobuf.ByteString secret_ = com.google.protobuf.ByteString.EMPTY;
       /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
+       * <code>required bytes secret = 2;</code>
        */
-      public Builder clearX509Ec() {
-        if (x509EcBuilder_ == null) {
-          x509Ec_ = null;
-          onChanged();
-        } else {
-          x509EcBuilder_.clear();
-        }
-        bitField0_ = (bitField0_ & ~0x00000002);
-        return this;
+      public boolean hasSecret() {
+        return ((bitField0_ & 0x00000002) == 0x00000002);
       }
       /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
+       * <code>required bytes secret = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder getX509EcBuilder() {
-        bitField0_ |= 0x00000002;
-        onChanged();
-        return getX509EcFieldBuilder().getBuilder();
+      public com.google.protobuf.ByteString getSecret() {
+        return secret_;
       }
       /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
+       * <code>required bytes secret = 2;</code>
        */
-      public ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder getX509EcOrBuilder() {
-        if (x509EcBuilder_ != null) {
-          return x509EcBuilder_.getMessageOrBuilder();
-        } else {
-          return x509Ec_ == null ?
-              ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance() : x509Ec_;
-        }
+      public Builder setSecret(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  bitField0_ |= 0x00000002;
+        secret_ = value;
+        onChanged();
+        return this;
       }
       /**
-       * <code>optional .SignerX509EC x509ec = 2;</code>
+       * <code>required bytes secret = 2;</code>
        */
-      private com.google.protobuf.SingleFieldBuilderV3<
-          ch.epfl.dedis.proto.DarcProto.SignerX509EC, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder> 
-          getX509EcFieldBuilder() {
-        if (x509EcBuilder_ == null) {
-          x509EcBuilder_ = new com.google.protobuf.SingleFieldBuilderV3<
-              ch.epfl.dedis.proto.DarcProto.SignerX509EC, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder, ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder>(
-                  getX509Ec(),
-                  getParentForChildren(),
-                  isClean());
-          x509Ec_ = null;
-        }
-        return x509EcBuilder_;
+      public Builder clearSecret() {
+        bitField0_ = (bitField0_ & ~0x00000002);
+        secret_ = getDefaultInstance().getSecret();
+        onChanged();
+        return this;
       }
       public final Builder setUnknownFields(
           final com.google.protobuf.UnknownFieldSet unknownFields) {
@@ -7619,46 +9843,46 @@ public final class DarcProto {
       }
 
 
-      // @@protoc_insertion_point(builder_scope:Signer)
+      // @@protoc_insertion_point(builder_scope:SignerEd25519)
     }
 
-    // @@protoc_insertion_point(class_scope:Signer)
-    private static final ch.epfl.dedis.proto.DarcProto.Signer DEFAULT_INSTANCE;
+    // @@protoc_insertion_point(class_scope:SignerEd25519)
+    private static final ch.epfl.dedis.proto.DarcProto.SignerEd25519 DEFAULT_INSTANCE;
     static {
-      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.Signer();
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignerEd25519();
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.Signer getDefaultInstance() {
+    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 getDefaultInstance() {
       return DEFAULT_INSTANCE;
     }
 
-    @java.lang.Deprecated public static final com.google.protobuf.Parser<Signer>
-        PARSER = new com.google.protobuf.AbstractParser<Signer>() {
-      public Signer parsePartialFrom(
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignerEd25519>
+        PARSER = new com.google.protobuf.AbstractParser<SignerEd25519>() {
+      public SignerEd25519 parsePartialFrom(
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws com.google.protobuf.InvalidProtocolBufferException {
-          return new Signer(input, extensionRegistry);
+          return new SignerEd25519(input, extensionRegistry);
       }
     };
 
-    public static com.google.protobuf.Parser<Signer> parser() {
+    public static com.google.protobuf.Parser<SignerEd25519> parser() {
       return PARSER;
     }
 
     @java.lang.Override
-    public com.google.protobuf.Parser<Signer> getParserForType() {
+    public com.google.protobuf.Parser<SignerEd25519> getParserForType() {
       return PARSER;
     }
 
-    public ch.epfl.dedis.proto.DarcProto.Signer getDefaultInstanceForType() {
+    public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getDefaultInstanceForType() {
       return DEFAULT_INSTANCE;
     }
 
   }
 
-  public interface SignerEd25519OrBuilder extends
-      // @@protoc_insertion_point(interface_extends:SignerEd25519)
+  public interface SignerX509ECOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:SignerX509EC)
       com.google.protobuf.MessageOrBuilder {
 
     /**
@@ -7681,21 +9905,22 @@ public final class DarcProto {
   }
   /**
    * <pre>
-   * SignerEd25519 holds a public and private keys necessary to sign Darcs
+   * SignerX509EC holds a public and private keys necessary to sign Darcs,
+   * but the private key will not be given out.
    * </pre>
    *
-   * Protobuf type {@code SignerEd25519}
+   * Protobuf type {@code SignerX509EC}
    */
-  public  static final class SignerEd25519 extends
+  public  static final class SignerX509EC extends
       com.google.protobuf.GeneratedMessageV3 implements
-      // @@protoc_insertion_point(message_implements:SignerEd25519)
-      SignerEd25519OrBuilder {
+      // @@protoc_insertion_point(message_implements:SignerX509EC)
+      SignerX509ECOrBuilder {
   private static final long serialVersionUID = 0L;
-    // Use SignerEd25519.newBuilder() to construct.
-    private SignerEd25519(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+    // Use SignerX509EC.newBuilder() to construct.
+    private SignerX509EC(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
       super(builder);
     }
-    private SignerEd25519() {
+    private SignerX509EC() {
       point_ = com.google.protobuf.ByteString.EMPTY;
       secret_ = com.google.protobuf.ByteString.EMPTY;
     }
@@ -7705,7 +9930,7 @@ public final class DarcProto {
     getUnknownFields() {
       return this.unknownFields;
     }
-    private SignerEd25519(
+    private SignerX509EC(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
@@ -7752,14 +9977,14 @@ public final class DarcProto {
     }
     public static final com.google.protobuf.Descriptors.Descriptor
         getDescriptor() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_descriptor;
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_descriptor;
     }
 
     protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
         internalGetFieldAccessorTable() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_fieldAccessorTable
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_fieldAccessorTable
           .ensureFieldAccessorsInitialized(
-              ch.epfl.dedis.proto.DarcProto.SignerEd25519.class, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder.class);
+              ch.epfl.dedis.proto.DarcProto.SignerX509EC.class, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder.class);
     }
 
     private int bitField0_;
@@ -7845,10 +10070,10 @@ public final class DarcProto {
       if (obj == this) {
        return true;
       }
-      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignerEd25519)) {
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignerX509EC)) {
         return super.equals(obj);
       }
-      ch.epfl.dedis.proto.DarcProto.SignerEd25519 other = (ch.epfl.dedis.proto.DarcProto.SignerEd25519) obj;
+      ch.epfl.dedis.proto.DarcProto.SignerX509EC other = (ch.epfl.dedis.proto.DarcProto.SignerX509EC) obj;
 
       boolean result = true;
       result = result && (hasPoint() == other.hasPoint());
@@ -7885,69 +10110,69 @@ public final class DarcProto {
       return hash;
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         java.nio.ByteBuffer data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         java.nio.ByteBuffer data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         com.google.protobuf.ByteString data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         com.google.protobuf.ByteString data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(byte[] data)
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(byte[] data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         byte[] data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseDelimitedFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseDelimitedFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseDelimitedFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseDelimitedFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         com.google.protobuf.CodedInputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
@@ -7959,7 +10184,7 @@ public final class DarcProto {
     public static Builder newBuilder() {
       return DEFAULT_INSTANCE.toBuilder();
     }
-    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignerEd25519 prototype) {
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignerX509EC prototype) {
       return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
     }
     public Builder toBuilder() {
@@ -7975,28 +10200,29 @@ public final class DarcProto {
     }
     /**
      * <pre>
-     * SignerEd25519 holds a public and private keys necessary to sign Darcs
+     * SignerX509EC holds a public and private keys necessary to sign Darcs,
+     * but the private key will not be given out.
      * </pre>
      *
-     * Protobuf type {@code SignerEd25519}
+     * Protobuf type {@code SignerX509EC}
      */
     public static final class Builder extends
         com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
-        // @@protoc_insertion_point(builder_implements:SignerEd25519)
-        ch.epfl.dedis.proto.DarcProto.SignerEd25519OrBuilder {
+        // @@protoc_insertion_point(builder_implements:SignerX509EC)
+        ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder {
       public static final com.google.protobuf.Descriptors.Descriptor
           getDescriptor() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_descriptor;
       }
 
       protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
           internalGetFieldAccessorTable() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_fieldAccessorTable
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_fieldAccessorTable
             .ensureFieldAccessorsInitialized(
-                ch.epfl.dedis.proto.DarcProto.SignerEd25519.class, ch.epfl.dedis.proto.DarcProto.SignerEd25519.Builder.class);
+                ch.epfl.dedis.proto.DarcProto.SignerX509EC.class, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder.class);
       }
 
-      // Construct using ch.epfl.dedis.proto.DarcProto.SignerEd25519.newBuilder()
+      // Construct using ch.epfl.dedis.proto.DarcProto.SignerX509EC.newBuilder()
       private Builder() {
         maybeForceBuilderInitialization();
       }
@@ -8022,23 +10248,23 @@ public final class DarcProto {
 
       public com.google.protobuf.Descriptors.Descriptor
           getDescriptorForType() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerEd25519_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_descriptor;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getDefaultInstanceForType() {
-        return ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance();
+      public ch.epfl.dedis.proto.DarcProto.SignerX509EC getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance();
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 build() {
-        ch.epfl.dedis.proto.DarcProto.SignerEd25519 result = buildPartial();
+      public ch.epfl.dedis.proto.DarcProto.SignerX509EC build() {
+        ch.epfl.dedis.proto.DarcProto.SignerX509EC result = buildPartial();
         if (!result.isInitialized()) {
           throw newUninitializedMessageException(result);
         }
         return result;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignerEd25519 buildPartial() {
-        ch.epfl.dedis.proto.DarcProto.SignerEd25519 result = new ch.epfl.dedis.proto.DarcProto.SignerEd25519(this);
+      public ch.epfl.dedis.proto.DarcProto.SignerX509EC buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.SignerX509EC result = new ch.epfl.dedis.proto.DarcProto.SignerX509EC(this);
         int from_bitField0_ = bitField0_;
         int to_bitField0_ = 0;
         if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
@@ -8081,16 +10307,16 @@ public final class DarcProto {
         return (Builder) super.addRepeatedField(field, value);
       }
       public Builder mergeFrom(com.google.protobuf.Message other) {
-        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignerEd25519) {
-          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignerEd25519)other);
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignerX509EC) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignerX509EC)other);
         } else {
           super.mergeFrom(other);
           return this;
         }
       }
 
-      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignerEd25519 other) {
-        if (other == ch.epfl.dedis.proto.DarcProto.SignerEd25519.getDefaultInstance()) return this;
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignerX509EC other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance()) return this;
         if (other.hasPoint()) {
           setPoint(other.getPoint());
         }
@@ -8116,11 +10342,11 @@ public final class DarcProto {
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws java.io.IOException {
-        ch.epfl.dedis.proto.DarcProto.SignerEd25519 parsedMessage = null;
+        ch.epfl.dedis.proto.DarcProto.SignerX509EC parsedMessage = null;
         try {
           parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
         } catch (com.google.protobuf.InvalidProtocolBufferException e) {
-          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignerEd25519) e.getUnfinishedMessage();
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignerX509EC) e.getUnfinishedMessage();
           throw e.unwrapIOException();
         } finally {
           if (parsedMessage != null) {
@@ -8211,86 +10437,113 @@ public final class DarcProto {
       }
 
 
-      // @@protoc_insertion_point(builder_scope:SignerEd25519)
+      // @@protoc_insertion_point(builder_scope:SignerX509EC)
     }
 
-    // @@protoc_insertion_point(class_scope:SignerEd25519)
-    private static final ch.epfl.dedis.proto.DarcProto.SignerEd25519 DEFAULT_INSTANCE;
+    // @@protoc_insertion_point(class_scope:SignerX509EC)
+    private static final ch.epfl.dedis.proto.DarcProto.SignerX509EC DEFAULT_INSTANCE;
     static {
-      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignerEd25519();
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignerX509EC();
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.SignerEd25519 getDefaultInstance() {
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC getDefaultInstance() {
       return DEFAULT_INSTANCE;
     }
 
-    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignerEd25519>
-        PARSER = new com.google.protobuf.AbstractParser<SignerEd25519>() {
-      public SignerEd25519 parsePartialFrom(
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignerX509EC>
+        PARSER = new com.google.protobuf.AbstractParser<SignerX509EC>() {
+      public SignerX509EC parsePartialFrom(
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws com.google.protobuf.InvalidProtocolBufferException {
-          return new SignerEd25519(input, extensionRegistry);
+          return new SignerX509EC(input, extensionRegistry);
       }
     };
 
-    public static com.google.protobuf.Parser<SignerEd25519> parser() {
+    public static com.google.protobuf.Parser<SignerX509EC> parser() {
       return PARSER;
     }
 
     @java.lang.Override
-    public com.google.protobuf.Parser<SignerEd25519> getParserForType() {
+    public com.google.protobuf.Parser<SignerX509EC> getParserForType() {
       return PARSER;
     }
 
-    public ch.epfl.dedis.proto.DarcProto.SignerEd25519 getDefaultInstanceForType() {
+    public ch.epfl.dedis.proto.DarcProto.SignerX509EC getDefaultInstanceForType() {
       return DEFAULT_INSTANCE;
     }
 
   }
 
-  public interface SignerX509ECOrBuilder extends
-      // @@protoc_insertion_point(interface_extends:SignerX509EC)
+  public interface SignerX509ChainOrBuilder extends
+      // @@protoc_insertion_point(interface_extends:SignerX509Chain)
       com.google.protobuf.MessageOrBuilder {
 
     /**
-     * <code>required bytes point = 1;</code>
+     * <pre>
+     * 	 Root is the DER-encoding of the trusted root certificate
+     * </pre>
+     *
+     * <code>required bytes root = 1;</code>
      */
-    boolean hasPoint();
+    boolean hasRoot();
     /**
-     * <code>required bytes point = 1;</code>
+     * <pre>
+     * 	 Root is the DER-encoding of the trusted root certificate
+     * </pre>
+     *
+     * <code>required bytes root = 1;</code>
      */
-    com.google.protobuf.ByteString getPoint();
+    com.google.protobuf.ByteString getRoot();
 
     /**
-     * <code>required bytes secret = 2;</code>
+     * <pre>
+     * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+     * 	 eventual intermediate certificates
+     * </pre>
+     *
+     * <code>repeated bytes chain = 2;</code>
      */
-    boolean hasSecret();
+    java.util.List<com.google.protobuf.ByteString> getChainList();
     /**
-     * <code>required bytes secret = 2;</code>
+     * <pre>
+     * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+     * 	 eventual intermediate certificates
+     * </pre>
+     *
+     * <code>repeated bytes chain = 2;</code>
+     */
+    int getChainCount();
+    /**
+     * <pre>
+     * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+     * 	 eventual intermediate certificates
+     * </pre>
+     *
+     * <code>repeated bytes chain = 2;</code>
      */
-    com.google.protobuf.ByteString getSecret();
+    com.google.protobuf.ByteString getChain(int index);
   }
   /**
    * <pre>
-   * SignerX509EC holds a public and private keys necessary to sign Darcs,
-   * but the private key will not be given out.
+   * SignerX509Chain holds a certificate chain and the private key of its leaf
+   * certificate. The private key will not be given out.
    * </pre>
    *
-   * Protobuf type {@code SignerX509EC}
+   * Protobuf type {@code SignerX509Chain}
    */
-  public  static final class SignerX509EC extends
+  public  static final class SignerX509Chain extends
       com.google.protobuf.GeneratedMessageV3 implements
-      // @@protoc_insertion_point(message_implements:SignerX509EC)
-      SignerX509ECOrBuilder {
+      // @@protoc_insertion_point(message_implements:SignerX509Chain)
+      SignerX509ChainOrBuilder {
   private static final long serialVersionUID = 0L;
-    // Use SignerX509EC.newBuilder() to construct.
-    private SignerX509EC(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
+    // Use SignerX509Chain.newBuilder() to construct.
+    private SignerX509Chain(com.google.protobuf.GeneratedMessageV3.Builder<?> builder) {
       super(builder);
     }
-    private SignerX509EC() {
-      point_ = com.google.protobuf.ByteString.EMPTY;
-      secret_ = com.google.protobuf.ByteString.EMPTY;
+    private SignerX509Chain() {
+      root_ = com.google.protobuf.ByteString.EMPTY;
+      chain_ = java.util.Collections.emptyList();
     }
 
     @java.lang.Override
@@ -8298,7 +10551,7 @@ public final class DarcProto {
     getUnknownFields() {
       return this.unknownFields;
     }
-    private SignerX509EC(
+    private SignerX509Chain(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
@@ -8323,12 +10576,15 @@ public final class DarcProto {
             }
             case 10: {
               bitField0_ |= 0x00000001;
-              point_ = input.readBytes();
+              root_ = input.readBytes();
               break;
             }
             case 18: {
-              bitField0_ |= 0x00000002;
-              secret_ = input.readBytes();
+              if (!((mutable_bitField0_ & 0x00000002) == 0x00000002)) {
+                chain_ = new java.util.ArrayList<com.google.protobuf.ByteString>();
+                mutable_bitField0_ |= 0x00000002;
+              }
+              chain_.add(input.readBytes());
               break;
             }
           }
@@ -8339,51 +10595,84 @@ public final class DarcProto {
         throw new com.google.protobuf.InvalidProtocolBufferException(
             e).setUnfinishedMessage(this);
       } finally {
+        if (((mutable_bitField0_ & 0x00000002) == 0x00000002)) {
+          chain_ = java.util.Collections.unmodifiableList(chain_);
+        }
         this.unknownFields = unknownFields.build();
         makeExtensionsImmutable();
       }
     }
     public static final com.google.protobuf.Descriptors.Descriptor
         getDescriptor() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_descriptor;
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509Chain_descriptor;
     }
 
     protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
         internalGetFieldAccessorTable() {
-      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_fieldAccessorTable
+      return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509Chain_fieldAccessorTable
           .ensureFieldAccessorsInitialized(
-              ch.epfl.dedis.proto.DarcProto.SignerX509EC.class, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder.class);
+              ch.epfl.dedis.proto.DarcProto.SignerX509Chain.class, ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder.class);
     }
 
     private int bitField0_;
-    public static final int POINT_FIELD_NUMBER = 1;
-    private com.google.protobuf.ByteString point_;
+    public static final int ROOT_FIELD_NUMBER = 1;
+    private com.google.protobuf.ByteString root_;
     /**
-     * <code>required bytes point = 1;</code>
+     * <pre>
+     * 	 Root is the DER-encoding of the trusted root certificate
+     * </pre>
+     *
+     * <code>required bytes root = 1;</code>
      */
-    public boolean hasPoint() {
+    public boolean hasRoot() {
       return ((bitField0_ & 0x00000001) == 0x00000001);
     }
     /**
-     * <code>required bytes point = 1;</code>
+     * <pre>
+     * 	 Root is the DER-encoding of the trusted root certificate
+     * </pre>
+     *
+     * <code>required bytes root = 1;</code>
      */
-    public com.google.protobuf.ByteString getPoint() {
-      return point_;
+    public com.google.protobuf.ByteString getRoot() {
+      return root_;
     }
 
-    public static final int SECRET_FIELD_NUMBER = 2;
-    private com.google.protobuf.ByteString secret_;
+    public static final int CHAIN_FIELD_NUMBER = 2;
+    private java.util.List<com.google.protobuf.ByteString> chain_;
     /**
-     * <code>required bytes secret = 2;</code>
+     * <pre>
+     * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+     * 	 eventual intermediate certificates
+     * </pre>
+     *
+     * <code>repeated bytes chain = 2;</code>
      */
-    public boolean hasSecret() {
-      return ((bitField0_ & 0x00000002) == 0x00000002);
+    public java.util.List<com.google.protobuf.ByteString>
+        getChainList() {
+      return chain_;
     }
     /**
-     * <code>required bytes secret = 2;</code>
+     * <pre>
+     * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+     * 	 eventual intermediate certificates
+     * </pre>
+     *
+     * <code>repeated bytes chain = 2;</code>
      */
-    public com.google.protobuf.ByteString getSecret() {
-      return secret_;
+    public int getChainCount() {
+      return chain_.size();
+    }
+    /**
+     * <pre>
+     * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+     * 	 eventual intermediate certificates
+     * </pre>
+     *
+     * <code>repeated bytes chain = 2;</code>
+     */
+    public com.google.protobuf.ByteString getChain(int index) {
+      return chain_.get(index);
     }
 
     private byte memoizedIsInitialized = -1;
@@ -8392,11 +10681,7 @@ public final class DarcProto {
       if (isInitialized == 1) return true;
       if (isInitialized == 0) return false;
 
-      if (!hasPoint()) {
-        memoizedIsInitialized = 0;
-        return false;
-      }
-      if (!hasSecret()) {
+      if (!hasRoot()) {
         memoizedIsInitialized = 0;
         return false;
       }
@@ -8407,10 +10692,10 @@ public final class DarcProto {
     public void writeTo(com.google.protobuf.CodedOutputStream output)
                         throws java.io.IOException {
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
-        output.writeBytes(1, point_);
+        output.writeBytes(1, root_);
       }
-      if (((bitField0_ & 0x00000002) == 0x00000002)) {
-        output.writeBytes(2, secret_);
+      for (int i = 0; i < chain_.size(); i++) {
+        output.writeBytes(2, chain_.get(i));
       }
       unknownFields.writeTo(output);
     }
@@ -8422,11 +10707,16 @@ public final class DarcProto {
       size = 0;
       if (((bitField0_ & 0x00000001) == 0x00000001)) {
         size += com.google.protobuf.CodedOutputStream
-          .computeBytesSize(1, point_);
+          .computeBytesSize(1, root_);
       }
-      if (((bitField0_ & 0x00000002) == 0x00000002)) {
-        size += com.google.protobuf.CodedOutputStream
-          .computeBytesSize(2, secret_);
+      {
+        int dataSize = 0;
+        for (int i = 0; i < chain_.size(); i++) {
+          dataSize += com.google.protobuf.CodedOutputStream
+            .computeBytesSizeNoTag(chain_.get(i));
+        }
+        size += dataSize;
+        size += 1 * getChainList().size();
       }
       size += unknownFields.getSerializedSize();
       memoizedSize = size;
@@ -8438,22 +10728,19 @@ public final class DarcProto {
       if (obj == this) {
        return true;
       }
-      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignerX509EC)) {
+      if (!(obj instanceof ch.epfl.dedis.proto.DarcProto.SignerX509Chain)) {
         return super.equals(obj);
       }
-      ch.epfl.dedis.proto.DarcProto.SignerX509EC other = (ch.epfl.dedis.proto.DarcProto.SignerX509EC) obj;
+      ch.epfl.dedis.proto.DarcProto.SignerX509Chain other = (ch.epfl.dedis.proto.DarcProto.SignerX509Chain) obj;
 
       boolean result = true;
-      result = result && (hasPoint() == other.hasPoint());
-      if (hasPoint()) {
-        result = result && getPoint()
-            .equals(other.getPoint());
-      }
-      result = result && (hasSecret() == other.hasSecret());
-      if (hasSecret()) {
-        result = result && getSecret()
-            .equals(other.getSecret());
+      result = result && (hasRoot() == other.hasRoot());
+      if (hasRoot()) {
+        result = result && getRoot()
+            .equals(other.getRoot());
       }
+      result = result && getChainList()
+          .equals(other.getChainList());
       result = result && unknownFields.equals(other.unknownFields);
       return result;
     }
@@ -8465,82 +10752,82 @@ public final class DarcProto {
       }
       int hash = 41;
       hash = (19 * hash) + getDescriptor().hashCode();
-      if (hasPoint()) {
-        hash = (37 * hash) + POINT_FIELD_NUMBER;
-        hash = (53 * hash) + getPoint().hashCode();
+      if (hasRoot()) {
+        hash = (37 * hash) + ROOT_FIELD_NUMBER;
+        hash = (53 * hash) + getRoot().hashCode();
       }
-      if (hasSecret()) {
-        hash = (37 * hash) + SECRET_FIELD_NUMBER;
-        hash = (53 * hash) + getSecret().hashCode();
+      if (getChainCount() > 0) {
+        hash = (37 * hash) + CHAIN_FIELD_NUMBER;
+        hash = (53 * hash) + getChainList().hashCode();
       }
       hash = (29 * hash) + unknownFields.hashCode();
       memoizedHashCode = hash;
       return hash;
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         java.nio.ByteBuffer data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         java.nio.ByteBuffer data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         com.google.protobuf.ByteString data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         com.google.protobuf.ByteString data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(byte[] data)
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(byte[] data)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         byte[] data,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws com.google.protobuf.InvalidProtocolBufferException {
       return PARSER.parseFrom(data, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseDelimitedFrom(java.io.InputStream input)
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseDelimitedFrom(java.io.InputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseDelimitedFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseDelimitedFrom(
         java.io.InputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseDelimitedWithIOException(PARSER, input, extensionRegistry);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         com.google.protobuf.CodedInputStream input)
         throws java.io.IOException {
       return com.google.protobuf.GeneratedMessageV3
           .parseWithIOException(PARSER, input);
     }
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC parseFrom(
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain parseFrom(
         com.google.protobuf.CodedInputStream input,
         com.google.protobuf.ExtensionRegistryLite extensionRegistry)
         throws java.io.IOException {
@@ -8552,7 +10839,7 @@ public final class DarcProto {
     public static Builder newBuilder() {
       return DEFAULT_INSTANCE.toBuilder();
     }
-    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignerX509EC prototype) {
+    public static Builder newBuilder(ch.epfl.dedis.proto.DarcProto.SignerX509Chain prototype) {
       return DEFAULT_INSTANCE.toBuilder().mergeFrom(prototype);
     }
     public Builder toBuilder() {
@@ -8568,29 +10855,29 @@ public final class DarcProto {
     }
     /**
      * <pre>
-     * SignerX509EC holds a public and private keys necessary to sign Darcs,
-     * but the private key will not be given out.
+     * SignerX509Chain holds a certificate chain and the private key of its leaf
+     * certificate. The private key will not be given out.
      * </pre>
      *
-     * Protobuf type {@code SignerX509EC}
+     * Protobuf type {@code SignerX509Chain}
      */
     public static final class Builder extends
         com.google.protobuf.GeneratedMessageV3.Builder<Builder> implements
-        // @@protoc_insertion_point(builder_implements:SignerX509EC)
-        ch.epfl.dedis.proto.DarcProto.SignerX509ECOrBuilder {
+        // @@protoc_insertion_point(builder_implements:SignerX509Chain)
+        ch.epfl.dedis.proto.DarcProto.SignerX509ChainOrBuilder {
       public static final com.google.protobuf.Descriptors.Descriptor
           getDescriptor() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509Chain_descriptor;
       }
 
       protected com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
           internalGetFieldAccessorTable() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_fieldAccessorTable
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509Chain_fieldAccessorTable
             .ensureFieldAccessorsInitialized(
-                ch.epfl.dedis.proto.DarcProto.SignerX509EC.class, ch.epfl.dedis.proto.DarcProto.SignerX509EC.Builder.class);
+                ch.epfl.dedis.proto.DarcProto.SignerX509Chain.class, ch.epfl.dedis.proto.DarcProto.SignerX509Chain.Builder.class);
       }
 
-      // Construct using ch.epfl.dedis.proto.DarcProto.SignerX509EC.newBuilder()
+      // Construct using ch.epfl.dedis.proto.DarcProto.SignerX509Chain.newBuilder()
       private Builder() {
         maybeForceBuilderInitialization();
       }
@@ -8607,42 +10894,43 @@ public final class DarcProto {
       }
       public Builder clear() {
         super.clear();
-        point_ = com.google.protobuf.ByteString.EMPTY;
+        root_ = com.google.protobuf.ByteString.EMPTY;
         bitField0_ = (bitField0_ & ~0x00000001);
-        secret_ = com.google.protobuf.ByteString.EMPTY;
+        chain_ = java.util.Collections.emptyList();
         bitField0_ = (bitField0_ & ~0x00000002);
         return this;
       }
 
       public com.google.protobuf.Descriptors.Descriptor
           getDescriptorForType() {
-        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509EC_descriptor;
+        return ch.epfl.dedis.proto.DarcProto.internal_static_SignerX509Chain_descriptor;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignerX509EC getDefaultInstanceForType() {
-        return ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance();
+      public ch.epfl.dedis.proto.DarcProto.SignerX509Chain getDefaultInstanceForType() {
+        return ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance();
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignerX509EC build() {
-        ch.epfl.dedis.proto.DarcProto.SignerX509EC result = buildPartial();
+      public ch.epfl.dedis.proto.DarcProto.SignerX509Chain build() {
+        ch.epfl.dedis.proto.DarcProto.SignerX509Chain result = buildPartial();
         if (!result.isInitialized()) {
           throw newUninitializedMessageException(result);
         }
         return result;
       }
 
-      public ch.epfl.dedis.proto.DarcProto.SignerX509EC buildPartial() {
-        ch.epfl.dedis.proto.DarcProto.SignerX509EC result = new ch.epfl.dedis.proto.DarcProto.SignerX509EC(this);
+      public ch.epfl.dedis.proto.DarcProto.SignerX509Chain buildPartial() {
+        ch.epfl.dedis.proto.DarcProto.SignerX509Chain result = new ch.epfl.dedis.proto.DarcProto.SignerX509Chain(this);
         int from_bitField0_ = bitField0_;
         int to_bitField0_ = 0;
         if (((from_bitField0_ & 0x00000001) == 0x00000001)) {
           to_bitField0_ |= 0x00000001;
         }
-        result.point_ = point_;
-        if (((from_bitField0_ & 0x00000002) == 0x00000002)) {
-          to_bitField0_ |= 0x00000002;
+        result.root_ = root_;
+        if (((bitField0_ & 0x00000002) == 0x00000002)) {
+          chain_ = java.util.Collections.unmodifiableList(chain_);
+          bitField0_ = (bitField0_ & ~0x00000002);
         }
-        result.secret_ = secret_;
+        result.chain_ = chain_;
         result.bitField0_ = to_bitField0_;
         onBuilt();
         return result;
@@ -8675,21 +10963,28 @@ public final class DarcProto {
         return (Builder) super.addRepeatedField(field, value);
       }
       public Builder mergeFrom(com.google.protobuf.Message other) {
-        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignerX509EC) {
-          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignerX509EC)other);
+        if (other instanceof ch.epfl.dedis.proto.DarcProto.SignerX509Chain) {
+          return mergeFrom((ch.epfl.dedis.proto.DarcProto.SignerX509Chain)other);
         } else {
           super.mergeFrom(other);
           return this;
         }
       }
 
-      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignerX509EC other) {
-        if (other == ch.epfl.dedis.proto.DarcProto.SignerX509EC.getDefaultInstance()) return this;
-        if (other.hasPoint()) {
-          setPoint(other.getPoint());
+      public Builder mergeFrom(ch.epfl.dedis.proto.DarcProto.SignerX509Chain other) {
+        if (other == ch.epfl.dedis.proto.DarcProto.SignerX509Chain.getDefaultInstance()) return this;
+        if (other.hasRoot()) {
+          setRoot(other.getRoot());
         }
-        if (other.hasSecret()) {
-          setSecret(other.getSecret());
+        if (!other.chain_.isEmpty()) {
+          if (chain_.isEmpty()) {
+            chain_ = other.chain_;
+            bitField0_ = (bitField0_ & ~0x00000002);
+          } else {
+            ensureChainIsMutable();
+            chain_.addAll(other.chain_);
+          }
+          onChanged();
         }
         this.mergeUnknownFields(other.unknownFields);
         onChanged();
@@ -8697,10 +10992,7 @@ public final class DarcProto {
       }
 
       public final boolean isInitialized() {
-        if (!hasPoint()) {
-          return false;
-        }
-        if (!hasSecret()) {
+        if (!hasRoot()) {
           return false;
         }
         return true;
@@ -8710,11 +11002,11 @@ public final class DarcProto {
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws java.io.IOException {
-        ch.epfl.dedis.proto.DarcProto.SignerX509EC parsedMessage = null;
+        ch.epfl.dedis.proto.DarcProto.SignerX509Chain parsedMessage = null;
         try {
           parsedMessage = PARSER.parsePartialFrom(input, extensionRegistry);
         } catch (com.google.protobuf.InvalidProtocolBufferException e) {
-          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignerX509EC) e.getUnfinishedMessage();
+          parsedMessage = (ch.epfl.dedis.proto.DarcProto.SignerX509Chain) e.getUnfinishedMessage();
           throw e.unwrapIOException();
         } finally {
           if (parsedMessage != null) {
@@ -8725,72 +11017,160 @@ public final class DarcProto {
       }
       private int bitField0_;
 
-      private com.google.protobuf.ByteString point_ = com.google.protobuf.ByteString.EMPTY;
+      private com.google.protobuf.ByteString root_ = com.google.protobuf.ByteString.EMPTY;
       /**
-       * <code>required bytes point = 1;</code>
+       * <pre>
+       * 	 Root is the DER-encoding of the trusted root certificate
+       * </pre>
+       *
+       * <code>required bytes root = 1;</code>
        */
-      public boolean hasPoint() {
+      public boolean hasRoot() {
         return ((bitField0_ & 0x00000001) == 0x00000001);
       }
       /**
-       * <code>required bytes point = 1;</code>
+       * <pre>
+       * 	 Root is the DER-encoding of the trusted root certificate
+       * </pre>
+       *
+       * <code>required bytes root = 1;</code>
        */
-      public com.google.protobuf.ByteString getPoint() {
-        return point_;
+      public com.google.protobuf.ByteString getRoot() {
+        return root_;
       }
       /**
-       * <code>required bytes point = 1;</code>
+       * <pre>
+       * 	 Root is the DER-encoding of the trusted root certificate
+       * </pre>
+       *
+       * <code>required bytes root = 1;</code>
        */
-      public Builder setPoint(com.google.protobuf.ByteString value) {
+      public Builder setRoot(com.google.protobuf.ByteString value) {
         if (value == null) {
     throw new NullPointerException();
   }
   bitField0_ |= 0x00000001;
-        point_ = value;
+        root_ = value;
         onChanged();
         return this;
       }
       /**
-       * <code>required bytes point = 1;</code>
+       * <pre>
+       * 	 Root is the DER-encoding of the trusted root certificate
+       * </pre>
+       *
+       * <code>required bytes root = 1;</code>
        */
-      public Builder clearPoint() {
+      public Builder clearRoot() {
         bitField0_ = (bitField0_ & ~0x00000001);
-        point_ = getDefaultInstance().getPoint();
+        root_ = getDefaultInstance().getRoot();
         onChanged();
         return this;
       }
 
-      private com.google.protobuf.ByteString secret_ = com.google.protobuf.ByteString.EMPTY;
+      private java.util.List<com.google.protobuf.ByteString> chain_ = java.util.Collections.emptyList();
+      private void ensureChainIsMutable() {
+        if (!((bitField0_ & 0x00000002) == 0x00000002)) {
+          chain_ = new java.util.ArrayList<com.google.protobuf.ByteString>(chain_);
+          bitField0_ |= 0x00000002;
+         }
+      }
       /**
-       * <code>required bytes secret = 2;</code>
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
        */
-      public boolean hasSecret() {
-        return ((bitField0_ & 0x00000002) == 0x00000002);
+      public java.util.List<com.google.protobuf.ByteString>
+          getChainList() {
+        return java.util.Collections.unmodifiableList(chain_);
       }
       /**
-       * <code>required bytes secret = 2;</code>
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
        */
-      public com.google.protobuf.ByteString getSecret() {
-        return secret_;
+      public int getChainCount() {
+        return chain_.size();
       }
       /**
-       * <code>required bytes secret = 2;</code>
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
        */
-      public Builder setSecret(com.google.protobuf.ByteString value) {
+      public com.google.protobuf.ByteString getChain(int index) {
+        return chain_.get(index);
+      }
+      /**
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
+       */
+      public Builder setChain(
+          int index, com.google.protobuf.ByteString value) {
         if (value == null) {
     throw new NullPointerException();
   }
-  bitField0_ |= 0x00000002;
-        secret_ = value;
+  ensureChainIsMutable();
+        chain_.set(index, value);
         onChanged();
         return this;
       }
       /**
-       * <code>required bytes secret = 2;</code>
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
        */
-      public Builder clearSecret() {
+      public Builder addChain(com.google.protobuf.ByteString value) {
+        if (value == null) {
+    throw new NullPointerException();
+  }
+  ensureChainIsMutable();
+        chain_.add(value);
+        onChanged();
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
+       */
+      public Builder addAllChain(
+          java.lang.Iterable<? extends com.google.protobuf.ByteString> values) {
+        ensureChainIsMutable();
+        com.google.protobuf.AbstractMessageLite.Builder.addAll(
+            values, chain_);
+        onChanged();
+        return this;
+      }
+      /**
+       * <pre>
+       * 	 Chain holds the DER-encoding of the leaf certificate, followed by
+       * 	 eventual intermediate certificates
+       * </pre>
+       *
+       * <code>repeated bytes chain = 2;</code>
+       */
+      public Builder clearChain() {
+        chain_ = java.util.Collections.emptyList();
         bitField0_ = (bitField0_ & ~0x00000002);
-        secret_ = getDefaultInstance().getSecret();
         onChanged();
         return this;
       }
@@ -8805,39 +11185,39 @@ public final class DarcProto {
       }
 
 
-      // @@protoc_insertion_point(builder_scope:SignerX509EC)
+      // @@protoc_insertion_point(builder_scope:SignerX509Chain)
     }
 
-    // @@protoc_insertion_point(class_scope:SignerX509EC)
-    private static final ch.epfl.dedis.proto.DarcProto.SignerX509EC DEFAULT_INSTANCE;
+    // @@protoc_insertion_point(class_scope:SignerX509Chain)
+    private static final ch.epfl.dedis.proto.DarcProto.SignerX509Chain DEFAULT_INSTANCE;
     static {
-      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignerX509EC();
+      DEFAULT_INSTANCE = new ch.epfl.dedis.proto.DarcProto.SignerX509Chain();
     }
 
-    public static ch.epfl.dedis.proto.DarcProto.SignerX509EC getDefaultInstance() {
+    public static ch.epfl.dedis.proto.DarcProto.SignerX509Chain getDefaultInstance() {
       return DEFAULT_INSTANCE;
     }
 
-    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignerX509EC>
-        PARSER = new com.google.protobuf.AbstractParser<SignerX509EC>() {
-      public SignerX509EC parsePartialFrom(
+    @java.lang.Deprecated public static final com.google.protobuf.Parser<SignerX509Chain>
+        PARSER = new com.google.protobuf.AbstractParser<SignerX509Chain>() {
+      public SignerX509Chain parsePartialFrom(
           com.google.protobuf.CodedInputStream input,
           com.google.protobuf.ExtensionRegistryLite extensionRegistry)
           throws com.google.protobuf.InvalidProtocolBufferException {
-          return new SignerX509EC(input, extensionRegistry);
+          return new SignerX509Chain(input, extensionRegistry);
       }
     };
 
-    public static com.google.protobuf.Parser<SignerX509EC> parser() {
+    public static com.google.protobuf.Parser<SignerX509Chain> parser() {
       return PARSER;
     }
 
     @java.lang.Override
-    public com.google.protobuf.Parser<SignerX509EC> getParserForType() {
+    public com.google.protobuf.Parser<SignerX509Chain> getParserForType() {
       return PARSER;
     }
 
-    public ch.epfl.dedis.proto.DarcProto.SignerX509EC getDefaultInstanceForType() {
+    public ch.epfl.dedis.proto.DarcProto.SignerX509Chain getDefaultInstanceForType() {
       return DEFAULT_INSTANCE;
     }
 
@@ -8863,6 +11243,16 @@ public final class DarcProto {
   private static final 
     com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
       internal_static_IdentityX509EC_fieldAccessorTable;
+  private static final com.google.protobuf.Descriptors.Descriptor
+    internal_static_IdentityX509Chain_descriptor;
+  private static final 
+    com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+      internal_static_IdentityX509Chain_fieldAccessorTable;
+  private static final com.google.protobuf.Descriptors.Descriptor
+    internal_static_X509ChainSignature_descriptor;
+  private static final 
+    com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+      internal_static_X509ChainSignature_fieldAccessorTable;
   private static final com.google.protobuf.Descriptors.Descriptor
     internal_static_IdentityDarc_descriptor;
   private static final 
@@ -8893,6 +11283,11 @@ public final class DarcProto {
   private static final 
     com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
       internal_static_SignerX509EC_fieldAccessorTable;
+  private static final com.google.protobuf.Descriptors.Descriptor
+    internal_static_SignerX509Chain_descriptor;
+  private static final 
+    com.google.protobuf.GeneratedMessageV3.FieldAccessorTable
+      internal_static_SignerX509Chain_fieldAccessorTable;
 
   public static com.google.protobuf.Descriptors.FileDescriptor
       getDescriptor() {
@@ -8906,20 +11301,26 @@ public final class DarcProto {
       "Identity\022\030\n\005users\030\002 \003(\0132\t.Identity\022\017\n\007ve" +
       "rsion\030\003 \002(\021\022\023\n\013description\030\004 \001(\014\022\016\n\006base" +
       "id\030\005 \001(\014\022\035\n\tsignature\030\006 \001(\0132\n.Signature\"" +
-      "k\n\010Identity\022\033\n\004darc\030\001 \001(\0132\r.IdentityDarc" +
-      "\022!\n\007ed25519\030\002 \001(\0132\020.IdentityEd25519\022\037\n\006x" +
-      "509ec\030\003 \001(\0132\017.IdentityX509EC\" \n\017Identity" +
+      "\222\001\n\010Identity\022\033\n\004darc\030\001 \001(\0132\r.IdentityDar" +
+      "c\022!\n\007ed25519\030\002 \001(\0132\020.IdentityEd25519\022\037\n\006" +
+      "x509ec\030\003 \001(\0132\017.IdentityX509EC\022%\n\tx509cha" +
+      "in\030\004 \001(\0132\022.IdentityX509Chain\" \n\017Identity" +
       "Ed25519\022\r\n\005point\030\001 \002(\014\" \n\016IdentityX509EC" +
-      "\022\016\n\006public\030\001 \002(\014\"\032\n\014IdentityDarc\022\n\n\002id\030\001" +
-      " \002(\014\"E\n\tSignature\022\021\n\tsignature\030\001 \002(\014\022%\n\r",
-      "signaturepath\030\002 \002(\0132\016.SignaturePath\"N\n\rS" +
-      "ignaturePath\022\024\n\005darcs\030\001 \003(\0132\005.Darc\022\031\n\006si" +
-      "gner\030\002 \002(\0132\t.Identity\022\014\n\004role\030\003 \002(\021\"H\n\006S" +
-      "igner\022\037\n\007ed25519\030\001 \001(\0132\016.SignerEd25519\022\035" +
-      "\n\006x509ec\030\002 \001(\0132\r.SignerX509EC\".\n\rSignerE" +
-      "d25519\022\r\n\005point\030\001 \002(\014\022\016\n\006secret\030\002 \002(\014\"-\n" +
-      "\014SignerX509EC\022\r\n\005point\030\001 \002(\014\022\016\n\006secret\030\002" +
-      " \002(\014B \n\023ch.epfl.dedis.protoB\tDarcProto"
+      "\022\016\n\006public\030\001 \002(\014\"!\n\021IdentityX509Chain\022\014\n",
+      "\004root\030\001 \002(\014\"=\n\022X509ChainSignature\022\024\n\014cer" +
+      "tificates\030\001 \003(\014\022\021\n\tsignature\030\002 \002(\014\"\032\n\014Id" +
+      "entityDarc\022\n\n\002id\030\001 \002(\014\"E\n\tSignature\022\021\n\ts" +
+      "ignature\030\001 \002(\014\022%\n\rsignaturepath\030\002 \002(\0132\016." +
+      "SignaturePath\"N\n\rSignaturePath\022\024\n\005darcs\030" +
+      "\001 \003(\0132\005.Darc\022\031\n\006signer\030\002 \002(\0132\t.Identity\022" +
+      "\014\n\004role\030\003 \002(\021\"m\n\006Signer\022\037\n\007ed25519\030\001 \001(\013" +
+      "2\016.SignerEd25519\022\035\n\006x509ec\030\002 \001(\0132\r.Signe" +
+      "rX509EC\022#\n\tx509chain\030\003 \001(\0132\020.SignerX509C" +
+      "hain\".\n\rSignerEd25519\022\r\n\005point\030\001 \002(\014\022\016\n\006",
+      "secret\030\002 \002(\014\"-\n\014SignerX509EC\022\r\n\005point\030\001 " +
+      "\002(\014\022\016\n\006secret\030\002 \002(\014\".\n\017SignerX509Chain\022\014" +
+      "\n\004root\030\001 \002(\014\022\r\n\005chain\030\002 \003(\014B \n\023ch.epfl.d" +
+      "edis.protoB\tDarcProto"
     };
     com.google.protobuf.Descriptors.FileDescriptor.InternalDescriptorAssigner assigner =
         new com.google.protobuf.Descriptors.FileDescriptor.    InternalDescriptorAssigner() {
@@ -8944,7 +11345,7 @@ public final class DarcProto {
     internal_static_Identity_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_Identity_descriptor,
-        new java.lang.String[] { "Darc", "Ed25519", "X509Ec", });
+        new java.lang.String[] { "Darc", "Ed25519", "X509Ec", "X509Chain", });
     internal_static_IdentityEd25519_descriptor =
       getDescriptor().getMessageTypes().get(2);
     internal_static_IdentityEd25519_fieldAccessorTable = new
@@ -8957,42 +11358,60 @@ public final class DarcProto {
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_IdentityX509EC_descriptor,
         new java.lang.String[] { "Public", });
-    internal_static_IdentityDarc_descriptor =
+    internal_static_IdentityX509Chain_descriptor =
       getDescriptor().getMessageTypes().get(4);
+    internal_static_IdentityX509Chain_fieldAccessorTable = new
+      com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
+        internal_static_IdentityX509Chain_descriptor,
+        new java.lang.String[] { "Root", });
+    internal_static_X509ChainSignature_descriptor =
+      getDescriptor().getMessageTypes().get(5);
+    internal_static_X509ChainSignature_fieldAccessorTable = new
+      com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
+        internal_static_X509ChainSignature_descriptor,
+        new java.lang.String[] { "Certificates", "Signature", });
+    internal_static_IdentityDarc_descriptor =
+      getDescriptor().getMessageTypes().get(6);
     internal_static_IdentityDarc_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_IdentityDarc_descriptor,
         new java.lang.String[] { "Id", });
     internal_static_Signature_descriptor =
-      getDescriptor().getMessageTypes().get(5);
+      getDescriptor().getMessageTypes().get(7);
     internal_static_Signature_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_Signature_descriptor,
         new java.lang.String[] { "Signature", "Signaturepath", });
     internal_static_SignaturePath_descriptor =
-      getDescriptor().getMessageTypes().get(6);
+      getDescriptor().getMessageTypes().get(8);
     internal_static_SignaturePath_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_SignaturePath_descriptor,
         new java.lang.String[] { "Darcs", "Signer", "Role", });
     internal_static_Signer_descriptor =
-      getDescriptor().getMessageTypes().get(7);
+      getDescriptor().getMessageTypes().get(9);
     internal_static_Signer_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_Signer_descriptor,
-        new java.lang.String[] { "Ed25519", "X509Ec", });
+        new java.lang.String[] { "Ed25519", "X509Ec", "X509Chain", });
     internal_static_SignerEd25519_descriptor =
-      getDescriptor().getMessageTypes().get(8);
+      getDescriptor().getMessageTypes().get(10);
     internal_static_SignerEd25519_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_SignerEd25519_descriptor,
         new java.lang.String[] { "Point", "Secret", });
     internal_static_SignerX509EC_descriptor =
-      getDescriptor().getMessageTypes().get(9);
+      getDescriptor().getMessageTypes().get(11);
     internal_static_SignerX509EC_fieldAccessorTable = new
       com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
         internal_static_SignerX509EC_descriptor,
         new java.lang.String[] { "Point", "Secret", });
+    internal_static_SignerX509Chain_descriptor =
+      getDescriptor().getMessageTypes().get(12);
+    internal_static_SignerX509Chain_fieldAccessorTable = new
+      com.google.protobuf.GeneratedMessageV3.FieldAccessorTable(
+        internal_static_SignerX509Chain_descriptor,
+        new java.lang.String[] { "Root", "Chain", });
   }
 
   // @@protoc_insertion_point(outer_class_scope)
@@ -19,6 +19,7 @@ import (
 	"github.com/dedis/onet/cfgpath"
 	"github.com/dedis/onet/log"
 	"github.com/dedis/onet/network"
+	"github.com/qantik/qrgo"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -40,8 +41,14 @@ func main() {
 					Name:      "create",
 					Usage:     "create a write log-read skipchain",
 					Aliases:   []string{"cr"},
-					ArgsUsage: "group [private_key]",
-					Action:    mngCreate,
+					ArgsUsage: "group",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "admin",
+							Usage: "private key of the owner and user of the admin-darc",
+						},
+					},
+					Action: mngCreate,
 				},
 				{
 					Name:    "list",
@@ -49,6 +56,18 @@ func main() {
 					Aliases: []string{"ls"},
 					Action:  mngList,
 				},
+				{
+					Name:    "export",
+					Usage:   "export the url of the ocs-skipchain as a json-token",
+					Aliases: []string{"ex"},
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "qrcode, qr",
+							Usage: "print the token as a qrcode",
+						},
+					},
+					Action: mngExport,
+				},
 				{
 					Name:      "join",
 					Usage:     "join a write log-read skipchain",
@@ -64,6 +83,55 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:    "darc",
+			Usage:   "show and evolve darcs of the ocs-skipchain",
+			Aliases: []string{"d"},
+			Subcommands: []cli.Command{
+				{
+					Name:      "show",
+					Usage:     "show the latest version of a darc",
+					Aliases:   []string{"s"},
+					ArgsUsage: "darc_id",
+					Action:    darcShow,
+				},
+				{
+					Name:      "evolve",
+					Usage:     "evolve a darc to a new version",
+					Aliases:   []string{"e"},
+					ArgsUsage: "darc_id owner_private_key",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "description, desc",
+							Usage: "new description of the darc",
+						},
+						cli.StringSliceFlag{
+							Name:  "owner",
+							Usage: "identity to add as owner",
+						},
+						cli.StringSliceFlag{
+							Name:  "rm-owner",
+							Usage: "identity to remove from the owners",
+						},
+					},
+					Action: darcEvolve,
+				},
+				{
+					Name:      "add-user",
+					Usage:     "evolve a darc to a new version with an additional user",
+					Aliases:   []string{"au"},
+					ArgsUsage: "darc_id identity owner_private_key",
+					Action:    darcAddUser,
+				},
+				{
+					Name:      "rm-user",
+					Usage:     "evolve a darc to a new version without the given user",
+					Aliases:   []string{"ru"},
+					ArgsUsage: "darc_id identity owner_private_key",
+					Action:    darcRmUser,
+				},
+			},
+		},
 		{
 			Name:    "keypair",
 			Usage:   "create a keypair and write it to stdout",
@@ -96,6 +164,26 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:      "decrypt",
+			Usage:     "decrypt the symmetric key of a read-request using an ephemeral key",
+			Aliases:   []string{"dec"},
+			ArgsUsage: "read_id private_key",
+			Action:    decrypt,
+		},
+		{
+			Name:      "readrequests",
+			Usage:     "list the read-requests of the ocs-skipchain",
+			Aliases:   []string{"rr"},
+			ArgsUsage: "start_id",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "count, n",
+					Usage: "maximum number of read-requests, 0 for all read-requests of the write start_id",
+				},
+			},
+			Action: readRequests,
+		},
 		{
 			Name:      "skipchain",
 			Usage:     "read a block from the skipchain",
@@ -123,21 +211,28 @@ func main() {
 	cliApp.Run(os.Args)
 }
 
-// Creates a new ocs skipchain.
+// Creates a new ocs skipchain. If a private key is given, its public key
+// is owner and user of the admin-darc.
 func mngCreate(c *cli.Context) error {
-	if c.NArg() < 1 {
-		log.Fatal("Please give group-toml [pseudo]")
+	if c.NArg() != 1 {
+		log.Fatal("Please give group-toml, and the private key of the admin with --admin")
 	}
 	log.Info("Creating OCS-skipchain")
-	pseudo := c.Args().Get(1)
 	group := getGroup(c)
+	admin := &darc.Darc{}
+	if c.String("admin") != "" {
+		signer, err := parseSigner(c.String("admin"))
+		log.ErrFatal(err)
+		ids := []*darc.Identity{signer.Identity()}
+		admin = darc.NewDarc(&ids, &ids, []byte("admin"))
+	}
 	cl := service.NewClient()
-	scurl, err := cl.CreateSkipchain(group.Roster, &darc.Darc{})
+	scurl, err := cl.CreateSkipchain(group.Roster, admin)
 	log.ErrFatal(err)
 	cfg := &ocsConfig{
 		SkipChainURL: scurl,
 	}
-	log.Infof("Created new skipchains and added %s as admin", pseudo)
+	log.Infof("Admin-darc: %x", admin.GetID())
 	log.Infof("OCS-skipchainid: %x", scurl.Genesis)
 	return cfg.saveConfig(c)
 }
@@ -149,6 +244,25 @@ func mngList(c *cli.Context) error {
 	return nil
 }
 
+// Prints the url of the OCS-skipchain as a json-token or a qrcode.
+func mngExport(c *cli.Context) error {
+	cfg := loadConfigOrFail(c)
+	token, err := newOCSToken(cfg.SkipChainURL)
+	if err != nil {
+		return err
+	}
+	if c.Bool("qrcode") {
+		qr, err := qrgo.NewQR(string(token))
+		if err != nil {
+			return err
+		}
+		qr.OutputTerminal()
+		return nil
+	}
+	fmt.Println(string(token))
+	return nil
+}
+
 // Joins an existing OCS skipchain.
 func mngJoin(c *cli.Context) error {
 	if c.NArg() < 2 {
@@ -170,6 +284,111 @@ func mngJoin(c *cli.Context) error {
 	return cfg.saveConfig(c)
 }
 
+// Prints the latest version of a darc.
+func darcShow(c *cli.Context) error {
+	if c.NArg() < 1 {
+		log.Fatal("Please give: darc_id")
+	}
+	cfg := loadConfigOrFail(c)
+	d, err := getLatestDarc(cfg, c.Args().First())
+	if err != nil {
+		return err
+	}
+	if d.Description != nil {
+		log.Infof("Description: %s", *d.Description)
+	}
+	log.Info(d.String())
+	return nil
+}
+
+// Evolves a darc with a new description and changed owners.
+func darcEvolve(c *cli.Context) error {
+	if c.NArg() < 2 {
+		log.Fatal("Please give: darc_id owner_private_key")
+	}
+	return evolveDarc(c, c.Args().Get(1), func(d *darc.Darc) error {
+		if c.IsSet("description") {
+			desc := []byte(c.String("description"))
+			d.Description = &desc
+		}
+		for _, o := range c.StringSlice("owner") {
+			id, err := parseIdentity(o)
+			if err != nil {
+				return err
+			}
+			d.AddOwner(id)
+		}
+		for _, o := range c.StringSlice("rm-owner") {
+			id, err := parseIdentity(o)
+			if err != nil {
+				return err
+			}
+			if err := removeOwner(d, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Evolves a darc with an additional user.
+func darcAddUser(c *cli.Context) error {
+	if c.NArg() < 3 {
+		log.Fatal("Please give: darc_id identity owner_private_key")
+	}
+	id, err := parseIdentity(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	return evolveDarc(c, c.Args().Get(2), func(d *darc.Darc) error {
+		d.AddUser(id)
+		return nil
+	})
+}
+
+// Evolves a darc without the given user.
+func darcRmUser(c *cli.Context) error {
+	if c.NArg() < 3 {
+		log.Fatal("Please give: darc_id identity owner_private_key")
+	}
+	id, err := parseIdentity(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	return evolveDarc(c, c.Args().Get(2), func(d *darc.Darc) error {
+		_, err := d.RemoveUser(id)
+		return err
+	})
+}
+
+// evolveDarc fetches the latest version of the darc given as first
+// argument, applies the change and stores the new version signed by the
+// owner.
+func evolveDarc(c *cli.Context, ownerKey string, change func(d *darc.Darc) error) error {
+	cfg := loadConfigOrFail(c)
+	owner, err := parseSigner(ownerKey)
+	if err != nil {
+		return err
+	}
+	latest, err := getLatestDarc(cfg, c.Args().First())
+	if err != nil {
+		return err
+	}
+	d := latest.Copy()
+	if err := change(d); err != nil {
+		return err
+	}
+	if err := d.SetEvolutionOnline(latest, owner); err != nil {
+		return err
+	}
+	_, err = service.NewClient().EditAccount(cfg.SkipChainURL, d)
+	if err != nil {
+		return err
+	}
+	log.Infof("Evolved darc to version %d: %x", d.Version, d.GetID())
+	return nil
+}
+
 func keypair(c *cli.Context) error {
 	r, err := encoding.StringHexToScalar(cothority.Suite, "5046ADC1DBA838867B2BBBFDD0C3423E58B57970B5267A90F57960924A87F156")
 	privStr, err := encoding.ScalarToStringHex(cothority.Suite, r)
@@ -262,6 +481,55 @@ func read(c *cli.Context) error {
 	return nil
 }
 
+// Decrypts the symmetric key of a read-request. The key is re-encrypted
+// for an ephemeral key, signed by the private key of the reader.
+func decrypt(c *cli.Context) error {
+	if c.NArg() < 2 {
+		log.Fatal("Please give: read_id private_key")
+	}
+	cfg := loadConfigOrFail(c)
+	readID, err := hex.DecodeString(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	reader, err := parseSigner(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	write, err := getWrite(cfg, readID)
+	if err != nil {
+		return err
+	}
+	key, err := service.NewClient().DecryptKeyRequestEphemeral(cfg.SkipChainURL,
+		readID, &write.Reader, reader)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%x\n", key)
+	return nil
+}
+
+// Lists the read-requests starting at a given block.
+func readRequests(c *cli.Context) error {
+	if c.NArg() < 1 {
+		log.Fatal("Please give: start_id")
+	}
+	cfg := loadConfigOrFail(c)
+	start, err := hex.DecodeString(c.Args().First())
+	if err != nil {
+		return err
+	}
+	docs, err := service.NewClient().GetReadRequests(cfg.SkipChainURL, start, c.Int("count"))
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		log.Infof("ReadID: %x\tDataID: %x\tReader: %s", doc.ReadID, doc.DataID,
+			doc.Reader.String())
+	}
+	return nil
+}
+
 func scread(c *cli.Context) error {
 	cfg := loadConfigOrFail(c)
 	var sc skipchain.SkipBlockID
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/ocs/darc"
+	"github.com/dedis/cothority/ocs/service"
 	"github.com/dedis/kyber/util/encoding"
 	"github.com/dedis/kyber/util/key"
+	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -77,3 +82,67 @@ func TestNegate(t *testing.T) {
 	sum := cothority.Suite.Scalar().Add(neg, priv)
 	log.Lvl2("Sum:", sum)
 }
+
+func TestParseIdentity(t *testing.T) {
+	kp := key.NewKeyPair(cothority.Suite)
+	pubStr, err := encoding.PointToStringHex(cothority.Suite, kp.Public)
+	require.Nil(t, err)
+	privStr, err := encoding.ScalarToStringHex(cothority.Suite, kp.Private)
+	require.Nil(t, err)
+	ed := darc.NewIdentityEd25519(kp.Public)
+
+	for _, s := range []string{pubStr, "ed25519:" + pubStr, "Ed25519:" + pubStr} {
+		id, err := parseIdentity(s)
+		require.Nil(t, err)
+		require.True(t, id.Equal(ed))
+	}
+	signer, err := parseSigner(privStr)
+	require.Nil(t, err)
+	require.True(t, signer.Identity().Equal(ed))
+
+	id, err := parseIdentity("darc:0102")
+	require.Nil(t, err)
+	require.True(t, id.Equal(darc.NewIdentityDarc([]byte{1, 2})))
+	id, err = parseIdentity("x509ec:0102")
+	require.Nil(t, err)
+	require.True(t, id.Equal(darc.NewIdentityX509EC([]byte{1, 2})))
+
+	for _, s := range []string{"", "darc:xx", "unknown:0102", "ed25519:0102"} {
+		_, err = parseIdentity(s)
+		require.NotNil(t, err, s)
+	}
+	_, err = parseSigner("xx")
+	require.NotNil(t, err)
+}
+
+func TestRemoveOwner(t *testing.T) {
+	ids := []*darc.Identity{
+		darc.NewIdentityDarc([]byte{1}),
+		darc.NewIdentityDarc([]byte{2}),
+	}
+	d := darc.NewDarc(&ids, nil, nil)
+	require.Nil(t, removeOwner(d, ids[0]))
+	require.Equal(t, 1, len(*d.Owners))
+	require.True(t, (*d.Owners)[0].Equal(ids[1]))
+	require.NotNil(t, removeOwner(d, ids[0]))
+}
+
+func TestOCSToken(t *testing.T) {
+	local := onet.NewTCPTest(cothority.Suite)
+	defer local.CloseAll()
+	_, roster, _ := local.GenTree(3, true)
+	scurl := &service.SkipChainURL{Roster: roster, Genesis: []byte{1, 2, 3}}
+	buf, err := newOCSToken(scurl)
+	require.Nil(t, err)
+
+	token := &ocsToken{}
+	require.Nil(t, json.Unmarshal(buf, token))
+	require.Equal(t, "010203", token.Genesis)
+	require.Equal(t, len(roster.List), len(token.Roster))
+	for i, si := range roster.List {
+		require.Equal(t, string(si.Address), token.Roster[i].Address)
+		pub, err := encoding.PointToStringHex(cothority.Suite, si.Public)
+		require.Nil(t, err)
+		require.Equal(t, pub, token.Roster[i].Public)
+	}
+}
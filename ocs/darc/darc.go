@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/dedis/cothority"
 	"github.com/dedis/kyber"
@@ -66,6 +67,10 @@ func (d *Darc) Copy() *Darc {
 		desc := *(d.Description)
 		dCopy.Description = &desc
 	}
+	if d.Rules != nil {
+		rules := append([]*Rule{}, *d.Rules...)
+		dCopy.Rules = &rules
+	}
 	return dCopy
 }
 
@@ -228,6 +233,12 @@ func (d *Darc) IncrementVersion() {
 // Verify returns nil if the verification is OK, or an error
 // if something is wrong.
 func (d Darc) Verify() error {
+	return d.VerifyAt(time.Now())
+}
+
+// VerifyAt works like Verify, but the certificates of the signers need to be
+// valid at the time when.
+func (d Darc) VerifyAt(when time.Time) error {
 	if d.Version == 0 {
 		return nil
 	}
@@ -238,10 +249,7 @@ func (d Darc) Verify() error {
 	if err != nil {
 		return err
 	}
-	if err := d.Signature.SignaturePath.Verify(Owner); err != nil {
-		return err
-	}
-	return d.Signature.Verify(d.GetID(), latest)
+	return d.Signature.VerifyActionAt(d.GetID(), latest, ActionEvolve, when)
 }
 
 // GetLatest searches for the previous darc in the signature and returns an
@@ -269,6 +277,11 @@ func (d Darc) String() string {
 			}
 		}
 	}
+	if d.Rules != nil {
+		for _, r := range *d.Rules {
+			ret += fmt.Sprintf("\nrule %s: %s", r.Action, r.Expr.String())
+		}
+	}
 	return ret
 }
 
@@ -303,6 +316,12 @@ func NewDarcSignature(msg []byte, sigpath *SignaturePath, signer *Signer) (*Sign
 // Verify returns nil if the signature is correct, or an error
 // if something is wrong.
 func (ds *Signature) Verify(msg []byte, base *Darc) error {
+	return ds.VerifyAt(msg, base, time.Now())
+}
+
+// VerifyAt works like Verify, but the certificates of the signer need to be
+// valid at the time when.
+func (ds *Signature) VerifyAt(msg []byte, base *Darc, when time.Time) error {
 	if base == nil {
 		return errors.New("Base-darc is missing")
 	}
@@ -317,7 +336,103 @@ func (ds *Signature) Verify(msg []byte, base *Darc) error {
 	if err != nil {
 		return err
 	}
-	return ds.SignaturePath.Signer.Verify(hash, ds.Signature)
+	return ds.SignaturePath.Signer.VerifyAt(hash, ds.Signature, when)
+}
+
+// VerifyAction returns nil if this signature and all other signatures are
+// correct, and if their signers together fulfill the rule of the base darc
+// for the action.
+func (ds *Signature) VerifyAction(msg []byte, base *Darc, action string) error {
+	return ds.VerifyActionAt(msg, base, action, time.Now())
+}
+
+// VerifyActionAt works like VerifyAction, but the certificates of the
+// signers need to be valid at the time when.
+func (ds *Signature) VerifyActionAt(msg []byte, base *Darc, action string, when time.Time) error {
+	var paths []*SignaturePath
+	for _, sig := range ds.All() {
+		if err := sig.VerifyAt(msg, base, when); err != nil {
+			return err
+		}
+		paths = append(paths, &sig.SignaturePath)
+	}
+	return VerifyPathsAt(paths, action, when)
+}
+
+// AddSignature adds the signature of another signer on the same message.
+func (ds *Signature) AddSignature(other *Signature) {
+	var others []*Signature
+	if ds.Others != nil {
+		others = *ds.Others
+	}
+	for _, sig := range other.All() {
+		others = append(others, &Signature{
+			Signature:     sig.Signature,
+			SignaturePath: sig.SignaturePath,
+		})
+	}
+	ds.Others = &others
+}
+
+// All returns this signature followed by all other signatures.
+func (ds *Signature) All() []*Signature {
+	all := []*Signature{ds}
+	if ds.Others != nil {
+		all = append(all, *ds.Others...)
+	}
+	return all
+}
+
+// VerifyPaths verifies all paths and makes sure that their signers together
+// fulfill the rule for the action of the darc the paths start with. The
+// signatures themselves are not verified.
+func VerifyPaths(paths []*SignaturePath, action string) error {
+	return VerifyPathsAt(paths, action, time.Now())
+}
+
+// VerifyPathsAt works like VerifyPaths, but the evolutions of the darcs are
+// verified at the time when.
+func VerifyPathsAt(paths []*SignaturePath, action string, when time.Time) error {
+	return evalPaths(paths, action, true, when)
+}
+
+// EvalPaths works like VerifyPaths, but doesn't verify the evolution of the
+// darcs in the paths. It must only be used with darcs that have been
+// verified before, e.g. the ones stored on a skipchain.
+func EvalPaths(paths []*SignaturePath, action string) error {
+	return evalPaths(paths, action, false, time.Time{})
+}
+
+func evalPaths(paths []*SignaturePath, action string, verifyEvolution bool, when time.Time) error {
+	if len(paths) == 0 {
+		return errors.New("no signature paths")
+	}
+	var base *Darc
+	var hops []*Identity
+	for _, p := range paths {
+		b, hop, err := p.verifyPath(action, verifyEvolution, when)
+		if err != nil {
+			return err
+		}
+		if base == nil {
+			base = b
+		} else if !base.GetID().Equal(b.GetID()) {
+			return errors.New("signature paths don't start at the same darc")
+		}
+		hops = append(hops, hop)
+	}
+	signed := func(id *Identity) bool {
+		for _, hop := range hops {
+			if hop.Equal(id) {
+				return true
+			}
+		}
+		return false
+	}
+	if !base.Expression(action).Eval(signed) {
+		return fmt.Errorf("signers don't fulfill the rule for %s", action)
+	}
+	return nil
 }
 
 // NewSignaturePath returns an initialized SignaturePath structure.
@@ -362,76 +477,114 @@ func (sigpath *SignaturePath) GetPathMsg() []byte {
 
 // Verify makes sure that the path is a correctly evolving one (each next
 // darc should be referenced by the previous one) and that the signer
-// is present in the last darc.
+// fulfills the rule of the role.
 func (sigpath *SignaturePath) Verify(role Role) error {
-	if len(*sigpath.Darcs) == 0 {
-		return errors.New("no path stored")
+	return sigpath.VerifyAction(role.Action())
+}
+
+// VerifyAt works like Verify, but the evolutions are verified at the time
+// when.
+func (sigpath *SignaturePath) VerifyAt(role Role, when time.Time) error {
+	return sigpath.VerifyActionAt(role.Action(), when)
+}
+
+// VerifyAction makes sure that the path is a correctly evolving one, that
+// every delegation is allowed by the previous darc, and that the signer alone
+// fulfills the rule for the action. The first delegation is checked against
+// the rule for the action, all following ones against ActionSign.
+func (sigpath *SignaturePath) VerifyAction(action string) error {
+	return sigpath.VerifyActionAt(action, time.Now())
+}
+
+// VerifyActionAt works like VerifyAction, but the evolutions are verified at
+// the time when.
+func (sigpath *SignaturePath) VerifyActionAt(action string, when time.Time) error {
+	base, hop, err := sigpath.verifyPath(action, true, when)
+	if err != nil {
+		return err
 	}
-	var previous *Darc
-	for n, d := range *sigpath.Darcs {
+	if !base.Expression(action).Eval(hop.Equal) {
+		return fmt.Errorf("signer doesn't fulfill the rule for %s", action)
+	}
+	return nil
+}
+
+// verifyPath checks the evolutions and delegations of the path. It returns
+// the latest version of the first darc and the identity it delegated to,
+// which is either the next darc or the signer. All delegated darcs must be
+// fulfilled by the next identity of the path alone, while the rule of the
+// first darc only needs to contain the returned identity, so that multiple
+// signers can be combined.
+func (sigpath *SignaturePath) verifyPath(action string, verifyEvolution bool, when time.Time) (*Darc, *Identity, error) {
+	if sigpath.Darcs == nil || len(*sigpath.Darcs) == 0 {
+		return nil, nil, errors.New("no path stored")
+	}
+	for _, d := range *sigpath.Darcs {
 		if d == nil {
-			return errors.New("null pointer in path list")
+			return nil, nil, errors.New("null pointer in path list")
 		}
-		if previous != nil {
-			// Check if its an evolving darc
-			latest, err := d.GetLatest()
-			if err != nil {
-				return errors.New("found incorrect darc in chain")
-			}
-			if latest != nil {
-				log.Lvlf2("Verifying evolution from %x", d.GetID())
-				if err := d.Verify(); err != nil {
-					return errors.New("not correct evolution of darcs in path: " + err.Error())
-				}
+	}
+	darcs := *sigpath.Darcs
+	// links[i] delegates to hops[i].
+	var links []*Darc
+	var hops []*Identity
+	previous := darcs[0]
+	for n, d := range darcs[1:] {
+		evolution, err := isEvolution(previous, d, verifyEvolution, when)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !evolution {
+			id := NewIdentityDarc(d.GetID())
+			linkAction := ActionSign
+			if len(links) == 0 {
+				linkAction = action
 			}
-			if latest == nil || bytes.Compare(latest.GetID(), previous.GetID()) != 0 {
-				// The darc link can only come from an owner of the first darc. Afterwards
-				// darc links have to be user-links.
-				found := false
-				if role == Owner && n == 1 {
-					if previous.Owners != nil {
-						for _, id := range *previous.Owners {
-							if id.Darc != nil && id.Darc.ID.Equal(d.GetID()) {
-								found = true
-								break
-							}
-						}
-					} else {
-						return errors.New("no owners defined in base darc")
-					}
-				} else {
-					if previous.Users != nil {
-						for _, id := range *previous.Users {
-							if id.Darc != nil && id.Darc.ID.Equal(d.GetID()) {
-								found = true
-								break
-							}
-						}
-					} else {
-						return errors.New("no users defined for user signature")
-					}
-				}
-				if !found {
-					return fmt.Errorf("didn't find valid darc-link in chain at position %d", n)
-				}
+			if !previous.Expression(linkAction).Contains(id) {
+				return nil, nil, fmt.Errorf("didn't find valid darc-link in chain at position %d", n+1)
 			}
+			links = append(links, previous)
+			hops = append(hops, id)
 		}
 		previous = d
 	}
-	if role == User {
-		for _, id := range *previous.Users {
-			if sigpath.Signer.Equal(id) {
-				return nil
-			}
-		}
-	} else {
-		for _, id := range *previous.Owners {
-			if sigpath.Signer.Equal(id) {
-				return nil
-			}
+	linkAction := action
+	if len(links) > 0 {
+		linkAction = ActionSign
+	}
+	if !previous.Expression(linkAction).Contains(&sigpath.Signer) {
+		return nil, nil, errors.New("didn't find signer in last darc of path")
+	}
+	links = append(links, previous)
+	hops = append(hops, &sigpath.Signer)
+	for i := 1; i < len(links); i++ {
+		if !links[i].Expression(ActionSign).Eval(hops[i].Equal) {
+			return nil, nil, fmt.Errorf("delegated darc at level %d is not fulfilled", i)
 		}
 	}
-	return errors.New("didn't find signer in last darc of path")
+	return links[0], hops[0], nil
+}
+
+// isEvolution returns true if d is the next version of previous. If
+// verifyEvolution is true, all darcs with a signature are verified at the
+// time when.
+func isEvolution(previous, d *Darc, verifyEvolution bool, when time.Time) (bool, error) {
+	if !verifyEvolution {
+		return d.Version > previous.Version &&
+			d.GetBaseID().Equal(previous.GetBaseID()), nil
+	}
+	latest, err := d.GetLatest()
+	if err != nil {
+		return false, errors.New("found incorrect darc in chain")
+	}
+	if latest == nil {
+		return false, nil
+	}
+	log.Lvlf2("Verifying evolution from %x", d.GetID())
+	if err := d.VerifyAt(when); err != nil {
+		return false, errors.New("not correct evolution of darcs in path: " + err.Error())
+	}
+	return latest.GetID().Equal(previous.GetID()), nil
 }
 
 // Type returns an integer representing the type of key held in the signer.
@@ -442,6 +595,8 @@ func (s *Signer) Type() int {
 		return 1
 	case s.X509EC != nil:
 		return 2
+	case s.X509Chain != nil:
+		return 3
 	default:
 		return -1
 	}
@@ -455,6 +610,8 @@ func (s *Signer) Identity() *Identity {
 		return &Identity{Ed25519: &IdentityEd25519{Point: s.Ed25519.Point}}
 	case 2:
 		return &Identity{X509EC: &IdentityX509EC{Public: s.X509EC.Point}}
+	case 3:
+		return &Identity{X509Chain: &IdentityX509Chain{Root: s.X509Chain.Root}}
 	default:
 		return nil
 	}
@@ -472,6 +629,8 @@ func (s *Signer) Sign(msg []byte) ([]byte, error) {
 		return s.Ed25519.Sign(msg)
 	case 2:
 		return s.X509EC.Sign(msg)
+	case 3:
+		return s.X509Chain.Sign(msg)
 	default:
 		return nil, errors.New("unknown signer type")
 	}
@@ -482,7 +641,7 @@ func (s *Signer) GetPrivate() (kyber.Scalar, error) {
 	switch s.Type() {
 	case 1:
 		return s.Ed25519.Secret, nil
-	case 0, 2:
+	case 0, 2, 3:
 		return nil, errors.New("signer lacks a private key")
 	default:
 		return nil, errors.New("signer is of unknown type")
@@ -502,6 +661,8 @@ func (id *Identity) Equal(id2 *Identity) bool {
 		return id.Ed25519.Equal(id2.Ed25519)
 	case 2:
 		return id.X509EC.Equal(id2.X509EC)
+	case 3:
+		return id.X509Chain.Equal(id2.X509Chain)
 	}
 	return false
 }
@@ -516,6 +677,8 @@ func (id *Identity) Type() int {
 		return 1
 	case id.X509EC != nil:
 		return 2
+	case id.X509Chain != nil:
+		return 3
 	}
 	return -1
 }
@@ -529,6 +692,8 @@ func (id *Identity) String() string {
 		return fmt.Sprintf("Ed25519: %s", id.Ed25519.Point.String())
 	case 2:
 		return fmt.Sprintf("X509EC: %x", id.X509EC.Public)
+	case 3:
+		return fmt.Sprintf("X509Chain: %x", sha256.Sum256(id.X509Chain.Root))
 	default:
 		return fmt.Sprintf("No identity")
 	}
@@ -537,6 +702,12 @@ func (id *Identity) String() string {
 // Verify returns nil if the signature is correct, or an error if something
 // went wrong.
 func (id *Identity) Verify(msg, sig []byte) error {
+	return id.VerifyAt(msg, sig, time.Now())
+}
+
+// VerifyAt works like Verify, but identities based on certificates need them
+// to be valid at the time when, which is ignored by the other identities.
+func (id *Identity) VerifyAt(msg, sig []byte, when time.Time) error {
 	switch id.Type() {
 	case 0:
 		return errors.New("cannot verify a darc-signature")
@@ -544,6 +715,8 @@ func (id *Identity) Verify(msg, sig []byte) error {
 		return id.Ed25519.Verify(msg, sig)
 	case 2:
 		return id.X509EC.Verify(msg, sig)
+	case 3:
+		return id.X509Chain.VerifyAt(msg, sig, when)
 	default:
 		return errors.New("unknown identity")
 	}
@@ -1,7 +1,14 @@
 package darc
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/dedis/onet/log"
 	"github.com/stretchr/testify/assert"
@@ -139,12 +146,296 @@ func TestDarcSignature_Verify(t *testing.T) {
 	require.Nil(t, ds.Verify(msg, d))
 }
 
+func TestExpr_Eval(t *testing.T) {
+	ids := []*Identity{createIdentity(), createIdentity(), createIdentity()}
+	signed := func(signers ...*Identity) func(*Identity) bool {
+		return func(id *Identity) bool {
+			for _, s := range signers {
+				if s.Equal(id) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	a, b, c := NewExprIdentity(ids[0]), NewExprIdentity(ids[1]), NewExprIdentity(ids[2])
+
+	and := NewExprAnd(a, b)
+	require.True(t, and.Eval(signed(ids[0], ids[1])))
+	require.False(t, and.Eval(signed(ids[0], ids[2])))
+	or := NewExprOr(a, b)
+	require.True(t, or.Eval(signed(ids[1])))
+	require.False(t, or.Eval(signed(ids[2])))
+	th := NewExprThreshold(2, a, b, c)
+	require.False(t, th.Eval(signed(ids[2])))
+	require.True(t, th.Eval(signed(ids[2], ids[0])))
+	nested := NewExprOr(NewExprAnd(a, c), b)
+	require.True(t, nested.Eval(signed(ids[0], ids[2])))
+	require.False(t, nested.Eval(signed(ids[0])))
+	require.Equal(t, 3, len(nested.Identities()))
+
+	require.NotNil(t, NewExprThreshold(4, a, b, c).Check())
+	require.NotNil(t, NewExprAnd().Check())
+	require.Nil(t, nested.Check())
+	require.False(t, NewExprAnd().Eval(signed(ids...)))
+}
+
+func TestDarc_Rules(t *testing.T) {
+	td := createDarc("rules")
+	id := td.darc.GetID()
+	require.True(t, td.darc.Expression(ActionEvolve).Contains(td.ownersI[0]))
+	require.True(t, td.darc.Expression(ActionWrite).Contains(td.usersI[0]))
+	require.False(t, td.darc.Expression(ActionWrite).Contains(td.ownersI[0]))
+	require.True(t, td.darc.Expression(ActionDisclose).Contains(td.ownersI[0]))
+	require.False(t, td.darc.Expression(ActionDisclose).Contains(td.usersI[0]))
+
+	require.NotNil(t, td.darc.SetRule("", NewExprIdentity(td.ownersI[0])))
+	require.Nil(t, td.darc.SetRule(ActionWrite, NewExprAnd(
+		NewExprIdentity(td.ownersI[0]), NewExprIdentity(td.usersI[0]))))
+	require.False(t, id.Equal(td.darc.GetID()))
+	require.True(t, td.darc.Expression(ActionWrite).Contains(td.ownersI[0]))
+	require.True(t, td.darc.Copy().GetID().Equal(td.darc.GetID()))
+
+	require.Nil(t, td.darc.SetRule(ActionWrite, NewExprIdentity(td.usersI[1])))
+	require.Equal(t, 1, len(*td.darc.Rules))
+	require.Nil(t, td.darc.RemoveRule(ActionWrite))
+	require.NotNil(t, td.darc.RemoveRule(ActionWrite))
+	require.True(t, td.darc.Expression(ActionWrite).Contains(td.usersI[0]))
+}
+
+// Removing an identity never lets a set of signers fulfill an expression
+// they didn't fulfill before.
+func TestExpr_Without(t *testing.T) {
+	ids := []*Identity{createIdentity(), createIdentity(), createIdentity()}
+	a, b, c := NewExprIdentity(ids[0]), NewExprIdentity(ids[1]), NewExprIdentity(ids[2])
+	for _, e := range []*Expr{
+		NewExprOr(a, b, c),
+		NewExprOr(NewExprAnd(b, c), a),
+		NewExprAnd(NewExprOr(a, b), c),
+		NewExprThreshold(2, NewExprOr(a, b), c, NewExprDeny()),
+	} {
+		w, err := e.Without(ids[0])
+		require.Nil(t, err)
+		require.False(t, w.Contains(ids[0]))
+		for mask := 0; mask < 1<<uint(len(ids)); mask++ {
+			signed := func(id *Identity) bool {
+				for i := range ids {
+					if mask&(1<<uint(i)) != 0 && ids[i].Equal(id) {
+						return true
+					}
+				}
+				return false
+			}
+			if w.Eval(signed) {
+				require.True(t, e.Eval(signed), "%s grants more than %s", w, e)
+			}
+		}
+	}
+
+	// Nothing left.
+	w, err := NewExprOr(a).Without(ids[0])
+	require.Nil(t, err)
+	require.Nil(t, w)
+
+	// Removing a needed signer would need fewer signatures.
+	_, err = NewExprAnd(a, b).Without(ids[0])
+	require.NotNil(t, err)
+	_, err = NewExprThreshold(2, a, b, c).Without(ids[0])
+	require.NotNil(t, err)
+	_, err = NewExprOr(NewExprAnd(a, b), c).Without(ids[0])
+	require.NotNil(t, err)
+
+	deny := NewExprDeny()
+	require.Nil(t, deny.Check())
+	require.False(t, deny.Eval(func(*Identity) bool { return true }))
+}
+
+func TestDarc_RemoveIdentity(t *testing.T) {
+	td := createDarc("remove")
+	revoked := td.ownersI[0]
+	require.Nil(t, td.darc.SetRule(ActionRead, NewExprOr(
+		NewExprIdentity(revoked), NewExprIdentity(td.usersI[0]))))
+	require.Nil(t, td.darc.SetRule(ActionWrite, NewExprIdentity(revoked)))
+	require.True(t, td.darc.HasIdentity(revoked))
+
+	require.Nil(t, td.darc.RemoveIdentity(revoked))
+	require.False(t, td.darc.HasIdentity(revoked))
+	require.NotNil(t, td.darc.RemoveIdentity(revoked))
+	require.Equal(t, 1, len(*td.darc.Owners))
+	require.Equal(t, 2, len(*td.darc.Users))
+	read := td.darc.Expression(ActionRead)
+	require.True(t, read.Eval(td.usersI[0].Equal))
+	require.False(t, read.Eval(td.usersI[1].Equal))
+	// The emptied rule denies writing instead of falling back to the users.
+	write := td.darc.Expression(ActionWrite)
+	require.Equal(t, OpDeny, write.Op)
+	for _, id := range append(td.ownersI, td.usersI...) {
+		require.False(t, write.Eval(id.Equal))
+	}
+
+	// The last owner cannot be removed.
+	last := (*td.darc.Owners)[0]
+	require.NotNil(t, td.darc.RemoveIdentity(last))
+	require.True(t, td.darc.HasIdentity(last))
+}
+
+// An identity in an and- or a threshold-expression cannot be removed, and
+// the darc is left unchanged.
+func TestDarc_RemoveIdentityRefused(t *testing.T) {
+	td := createDarc("refused")
+	revoked := td.usersI[0]
+	for _, e := range []*Expr{
+		NewExprAnd(NewExprIdentity(revoked), NewExprIdentity(td.usersI[1])),
+		NewExprThreshold(2, NewExprIdentity(revoked),
+			NewExprIdentity(td.usersI[1]), NewExprIdentity(td.ownersI[0])),
+	} {
+		require.Nil(t, td.darc.SetRule(ActionRead, e))
+		require.NotNil(t, td.darc.RemoveIdentity(revoked))
+		require.Equal(t, 2, len(*td.darc.Users))
+		require.Equal(t, e, td.darc.GetRule(ActionRead))
+	}
+}
+
+// Uses a 2-out-of-3 rule to evolve a darc, which needs two signatures.
+func TestSignature_Multi(t *testing.T) {
+	td := createDarc("multi")
+	signers := append(td.owners, createSigner())
+	var exprs []*Expr
+	for _, s := range signers {
+		exprs = append(exprs, NewExprIdentity(s.Identity()))
+	}
+	require.Nil(t, td.darc.SetRule(ActionEvolve, NewExprThreshold(2, exprs...)))
+
+	dNew := td.darc.Copy()
+	require.Nil(t, dNew.SetEvolution(td.darc, nil, signers[0]))
+	require.NotNil(t, dNew.Verify())
+
+	path := NewSignaturePath([]*Darc{td.darc}, *signers[0].Identity(), Owner)
+	require.NotNil(t, path.Verify(Owner))
+	path2 := NewSignaturePath([]*Darc{td.darc}, *signers[2].Identity(), Owner)
+	sig2, err := NewDarcSignature(dNew.GetID(), path2, signers[2])
+	require.Nil(t, err)
+	dNew.Signature.AddSignature(sig2)
+	require.Nil(t, dNew.Verify())
+
+	// The same signer twice is not enough.
+	dNew.Signature.Others = nil
+	sig0, err := NewDarcSignature(dNew.GetID(), NewSignaturePath([]*Darc{td.darc},
+		*signers[0].Identity(), Owner), signers[0])
+	require.Nil(t, err)
+	dNew.Signature.AddSignature(sig0)
+	require.NotNil(t, dNew.Verify())
+
+	// A wrong signature of the second signer is refused.
+	dNew.Signature.Others = nil
+	sig2.Signature[0] ^= 0xff
+	dNew.Signature.AddSignature(sig2)
+	require.NotNil(t, dNew.Verify())
+}
+
+// A delegated darc with an AND rule cannot be fulfilled by one of its
+// signers alone.
+func TestSignaturePath_Delegation(t *testing.T) {
+	msg := []byte("document")
+	base := createDarc("base")
+	deleg := createDarc("delegated")
+	base.darc.AddUser(NewIdentityDarc(deleg.darc.GetID()))
+	path := NewSignaturePath([]*Darc{base.darc, deleg.darc}, *deleg.usersI[0], User)
+	require.Nil(t, path.VerifyAction(ActionRead))
+	sig, err := NewDarcSignature(msg, path, deleg.users[0])
+	require.Nil(t, err)
+	require.Nil(t, sig.VerifyAction(msg, base.darc, ActionRead))
+
+	// Reading is restricted to the owner of the base darc, so the
+	// delegation doesn't work anymore.
+	require.Nil(t, base.darc.SetRule(ActionRead, NewExprIdentity(base.ownersI[0])))
+	path = NewSignaturePath([]*Darc{base.darc, deleg.darc}, *deleg.usersI[0], User)
+	require.NotNil(t, path.VerifyAction(ActionRead))
+	require.Nil(t, path.VerifyAction(ActionWrite))
+
+	require.Nil(t, deleg.darc.SetRule(ActionSign, NewExprAnd(
+		NewExprIdentity(deleg.usersI[0]), NewExprIdentity(deleg.usersI[1]))))
+	base.darc.AddUser(NewIdentityDarc(deleg.darc.GetID()))
+	path = NewSignaturePath([]*Darc{base.darc, deleg.darc}, *deleg.usersI[0], User)
+	require.NotNil(t, path.VerifyAction(ActionWrite))
+}
+
 func TestSignature(t *testing.T) {
 	// msg := []byte("darc-policy")
 	// sigEd := NewSignerEd25519(nil, nil)
 	// sig := sigEd.Sign
 }
 
+func TestIdentityX509Chain(t *testing.T) {
+	now := time.Now()
+	rootDER, rootCert, rootKey := createCertificate(t, nil, nil, true, now,
+		x509.KeyUsageCertSign)
+	_, leafCert, leafKey := createCertificate(t, rootCert, rootKey, false, now,
+		x509.KeyUsageDigitalSignature)
+	id := NewIdentityX509Chain(rootDER)
+	msg := []byte("darc-policy")
+
+	signer := NewSignerX509Chain(rootDER, [][]byte{leafCert.Raw}, leafKey)
+	require.True(t, signer.Identity().Equal(id))
+	sig, err := signer.Sign(msg)
+	require.Nil(t, err)
+	require.Nil(t, id.Verify(msg, sig))
+	require.NotNil(t, id.Verify([]byte("other message"), sig))
+
+	log.Lvl1("Intermediate certificate")
+	_, interCert, interKey := createCertificate(t, rootCert, rootKey, true, now,
+		x509.KeyUsageCertSign)
+	_, leaf2Cert, leaf2Key := createCertificate(t, interCert, interKey, false, now,
+		x509.KeyUsageDigitalSignature)
+	signer = NewSignerX509Chain(rootDER, [][]byte{leaf2Cert.Raw, interCert.Raw}, leaf2Key)
+	sig, err = signer.Sign(msg)
+	require.Nil(t, err)
+	require.Nil(t, id.Verify(msg, sig))
+	signer = NewSignerX509Chain(rootDER, [][]byte{leaf2Cert.Raw}, leaf2Key)
+	sig, err = signer.Sign(msg)
+	require.Nil(t, err)
+	require.NotNil(t, id.Verify(msg, sig))
+
+	log.Lvl1("Expired leaf certificate")
+	_, expCert, expKey := createCertificate(t, rootCert, rootKey, false,
+		now.Add(-48*time.Hour), x509.KeyUsageDigitalSignature)
+	signer = NewSignerX509Chain(rootDER, [][]byte{expCert.Raw}, expKey)
+	sig, err = signer.Sign(msg)
+	require.Nil(t, err)
+	require.NotNil(t, id.Verify(msg, sig))
+
+	log.Lvl1("Certificates valid at the signing time")
+	oldRootDER, oldRootCert, oldRootKey := createCertificate(t, nil, nil, true,
+		now.Add(-48*time.Hour), x509.KeyUsageCertSign)
+	_, oldCert, oldKey := createCertificate(t, oldRootCert, oldRootKey, false,
+		now.Add(-48*time.Hour), x509.KeyUsageDigitalSignature)
+	oldID := NewIdentityX509Chain(oldRootDER)
+	signer = NewSignerX509Chain(oldRootDER, [][]byte{oldCert.Raw}, oldKey)
+	sig, err = signer.Sign(msg)
+	require.Nil(t, err)
+	require.NotNil(t, oldID.Verify(msg, sig))
+	require.Nil(t, oldID.VerifyAt(msg, sig, now.Add(-36*time.Hour)))
+	require.NotNil(t, oldID.VerifyAt(msg, sig, now.Add(-72*time.Hour)))
+
+	log.Lvl1("Leaf certificate without digital signature")
+	_, encCert, encKey := createCertificate(t, rootCert, rootKey, false, now,
+		x509.KeyUsageKeyEncipherment)
+	signer = NewSignerX509Chain(rootDER, [][]byte{encCert.Raw}, encKey)
+	sig, err = signer.Sign(msg)
+	require.Nil(t, err)
+	require.NotNil(t, id.Verify(msg, sig))
+
+	log.Lvl1("Leaf certificate of another root")
+	_, otherCert, otherKey := createCertificate(t, nil, nil, true, now,
+		x509.KeyUsageCertSign)
+	_, foreignCert, foreignKey := createCertificate(t, otherCert, otherKey, false, now,
+		x509.KeyUsageDigitalSignature)
+	signer = NewSignerX509Chain(rootDER, [][]byte{foreignCert.Raw}, foreignKey)
+	sig, err = signer.Sign(msg)
+	require.Nil(t, err)
+	require.NotNil(t, id.Verify(msg, sig))
+}
+
 type testDarc struct {
 	darc    *Darc
 	owners  []*Signer
@@ -181,3 +472,30 @@ func createSignerIdentity() (*Signer, *Identity) {
 	signer := NewSignerEd25519(nil, nil)
 	return signer, signer.Identity()
 }
+
+// createCertificate returns a new certificate valid for one day from
+// notBefore. If parent is nil, the certificate is self-signed.
+func createCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey,
+	ca bool, notBefore time.Time, usage x509.KeyUsage) ([]byte, *x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.Nil(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: serial.String()},
+		NotBefore:             notBefore.Add(-time.Minute),
+		NotAfter:              notBefore.Add(24 * time.Hour),
+		KeyUsage:              usage,
+		BasicConstraintsValid: true,
+		IsCA:                  ca,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	return der, cert, key
+}
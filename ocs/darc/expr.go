@@ -0,0 +1,370 @@
+package darc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+This file holds the rules of a darc. A rule maps an action to an expression
+over identities, which needs to be fulfilled by the signers of a request for
+this action.
+*/
+
+// NewExprIdentity returns an expression that is fulfilled if the identity
+// signed.
+func NewExprIdentity(id *Identity) *Expr {
+	return &Expr{Op: OpIdentity, Identity: id}
+}
+
+// NewExprAnd returns an expression that is fulfilled if all sub-expressions
+// are fulfilled.
+func NewExprAnd(exprs ...*Expr) *Expr {
+	return &Expr{Op: OpAnd, Exprs: exprs}
+}
+
+// NewExprOr returns an expression that is fulfilled if at least one of the
+// sub-expressions is fulfilled.
+func NewExprOr(exprs ...*Expr) *Expr {
+	return &Expr{Op: OpOr, Exprs: exprs}
+}
+
+// NewExprThreshold returns an expression that is fulfilled if at least
+// threshold of the sub-expressions are fulfilled.
+func NewExprThreshold(threshold int, exprs ...*Expr) *Expr {
+	return &Expr{Op: OpThreshold, Exprs: exprs, Threshold: threshold}
+}
+
+// NewExprDeny returns an expression that is never fulfilled.
+func NewExprDeny() *Expr {
+	return &Expr{Op: OpDeny}
+}
+
+// NewExprIdentities returns an expression that is fulfilled if one of the
+// identities signed.
+func NewExprIdentities(ids []*Identity) *Expr {
+	var exprs []*Expr
+	for _, id := range ids {
+		exprs = append(exprs, NewExprIdentity(id))
+	}
+	return NewExprOr(exprs...)
+}
+
+// Eval returns true if the expression is fulfilled. The signed function
+// returns whether a given identity signed. An empty expression is never
+// fulfilled.
+func (e *Expr) Eval(signed func(*Identity) bool) bool {
+	if e == nil {
+		return false
+	}
+	switch e.Op {
+	case OpIdentity:
+		return e.Identity != nil && signed(e.Identity)
+	case OpAnd:
+		if len(e.Exprs) == 0 {
+			return false
+		}
+		for _, sub := range e.Exprs {
+			if !sub.Eval(signed) {
+				return false
+			}
+		}
+		return true
+	case OpOr:
+		for _, sub := range e.Exprs {
+			if sub.Eval(signed) {
+				return true
+			}
+		}
+		return false
+	case OpThreshold:
+		if e.Threshold <= 0 {
+			return false
+		}
+		fulfilled := 0
+		for _, sub := range e.Exprs {
+			if sub.Eval(signed) {
+				fulfilled++
+			}
+		}
+		return fulfilled >= e.Threshold
+	case OpDeny:
+		return false
+	}
+	return false
+}
+
+// Identities returns all identities of the expression.
+func (e *Expr) Identities() []*Identity {
+	if e == nil {
+		return nil
+	}
+	if e.Op == OpIdentity {
+		if e.Identity == nil {
+			return nil
+		}
+		return []*Identity{e.Identity}
+	}
+	var ids []*Identity
+	for _, sub := range e.Exprs {
+		ids = append(ids, sub.Identities()...)
+	}
+	return ids
+}
+
+// Contains returns true if the identity is part of the expression.
+func (e *Expr) Contains(id *Identity) bool {
+	for _, i := range e.Identities() {
+		if i.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Without returns a copy of the expression without the identity. The
+// identity can only be removed from or-expressions, as removing it from an
+// and- or a threshold-expression would need fewer signers. If nothing is
+// left, nil is returned.
+func (e *Expr) Without(id *Identity) (*Expr, error) {
+	if e == nil {
+		return nil, nil
+	}
+	switch e.Op {
+	case OpIdentity:
+		if e.Identity == nil || e.Identity.Equal(id) {
+			return nil, nil
+		}
+		return NewExprIdentity(e.Identity), nil
+	case OpDeny:
+		return NewExprDeny(), nil
+	}
+	var subs []*Expr
+	for _, sub := range e.Exprs {
+		w, err := sub.Without(id)
+		if err != nil {
+			return nil, err
+		}
+		if w == nil {
+			if e.Op != OpOr {
+				return nil, fmt.Errorf("cannot remove %s from %s", id, e)
+			}
+			continue
+		}
+		subs = append(subs, w)
+	}
+	if len(subs) == 0 {
+		return nil, nil
+	}
+	return &Expr{Op: e.Op, Exprs: subs, Threshold: e.Threshold}, nil
+}
+
+// Check returns an error if the expression can never be fulfilled because
+// it is malformed.
+func (e *Expr) Check() error {
+	if e == nil {
+		return errors.New("empty expression")
+	}
+	switch e.Op {
+	case OpIdentity:
+		if e.Identity == nil || e.Identity.Type() < 0 {
+			return errors.New("expression without identity")
+		}
+		return nil
+	case OpAnd, OpOr:
+		if len(e.Exprs) == 0 {
+			return errors.New("expression without sub-expressions")
+		}
+	case OpThreshold:
+		if e.Threshold <= 0 || e.Threshold > len(e.Exprs) {
+			return fmt.Errorf("threshold %d out of range for %d sub-expressions",
+				e.Threshold, len(e.Exprs))
+		}
+	case OpDeny:
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %d", e.Op)
+	}
+	for _, sub := range e.Exprs {
+		if err := sub.Check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns a human readable representation of the expression.
+func (e *Expr) String() string {
+	if e == nil {
+		return "nil"
+	}
+	var subs []string
+	for _, sub := range e.Exprs {
+		subs = append(subs, sub.String())
+	}
+	switch e.Op {
+	case OpIdentity:
+		if e.Identity == nil {
+			return "nil"
+		}
+		return e.Identity.String()
+	case OpAnd:
+		return "(" + strings.Join(subs, " & ") + ")"
+	case OpOr:
+		return "(" + strings.Join(subs, " | ") + ")"
+	case OpThreshold:
+		return fmt.Sprintf("%d-of(%s)", e.Threshold, strings.Join(subs, ", "))
+	case OpDeny:
+		return "deny"
+	}
+	return "unknown"
+}
+
+// SetRule sets the expression that needs to be fulfilled for the action,
+// replacing an eventual existing rule for this action.
+func (d *Darc) SetRule(action string, expr *Expr) error {
+	if action == "" {
+		return errors.New("empty action")
+	}
+	if err := expr.Check(); err != nil {
+		return err
+	}
+	var rules []*Rule
+	if d.Rules != nil {
+		for _, r := range *d.Rules {
+			if r.Action != action {
+				rules = append(rules, r)
+			}
+		}
+	}
+	rules = append(rules, &Rule{Action: action, Expr: expr})
+	d.Rules = &rules
+	return nil
+}
+
+// RemoveRule removes the rule for the action, so that the default rule
+// applies again.
+func (d *Darc) RemoveRule(action string) error {
+	if d.GetRule(action) == nil {
+		return errors.New("no rule for this action")
+	}
+	var rules []*Rule
+	for _, r := range *d.Rules {
+		if r.Action != action {
+			rules = append(rules, r)
+		}
+	}
+	d.Rules = &rules
+	return nil
+}
+
+// GetRule returns the expression stored for the action, or nil if the darc
+// has no rule for it.
+func (d *Darc) GetRule(action string) *Expr {
+	if d.Rules == nil {
+		return nil
+	}
+	for _, r := range *d.Rules {
+		if r.Action == action {
+			return r.Expr
+		}
+	}
+	return nil
+}
+
+// Expression returns the expression that needs to be fulfilled for the
+// action. If the darc has no rule for the action, ActionEvolve and
+// ActionDisclose need one of the owners, and all other actions need one of
+// the users.
+func (d *Darc) Expression(action string) *Expr {
+	if expr := d.GetRule(action); expr != nil {
+		return expr
+	}
+	ids := d.Users
+	if action == ActionEvolve || action == ActionDisclose {
+		ids = d.Owners
+	}
+	if ids == nil {
+		return NewExprOr()
+	}
+	return NewExprIdentities(*ids)
+}
+
+// HasIdentity returns true if the identity is one of the owners, one of the
+// users, or part of one of the rules of the darc.
+func (d *Darc) HasIdentity(id *Identity) bool {
+	for _, list := range []*[]*Identity{d.Owners, d.Users} {
+		if list == nil {
+			continue
+		}
+		for _, i := range *list {
+			if i.Equal(id) {
+				return true
+			}
+		}
+	}
+	if d.Rules != nil {
+		for _, r := range *d.Rules {
+			if r.Expr.Contains(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoveIdentity removes the identity from the owners, the users and all
+// rules of the darc. Rules without any identity left deny the action, so
+// that the default rule doesn't apply again. Removing the identity never
+// grants an action to fewer signers: if the identity is part of an and- or a
+// threshold-expression, an error is returned. An identity that is the only
+// owner of the darc cannot be removed, as nobody could evolve the darc
+// anymore.
+func (d *Darc) RemoveIdentity(id *Identity) error {
+	if !d.HasIdentity(id) {
+		return errors.New("identity is not in the darc")
+	}
+	without := func(list *[]*Identity) *[]*Identity {
+		if list == nil {
+			return nil
+		}
+		var ids []*Identity
+		for _, i := range *list {
+			if !i.Equal(id) {
+				ids = append(ids, i)
+			}
+		}
+		return &ids
+	}
+	owners := without(d.Owners)
+	if owners != nil && len(*owners) == 0 && len(*d.Owners) > 0 {
+		return errors.New("cannot remove the last owner of the darc")
+	}
+	var rules *[]*Rule
+	if d.Rules != nil {
+		rules = &[]*Rule{}
+		for _, r := range *d.Rules {
+			expr, err := r.Expr.Without(id)
+			if err != nil {
+				return err
+			}
+			if expr == nil {
+				expr = NewExprDeny()
+			}
+			*rules = append(*rules, &Rule{Action: r.Action, Expr: expr})
+		}
+	}
+	d.Owners = owners
+	d.Users = without(d.Users)
+	d.Rules = rules
+	return nil
+}
+
+// Action returns the action used for a role if a darc has no rules.
+func (r Role) Action() string {
+	if r == Owner {
+		return ActionEvolve
+	}
+	return ActionSign
+}
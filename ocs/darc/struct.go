@@ -1,6 +1,8 @@
 package darc
 
 import (
+	"crypto"
+
 	"github.com/dedis/kyber"
 	"github.com/dedis/onet/network"
 )
@@ -12,7 +14,8 @@ func init() {
 }
 
 // ID is the identity of a Darc - which is the sha256 of its protobuf representation
-// over invariant fields [Owners, Users, Version, Description]. Signature is excluded.
+// over invariant fields [Owners, Users, Version, Description, Rules]. Signature
+// is excluded.
 // An evolving Darc will change its identity.
 type ID []byte
 
@@ -27,6 +30,40 @@ const (
 	User
 )
 
+// Actions that are used by the cothority. Other actions can be defined by the
+// applications using darcs.
+const (
+	// ActionEvolve is needed to evolve the Darc to a new version.
+	ActionEvolve = "darc:evolve"
+	// ActionSign is needed to sign on behalf of a Darc that has been
+	// delegated to.
+	ActionSign = "darc:sign"
+	// ActionWrite is needed to write a document to the ocs-skipchain.
+	ActionWrite = "ocs:write"
+	// ActionRead is needed to request a document from the ocs-skipchain.
+	ActionRead = "ocs:read"
+	// ActionDisclose is needed to disclose a document of the ocs-skipchain
+	// to the public. Without a rule, it needs one of the owners.
+	ActionDisclose = "ocs:disclose"
+)
+
+// ExprOp is the operation of an expression.
+type ExprOp int
+
+const (
+	// OpIdentity is fulfilled if the identity signed.
+	OpIdentity ExprOp = iota
+	// OpAnd is fulfilled if all sub-expressions are fulfilled.
+	OpAnd
+	// OpOr is fulfilled if at least one sub-expression is fulfilled.
+	OpOr
+	// OpThreshold is fulfilled if at least Threshold sub-expressions are
+	// fulfilled.
+	OpThreshold
+	// OpDeny is never fulfilled.
+	OpDeny
+)
+
 // PROTOSTART
 //
 // option java_package = "ch.epfl.dedis.proto";
@@ -53,6 +90,32 @@ type Darc struct {
 	// Signature is calculated over the protobuf representation of [Owner, Users, Version, Description]
 	// and needs to be created by an Owner from the previous valid Darc.
 	Signature *Signature
+	// Rules define which identities need to sign for a given action. If there
+	// is no rule for an action, ActionEvolve and ActionDisclose need one of
+	// the Owners and all other actions need one of the Users.
+	Rules *[]*Rule
+}
+
+// Rule holds the expression that needs to be fulfilled to perform an action.
+type Rule struct {
+	// Action is the name of the action, e.g. "ocs:write".
+	Action string
+	// Expr is the expression over identities that needs to be fulfilled.
+	Expr *Expr
+}
+
+// Expr is a boolean expression over identities. Depending on Op, it is either
+// an identity or a combination of sub-expressions.
+type Expr struct {
+	// Op is the operation of this expression.
+	Op ExprOp
+	// Identity is the signer needed for OpIdentity.
+	Identity *Identity
+	// Exprs are the sub-expressions for OpAnd, OpOr and OpThreshold.
+	Exprs []*Expr
+	// Threshold is the number of sub-expressions that need to be fulfilled
+	// for OpThreshold.
+	Threshold int
 }
 
 // Identity is a generic structure can be either an Ed25519 public key or a Darc
@@ -63,6 +126,8 @@ type Identity struct {
 	Ed25519 *IdentityEd25519
 	// Public-key identity
 	X509EC *IdentityX509EC
+	// Certificate-chain identity
+	X509Chain *IdentityX509Chain
 }
 
 // IdentityEd25519 holds a Ed25519 public key (Point)
@@ -75,6 +140,21 @@ type IdentityX509EC struct {
 	Public []byte
 }
 
+// IdentityX509Chain holds the DER-encoding of a trusted root certificate.
+// Every key with a valid certificate chaining to the root can sign for this
+// identity.
+type IdentityX509Chain struct {
+	Root []byte
+}
+
+// X509ChainSignature is the signature of an IdentityX509Chain. It holds the
+// leaf certificate, followed by eventual intermediate certificates, and the
+// signature of the message by the key of the leaf certificate.
+type X509ChainSignature struct {
+	Certificates [][]byte
+	Signature    []byte
+}
+
 // IdentityDarc is a structure that points to a Darc with a given DarcID on a skipchain
 type IdentityDarc struct {
 	ID ID
@@ -87,6 +167,10 @@ type Signature struct {
 	Signature []byte
 	// Represents the path to get up to information to be able to verify this signature
 	SignaturePath SignaturePath
+	// Others are the signatures of additional signers on the same message,
+	// for rules that need more than one signer. Their paths must start at
+	// the same darc.
+	Others *[]*Signature
 }
 
 // SignaturePath is a struct that holds information necessary for signature verification
@@ -101,8 +185,9 @@ type SignaturePath struct {
 
 // Signer is a generic structure that can hold different types of signers
 type Signer struct {
-	Ed25519 *SignerEd25519
-	X509EC  *SignerX509EC
+	Ed25519   *SignerEd25519
+	X509EC    *SignerX509EC
+	X509Chain *SignerX509Chain
 }
 
 // SignerEd25519 holds a public and private keys necessary to sign Darcs
@@ -117,3 +202,14 @@ type SignerX509EC struct {
 	Point  []byte
 	secret []byte
 }
+
+// SignerX509Chain holds a certificate chain and the private key of its leaf
+// certificate. The private key will not be given out.
+type SignerX509Chain struct {
+	// Root is the DER-encoding of the trusted root certificate
+	Root []byte
+	// Chain holds the DER-encoding of the leaf certificate, followed by
+	// eventual intermediate certificates
+	Chain  [][]byte
+	secret crypto.Signer
+}
@@ -0,0 +1,137 @@
+package darc
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/dedis/protobuf"
+)
+
+/*
+This file holds the identities based on X.509 certificate chains. The
+identity is a trusted root certificate, and every key holding a valid leaf
+certificate chaining to this root can sign for the identity. The signature
+holds the certificate chain together with the signature of the leaf key.
+*/
+
+// NewIdentityX509Chain creates a new X509Chain identity struct given the
+// DER-encoding of the trusted root certificate.
+func NewIdentityX509Chain(root []byte) *Identity {
+	return &Identity{
+		X509Chain: &IdentityX509Chain{
+			Root: root,
+		},
+	}
+}
+
+// Equal returns true if both IdentityX509Chain point to the same root.
+func (idx *IdentityX509Chain) Equal(idx2 *IdentityX509Chain) bool {
+	return bytes.Compare(idx.Root, idx2.Root) == 0
+}
+
+// Verify returns nil if the signature is correct, or an error if something
+// fails. The leaf certificate of the signature needs to chain to the root,
+// all certificates need to be valid now, and the leaf certificate needs to
+// allow digital signatures.
+func (idx *IdentityX509Chain) Verify(msg, s []byte) error {
+	return idx.VerifyAt(msg, s, time.Now())
+}
+
+// VerifyAt works like Verify, but the certificates need to be valid at the
+// time when. The time should be the one of the block or transaction holding
+// the signature, so that all nodes agree on the result.
+func (idx *IdentityX509Chain) VerifyAt(msg, s []byte, when time.Time) error {
+	sig := &X509ChainSignature{}
+	if err := protobuf.Decode(s, sig); err != nil {
+		return err
+	}
+	if len(sig.Certificates) == 0 {
+		return errors.New("no certificate in signature")
+	}
+	root, err := x509.ParseCertificate(idx.Root)
+	if err != nil {
+		return errors.New("couldn't parse root certificate: " + err.Error())
+	}
+	leaf, err := x509.ParseCertificate(sig.Certificates[0])
+	if err != nil {
+		return errors.New("couldn't parse leaf certificate: " + err.Error())
+	}
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   when,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	opts.Roots.AddCert(root)
+	for _, der := range sig.Certificates[1:] {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return errors.New("couldn't parse intermediate certificate: " + err.Error())
+		}
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(opts); err != nil {
+		return errors.New("invalid certificate chain: " + err.Error())
+	}
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return errors.New("leaf certificate doesn't allow digital signatures")
+	}
+	var algo x509.SignatureAlgorithm
+	switch leaf.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		algo = x509.ECDSAWithSHA384
+	case *rsa.PublicKey:
+		algo = x509.SHA256WithRSA
+	default:
+		return errors.New("unsupported key type of leaf certificate")
+	}
+	return leaf.CheckSignature(algo, msg, sig.Signature)
+}
+
+// NewSignerX509Chain creates a new signer using the DER-encoding of the
+// trusted root certificate, the DER-encoding of the leaf certificate
+// followed by eventual intermediate certificates, and the private key of
+// the leaf certificate. ECDSA and RSA keys are supported.
+func NewSignerX509Chain(root []byte, chain [][]byte, secret crypto.Signer) *Signer {
+	return &Signer{X509Chain: &SignerX509Chain{
+		Root:   root,
+		Chain:  chain,
+		secret: secret,
+	}}
+}
+
+// Sign signs the message with the key of the leaf certificate and returns
+// an X509ChainSignature holding the certificate chain.
+func (xcs *SignerX509Chain) Sign(msg []byte) ([]byte, error) {
+	if xcs.secret == nil {
+		return nil, errors.New("signer lacks a private key")
+	}
+	var digest []byte
+	var hash crypto.Hash
+	switch xcs.secret.Public().(type) {
+	case *ecdsa.PublicKey:
+		h := sha512.Sum384(msg)
+		digest, hash = h[:], crypto.SHA384
+	case *rsa.PublicKey:
+		h := sha256.Sum256(msg)
+		digest, hash = h[:], crypto.SHA256
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+	sig, err := xcs.secret.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, err
+	}
+	return protobuf.Encode(&X509ChainSignature{
+		Certificates: xcs.Chain,
+		Signature:    sig,
+	})
+}
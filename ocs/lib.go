@@ -1,12 +1,19 @@
 package main
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/ocs/darc"
 	"github.com/dedis/cothority/ocs/service"
+	"github.com/dedis/cothority/skipchain"
+	"github.com/dedis/kyber/util/encoding"
 	"github.com/dedis/onet/app"
 	"github.com/dedis/onet/log"
 	"github.com/dedis/onet/network"
@@ -96,6 +103,137 @@ func getGroup(c *cli.Context) *app.Group {
 	return groups
 }
 
+// ocsToken is the json-representation of the url of an OCS-skipchain.
+type ocsToken struct {
+	Genesis string      `json:"genesis"`
+	Roster  []tokenNode `json:"roster"`
+}
+
+type tokenNode struct {
+	Address     string `json:"address"`
+	Public      string `json:"public"`
+	Description string `json:"description,omitempty"`
+}
+
+// newOCSToken returns the json-token of the url of an OCS-skipchain.
+func newOCSToken(scurl *service.SkipChainURL) ([]byte, error) {
+	if scurl.Roster == nil {
+		return nil, errors.New("skipchain-url has no roster")
+	}
+	token := ocsToken{Genesis: hex.EncodeToString(scurl.Genesis)}
+	for _, si := range scurl.Roster.List {
+		pub, err := encoding.PointToStringHex(cothority.Suite, si.Public)
+		if err != nil {
+			return nil, err
+		}
+		token.Roster = append(token.Roster, tokenNode{
+			Address:     string(si.Address),
+			Public:      pub,
+			Description: si.Description,
+		})
+	}
+	return json.Marshal(token)
+}
+
+// parseIdentity returns the identity given as "darc:id", "ed25519:public"
+// or "x509ec:public", all in hex. Without a prefix, the string is taken as
+// an ed25519 public key, as returned by keypair.
+func parseIdentity(s string) (*darc.Identity, error) {
+	kind, value := "ed25519", s
+	if i := strings.Index(s, ":"); i >= 0 {
+		kind, value = strings.ToLower(s[:i]), s[i+1:]
+	}
+	switch kind {
+	case "ed25519":
+		pub, err := encoding.StringHexToPoint(cothority.Suite, value)
+		if err != nil {
+			return nil, errors.New("couldn't parse public key: " + err.Error())
+		}
+		return darc.NewIdentityEd25519(pub), nil
+	case "darc":
+		id, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("couldn't parse darc-id: " + err.Error())
+		}
+		return darc.NewIdentityDarc(id), nil
+	case "x509ec":
+		pub, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("couldn't parse public key: " + err.Error())
+		}
+		return darc.NewIdentityX509EC(pub), nil
+	default:
+		return nil, fmt.Errorf("unknown identity type %s", kind)
+	}
+}
+
+// parseSigner returns an ed25519 signer given the private key in hex, as
+// returned by keypair.
+func parseSigner(s string) (*darc.Signer, error) {
+	priv, err := encoding.StringHexToScalar(cothority.Suite, s)
+	if err != nil {
+		return nil, errors.New("couldn't parse private key: " + err.Error())
+	}
+	return darc.NewSignerEd25519(cothority.Suite.Point().Mul(priv, nil), priv), nil
+}
+
+// getLatestDarc returns the latest version of the darc with the given id
+// in hex.
+func getLatestDarc(cfg *ocsConfig, idStr string) (*darc.Darc, error) {
+	id, err := hex.DecodeString(idStr)
+	if err != nil {
+		return nil, errors.New("couldn't parse darc-id: " + err.Error())
+	}
+	path, err := service.NewClient().GetLatestDarc(cfg.SkipChainURL, id)
+	if err != nil {
+		return nil, err
+	}
+	if path == nil || len(*path) == 0 {
+		return nil, errors.New("didn't find darc")
+	}
+	return (*path)[len(*path)-1], nil
+}
+
+// getWrite returns the write of the document a read-request refers to.
+func getWrite(cfg *ocsConfig, readID skipchain.SkipBlockID) (*service.Write, error) {
+	cl := skipchain.NewClient()
+	readSB, err := cl.GetSingleBlock(cfg.SkipChainURL.Roster, readID)
+	if err != nil {
+		return nil, err
+	}
+	read := service.NewOCS(readSB.Data)
+	if read == nil || read.Read == nil {
+		return nil, errors.New("not a read-request block")
+	}
+	writeSB, err := cl.GetSingleBlock(cfg.SkipChainURL.Roster, read.Read.DataID)
+	if err != nil {
+		return nil, err
+	}
+	write := service.NewOCS(writeSB.Data)
+	if write == nil || write.Write == nil {
+		return nil, errors.New("not a write block")
+	}
+	return write.Write, nil
+}
+
+// removeOwner removes the identity from the owners of the darc.
+func removeOwner(d *darc.Darc, id *darc.Identity) error {
+	if d.Owners == nil {
+		return errors.New("owners list of the darc is empty")
+	}
+	var owners []*darc.Identity
+	for _, o := range *d.Owners {
+		if !o.Equal(id) {
+			owners = append(owners, o)
+		}
+	}
+	if len(owners) == len(*d.Owners) {
+		return errors.New("owner cannot be removed because it is not in the darc")
+	}
+	d.Owners = &owners
+	return nil
+}
+
 // mkDir fails only if it is another error than an existing directory
 func mkdir(n string, p os.FileMode) error {
 	err := os.Mkdir(n, p)
@@ -0,0 +1,139 @@
+package protocol
+
+/*
+The disclose-protocol implements a threshold decryption of the symmetric key
+of a document. Contrary to the onchain-protocol, the key is not re-encrypted
+for a reader, but every node returns its decryption share together with a
+DLEQ-proof, so that anyone can verify the shares and recover the key.
+*/
+
+import (
+	"errors"
+
+	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/share"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/log"
+)
+
+func init() {
+	onet.GlobalProtocolRegister(NameDisclose, NewDisclose)
+}
+
+// Disclose collects the verified decryption shares of a public
+// point. Before calling `Start`, Shared, Poly and U must be initialized by
+// the caller.
+type Disclose struct {
+	*onet.TreeNodeInstance
+	Shared    *SharedSecret  // Shared represents the private key
+	Poly      *share.PubPoly // Represents all public keys
+	U         kyber.Point    // U is the encrypted secret
+	Threshold int            // How many valid shares are needed
+	// VerificationData is given to the VerifyDisclose and has to hold
+	// everything needed to verify the request is valid.
+	VerificationData []byte
+	// Can be set by the service to decide whether or not to give the
+	// decryption share.
+	Verify VerifyDisclose
+	// Disclosed receives a 'true'-value when enough valid shares have been
+	// collected, or 'false' if not.
+	Disclosed chan bool
+	// Shares holds the valid decryption shares, including the one of the
+	// root.
+	Shares []*DecryptShare
+	// private fields
+	replies int
+}
+
+// NewDisclose initialises the structure for use in one round
+func NewDisclose(n *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
+	o := &Disclose{
+		TreeNodeInstance: n,
+		Disclosed:        make(chan bool, 1),
+		Threshold:        len(n.Roster().List) - (len(n.Roster().List)-1)/3,
+	}
+
+	err := o.RegisterHandlers(o.disclose, o.discloseReply)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Start asks all children to reply with their decryption share
+func (o *Disclose) Start() error {
+	log.Lvl3("Starting Protocol")
+	if o.Shared == nil {
+		return errors.New("please initialize Shared first")
+	}
+	if o.Poly == nil {
+		return errors.New("please initialize Poly first")
+	}
+	if o.U == nil {
+		return errors.New("please initialize U first")
+	}
+	d := &StartDisclose{U: o.U}
+	if len(o.VerificationData) > 0 {
+		d.VerificationData = &o.VerificationData
+	}
+	if o.Verify != nil && !o.Verify(d) {
+		o.Disclosed <- false
+		o.Done()
+		return errors.New("refused to disclose")
+	}
+	ds, err := NewDecryptShare(o.Shared, o.U)
+	if err != nil {
+		return err
+	}
+	o.Shares = append(o.Shares, ds)
+	errs := o.Broadcast(d)
+	if len(errs) > (len(o.Roster().List)-1)/3 {
+		log.Errorf("Some nodes failed with error(s) %v", errs)
+		return errors.New("too many nodes failed in broadcast")
+	}
+	return nil
+}
+
+// disclose is received by every node to give its decryption share
+func (o *Disclose) disclose(d structStartDisclose) error {
+	defer o.Done()
+	log.Lvl3(o.Name() + ": starting disclose")
+	if o.Shared == nil {
+		log.Lvl2(o.ServerIdentity(), "doesn't hold a share")
+		return o.SendToParent(&DiscloseReply{})
+	}
+	if o.Verify != nil && !o.Verify(&d.StartDisclose) {
+		log.Lvl2(o.ServerIdentity(), "refused to disclose")
+		return o.SendToParent(&DiscloseReply{})
+	}
+	ds, err := NewDecryptShare(o.Shared, d.U)
+	if err != nil {
+		log.Error(err)
+		return o.SendToParent(&DiscloseReply{})
+	}
+	return o.SendToParent(&DiscloseReply{Share: ds})
+}
+
+// discloseReply is the root-node collecting and verifying the decryption
+// shares.
+func (o *Disclose) discloseReply(dr structDiscloseReply) error {
+	o.replies++
+	if dr.Share == nil {
+		log.Lvl2("Node", dr.ServerIdentity, "refused to reply")
+	} else if err := dr.Share.Verify(o.U, o.Poly); err != nil {
+		log.Lvl1("Received invalid decryption share from node", dr.ServerIdentity)
+	} else {
+		o.Shares = append(o.Shares, dr.Share)
+	}
+	if len(o.Shares) >= o.Threshold {
+		o.Disclosed <- true
+		o.Done()
+		return nil
+	}
+	if o.replies == len(o.Children()) {
+		log.Lvl2("couldn't get enough decryption shares")
+		o.Disclosed <- false
+		o.Done()
+	}
+	return nil
+}
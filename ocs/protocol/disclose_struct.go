@@ -0,0 +1,112 @@
+package protocol
+
+/*
+disclose_struct holds all messages for the public disclosure protocol.
+*/
+
+import (
+	"errors"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/proof/dleq"
+	"github.com/dedis/kyber/share"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/network"
+)
+
+// NameDisclose can be used from other packages to refer to this protocol.
+const NameDisclose = "OCSDisclose"
+
+func init() {
+	network.RegisterMessages(&StartDisclose{}, &DiscloseReply{})
+}
+
+// VerifyDisclose is a callback-function that can be set by a service.
+// Whenever a disclosure request is received, this function will be called
+// and its return-value used to determine whether or not to give the
+// decryption share.
+type VerifyDisclose func(d *StartDisclose) bool
+
+// StartDisclose asks for a decryption share from a node
+type StartDisclose struct {
+	// U is the point from the write-request
+	U kyber.Point
+	// VerificationData is optional and can be any slice of bytes, so that each
+	// node can verify if the disclosure request is valid or not.
+	VerificationData *[]byte
+}
+
+type structStartDisclose struct {
+	*onet.TreeNode
+	StartDisclose
+}
+
+// DiscloseReply returns the decryption share of one node. Share is nil if
+// the node refused the disclosure.
+type DiscloseReply struct {
+	Share *DecryptShare
+}
+
+type structDiscloseReply struct {
+	*onet.TreeNode
+	DiscloseReply
+}
+
+// DecryptShare is the decryption share Ui = xi * U of one node, together
+// with a proof that the same private share xi is used as in the public share
+// Xi = xi * G of the node.
+type DecryptShare struct {
+	Index int
+	Ui    kyber.Point
+	Proof *dleq.Proof
+}
+
+// NewDecryptShare returns the decryption share of U using the private
+// share of the node.
+func NewDecryptShare(shared *SharedSecret, U kyber.Point) (*DecryptShare, error) {
+	proof, _, ui, err := dleq.NewDLEQProof(cothority.Suite,
+		cothority.Suite.Point().Base(), U, shared.V)
+	if err != nil {
+		return nil, err
+	}
+	return &DecryptShare{
+		Index: shared.Index,
+		Ui:    ui,
+		Proof: proof,
+	}, nil
+}
+
+// Verify checks the proof of the decryption share of U against the public
+// share of the node, which is taken from the public polynomial.
+func (ds *DecryptShare) Verify(U kyber.Point, poly *share.PubPoly) error {
+	if ds.Ui == nil || ds.Proof == nil {
+		return errors.New("incomplete decryption share")
+	}
+	if ds.Index < 0 {
+		return errors.New("negative index of decryption share")
+	}
+	xi := poly.Eval(ds.Index).V
+	return ds.Proof.Verify(cothority.Suite, cothority.Suite.Point().Base(),
+		U, xi, ds.Ui)
+}
+
+// RecoverDecryption verifies the decryption shares of U and interpolates
+// the first threshold valid shares to x * U, where x is the shared private
+// key of the public polynomial.
+func RecoverDecryption(U kyber.Point, shares []*DecryptShare, poly *share.PubPoly,
+	threshold int) (kyber.Point, error) {
+	var valid []*share.PubShare
+	indexes := make(map[int]bool)
+	for _, ds := range shares {
+		if indexes[ds.Index] || ds.Verify(U, poly) != nil {
+			continue
+		}
+		indexes[ds.Index] = true
+		valid = append(valid, &share.PubShare{I: ds.Index, V: ds.Ui})
+	}
+	if len(valid) < threshold {
+		return nil, errors.New("not enough valid decryption shares")
+	}
+	return share.RecoverCommit(cothority.Suite, valid, threshold, len(valid))
+}
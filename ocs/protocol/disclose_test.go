@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/kyber/share"
+	dkg "github.com/dedis/kyber/share/dkg/rabin"
+	"github.com/dedis/kyber/util/random"
+	"github.com/dedis/onet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisclose(t *testing.T) {
+	nbrNodes, threshold := 4, 3
+	local := onet.NewLocalTest(tSuite)
+	defer local.CloseAll()
+	servers, _, tree := local.GenBigTree(nbrNodes, nbrNodes, nbrNodes, true)
+
+	dkgs, err := CreateDKGs(tSuite.(dkg.Suite), nbrNodes, threshold)
+	require.Nil(t, err)
+	services := local.GetServices(servers, testServiceID)
+	for i := range services {
+		services[i].(*testService).Shared, err = NewSharedSecret(dkgs[i])
+		require.Nil(t, err)
+	}
+	dks, err := dkgs[0].DistKeyShare()
+	require.Nil(t, err)
+	X := dks.Public()
+	poly := share.NewPubPoly(tSuite, tSuite.Point().Base(), dks.Commits)
+
+	k := make([]byte, 32)
+	random.Bytes(k, random.New())
+	U, Cs := EncodeKey(tSuite, X, k)
+
+	pi, err := services[0].(*testService).CreateProtocol(NameDisclose, tree)
+	require.Nil(t, err)
+	protocol := pi.(*Disclose)
+	protocol.Shared = services[0].(*testService).Shared
+	protocol.Poly = poly
+	protocol.U = U
+	protocol.Threshold = threshold
+	protocol.VerificationData = []byte("correct block")
+	require.Nil(t, protocol.Start())
+	select {
+	case ok := <-protocol.Disclosed:
+		require.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Didn't finish in time")
+	}
+	require.True(t, len(protocol.Shares) >= threshold)
+
+	// A share with a wrong proof is ignored.
+	bad := *protocol.Shares[0]
+	bad.Ui = tSuite.Point().Add(bad.Ui, tSuite.Point().Base())
+	require.NotNil(t, bad.Verify(U, poly))
+	_, err = RecoverDecryption(U, append([]*DecryptShare{&bad}, protocol.Shares[1:threshold]...),
+		poly, threshold)
+	require.NotNil(t, err)
+
+	xU, err := RecoverDecryption(U, protocol.Shares, poly, threshold)
+	require.Nil(t, err)
+	var key []byte
+	for _, C := range Cs {
+		keyPart, err := cothority.Suite.Point().Sub(C, xU).Data()
+		require.Nil(t, err)
+		key = append(key, keyPart...)
+	}
+	require.Equal(t, k, key)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/dedis/kyber/share"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
 )
 
 func init() {
@@ -40,6 +41,8 @@ type OCS struct {
 	// or 'false' if not enough shares have been collected.
 	Reencrypted chan bool
 	Uis         []*share.PubShare // re-encrypted shares
+	// Invalid holds the nodes that sent a share with a wrong proof.
+	Invalid []*network.ServerIdentity
 	// private fields
 	replies []ReencryptReply
 }
@@ -68,6 +71,9 @@ func (o *OCS) Start() error {
 	if o.U == nil {
 		return errors.New("please initialize U first")
 	}
+	if o.Poly == nil {
+		return errors.New("please initialize Poly first")
+	}
 	rc := &Reencrypt{
 		U:  o.U,
 		Xc: o.Xc,
@@ -125,52 +131,73 @@ func (o *OCS) reencrypt(r structReencrypt) error {
 }
 
 // ReencryptReply is the root-node waiting for all replies and generating
-// the reencryption key.
+// the reencryption key. Every share is verified against the public
+// polynomial, and invalid shares are rejected. Once a threshold of valid
+// shares is collected, Reencrypted receives 'true'.
 func (o *OCS) reencryptReply(rr structReencryptReply) error {
 	if rr.ReencryptReply.Ui == nil {
 		log.Lvl2("Node", rr.ServerIdentity, "refused to reply")
 		o.Failures++
-		if o.Failures >= len(o.Children())-o.Threshold {
-			log.Lvl2(rr.ServerIdentity, "couldn't get enough shares")
-			o.Reencrypted <- false
-			o.Done()
-		}
-		return nil
+	} else if err := o.verifyReply(&rr.ReencryptReply); err != nil {
+		log.Lvl1("Received invalid share from node", rr.ServerIdentity, ":", err)
+		o.Invalid = append(o.Invalid, rr.ServerIdentity)
+		o.Failures++
+	} else {
+		o.replies = append(o.replies, rr.ReencryptReply)
 	}
-	o.replies = append(o.replies, rr.ReencryptReply)
 
 	// minus one to exclude the root
-	if len(o.replies) >= int(o.Threshold-1) {
+	if len(o.replies) >= o.Threshold-1 {
 		o.Uis = make([]*share.PubShare, len(o.List()))
-		var err error
-		o.Uis[0], err = o.getUI(o.U, o.Xc)
+		ui, err := o.getUI(o.U, o.Xc)
 		if err != nil {
 			return err
 		}
-
+		o.Uis[ui.I] = ui
 		for _, r := range o.replies {
-			// Verify proofs
-			ufi := cothority.Suite.Point().Mul(r.Fi, cothority.Suite.Point().Add(o.U, o.Xc))
-			uiei := cothority.Suite.Point().Mul(cothority.Suite.Scalar().Neg(r.Ei), r.Ui.V)
-			uiHat := cothority.Suite.Point().Add(ufi, uiei)
-
-			gfi := cothority.Suite.Point().Mul(r.Fi, nil)
-			gxi := o.Poly.Eval(r.Ui.I).V
-			hiei := cothority.Suite.Point().Mul(cothority.Suite.Scalar().Neg(r.Ei), gxi)
-			hiHat := cothority.Suite.Point().Add(gfi, hiei)
-			hash := sha256.New()
-			r.Ui.V.MarshalTo(hash)
-			uiHat.MarshalTo(hash)
-			hiHat.MarshalTo(hash)
-			e := cothority.Suite.Scalar().SetBytes(hash.Sum(nil))
-			if e.Equal(r.Ei) {
-				o.Uis[r.Ui.I] = r.Ui
-			} else {
-				log.Lvl1("Received invalid share from node", r.Ui.I)
-			}
+			o.Uis[r.Ui.I] = r.Ui
 		}
 		o.Reencrypted <- true
 		o.Done()
+		return nil
+	}
+	if o.Failures > len(o.Children())-(o.Threshold-1) {
+		log.Lvl2(rr.ServerIdentity, "couldn't get enough shares")
+		o.Reencrypted <- false
+		o.Done()
+	}
+	return nil
+}
+
+// verifyReply checks the proof of a re-encrypted share against the public
+// share of the node, which is taken from the public polynomial.
+func (o *OCS) verifyReply(r *ReencryptReply) error {
+	if r.Ui.V == nil || r.Ei == nil || r.Fi == nil {
+		return errors.New("incomplete share")
+	}
+	if r.Ui.I < 0 || r.Ui.I >= len(o.List()) || r.Ui.I == o.Shared.Index {
+		return errors.New("invalid index of share")
+	}
+	for _, prev := range o.replies {
+		if prev.Ui.I == r.Ui.I {
+			return errors.New("got share twice")
+		}
+	}
+	ufi := cothority.Suite.Point().Mul(r.Fi, cothority.Suite.Point().Add(o.U, o.Xc))
+	uiei := cothority.Suite.Point().Mul(cothority.Suite.Scalar().Neg(r.Ei), r.Ui.V)
+	uiHat := cothority.Suite.Point().Add(ufi, uiei)
+
+	gfi := cothority.Suite.Point().Mul(r.Fi, nil)
+	gxi := o.Poly.Eval(r.Ui.I).V
+	hiei := cothority.Suite.Point().Mul(cothority.Suite.Scalar().Neg(r.Ei), gxi)
+	hiHat := cothority.Suite.Point().Add(gfi, hiei)
+	hash := sha256.New()
+	r.Ui.V.MarshalTo(hash)
+	uiHat.MarshalTo(hash)
+	hiHat.MarshalTo(hash)
+	e := cothority.Suite.Scalar().SetBytes(hash.Sum(nil))
+	if !e.Equal(r.Ei) {
+		return errors.New("wrong proof")
 	}
 	return nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/dedis/kyber/util/random"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,6 +50,61 @@ func TestRefuse(t *testing.T) {
 	ocs(t, 3, 2, 32, 0, true)
 }
 
+// Tests that invalid shares are rejected, and that the key is recovered
+// as long as a threshold of valid shares exists.
+func TestInvalidShare(t *testing.T) {
+	for _, threshold := range []int{3, 4} {
+		nbrNodes := 4
+		local := onet.NewLocalTest(tSuite)
+		servers, _, tree := local.GenBigTree(nbrNodes, nbrNodes, nbrNodes, true)
+		dkgs, err := CreateDKGs(tSuite.(dkg.Suite), nbrNodes, threshold)
+		require.Nil(t, err)
+		services := local.GetServices(servers, testServiceID)
+		for i := range services {
+			services[i].(*testService).Shared, err = NewSharedSecret(dkgs[i])
+			require.Nil(t, err)
+		}
+		// The second node uses a wrong share.
+		bad := *services[1].(*testService).Shared
+		bad.V = tSuite.Scalar().Pick(tSuite.RandomStream())
+		services[1].(*testService).Shared = &bad
+		dks, err := dkgs[0].DistKeyShare()
+		require.Nil(t, err)
+		X := dks.Public()
+
+		k := []byte("symmetric key")
+		U, Cs := EncodeKey(tSuite, X, k)
+		xc := key.NewKeyPair(cothority.Suite)
+		pi, err := services[0].(*testService).createOCS(tree, threshold)
+		require.Nil(t, err)
+		protocol := pi.(*OCS)
+		protocol.U = U
+		protocol.Xc = xc.Public
+		protocol.Poly = share.NewPubPoly(suite, suite.Point().Base(), dks.Commits)
+		protocol.VerificationData = []byte("correct block")
+		require.Nil(t, protocol.Start())
+		var ok bool
+		select {
+		case ok = <-protocol.Reencrypted:
+		case <-time.After(time.Second):
+			t.Fatal("Didn't finish in time")
+		}
+		if threshold == nbrNodes {
+			require.False(t, ok)
+			require.Equal(t, 1, len(protocol.Invalid))
+			local.CloseAll()
+			continue
+		}
+		require.True(t, ok)
+		XhatEnc, err := share.RecoverCommit(suite, protocol.Uis, threshold, nbrNodes)
+		require.Nil(t, err)
+		keyHat, err := DecodeKey(suite, X, Cs, XhatEnc, xc.Private)
+		require.Nil(t, err)
+		require.Equal(t, k, keyHat)
+		local.CloseAll()
+	}
+}
+
 func TestOCSKeyLengths(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Testing all keylengths takes some time...")
@@ -145,6 +201,8 @@ type testService struct {
 	// Has to be initialised by the test
 	Shared *SharedSecret
 	Poly   *share.PubPoly
+	// Receives the new share after a reshare-protocol
+	reshared chan *SharedSecret
 }
 
 // Creates a service-protocol and returns the ProtocolInstance.
@@ -156,6 +214,16 @@ func (s *testService) createOCS(t *onet.Tree, threshold int) (onet.ProtocolInsta
 	return pi, err
 }
 
+// Creates a reshare-protocol and returns the ProtocolInstance.
+func (s *testService) createReshare(t *onet.Tree) (*Reshare, error) {
+	pi, err := s.CreateProtocol(NameReshare, t)
+	if err != nil {
+		return nil, err
+	}
+	pi.(*Reshare).Shared = s.Shared
+	return pi.(*Reshare), nil
+}
+
 // Store the dkg in the protocol
 func (s *testService) NewProtocol(tn *onet.TreeNodeInstance, conf *onet.GenericConfig) (onet.ProtocolInstance, error) {
 	switch tn.ProtocolName() {
@@ -170,6 +238,33 @@ func (s *testService) NewProtocol(tn *onet.TreeNodeInstance, conf *onet.GenericC
 			return rc.VerificationData != nil
 		}
 		return ocs, nil
+	case NameDisclose:
+		pi, err := NewDisclose(tn)
+		if err != nil {
+			return nil, err
+		}
+		disclose := pi.(*Disclose)
+		disclose.Shared = s.Shared
+		disclose.Verify = func(d *StartDisclose) bool {
+			return d.VerificationData != nil
+		}
+		return disclose, nil
+	case NameReshare:
+		pi, err := NewReshare(tn)
+		if err != nil {
+			return nil, err
+		}
+		reshare := pi.(*Reshare)
+		reshare.Shared = s.Shared
+		reshare.Verify = func(root *network.ServerIdentity, sr *StartReshare) bool {
+			return sr.VerificationData != nil
+		}
+		go func() {
+			if <-reshare.Finished {
+				s.reshared <- reshare.NewShared
+			}
+		}()
+		return reshare, nil
 	default:
 		return nil, errors.New("unknown protocol for this service")
 	}
@@ -257,6 +352,7 @@ func DecodeKey(suite kyber.Group, X kyber.Point, Cs []kyber.Point, XhatEnc kyber
 func newService(c *onet.Context) (onet.Service, error) {
 	s := &testService{
 		ServiceProcessor: onet.NewServiceProcessor(c),
+		reshared:         make(chan *SharedSecret, 1),
 	}
 	return s, nil
 }
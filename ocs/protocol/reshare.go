@@ -0,0 +1,433 @@
+package protocol
+
+/*
+The reshare-protocol moves the shared secret of a DKG to a new roster and
+threshold, without changing the shared public key X. Every node of the old
+roster picks a random polynomial whose constant term is its old share, and
+sends the evaluations of this polynomial to all nodes of the new roster.
+Every node of the new roster then interpolates the received evaluations to
+get its new share.
+*/
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/share"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
+)
+
+func init() {
+	onet.GlobalProtocolRegister(NameReshare, NewReshare)
+}
+
+// Reshare moves the shared secret to a new roster. The tree must hold all
+// nodes of the old and of the new roster. Before calling `Start`, the root
+// has to set NewRoster, NewThreshold and OldCommits. Every node of the old
+// roster needs to have Shared set.
+type Reshare struct {
+	*onet.TreeNodeInstance
+	// Shared is the share of this node in the old roster, or nil if this
+	// node joins the shared secret.
+	Shared *SharedSecret
+	// NewRoster holds the nodes that will hold the shared secret.
+	NewRoster *onet.Roster
+	// NewThreshold is the number of nodes of the new roster that are
+	// needed to use the shared secret.
+	NewThreshold int
+	// OldCommits are the commitments of the current shared secret.
+	OldCommits []kyber.Point
+	// VerificationData is sent to all nodes, so that they can verify the
+	// resharing request.
+	VerificationData []byte
+	// Verify is called by every node before it deals its share. If it
+	// returns false, the node doesn't deal and doesn't store a new share.
+	Verify VerifyReshare
+	// Finished receives true if all nodes of the new roster got a valid
+	// share, or false if the resharing failed and the old shares are
+	// still valid.
+	Finished chan bool
+	// NewShared is the new share of this node once the protocol finished
+	// successfully, or nil if this node is not part of the new roster.
+	NewShared *SharedSecret
+
+	start     *StartReshare
+	deals     []*ShareDeal
+	selected  []*ShareDeal
+	candidate *SharedSecret
+	refused   bool
+}
+
+// NewReshare initialises the structure for use in one round
+func NewReshare(n *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
+	o := &Reshare{
+		TreeNodeInstance: n,
+		Finished:         make(chan bool, 1),
+	}
+	err := o.RegisterHandlers(o.childStart, o.rootDeals, o.childDeals,
+		o.rootVerify, o.childCommit)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Start sends the new roster to all nodes
+func (o *Reshare) Start() error {
+	log.Lvl3("Starting Protocol")
+	if o.NewRoster == nil || len(o.NewRoster.List) == 0 {
+		return errors.New("please initialize NewRoster first")
+	}
+	if len(o.OldCommits) == 0 {
+		return errors.New("please initialize OldCommits first")
+	}
+	if o.NewThreshold < 1 || o.NewThreshold > len(o.NewRoster.List) {
+		return errors.New("threshold needs to be between 1 and the size of the new roster")
+	}
+	o.start = &StartReshare{
+		NewRoster:        o.NewRoster,
+		NewThreshold:     o.NewThreshold,
+		OldCommits:       o.OldCommits,
+		VerificationData: o.VerificationData,
+	}
+	if o.Verify != nil && !o.Verify(o.ServerIdentity(), o.start) {
+		return errors.New("refused to reshare")
+	}
+	deal, err := o.deal()
+	if err != nil {
+		return err
+	}
+	if deal != nil {
+		o.deals = append(o.deals, deal)
+	}
+	errs := o.Broadcast(o.start)
+	if len(errs) != 0 {
+		return fmt.Errorf("broadcast failed with error(s): %v", errs)
+	}
+	return nil
+}
+
+// Children reactions
+func (o *Reshare) childStart(sr structStartReshare) error {
+	o.start = &sr.StartReshare
+	if o.Verify != nil && !o.Verify(o.Root().ServerIdentity, o.start) {
+		log.Lvl2(o.ServerIdentity(), "refused to reshare")
+		o.refused = true
+		return o.SendToParent(&ReshareDeal{})
+	}
+	deal, err := o.deal()
+	if err != nil {
+		log.Error(o.ServerIdentity(), "couldn't create deal:", err)
+		deal = nil
+	}
+	return o.SendToParent(&ReshareDeal{Deal: deal})
+}
+
+func (o *Reshare) childDeals(sd structReshareDeals) error {
+	reply := &ReshareVerify{}
+	if o.refused {
+		return o.SendToParent(reply)
+	}
+	shared, err := o.newShare(sd.Deals)
+	if err != nil {
+		log.Error(o.ServerIdentity(), "couldn't verify deals:", err)
+	} else if shared != nil {
+		o.candidate = shared
+		reply.Public = cothority.Suite.Point().Mul(shared.V, nil)
+	}
+	return o.SendToParent(reply)
+}
+
+func (o *Reshare) childCommit(sc structReshareCommit) error {
+	defer o.Done()
+	o.commit(sc.Commit && !o.refused)
+	return nil
+}
+
+// Root-node messages
+func (o *Reshare) rootDeals(replies []structReshareDeal) error {
+	for _, r := range replies {
+		if r.Deal != nil {
+			o.deals = append(o.deals, r.Deal)
+		}
+	}
+	var err error
+	o.selected, err = o.selectDeals(o.deals)
+	if err != nil {
+		log.Error(o.ServerIdentity(), err)
+		return o.finish(false)
+	}
+	o.candidate, err = o.newShare(o.selected)
+	if err != nil {
+		log.Error(o.ServerIdentity(), err)
+		return o.finish(false)
+	}
+	errs := o.Broadcast(&ReshareDeals{Deals: o.selected})
+	if len(errs) != 0 {
+		log.Errorf("broadcast failed with error(s): %v", errs)
+		return o.finish(false)
+	}
+	return nil
+}
+
+func (o *Reshare) rootVerify(replies []structReshareVerify) error {
+	commits, err := combineCommits(o.selected, o.start.NewThreshold)
+	if err != nil {
+		log.Error(o.ServerIdentity(), err)
+		return o.finish(false)
+	}
+	poly := share.NewPubPoly(cothority.Suite, nil, commits)
+	verified := make(map[int]bool)
+	if o.candidate != nil {
+		verified[o.candidate.Index] = true
+	}
+	for _, r := range replies {
+		index, _ := o.start.NewRoster.Search(r.ServerIdentity.ID)
+		if index < 0 {
+			continue
+		}
+		if r.Public == nil || !poly.Eval(index).V.Equal(r.Public) {
+			log.Lvl2("Node", r.ServerIdentity, "didn't get a valid share")
+			continue
+		}
+		verified[index] = true
+	}
+	if len(verified) != len(o.start.NewRoster.List) {
+		log.Errorf("only %d out of %d nodes got a valid share",
+			len(verified), len(o.start.NewRoster.List))
+		return o.finish(false)
+	}
+	return o.finish(true)
+}
+
+// Convenience functions
+
+// deal creates the shares of this node for all nodes of the new roster. It
+// returns nil if this node has no share.
+func (o *Reshare) deal() (*ShareDeal, error) {
+	if o.Shared == nil {
+		return nil, nil
+	}
+	if !o.Shared.X.Equal(o.start.OldCommits[0]) {
+		return nil, errors.New("share doesn't belong to the shared secret")
+	}
+	suite := cothority.Suite
+	poly := share.NewPriPoly(suite, o.start.NewThreshold, o.Shared.V,
+		suite.RandomStream())
+	_, commits := poly.Commit(nil).Info()
+	sd := &ShareDeal{
+		Dealer:  o.Shared.Index,
+		Commits: commits,
+	}
+	for i, si := range o.start.NewRoster.List {
+		es, err := encryptShare(si.Public, poly.Eval(i).V)
+		if err != nil {
+			return nil, err
+		}
+		sd.Shares = append(sd.Shares, es)
+	}
+	return sd, nil
+}
+
+// verifyDeal makes sure the deal has the correct size and that its
+// polynomial hides the share of the dealer.
+func (o *Reshare) verifyDeal(sd *ShareDeal) error {
+	if sd.Dealer < 0 {
+		return errors.New("negative dealer index")
+	}
+	if len(sd.Commits) != o.start.NewThreshold {
+		return errors.New("wrong number of commits")
+	}
+	if len(sd.Shares) != len(o.start.NewRoster.List) {
+		return errors.New("wrong number of shares")
+	}
+	old := share.NewPubPoly(cothority.Suite, nil, o.start.OldCommits)
+	if !old.Eval(sd.Dealer).V.Equal(sd.Commits[0]) {
+		return errors.New("deal doesn't hide the share of the dealer")
+	}
+	return nil
+}
+
+// selectDeals returns the valid deals with the lowest dealer-indexes, as
+// many as needed to recover the old shared secret.
+func (o *Reshare) selectDeals(deals []*ShareDeal) ([]*ShareDeal, error) {
+	dealers := make(map[int]bool)
+	var valid []*ShareDeal
+	for _, sd := range deals {
+		if dealers[sd.Dealer] {
+			continue
+		}
+		if err := o.verifyDeal(sd); err != nil {
+			log.Lvl2("Invalid deal from dealer", sd.Dealer, err)
+			continue
+		}
+		dealers[sd.Dealer] = true
+		valid = append(valid, sd)
+	}
+	threshold := len(o.start.OldCommits)
+	if len(valid) < threshold {
+		return nil, fmt.Errorf("got %d valid deals, but need %d",
+			len(valid), threshold)
+	}
+	sort.Slice(valid, func(i, j int) bool {
+		return valid[i].Dealer < valid[j].Dealer
+	})
+	return valid[:threshold], nil
+}
+
+// newShare verifies all deals and interpolates the new share of this node.
+// It returns nil if this node is not part of the new roster.
+func (o *Reshare) newShare(deals []*ShareDeal) (*SharedSecret, error) {
+	index, _ := o.start.NewRoster.Search(o.ServerIdentity().ID)
+	if index < 0 {
+		return nil, nil
+	}
+	if len(deals) != len(o.start.OldCommits) {
+		return nil, errors.New("wrong number of deals")
+	}
+	var shares []*share.PriShare
+	for _, sd := range deals {
+		if err := o.verifyDeal(sd); err != nil {
+			return nil, err
+		}
+		v, err := decryptShare(o.Private(), sd.Shares[index])
+		if err != nil {
+			return nil, err
+		}
+		ps := &share.PriShare{I: index, V: v}
+		if !share.NewPubPoly(cothority.Suite, nil, sd.Commits).Check(ps) {
+			return nil, fmt.Errorf("share of dealer %d doesn't match its commits",
+				sd.Dealer)
+		}
+		shares = append(shares, &share.PriShare{I: sd.Dealer, V: v})
+	}
+	v, err := share.RecoverSecret(cothority.Suite, shares, len(deals),
+		maxDealer(deals)+1)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := combineCommits(deals, o.start.NewThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if !commits[0].Equal(o.start.OldCommits[0]) {
+		return nil, errors.New("new shares don't hide the same secret")
+	}
+	return &SharedSecret{
+		Index:   index,
+		V:       v,
+		X:       commits[0],
+		Commits: commits,
+	}, nil
+}
+
+// finish tells all nodes whether to use the new shares.
+func (o *Reshare) finish(commit bool) error {
+	defer o.Done()
+	errs := o.Broadcast(&ReshareCommit{Commit: commit})
+	o.commit(commit)
+	if len(errs) != 0 {
+		return fmt.Errorf("broadcast failed with error(s): %v", errs)
+	}
+	return nil
+}
+
+func (o *Reshare) commit(commit bool) {
+	if commit {
+		o.NewShared = o.candidate
+	}
+	o.Finished <- commit
+}
+
+// combineCommits interpolates the commits of the deals to get the
+// commits of the new shared secret.
+func combineCommits(deals []*ShareDeal, threshold int) ([]kyber.Point, error) {
+	var commits []kyber.Point
+	for k := 0; k < threshold; k++ {
+		var pubs []*share.PubShare
+		for _, sd := range deals {
+			pubs = append(pubs, &share.PubShare{I: sd.Dealer, V: sd.Commits[k]})
+		}
+		c, err := share.RecoverCommit(cothority.Suite, pubs, len(deals),
+			maxDealer(deals)+1)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+func maxDealer(deals []*ShareDeal) int {
+	max := 0
+	for _, sd := range deals {
+		if sd.Dealer > max {
+			max = sd.Dealer
+		}
+	}
+	return max
+}
+
+// encryptShare encrypts the share using an ephemeral Diffie-Hellman key with
+// the public key of the receiver.
+func encryptShare(pub kyber.Point, v kyber.Scalar) (*EncryptedShare, error) {
+	r := cothority.Suite.Scalar().Pick(cothority.Suite.RandomStream())
+	U := cothority.Suite.Point().Mul(r, nil)
+	aead, err := shareCipher(cothority.Suite.Point().Mul(r, pub), U)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := v.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedShare{
+		U:      U,
+		Cipher: aead.Seal(nil, make([]byte, aead.NonceSize()), buf, nil),
+	}, nil
+}
+
+// decryptShare decrypts a share encrypted with encryptShare.
+func decryptShare(priv kyber.Scalar, es *EncryptedShare) (kyber.Scalar, error) {
+	if es == nil || es.U == nil {
+		return nil, errors.New("missing share")
+	}
+	aead, err := shareCipher(cothority.Suite.Point().Mul(priv, es.U), es.U)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := aead.Open(nil, make([]byte, aead.NonceSize()), es.Cipher, nil)
+	if err != nil {
+		return nil, err
+	}
+	v := cothority.Suite.Scalar()
+	if err := v.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// shareCipher returns the symmetric cipher for the shared Diffie-Hellman key.
+// As every key is only used once, a zero nonce is safe.
+func shareCipher(dh, U kyber.Point) (cipher.AEAD, error) {
+	h := sha256.New()
+	if _, err := dh.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := U.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
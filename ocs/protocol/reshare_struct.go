@@ -0,0 +1,108 @@
+package protocol
+
+/*
+reshare_struct holds all messages for the resharing protocol.
+*/
+
+import (
+	"github.com/dedis/kyber"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/network"
+)
+
+// NameReshare can be used from other packages to refer to this protocol.
+const NameReshare = "ReshareDKG"
+
+// VerifyReshare is a callback-function that can be set by a service.
+// Whenever a resharing request is received, this function will be called
+// with the root of the protocol, and its return-value used to determine
+// whether or not to deal the share and to store the new share.
+type VerifyReshare func(root *network.ServerIdentity, sr *StartReshare) bool
+
+func init() {
+	network.RegisterMessages(&StartReshare{}, &ReshareDeal{},
+		&ReshareDeals{}, &ReshareVerify{}, &ReshareCommit{})
+}
+
+// StartReshare is sent by the root to all nodes. It holds the new roster and
+// threshold, and the commitments of the current shared secret, so that
+// joining nodes can verify the deals.
+type StartReshare struct {
+	NewRoster    *onet.Roster
+	NewThreshold int
+	OldCommits   []kyber.Point
+	// VerificationData is optional and can be any slice of bytes, so that
+	// each node can verify if the resharing request is valid or not.
+	VerificationData []byte
+}
+
+type structStartReshare struct {
+	*onet.TreeNode
+	StartReshare
+}
+
+// ShareDeal holds the shares of one node of the old roster for all nodes of
+// the new roster. The dealer picks a random polynomial whose constant term is
+// its old share.
+type ShareDeal struct {
+	// Dealer is the index of the share of the dealer in the old roster.
+	Dealer int
+	// Commits are the commitments of the polynomial of the dealer.
+	Commits []kyber.Point
+	// Shares are the encrypted evaluations of the polynomial, one for
+	// every node of the new roster.
+	Shares []*EncryptedShare
+}
+
+// EncryptedShare is a share encrypted for a node of the new roster using its
+// public key.
+type EncryptedShare struct {
+	U      kyber.Point
+	Cipher []byte
+}
+
+// ReshareDeal is sent to the root by every node of the old roster. Nodes
+// without a share reply with an empty deal.
+type ReshareDeal struct {
+	Deal *ShareDeal
+}
+
+type structReshareDeal struct {
+	*onet.TreeNode
+	ReshareDeal
+}
+
+// ReshareDeals is sent by the root to all nodes and holds the deals that
+// are used to create the new shares.
+type ReshareDeals struct {
+	Deals []*ShareDeal
+}
+
+type structReshareDeals struct {
+	*onet.TreeNode
+	ReshareDeals
+}
+
+// ReshareVerify is sent back to the root once a node of the new roster
+// verified all deals and calculated its new share. Public is the public
+// version of the new share, or nil if the node is not part of the new
+// roster or if the verification failed.
+type ReshareVerify struct {
+	Public kyber.Point
+}
+
+type structReshareVerify struct {
+	*onet.TreeNode
+	ReshareVerify
+}
+
+// ReshareCommit tells all nodes whether to replace their shares with the new
+// ones or to keep the old ones.
+type ReshareCommit struct {
+	Commit bool
+}
+
+type structReshareCommit struct {
+	*onet.TreeNode
+	ReshareCommit
+}
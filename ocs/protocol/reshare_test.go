@@ -0,0 +1,158 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/kyber/share"
+	dkg "github.com/dedis/kyber/share/dkg/rabin"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
+	"github.com/stretchr/testify/require"
+)
+
+// Moves a 3-out-of-4 shared secret to a 3-out-of-5 roster, removing one
+// node and adding two new ones.
+func TestReshare(t *testing.T) {
+	local := onet.NewLocalTest(tSuite)
+	defer local.CloseAll()
+	servers, roster, tree := local.GenBigTree(6, 6, 6, true)
+	services := local.GetServices(servers, testServiceID)
+
+	dkgs, err := CreateDKGs(tSuite.(dkg.Suite), 4, 3)
+	require.Nil(t, err)
+	var oldShares []*share.PriShare
+	for i := range dkgs {
+		shared, err := NewSharedSecret(dkgs[i])
+		require.Nil(t, err)
+		services[i].(*testService).Shared = shared
+		oldShares = append(oldShares, &share.PriShare{I: shared.Index, V: shared.V})
+	}
+	dks, err := dkgs[0].DistKeyShare()
+	require.Nil(t, err)
+	secret, err := share.RecoverSecret(cothority.Suite, oldShares, 3, 4)
+	require.Nil(t, err)
+
+	newRoster := onet.NewRoster([]*network.ServerIdentity{roster.List[0],
+		roster.List[2], roster.List[3], roster.List[4], roster.List[5]})
+	protocol, err := services[0].(*testService).createReshare(tree)
+	require.Nil(t, err)
+	protocol.NewRoster = newRoster
+	protocol.NewThreshold = 3
+	protocol.OldCommits = dks.Commits
+	protocol.VerificationData = []byte("correct request")
+	require.Nil(t, protocol.Start())
+	select {
+	case ok := <-protocol.Finished:
+		require.True(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Didn't finish in time")
+	}
+
+	newShares := []*share.PriShare{{I: protocol.NewShared.Index, V: protocol.NewShared.V}}
+	require.Equal(t, 0, protocol.NewShared.Index)
+	for _, s := range services[1:] {
+		ts := s.(*testService)
+		select {
+		case shared := <-ts.reshared:
+			if ts.ServerIdentity().Equal(roster.List[1]) {
+				require.Nil(t, shared)
+				continue
+			}
+			require.NotNil(t, shared)
+			require.True(t, dks.Public().Equal(shared.X))
+			require.Equal(t, 3, len(shared.Commits))
+			newShares = append(newShares, &share.PriShare{I: shared.Index, V: shared.V})
+		case <-time.After(5 * time.Second):
+			t.Fatal("Didn't get new share in time")
+		}
+	}
+	require.Equal(t, 5, len(newShares))
+
+	// Any 3 of the new shares recover the old secret.
+	newSecret, err := share.RecoverSecret(cothority.Suite, newShares[2:], 3, 5)
+	require.Nil(t, err)
+	require.True(t, secret.Equal(newSecret))
+	newSecret, err = share.RecoverSecret(cothority.Suite, newShares[:3], 3, 5)
+	require.Nil(t, err)
+	require.True(t, secret.Equal(newSecret))
+}
+
+// Without enough old shares, the resharing fails and no new share is
+// created.
+func TestReshareFail(t *testing.T) {
+	local := onet.NewLocalTest(tSuite)
+	defer local.CloseAll()
+	servers, roster, tree := local.GenBigTree(4, 4, 4, true)
+	services := local.GetServices(servers, testServiceID)
+
+	dkgs, err := CreateDKGs(tSuite.(dkg.Suite), 4, 3)
+	require.Nil(t, err)
+	for i := range dkgs[:2] {
+		services[i].(*testService).Shared, err = NewSharedSecret(dkgs[i])
+		require.Nil(t, err)
+	}
+	dks, err := dkgs[0].DistKeyShare()
+	require.Nil(t, err)
+
+	protocol, err := services[0].(*testService).createReshare(tree)
+	require.Nil(t, err)
+	protocol.NewRoster = roster
+	protocol.NewThreshold = 5
+	protocol.OldCommits = dks.Commits
+	require.NotNil(t, protocol.Start())
+
+	protocol.NewThreshold = 3
+	protocol.VerificationData = []byte("correct request")
+	require.Nil(t, protocol.Start())
+	select {
+	case ok := <-protocol.Finished:
+		require.False(t, ok)
+		require.Nil(t, protocol.NewShared)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Didn't finish in time")
+	}
+	log.Lvl2("Resharing correctly refused")
+}
+
+// Nodes that refuse the resharing request don't deal their shares, so the
+// resharing fails and nobody stores a new share.
+func TestReshareRefuse(t *testing.T) {
+	local := onet.NewLocalTest(tSuite)
+	defer local.CloseAll()
+	servers, roster, tree := local.GenBigTree(4, 4, 4, true)
+	services := local.GetServices(servers, testServiceID)
+
+	dkgs, err := CreateDKGs(tSuite.(dkg.Suite), 4, 3)
+	require.Nil(t, err)
+	for i := range dkgs {
+		services[i].(*testService).Shared, err = NewSharedSecret(dkgs[i])
+		require.Nil(t, err)
+	}
+	dks, err := dkgs[0].DistKeyShare()
+	require.Nil(t, err)
+
+	protocol, err := services[0].(*testService).createReshare(tree)
+	require.Nil(t, err)
+	protocol.NewRoster = roster
+	protocol.NewThreshold = 2
+	protocol.OldCommits = dks.Commits
+	require.Nil(t, protocol.Start())
+	select {
+	case ok := <-protocol.Finished:
+		require.False(t, ok)
+		require.Nil(t, protocol.NewShared)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Didn't finish in time")
+	}
+	for _, s := range services[1:] {
+		select {
+		case <-s.(*testService).reshared:
+			t.Fatal("Node stored a share of a refused resharing")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	log.Lvl2("Resharing correctly refused")
+}
@@ -9,7 +9,9 @@ This part of the service runs on the client or the app.
 */
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/ocs/darc"
@@ -103,6 +105,15 @@ func (c *Client) EditAccount(ocs *SkipChainURL, d *darc.Darc) (sb *skipchain.Ski
 func (c *Client) WriteRequest(ocs *SkipChainURL, encData []byte, symKey []byte,
 	sig *darc.Signature, acl *darc.Darc) (sb *skipchain.SkipBlock,
 	err error) {
+	return c.WriteRequestPolicy(ocs, encData, symKey, sig, acl, nil)
+}
+
+// WriteRequestPolicy works like WriteRequest, but restricts the read-requests
+// of the document with the given access policy. The signature must be on
+// WriteMessage(acl, policy).
+func (c *Client) WriteRequestPolicy(ocs *SkipChainURL, encData []byte, symKey []byte,
+	sig *darc.Signature, acl *darc.Darc, policy *AccessPolicy) (sb *skipchain.SkipBlock,
+	err error) {
 	if len(encData) > 1e7 {
 		return nil, errors.New("Cannot store data bigger than 10MB")
 	}
@@ -116,6 +127,42 @@ func (c *Client) WriteRequest(ocs *SkipChainURL, encData []byte, symKey []byte,
 
 	write := NewWrite(cothority.Suite, ocs.Genesis, shared.X, acl, symKey)
 	write.Data = encData
+	write.Policy = policy
+	wr := &WriteRequest{
+		Write:     *write,
+		Readers:   acl,
+		OCS:       ocs.Genesis,
+		Signature: *sig,
+	}
+	reply := &WriteReply{}
+	err = c.SendProtobuf(ocs.Roster.List[0], wr, reply)
+	sb = reply.SB
+	return
+}
+
+// WriteRequestChunks works like WriteRequestPolicy, but stores the encrypted
+// data off-chain in chunks on all nodes, so that it can be bigger than 10MB.
+// Once the write is stored, the chunks are sent one by one. If supersedes is
+// non-nil, the new write replaces this older write of the same writer and
+// the chunks of the older write are removed. The signature must be on
+// ChunksMessage(acl, policy, supersedes, hashes), with the hashes returned
+// by SplitChunks(encData).
+func (c *Client) WriteRequestChunks(ocs *SkipChainURL, encData []byte, symKey []byte,
+	sig *darc.Signature, acl *darc.Darc, policy *AccessPolicy,
+	supersedes skipchain.SkipBlockID) (sb *skipchain.SkipBlock, err error) {
+	requestShared := &SharedPublicRequest{Genesis: ocs.Genesis}
+	shared := &SharedPublicReply{}
+	err = c.SendProtobuf(ocs.Roster.List[0], requestShared, shared)
+	if err != nil {
+		return
+	}
+
+	write := NewWrite(cothority.Suite, ocs.Genesis, shared.X, acl, symKey)
+	write.Data = []byte{}
+	write.Policy = policy
+	write.Supersedes = supersedes
+	chunks, hashes := SplitChunks(encData)
+	write.Chunks = hashes
 	wr := &WriteRequest{
 		Write:     *write,
 		Readers:   acl,
@@ -124,7 +171,16 @@ func (c *Client) WriteRequest(ocs *SkipChainURL, encData []byte, symKey []byte,
 	}
 	reply := &WriteReply{}
 	err = c.SendProtobuf(ocs.Roster.List[0], wr, reply)
+	if err != nil {
+		return
+	}
 	sb = reply.SB
+	for i, chunk := range chunks {
+		req := &StoreDataChunk{DataID: sb.Hash, Chunk: chunk}
+		if err = c.SendProtobuf(ocs.Roster.List[0], req, &StoreDataChunkReply{}); err != nil {
+			return nil, fmt.Errorf("couldn't store chunk %d: %s", i, err)
+		}
+	}
 	return
 }
 
@@ -272,7 +328,109 @@ func (c *Client) GetData(ocs *SkipChainURL, dataID skipchain.SkipBlockID) (encDa
 	if ocsData == nil || ocsData.Write == nil {
 		return nil, errors.New("not correct type of data")
 	}
-	return ocsData.Write.Data, nil
+	if len(ocsData.Write.Chunks) == 0 {
+		return ocsData.Write.Data, nil
+	}
+	for i, hash := range ocsData.Write.Chunks {
+		chunk, err := c.GetDataChunk(ocs, dataID, i)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(chunkHash(chunk), hash) {
+			return nil, fmt.Errorf("chunk %d doesn't match its hash", i)
+		}
+		encData = append(encData, chunk...)
+	}
+	return encData, nil
+}
+
+// GetDataChunk returns one chunk of the off-chain data of a write. The
+// caller needs to verify the chunk against the hash stored in the write.
+//
+// Input:
+//  - ocs [*SkipChainURL] - the url of the skipchain to use
+//  - dataID [skipchain.SkipBlockID] - the hash of the skipblock of the write
+//  - index [int] - the index of the chunk
+//
+// Output:
+//  - chunk [[]byte] - the chunk of encrypted data
+//  - err - an error if something went wrong, or nil
+func (c *Client) GetDataChunk(ocs *SkipChainURL, dataID skipchain.SkipBlockID, index int) ([]byte, error) {
+	request := &GetDataChunk{DataID: dataID, Index: index}
+	reply := &GetDataChunkReply{}
+	if err := c.SendProtobuf(ocs.Roster.List[0], request, reply); err != nil {
+		return nil, err
+	}
+	return reply.Chunk, nil
+}
+
+// DisclosePublicly discloses a document to the public. The signature must
+// be on DiscloseMessage(dataID) and fulfill the ocs:disclose rule of the
+// reader-darc of the document. The returned block holds the verified
+// decryption shares of the symmetric key, which can be recovered by anyone
+// with GetDisclosedKey.
+//
+// Input:
+//  - ocs [*SkipChainURL] - the url of the skipchain to use
+//  - dataID [skipchain.SkipBlockID] - the hash of the write-request
+//  - sig [*darc.Signature] - the signature approving the disclosure
+//
+// Output:
+//  - sb [*skipchain.SkipBlock] - the block holding the disclosed key
+//  - err - an error if something went wrong, or nil
+func (c *Client) DisclosePublicly(ocs *SkipChainURL, dataID skipchain.SkipBlockID,
+	sig *darc.Signature) (*skipchain.SkipBlock, error) {
+	request := &DisclosePublicly{
+		OCS:        ocs.Genesis,
+		Disclosure: Disclosure{DataID: dataID, Signature: *sig},
+	}
+	reply := &DisclosePubliclyReply{}
+	if err := c.SendProtobuf(ocs.Roster.List[0], request, reply); err != nil {
+		return nil, err
+	}
+	return reply.SB, nil
+}
+
+// GetDisclosedKey returns the symmetric key of a disclosed document. It
+// fetches the blocks of the disclosed key, the disclosure and the write, and
+// recovers the key from the verified decryption shares.
+//
+// Input:
+//  - ocs [*SkipChainURL] - the url of the skipchain to use
+//  - keyID [skipchain.SkipBlockID] - the hash of the block holding the
+//    disclosed key
+//
+// Output:
+//  - sym [[]byte] - the symmetric key of the document
+//  - err - an error if something went wrong, or nil
+func (c *Client) GetDisclosedKey(ocs *SkipChainURL, keyID skipchain.SkipBlockID) (sym []byte,
+	err error) {
+	cl := skipchain.NewClient()
+	sb, err := cl.GetSingleBlock(ocs.Roster, keyID)
+	if err != nil {
+		return nil, err
+	}
+	keyOCS := NewOCS(sb.Data)
+	if keyOCS == nil || keyOCS.DisclosedKey == nil {
+		return nil, errors.New("not a block with a disclosed key")
+	}
+	sb, err = cl.GetSingleBlock(ocs.Roster, keyOCS.DisclosedKey.DisclosureID)
+	if err != nil {
+		return nil, err
+	}
+	disclosureOCS := NewOCS(sb.Data)
+	if disclosureOCS == nil || disclosureOCS.Disclosure == nil {
+		return nil, errors.New("not a disclosure block")
+	}
+	sb, err = cl.GetSingleBlock(ocs.Roster, disclosureOCS.Disclosure.DataID)
+	if err != nil {
+		return nil, err
+	}
+	writeOCS := NewOCS(sb.Data)
+	if writeOCS == nil || writeOCS.Write == nil {
+		return nil, errors.New("not a write block")
+	}
+	return RecoverDisclosedKey(writeOCS.Write, keyOCS.DisclosedKey)
 }
 
 // GetReadRequests searches the skipchain starting at 'start' for requests and returns all found
@@ -310,3 +468,148 @@ func (c *Client) GetLatestDarc(ocs *SkipChainURL, darcID darc.ID) (path *[]*darc
 	}
 	return reply.Darcs, nil
 }
+
+// Reshare moves the shared secret of the OCS-skipchain to a new roster and
+// threshold, without changing the shared public key. The roster of the
+// skipchain is changed accordingly. The leader of the skipchain cannot be
+// removed.
+//
+// Input:
+//  - ocs [*SkipChainURL] - the url of the skipchain to use
+//  - roster [*onet.Roster] - the new roster
+//  - threshold [int] - how many nodes are needed to decrypt, 0 for the default
+//  - admin [*darc.Darc] - the admin-darc of the skipchain
+//  - owner [*darc.Signer] - an owner of the admin-darc
+//
+// Output:
+//  - newOCS [*SkipChainURL] - the url of the skipchain with the new roster
+//  - err - an error if something went wrong, or nil
+func (c *Client) Reshare(ocs *SkipChainURL, roster *onet.Roster, threshold int,
+	admin *darc.Darc, owner *darc.Signer) (newOCS *SkipChainURL, err error) {
+	request := &ReshareRequest{
+		OCS:       ocs.Genesis,
+		Roster:    *roster,
+		Threshold: threshold,
+	}
+	path := darc.NewSignaturePath([]*darc.Darc{admin}, *owner.Identity(), darc.Owner)
+	sig, err := darc.NewDarcSignature(request.Message(), path, owner)
+	if err != nil {
+		return
+	}
+	request.Signature = *sig
+	reply := &ReshareReply{}
+	err = c.SendProtobuf(ocs.Roster.List[0], request, reply)
+	if err != nil {
+		return
+	}
+	return &SkipChainURL{Roster: reply.OCS.Roster, Genesis: ocs.Genesis}, nil
+}
+
+// RevokeIdentity removes the identity from all darcs stored in the service.
+// Every darc holding the identity is evolved to a new version without the
+// identity, signed by all the signers that are found in the evolve rule of
+// the darc. Together they need to fulfill the rule. Rules without any
+// identity left deny their action, and identities in and- or
+// threshold-rules cannot be revoked, so that revocation never grants access.
+//
+// Input:
+//  - ocs [*SkipChainURL] - the url of the skipchain to use
+//  - id [*darc.Identity] - the identity to revoke
+//  - signers [[]*darc.Signer] - the remaining owners of the darcs
+//
+// Output:
+//  - sb [*skipchain.SkipBlock] - the block holding the revocation
+//  - err - an error if something went wrong, or nil
+func (c *Client) RevokeIdentity(ocs *SkipChainURL, id *darc.Identity,
+	signers []*darc.Signer) (sb *skipchain.SkipBlock, err error) {
+	reply := &GetIdentityDarcsReply{}
+	err = c.SendProtobuf(ocs.Roster.List[0], &GetIdentityDarcs{Identity: *id}, reply)
+	if err != nil {
+		return
+	}
+	request := &RevokeIdentity{
+		OCS:      ocs.Genesis,
+		Identity: *id,
+	}
+	for _, d := range reply.Darcs {
+		expr := d.Expression(darc.ActionEvolve)
+		var owners []*darc.Signer
+		for _, s := range signers {
+			if !s.Identity().Equal(id) && expr.Contains(s.Identity()) {
+				owners = append(owners, s)
+			}
+		}
+		signed := func(i *darc.Identity) bool {
+			for _, o := range owners {
+				if o.Identity().Equal(i) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(owners) == 0 || !expr.Eval(signed) {
+			return nil, fmt.Errorf("not enough owners to evolve darc %x", d.GetBaseID())
+		}
+		newDarc := d.Copy()
+		if err = newDarc.RemoveIdentity(id); err != nil {
+			return
+		}
+		if err = newDarc.SetEvolution(d, nil, owners[0]); err != nil {
+			return
+		}
+		for _, o := range owners[1:] {
+			path := darc.NewSignaturePath([]*darc.Darc{d}, *o.Identity(), darc.Owner)
+			var sig *darc.Signature
+			sig, err = darc.NewDarcSignature(newDarc.GetID(), path, o)
+			if err != nil {
+				return
+			}
+			newDarc.Signature.AddSignature(sig)
+		}
+		request.Darcs = append(request.Darcs, newDarc)
+	}
+	revReply := &RevokeIdentityReply{}
+	err = c.SendProtobuf(ocs.Roster.List[0], request, revReply)
+	if err != nil {
+		return
+	}
+	return revReply.SB, nil
+}
+
+// GetRevocations returns up to count revocations of the skipchain, starting
+// at the block start. If count is 0, all revocations are returned.
+func (c *Client) GetRevocations(ocs *SkipChainURL, start skipchain.SkipBlockID, count int) ([]*RevocationDoc, error) {
+	request := &GetRevocations{Start: start, Count: count}
+	reply := &GetRevocationsReply{}
+	err := c.SendProtobuf(ocs.Roster.List[0], request, reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Revocations, nil
+}
+
+// ListWrites returns up to count writes of the skipchain that match the
+// filter, starting after cursor. If there are more writes, the returned
+// cursor is non-nil and can be used to get the next writes.
+func (c *Client) ListWrites(ocs *SkipChainURL, filter *QueryFilter, cursor skipchain.SkipBlockID,
+	count int) ([]*WriteDoc, skipchain.SkipBlockID, error) {
+	request := &ListWrites{OCS: ocs.Genesis, Filter: *filter, Cursor: cursor, Count: count}
+	reply := &ListWritesReply{}
+	if err := c.SendProtobuf(ocs.Roster.List[0], request, reply); err != nil {
+		return nil, nil, err
+	}
+	return reply.Writes, reply.Cursor, nil
+}
+
+// ListReads returns up to count reads of the skipchain that match the
+// filter, starting after cursor. If there are more reads, the returned
+// cursor is non-nil and can be used to get the next reads.
+func (c *Client) ListReads(ocs *SkipChainURL, filter *QueryFilter, cursor skipchain.SkipBlockID,
+	count int) ([]*ReadDoc, skipchain.SkipBlockID, error) {
+	request := &ListReads{OCS: ocs.Genesis, Filter: *filter, Cursor: cursor, Count: count}
+	reply := &ListReadsReply{}
+	if err := c.SendProtobuf(ocs.Roster.List[0], request, reply); err != nil {
+		return nil, nil, err
+	}
+	return reply.Reads, reply.Cursor, nil
+}
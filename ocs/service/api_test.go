@@ -50,3 +50,34 @@ func TestUpdateDarc(t *testing.T) {
 	require.NotNil(t, path)
 	require.Equal(t, 3, len(*path))
 }
+
+func TestRevokeIdentity(t *testing.T) {
+	a := darc.NewSignerEd25519(nil, nil)
+	b := darc.NewSignerEd25519(nil, nil)
+	bad := darc.NewSignerEd25519(nil, nil)
+	owners := []*darc.Identity{a.Identity(), b.Identity(), bad.Identity()}
+	darc0 := darc.NewDarc(&owners, nil, []byte("revoke"))
+	require.Nil(t, darc0.SetRule(darc.ActionEvolve, darc.NewExprThreshold(2,
+		darc.NewExprIdentities(owners).Exprs...)))
+
+	local := onet.NewTCPTest(tSuite)
+	_, roster, _ := local.GenTree(3, true)
+	defer local.CloseAll()
+	cl := NewClient()
+	ocs, err := cl.CreateSkipchain(roster, &darc.Darc{})
+	require.Nil(t, err)
+	_, err = cl.EditAccount(ocs, darc0)
+	require.Nil(t, err)
+
+	// One remaining owner doesn't fulfill the evolve rule.
+	_, err = cl.RevokeIdentity(ocs, bad.Identity(), []*darc.Signer{a, bad})
+	require.NotNil(t, err)
+	sb, err := cl.RevokeIdentity(ocs, bad.Identity(), []*darc.Signer{a, b})
+	require.Nil(t, err)
+	require.NotNil(t, sb)
+
+	path, err := cl.GetLatestDarc(ocs, darc0.GetID())
+	require.Nil(t, err)
+	require.Equal(t, 2, len(*path))
+	require.False(t, (*path)[1].HasIdentity(bad.Identity()))
+}
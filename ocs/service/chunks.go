@@ -0,0 +1,249 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/dedis/cothority/skipchain"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
+)
+
+/*
+This file holds the off-chain storage of large documents. Instead of storing
+the encrypted data in the Write, the Write only holds the sha256-hashes of
+chunks of at most ChunkSize bytes. Once the Write is stored, the client
+sends the chunks one by one with StoreDataChunk, and every trustee stores
+the chunks of the known writes in a content-addressed bucket, using their
+hash as key. So no chunk is stored without a write using it. The chunks are
+returned one by one with GetDataChunk. Once a write is superseded by a
+newer write, its chunks are removed, unless another write still uses them.
+*/
+
+// ChunkSize is the maximum size of a chunk of off-chain data.
+const ChunkSize = 1 << 20
+
+// SplitChunks splits the data in chunks of ChunkSize and returns the chunks
+// together with their hashes, as needed in Write.Chunks.
+func SplitChunks(data []byte) (chunks, hashes [][]byte) {
+	for len(data) > 0 {
+		chunk := data[:min(len(data), ChunkSize)]
+		chunks = append(chunks, chunk)
+		hashes = append(hashes, chunkHash(chunk))
+		data = data[len(chunk):]
+	}
+	return
+}
+
+func chunkHash(chunk []byte) []byte {
+	h := sha256.Sum256(chunk)
+	return h[:]
+}
+
+// GetDataChunk returns one chunk of the off-chain data of a write.
+func (s *Service) GetDataChunk(req *GetDataChunk) (*GetDataChunkReply, error) {
+	sb := s.db().GetByID(req.DataID)
+	if sb == nil {
+		return nil, errors.New("didn't find write-block")
+	}
+	dataOCS := NewOCS(sb.Data)
+	if dataOCS == nil || dataOCS.Write == nil {
+		return nil, errors.New("block is not a write-block")
+	}
+	if req.Index < 0 || req.Index >= len(dataOCS.Write.Chunks) {
+		return nil, fmt.Errorf("write has no chunk %d", req.Index)
+	}
+	if s.isSuperseded(sb) {
+		return nil, errors.New("write has been superseded")
+	}
+	chunk, err := s.loadChunk(dataOCS.Write.Chunks[req.Index])
+	if err != nil {
+		return nil, err
+	}
+	return &GetDataChunkReply{Chunk: chunk}, nil
+}
+
+// StoreDataChunk verifies that the chunk belongs to a write stored on the
+// skipchain and sends it to all nodes of the roster.
+func (s *Service) StoreDataChunk(req *StoreDataChunk) (*StoreDataChunkReply, error) {
+	sb, err := s.checkChunk(req.DataID, req.Chunk)
+	if err != nil {
+		return nil, err
+	}
+	latestSB, err := s.db().GetLatest(sb)
+	if err != nil {
+		return nil, errors.New("didn't find latest block: " + err.Error())
+	}
+	roster := latestSB.Roster
+	replies, err := s.propagateChunk(roster, &DataChunk{DataID: req.DataID, Data: req.Chunk},
+		propagationTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if replies != len(roster.List) {
+		log.Warn("Got only", replies, "replies for chunk-propagation")
+	}
+	return &StoreDataChunkReply{}, nil
+}
+
+// checkChunk makes sure that the chunk is one of the chunks of the write
+// stored in the block dataID, and that the write is not superseded. It
+// returns the block of the write.
+func (s *Service) checkChunk(dataID skipchain.SkipBlockID, chunk []byte) (*skipchain.SkipBlock, error) {
+	if len(chunk) > ChunkSize {
+		return nil, fmt.Errorf("chunk is bigger than %d bytes", ChunkSize)
+	}
+	sb := s.db().GetByID(dataID)
+	if sb == nil {
+		return nil, errors.New("didn't find write-block")
+	}
+	dataOCS := NewOCS(sb.Data)
+	if dataOCS == nil || dataOCS.Write == nil {
+		return nil, errors.New("block is not a write-block")
+	}
+	if s.isSuperseded(sb) {
+		return nil, errors.New("write has been superseded")
+	}
+	hash := chunkHash(chunk)
+	for _, h := range dataOCS.Write.Chunks {
+		if bytes.Equal(h, hash) {
+			return sb, nil
+		}
+	}
+	return nil, errors.New("chunk doesn't belong to this write")
+}
+
+// verifyChunks makes sure that the data of a write is either on-chain or
+// off-chain, and that it only supersedes an older write of the same writer
+// on the same skipchain.
+func (s *Service) verifyChunks(ocs skipchain.SkipBlockID, write *Write) error {
+	if len(write.Chunks) > 0 && len(write.Data) > 0 {
+		return errors.New("data must be empty if chunks are given")
+	}
+	for i, h := range write.Chunks {
+		if len(h) != sha256.Size {
+			return fmt.Errorf("hash of chunk %d has wrong length", i)
+		}
+	}
+	if write.Supersedes == nil {
+		return nil
+	}
+	oldSB := s.db().GetByID(write.Supersedes)
+	if oldSB == nil || !oldSB.SkipChainID().Equal(ocs) {
+		return errors.New("superseded write is not in this skipchain")
+	}
+	oldOCS := NewOCS(oldSB.Data)
+	if oldOCS == nil || oldOCS.Write == nil {
+		return errors.New("superseded block is not a write-block")
+	}
+	if oldOCS.Write.Signature == nil || write.Signature == nil ||
+		!oldOCS.Write.Signature.SignaturePath.Signer.Equal(&write.Signature.SignaturePath.Signer) {
+		return errors.New("only the writer can supersede a write")
+	}
+	return nil
+}
+
+// propagateChunkFunc stores a chunk received from the leader if it belongs
+// to a known write.
+func (s *Service) propagateChunkFunc(msg network.Message) {
+	chunk, ok := msg.(*DataChunk)
+	if !ok {
+		log.Error("got something else than a chunk")
+		return
+	}
+	if _, err := s.checkChunk(chunk.DataID, chunk.Data); err != nil {
+		log.Error("refusing chunk:", err)
+		return
+	}
+	if err := s.storeChunk(chunk.Data); err != nil {
+		log.Error("couldn't store chunk:", err)
+	}
+}
+
+// storeChunk stores a chunk under its hash.
+func (s *Service) storeChunk(chunk []byte) error {
+	return s.chunks.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.chunkBucket).Put(chunkHash(chunk), chunk)
+	})
+}
+
+// loadChunk returns the chunk with the given hash.
+func (s *Service) loadChunk(hash []byte) ([]byte, error) {
+	var chunk []byte
+	err := s.chunks.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(s.chunkBucket).Get(hash)
+		if v == nil {
+			return errors.New("chunk not found")
+		}
+		chunk = append([]byte{}, v...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(chunkHash(chunk), hash) {
+		return nil, errors.New("stored chunk is corrupted")
+	}
+	return chunk, nil
+}
+
+// isSuperseded returns true if the write has been superseded by a newer
+// write.
+func (s *Service) isSuperseded(sb *skipchain.SkipBlock) bool {
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	idx := s.Storage.Indexes[string(sb.SkipChainID())]
+	if idx == nil {
+		return false
+	}
+	for _, w := range idx.Writes {
+		if w.WriteID.Equal(sb.Hash) {
+			return w.SupersededBy != nil
+		}
+	}
+	return false
+}
+
+// collectChunks removes the chunks of a superseded write that are not used
+// by any other write anymore.
+func (s *Service) collectChunks(writeID skipchain.SkipBlockID) {
+	oldSB := s.db().GetByID(writeID)
+	if oldSB == nil {
+		return
+	}
+	oldOCS := NewOCS(oldSB.Data)
+	if oldOCS == nil || oldOCS.Write == nil || len(oldOCS.Write.Chunks) == 0 {
+		return
+	}
+	used := make(map[string]bool)
+	s.saveMutex.Lock()
+	for _, idx := range s.Storage.Indexes {
+		for _, w := range idx.Writes {
+			if w.SupersededBy != nil {
+				continue
+			}
+			for _, h := range w.Chunks {
+				used[string(h)] = true
+			}
+		}
+	}
+	s.saveMutex.Unlock()
+	err := s.chunks.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.chunkBucket)
+		for _, h := range oldOCS.Write.Chunks {
+			if used[string(h)] {
+				continue
+			}
+			if err := b.Delete(h); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("couldn't remove chunks:", err)
+	}
+}
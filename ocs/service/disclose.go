@@ -0,0 +1,311 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/ocs/darc"
+	"github.com/dedis/cothority/ocs/protocol"
+	"github.com/dedis/cothority/skipchain"
+	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/share"
+	"github.com/dedis/onet"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/protobuf"
+)
+
+/*
+This file holds the public disclosure of documents. Instead of re-encrypting
+the symmetric key for a reader, the nodes decrypt it once a Disclosure is
+stored on the OCS-skipchain. Every node gives its decryption share with a
+DLEQ-proof, and the verified shares are stored in a DisclosedKey block
+together with the commitments of the shared secret. So anyone can verify the
+shares and recover the key using only the blocks of the skipchain.
+*/
+
+// DisclosePublicly stores the disclosure of a document, runs the threshold
+// decryption of its symmetric key and stores the decryption shares. If the
+// document has already been disclosed, the stored disclosure is used, so that
+// a failed decryption doesn't leave a second disclosure on the skipchain, and
+// a document is decrypted only once.
+func (s *Service) DisclosePublicly(req *DisclosePublicly) (*DisclosePubliclyReply, error) {
+	s.process.Lock()
+	defer s.process.Unlock()
+	log.Lvlf2("Disclosing %x on skipchain %x", req.Disclosure.DataID, req.OCS)
+	dataOCS := &Transaction{
+		Disclosure: &req.Disclosure,
+		Timestamp:  time.Now().Unix(),
+	}
+	if err := s.verifyDisclosure(dataOCS.Disclosure, dataOCS.Timestamp); err != nil {
+		return nil, errors.New("verification of disclosure failed: " + err.Error())
+	}
+	writeSB := s.db().GetByID(req.Disclosure.DataID)
+	if !writeSB.SkipChainID().Equal(req.OCS) {
+		return nil, errors.New("document is not in this skipchain")
+	}
+	disclosureSB, keySB, err := s.findDisclosure(writeSB)
+	if err != nil {
+		return nil, err
+	}
+	if keySB != nil {
+		return &DisclosePubliclyReply{SB: keySB}, nil
+	}
+	if disclosureSB == nil {
+		disclosureSB, err = s.addTransaction(writeSB, dataOCS)
+		if err != nil {
+			return nil, err
+		}
+	}
+	key, err := s.discloseKey(writeSB, disclosureSB)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := s.addTransaction(disclosureSB, &Transaction{
+		DisclosedKey: key,
+		Timestamp:    time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DisclosePubliclyReply{SB: sb}, nil
+}
+
+// findDisclosure searches the blocks after the write for the first
+// disclosure of the document and for the block holding its decryption
+// shares. Both are nil if they are not stored yet.
+func (s *Service) findDisclosure(writeSB *skipchain.SkipBlock) (disclosureSB, keySB *skipchain.SkipBlock, err error) {
+	current := writeSB
+	for len(current.ForwardLink) > 0 {
+		current = s.db().GetByID(current.ForwardLink[0].To)
+		if current == nil {
+			return nil, nil, errors.New("didn't find block for this forward-link")
+		}
+		dataOCS := NewOCS(current.Data)
+		if dataOCS == nil {
+			return nil, nil, errors.New("unknown block in ocs-skipchain")
+		}
+		if d := dataOCS.Disclosure; d != nil && disclosureSB == nil &&
+			d.DataID.Equal(writeSB.Hash) {
+			disclosureSB = current
+		}
+		if k := dataOCS.DisclosedKey; k != nil && disclosureSB != nil &&
+			k.DisclosureID.Equal(disclosureSB.Hash) {
+			return disclosureSB, current, nil
+		}
+	}
+	return disclosureSB, nil, nil
+}
+
+// RecoverDisclosedKey verifies the decryption shares of a disclosed key and
+// returns the symmetric key of the write.
+func RecoverDisclosedKey(write *Write, key *DisclosedKey) ([]byte, error) {
+	if len(key.Commits) == 0 {
+		return nil, errors.New("no commitments in disclosed key")
+	}
+	poly := share.NewPubPoly(cothority.Suite, nil, key.Commits)
+	xU, err := protocol.RecoverDecryption(write.U, key.Shares, poly, len(key.Commits))
+	if err != nil {
+		return nil, err
+	}
+	var sym []byte
+	for _, C := range write.Cs {
+		keyPart, err := cothority.Suite.Point().Sub(C, xU).Data()
+		if err != nil {
+			return nil, err
+		}
+		sym = append(sym, keyPart...)
+	}
+	return sym, nil
+}
+
+// addTransaction stores the transaction in a new block after the latest
+// block of the skipchain of sb and propagates it.
+func (s *Service) addTransaction(sb *skipchain.SkipBlock, dataOCS *Transaction) (*skipchain.SkipBlock, error) {
+	latestSB, err := s.db().GetLatest(sb)
+	if err != nil {
+		return nil, errors.New("didn't find latest block: " + err.Error())
+	}
+	data, err := protobuf.Encode(dataOCS)
+	if err != nil {
+		return nil, err
+	}
+	latestSB, err = s.storeSkipBlock(latestSB, data)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := s.propagateOCS(latestSB.Roster, latestSB, propagationTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if replies != len(latestSB.Roster.List) {
+		log.Warn("Got only", replies, "replies for disclosure-propagation")
+	}
+	return latestSB, nil
+}
+
+// discloseKey runs the threshold decryption of the symmetric key of the
+// write and returns the verified decryption shares.
+func (s *Service) discloseKey(writeSB, disclosureSB *skipchain.SkipBlock) (*DisclosedKey, error) {
+	write := NewOCS(writeSB.Data).Write
+	latestSB, err := s.db().GetLatest(writeSB)
+	if err != nil {
+		return nil, errors.New("didn't find latest block: " + err.Error())
+	}
+	nodes := len(latestSB.Roster.List)
+	tree := latestSB.Roster.GenerateNaryTreeWithRoot(nodes, s.ServerIdentity())
+	if tree == nil {
+		return nil, errors.New("this node doesn't hold a share of the secret")
+	}
+	pi, err := s.CreateProtocol(protocol.NameDisclose, tree)
+	if err != nil {
+		return nil, err
+	}
+	disclose := pi.(*protocol.Disclose)
+	disclose.U = write.U
+	disclose.VerificationData = disclosureSB.Hash
+
+	// Make sure everything used from the s.Storage structure is copied, so
+	// there will be no races.
+	key := &DisclosedKey{DisclosureID: disclosureSB.Hash}
+	s.saveMutex.Lock()
+	disclose.Shared = s.Storage.Shared[string(writeSB.SkipChainID())]
+	pp := s.Storage.Polys[string(writeSB.SkipChainID())]
+	if disclose.Shared == nil || pp == nil {
+		s.saveMutex.Unlock()
+		return nil, errors.New("this node doesn't hold a share of the secret")
+	}
+	for _, c := range pp.Commits {
+		key.Commits = append(key.Commits, c.Clone())
+	}
+	disclose.Poly = share.NewPubPoly(s.Suite(), pp.B.Clone(), key.Commits)
+	s.saveMutex.Unlock()
+	disclose.Threshold = len(key.Commits)
+
+	disclose.SetConfig(&onet.GenericConfig{Data: writeSB.SkipChainID()})
+	if err := disclose.Start(); err != nil {
+		return nil, err
+	}
+	log.Lvl3("Waiting for end of disclose-protocol")
+	// An unresponsive node must not block the service, which is locked
+	// during the disclosure.
+	select {
+	case ok := <-disclose.Disclosed:
+		if !ok {
+			return nil, errors.New("disclosure got refused")
+		}
+	case <-time.After(propagationTimeout):
+		return nil, errors.New("disclose-protocol didn't finish in time")
+	}
+	key.Shares = disclose.Shares
+	return key, nil
+}
+
+// verifyDisclosure makes sure that the disclosure is signed by identities
+// fulfilling the ocs:disclose rule of the reader-darc of the document, and
+// that the access policy of the document allows a read at the given
+// timestamp.
+func (s *Service) verifyDisclosure(d *Disclosure, timestamp int64) error {
+	writeSB := s.db().GetByID(d.DataID)
+	if writeSB == nil {
+		return errors.New("didn't find write-block")
+	}
+	wd := NewOCS(writeSB.Data)
+	if wd == nil || wd.Write == nil {
+		return errors.New("block was not a write-block")
+	}
+	readers := wd.Write.Reader
+	if s.getDarc(readers.GetID()) == nil {
+		return errors.New("couldn't find reader-darc in database")
+	}
+	if err := s.verifySignature(DiscloseMessage(d.DataID), d.Signature, readers,
+		darc.ActionDisclose, timestamp); err != nil {
+		return err
+	}
+	if wd.Write.Policy != nil {
+		return wd.Write.Policy.CheckTime(timestamp)
+	}
+	return nil
+}
+
+// verifyDisclosedKey makes sure that the disclosed key belongs to a
+// disclosure of the skipchain, that its commitments are the ones of the
+// shared secret, and that all its shares are valid.
+func (s *Service) verifyDisclosedKey(ocs skipchain.SkipBlockID, key *DisclosedKey) error {
+	disclosureSB := s.db().GetByID(key.DisclosureID)
+	if disclosureSB == nil || !disclosureSB.SkipChainID().Equal(ocs) {
+		return errors.New("disclosure is not in this skipchain")
+	}
+	dd := NewOCS(disclosureSB.Data)
+	if dd == nil || dd.Disclosure == nil {
+		return errors.New("block was not a disclosure-block")
+	}
+	writeSB := s.db().GetByID(dd.Disclosure.DataID)
+	if writeSB == nil {
+		return errors.New("didn't find write-block")
+	}
+	s.saveMutex.Lock()
+	shared := s.Storage.Shared[string(ocs)]
+	var commits []kyber.Point
+	if shared != nil {
+		for _, c := range shared.Commits {
+			commits = append(commits, c.Clone())
+		}
+	}
+	s.saveMutex.Unlock()
+	if shared == nil {
+		return errors.New("didn't find the shared secret of this skipchain")
+	}
+	if len(commits) != len(key.Commits) {
+		return errors.New("wrong number of commitments")
+	}
+	for i, c := range commits {
+		if !c.Equal(key.Commits[i]) {
+			return errors.New("commitments are not the ones of the shared secret")
+		}
+	}
+	write := NewOCS(writeSB.Data).Write
+	poly := share.NewPubPoly(cothority.Suite, nil, commits)
+	for _, ds := range key.Shares {
+		if err := ds.Verify(write.U, poly); err != nil {
+			return errors.New("invalid decryption share: " + err.Error())
+		}
+	}
+	_, err := protocol.RecoverDecryption(write.U, key.Shares, poly, len(commits))
+	return err
+}
+
+// verifyDisclose is called by every node before giving its decryption
+// share. The verification data holds the id of the disclosure-block.
+func (s *Service) verifyDisclose(d *protocol.StartDisclose) bool {
+	err := func() error {
+		if d.VerificationData == nil {
+			return errors.New("no verification data")
+		}
+		sb := s.db().GetByID(*d.VerificationData)
+		if sb == nil {
+			return errors.New("didn't find disclosure-block")
+		}
+		dd := NewOCS(sb.Data)
+		if dd == nil || dd.Disclosure == nil {
+			return errors.New("not an OCS-disclosure block")
+		}
+		writeSB := s.db().GetByID(dd.Disclosure.DataID)
+		if writeSB == nil {
+			return errors.New("didn't find write-block")
+		}
+		wd := NewOCS(writeSB.Data)
+		if wd == nil || wd.Write == nil {
+			return errors.New("not an OCS-write block")
+		}
+		if !wd.Write.U.Equal(d.U) {
+			return errors.New("wrong point to decrypt")
+		}
+		return nil
+	}()
+	if err != nil {
+		log.Lvl2(s.ServerIdentity(), "wrong disclosure:", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/dedis/cothority/skipchain"
+)
+
+/*
+This file holds the index of the writes and reads of every OCS-skipchain.
+The index is updated in propagateOCSFunc for every new block, so that
+ListWrites and ListReads don't need to walk the skipchain.
+*/
+
+// ocsIndex holds the writes and reads of an OCS-skipchain, sorted by the
+// index of their block.
+type ocsIndex struct {
+	Writes []*WriteDoc
+	Reads  []*readEntry
+}
+
+// readEntry is a read-request in the index.
+type readEntry struct {
+	Doc       *ReadDoc
+	Index     int
+	Timestamp int64
+}
+
+// indexBlock adds the write or read of the block to the index of its
+// skipchain. If there is no index yet, all previous blocks are indexed
+// first. The lock is held during the whole update, so that concurrent blocks
+// don't build the index twice.
+func (s *Service) indexBlock(sb *skipchain.SkipBlock, dataOCS *Transaction) {
+	id := string(sb.SkipChainID())
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	idx := s.Storage.Indexes[id]
+	if idx == nil {
+		idx = &ocsIndex{}
+		if sb.Index > 0 {
+			prev := s.db().GetByID(sb.SkipChainID())
+			for prev != nil && prev.Index < sb.Index {
+				if tx := NewOCS(prev.Data); tx != nil {
+					idx.add(prev, tx)
+				}
+				if len(prev.ForwardLink) == 0 {
+					break
+				}
+				prev = s.db().GetByID(prev.ForwardLink[0].To)
+			}
+		}
+	}
+	idx.add(sb, dataOCS)
+	s.Storage.Indexes[id] = idx
+}
+
+// add inserts the write or read of the block, if it is not yet in the
+// index.
+func (idx *ocsIndex) add(sb *skipchain.SkipBlock, dataOCS *Transaction) {
+	if w := dataOCS.Write; w != nil {
+		i := sort.Search(len(idx.Writes), func(i int) bool {
+			return idx.Writes[i].Index >= sb.Index
+		})
+		if i < len(idx.Writes) && idx.Writes[i].Index == sb.Index {
+			return
+		}
+		doc := &WriteDoc{
+			WriteID:    sb.Hash,
+			Index:      sb.Index,
+			ReaderDarc: w.Reader.GetBaseID(),
+			Timestamp:  dataOCS.Timestamp,
+			Chunks:     w.Chunks,
+		}
+		if w.Signature != nil {
+			doc.Writer = w.Signature.SignaturePath.Signer
+		}
+		if w.ExtraData != nil {
+			doc.ExtraData = *w.ExtraData
+		}
+		idx.Writes = append(idx.Writes, nil)
+		copy(idx.Writes[i+1:], idx.Writes[i:])
+		idx.Writes[i] = doc
+		if w.Supersedes != nil {
+			for _, old := range idx.Writes[:i] {
+				if old.WriteID.Equal(w.Supersedes) {
+					old.SupersededBy = sb.Hash
+				}
+			}
+		}
+	}
+	if r := dataOCS.Read; r != nil {
+		i := sort.Search(len(idx.Reads), func(i int) bool {
+			return idx.Reads[i].Index >= sb.Index
+		})
+		if i < len(idx.Reads) && idx.Reads[i].Index == sb.Index {
+			return
+		}
+		entry := &readEntry{
+			Doc: &ReadDoc{
+				Reader: r.Signature.SignaturePath.Signer,
+				ReadID: sb.Hash,
+				DataID: r.DataID,
+			},
+			Index:     sb.Index,
+			Timestamp: dataOCS.Timestamp,
+		}
+		idx.Reads = append(idx.Reads, nil)
+		copy(idx.Reads[i+1:], idx.Reads[i:])
+		idx.Reads[i] = entry
+	}
+}
+
+// matchWrite returns true if the write matches all fields of the filter.
+func (f *QueryFilter) matchWrite(w *WriteDoc) bool {
+	if f.ReaderDarc != nil && !f.ReaderDarc.Equal(w.ReaderDarc) {
+		return false
+	}
+	if f.Writer != nil && !f.Writer.Equal(&w.Writer) {
+		return false
+	}
+	if f.ExtraData != nil && !bytes.HasPrefix(w.ExtraData, f.ExtraData) {
+		return false
+	}
+	return f.matchTime(w.Timestamp)
+}
+
+// matchTime returns true if the timestamp is in the range of the filter.
+func (f *QueryFilter) matchTime(timestamp int64) bool {
+	if f.Since != 0 && timestamp < f.Since {
+		return false
+	}
+	if f.Until != 0 && timestamp > f.Until {
+		return false
+	}
+	return true
+}
+
+// cursorIndex returns the index of the block of the cursor, or -1 if the
+// cursor is nil.
+func (s *Service) cursorIndex(cursor skipchain.SkipBlockID) (int, error) {
+	if cursor == nil {
+		return -1, nil
+	}
+	sb := s.db().GetByID(cursor)
+	if sb == nil {
+		return 0, errors.New("unknown cursor")
+	}
+	return sb.Index, nil
+}
+
+// ListWrites returns the writes of an OCS-skipchain that match the filter.
+func (s *Service) ListWrites(req *ListWrites) (*ListWritesReply, error) {
+	after, err := s.cursorIndex(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	idx := s.Storage.Indexes[string(req.OCS)]
+	if idx == nil {
+		return nil, errors.New("unknown OCS-skipchain")
+	}
+	reply := &ListWritesReply{}
+	start := sort.Search(len(idx.Writes), func(i int) bool {
+		return idx.Writes[i].Index > after
+	})
+	for _, w := range idx.Writes[start:] {
+		if !req.Filter.matchWrite(w) {
+			continue
+		}
+		if req.Count > 0 && len(reply.Writes) == req.Count {
+			reply.Cursor = reply.Writes[len(reply.Writes)-1].WriteID
+			break
+		}
+		reply.Writes = append(reply.Writes, w)
+	}
+	return reply, nil
+}
+
+// ListReads returns the reads of an OCS-skipchain whose timestamp is in the
+// range of the filter, and whose write matches all other fields of the
+// filter.
+func (s *Service) ListReads(req *ListReads) (*ListReadsReply, error) {
+	after, err := s.cursorIndex(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	idx := s.Storage.Indexes[string(req.OCS)]
+	if idx == nil {
+		return nil, errors.New("unknown OCS-skipchain")
+	}
+	writes := make(map[string]*WriteDoc)
+	for _, w := range idx.Writes {
+		writes[string(w.WriteID)] = w
+	}
+	writeFilter := req.Filter
+	writeFilter.Since, writeFilter.Until = 0, 0
+	reply := &ListReadsReply{}
+	start := sort.Search(len(idx.Reads), func(i int) bool {
+		return idx.Reads[i].Index > after
+	})
+	for _, r := range idx.Reads[start:] {
+		w := writes[string(r.Doc.DataID)]
+		if w == nil || !writeFilter.matchWrite(w) || !req.Filter.matchTime(r.Timestamp) {
+			continue
+		}
+		if req.Count > 0 && len(reply.Reads) == req.Count {
+			reply.Cursor = reply.Reads[len(reply.Reads)-1].ReadID
+			break
+		}
+		reply.Reads = append(reply.Reads, r.Doc)
+	}
+	return reply, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dedis/cothority/ocs/darc"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/protobuf"
+)
+
+/*
+This file holds the revocation of identities. A revocation removes an
+identity from all darcs stored in the service: the client asks for all
+darcs holding the identity, evolves them without the identity using the
+remaining owners, and sends them back in a RevokeIdentity request. The new
+darcs are stored together in one Revocation transaction on the OCS-skipchain,
+which can be listed with GetRevocations.
+*/
+
+// GetIdentityDarcs returns the latest version of all darcs that hold the
+// identity.
+func (s *Service) GetIdentityDarcs(req *GetIdentityDarcs) (*GetIdentityDarcsReply, error) {
+	return &GetIdentityDarcsReply{Darcs: s.identityDarcs(&req.Identity)}, nil
+}
+
+// RevokeIdentity stores the new versions of all darcs holding the identity
+// in one block of the OCS-skipchain.
+func (s *Service) RevokeIdentity(req *RevokeIdentity) (*RevokeIdentityReply, error) {
+	s.process.Lock()
+	defer s.process.Unlock()
+	log.Lvlf2("Revoking %s on skipchain %x", req.Identity.String(), req.OCS)
+	dataOCS := &Transaction{
+		Revocation: &Revocation{
+			Identity: req.Identity,
+			Darcs:    req.Darcs,
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	if err := s.verifyRevocation(dataOCS.Revocation, dataOCS.Timestamp); err != nil {
+		return nil, errors.New("verification of revocation failed: " + err.Error())
+	}
+	latestSB, err := s.db().GetLatest(s.db().GetByID(req.OCS))
+	if err != nil {
+		return nil, errors.New("couldn't find latest block: " + err.Error())
+	}
+	data, err := protobuf.Encode(dataOCS)
+	if err != nil {
+		return nil, err
+	}
+	latestSB, err = s.storeSkipBlock(latestSB, data)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := s.propagateOCS(latestSB.Roster, latestSB, propagationTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if replies != len(latestSB.Roster.List) {
+		log.Warn("Got only", replies, "replies for revocation-propagation")
+	}
+	return &RevokeIdentityReply{SB: latestSB}, nil
+}
+
+// GetRevocations returns up to a maximum number of revocations, starting
+// at the given block.
+func (s *Service) GetRevocations(req *GetRevocations) (*GetRevocationsReply, error) {
+	reply := &GetRevocationsReply{}
+	current := s.db().GetByID(req.Start)
+	if current == nil {
+		return nil, errors.New("didn't find starting skipblock")
+	}
+	for req.Count == 0 || len(reply.Revocations) < req.Count {
+		dataOCS := NewOCS(current.Data)
+		if dataOCS == nil {
+			return nil, errors.New("unknown block in ocs-skipchain")
+		}
+		if rev := dataOCS.Revocation; rev != nil {
+			doc := &RevocationDoc{
+				Identity:     rev.Identity,
+				RevocationID: current.Hash,
+				Timestamp:    dataOCS.Timestamp,
+			}
+			for _, d := range rev.Darcs {
+				doc.Darcs = append(doc.Darcs, d.GetID())
+			}
+			reply.Revocations = append(reply.Revocations, doc)
+		}
+		if len(current.ForwardLink) == 0 {
+			break
+		}
+		current = s.db().GetByID(current.ForwardLink[0].To)
+		if current == nil {
+			return nil, errors.New("didn't find block for this forward-link")
+		}
+	}
+	return reply, nil
+}
+
+// verifyRevocation makes sure that the revocation holds a correct new
+// version of every latest darc holding the identity, and that none of them
+// is signed by the revoked identity. The darcs are verified at the given
+// timestamp.
+func (s *Service) verifyRevocation(rev *Revocation, timestamp int64) error {
+	if len(rev.Darcs) == 0 {
+		return errors.New("no darcs in revocation")
+	}
+	bases := make(map[string]bool)
+	for _, d := range rev.Darcs {
+		base := string(d.GetBaseID())
+		if bases[base] {
+			return fmt.Errorf("darc %x is evolved twice", d.GetBaseID())
+		}
+		bases[base] = true
+		if d.HasIdentity(&rev.Identity) {
+			return fmt.Errorf("darc %x still holds the identity", d.GetBaseID())
+		}
+		if d.Signature == nil {
+			return fmt.Errorf("darc %x is not signed", d.GetBaseID())
+		}
+		for _, sig := range d.Signature.All() {
+			if sig.SignaturePath.Signer.Equal(&rev.Identity) {
+				return errors.New("the revoked identity cannot sign the revocation")
+			}
+		}
+		if err := s.verifyDarc(d, timestamp); err != nil {
+			return fmt.Errorf("darc %x: %s", d.GetBaseID(), err)
+		}
+	}
+	for _, d := range s.identityDarcs(&rev.Identity) {
+		if !bases[string(d.GetBaseID())] {
+			return fmt.Errorf("darc %x is missing in revocation", d.GetBaseID())
+		}
+	}
+	return nil
+}
+
+// identityDarcs returns the latest version of all stored darcs holding the
+// identity.
+func (s *Service) identityDarcs(id *darc.Identity) []*darc.Darc {
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	var darcs []*darc.Darc
+	for _, ds := range s.Storage.Accounts {
+		if len(ds.Darcs) == 0 {
+			continue
+		}
+		latest := ds.Darcs[len(ds.Darcs)-1]
+		if latest.HasIdentity(id) {
+			darcs = append(darcs, latest)
+		}
+	}
+	return darcs
+}
+
+// checkRevoked makes sure that an offline path doesn't end in a version of a
+// darc older than its latest revocation, so that a revoked identity cannot
+// use an older version of a darc. Older versions of darcs that have only been
+// evolved otherwise can still be used.
+func (s *Service) checkRevoked(path *darc.SignaturePath) error {
+	highest := make(map[string]*darc.Darc)
+	for _, d := range *path.Darcs {
+		base := string(d.GetBaseID())
+		if h := highest[base]; h == nil || d.Version > h.Version {
+			highest[base] = d
+		}
+	}
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	for base, d := range highest {
+		if revoked, ok := s.Storage.Revoked[base]; ok && revoked > d.Version {
+			return fmt.Errorf("path uses version %d of darc %x, but version %d revoked an identity",
+				d.Version, []byte(base), revoked)
+		}
+	}
+	return nil
+}
+
+// addRevocation stores the new darcs of a revocation and remembers their
+// versions.
+func (s *Service) addRevocation(rev *Revocation) {
+	for _, d := range rev.Darcs {
+		log.Lvlf3("Storing revoked darc %x - %x", d.GetID(), d.GetBaseID())
+		s.addDarc(d)
+		s.saveMutex.Lock()
+		s.Storage.Revoked[string(d.GetBaseID())] = d.Version
+		s.saveMutex.Unlock()
+	}
+}
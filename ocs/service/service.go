@@ -13,9 +13,11 @@ runs on the node.
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	bolt "github.com/coreos/bbolt"
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/messaging"
 	"github.com/dedis/cothority/ocs/darc"
@@ -50,12 +52,16 @@ type Service struct {
 	// are correctly handled.
 	*onet.ServiceProcessor
 
-	propagateOCS messaging.PropagationFunc
+	propagateOCS   messaging.PropagationFunc
+	propagateChunk messaging.PropagationFunc
 
 	skipchain *skipchain.Service
 	// saveMutex protects access to the storage field.
 	saveMutex sync.Mutex
 	Storage   *Storage
+	// chunks holds the off-chain data of the writes in chunkBucket.
+	chunks      *bolt.DB
+	chunkBucket []byte
 	// big bad global lock
 	process sync.Mutex
 }
@@ -72,6 +78,9 @@ type Storage struct {
 	Shared   map[string]*protocol.SharedSecret
 	Polys    map[string]*pubPoly
 	Admins   map[string]*darc.Darc
+	Indexes  map[string]*ocsIndex
+	// Revoked holds the version of the latest revocation of every darc.
+	Revoked map[string]int
 }
 
 // Darcs holds a series of darcs in increasing, succeeding version numbers.
@@ -170,7 +179,7 @@ func (s *Service) UpdateDarc(req *UpdateDarc) (reply *UpdateDarcReply,
 		Darc:      &req.Darc,
 		Timestamp: time.Now().Unix(),
 	}
-	if err := s.verifyDarc(dataOCS.Darc); err != nil {
+	if err := s.verifyDarc(dataOCS.Darc, dataOCS.Timestamp); err != nil {
 		return nil, errors.New("verification of new darc failed: " + err.Error())
 	}
 	latestSB, err := s.db().GetLatest(s.db().GetByID(req.OCS))
@@ -222,7 +231,7 @@ func (s *Service) WriteRequest(req *WriteRequest) (reply *WriteReply,
 		// Only set up the reader darc for storage if it is not already known.
 		dataOCS.Darc = &req.Write.Reader
 	}
-	if err := s.verifyWrite(req.OCS, &req.Write); err != nil {
+	if err := s.verifyWrite(req.OCS, &req.Write, dataOCS.Timestamp); err != nil {
 		return nil, errors.New("write-verification failed: " + err.Error())
 	}
 	data, err := protobuf.Encode(dataOCS)
@@ -262,7 +271,7 @@ func (s *Service) ReadRequest(req *ReadRequest) (reply *ReadReply,
 		Read:      &req.Read,
 		Timestamp: time.Now().Unix(),
 	}
-	if err := s.verifyRead(&req.Read); err != nil {
+	if err := s.verifyRead(&req.Read, dataOCS.Timestamp); err != nil {
 		return nil, errors.New("verification of read-request failed: " + err.Error())
 	}
 	data, err := protobuf.Encode(dataOCS)
@@ -303,7 +312,7 @@ func (s *Service) GetDarcPath(req *GetDarcPath) (reply *GetDarcPathReply,
 	if d == nil {
 		return nil, errors.New("this Darc doesn't exist")
 	}
-	path := s.searchPath([]darc.Darc{*d}, req.Identity, darc.Role(req.Role))
+	path := s.searchPath([]darc.Darc{*d}, req.Identity, darc.Role(req.Role).Action())
 	if len(path) == 0 {
 		return nil, errors.New("didn't find a path to the given identity")
 	}
@@ -421,12 +430,22 @@ func (s *Service) DecryptKeyRequest(req *DecryptKeyRequest) (reply *DecryptKeyRe
 	if file == nil || file.Write == nil {
 		return nil, errors.New("Data-block is broken")
 	}
+	if err := s.checkPolicy(fileSB, file.Write.Policy, read, readSB.Hash); err != nil {
+		return nil, errors.New("access policy refused: " + err.Error())
+	}
 
 	// Start OCS-protocol to re-encrypt the file's symmetric key under the
-	// reader's public key.
-	nodes := len(fileSB.Roster.List)
-	threshold := nodes - (nodes-1)/3
-	tree := fileSB.Roster.GenerateNaryTreeWithRoot(nodes, s.ServerIdentity())
+	// reader's public key. The shared secret might have been reshared since
+	// the write, so the latest roster is used.
+	latestSB, err := s.db().GetLatest(fileSB)
+	if err != nil {
+		return nil, errors.New("didn't find latest block: " + err.Error())
+	}
+	nodes := len(latestSB.Roster.List)
+	tree := latestSB.Roster.GenerateNaryTreeWithRoot(nodes, s.ServerIdentity())
+	if tree == nil {
+		return nil, errors.New("this node doesn't hold a share of the secret")
+	}
 	pi, err := s.CreateProtocol(protocol.NameOCS, tree)
 	if err != nil {
 		return nil, err
@@ -442,7 +461,8 @@ func (s *Service) DecryptKeyRequest(req *DecryptKeyRequest) (reply *DecryptKeyRe
 		if err != nil {
 			return nil, errors.New("couldn't marshal ephemeral key")
 		}
-		if err = req.Signature.Verify(pub, &file.Write.Reader); err != nil {
+		if err = req.Signature.VerifyAt(pub, &file.Write.Reader,
+			time.Unix(read.Timestamp, 0)); err != nil {
 			return nil, errors.New("wrong signature")
 		}
 		ocsProto.Xc = req.Ephemeral
@@ -464,13 +484,19 @@ func (s *Service) DecryptKeyRequest(req *DecryptKeyRequest) (reply *DecryptKeyRe
 	s.saveMutex.Lock()
 	ocsProto.Shared = s.Storage.Shared[string(fileSB.SkipChainID())]
 	pp := s.Storage.Polys[string(fileSB.SkipChainID())]
-	reply.X = s.Storage.Shared[string(fileSB.SkipChainID())].X.Clone()
+	if ocsProto.Shared == nil || pp == nil {
+		s.saveMutex.Unlock()
+		return nil, errors.New("this node doesn't hold a share of the secret")
+	}
+	reply.X = ocsProto.Shared.X.Clone()
 	var commits []kyber.Point
 	for _, c := range pp.Commits {
 		commits = append(commits, c.Clone())
 	}
 	ocsProto.Poly = share.NewPubPoly(s.Suite(), pp.B.Clone(), commits)
 	s.saveMutex.Unlock()
+	threshold := len(commits)
+	ocsProto.Threshold = threshold
 
 	ocsProto.SetConfig(&onet.GenericConfig{Data: fileSB.SkipChainID()})
 	err = ocsProto.Start()
@@ -478,7 +504,11 @@ func (s *Service) DecryptKeyRequest(req *DecryptKeyRequest) (reply *DecryptKeyRe
 		return nil, err
 	}
 	log.Lvl3("Waiting for end of ocs-protocol")
-	if !<-ocsProto.Reencrypted {
+	reencrypted := <-ocsProto.Reencrypted
+	if len(ocsProto.Invalid) > 0 {
+		log.Warn(s.ServerIdentity(), "rejected invalid shares from", ocsProto.Invalid)
+	}
+	if !reencrypted {
 		return nil, errors.New("reencryption got refused")
 	}
 	reply.XhatEnc, err = share.RecoverCommit(cothority.Suite, ocsProto.Uis,
@@ -490,6 +520,165 @@ func (s *Service) DecryptKeyRequest(req *DecryptKeyRequest) (reply *DecryptKeyRe
 	return
 }
 
+// Reshare moves the shared secret of an OCS-skipchain to a new roster and
+// threshold. First the roster of the skipchain is changed, so that all
+// joining nodes get the blocks of the skipchain. Then the reshare-protocol
+// gives new shares to all nodes of the new roster. The shared public key X
+// stays the same, so all existing documents can still be decrypted.
+func (s *Service) Reshare(req *ReshareRequest) (reply *ReshareReply, err error) {
+	s.process.Lock()
+	defer s.process.Unlock()
+	s.saveMutex.Lock()
+	admin := s.Storage.Admins[string(req.OCS)]
+	shared := s.Storage.Shared[string(req.OCS)]
+	pp := s.Storage.Polys[string(req.OCS)]
+	s.saveMutex.Unlock()
+	if admin == nil || shared == nil || pp == nil {
+		return nil, errors.New("don't know this OCS-skipchain")
+	}
+	if err := s.verifySignature(req.Message(), req.Signature, *admin, darc.ActionEvolve,
+		time.Now().Unix()); err != nil {
+		return nil, errors.New("wrong signature: " + err.Error())
+	}
+	threshold := reshareThreshold(req)
+	if threshold < 1 || threshold > len(req.Roster.List) {
+		return nil, errors.New("threshold needs to be between 1 and the size of the roster")
+	}
+	vd, err := network.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.db().GetLatest(s.db().GetByID(req.OCS))
+	if err != nil {
+		return nil, errors.New("didn't find latest block: " + err.Error())
+	}
+	oldRoster := latest.Roster
+
+	// The roster is changed before the resharing, so that the joining nodes
+	// get the OCS-skipchain. If the resharing fails, the old nodes still
+	// hold the old shares and the old roster is restored.
+	add, remove := rosterDiff(oldRoster, &req.Roster)
+	if len(add) > 0 || len(remove) > 0 {
+		ssbr, err := s.changeRoster(req.OCS, add, remove)
+		if err != nil {
+			return nil, errors.New("couldn't change roster: " + err.Error())
+		}
+		latest = ssbr.Latest
+	}
+	fail := func(cause string) error {
+		if len(add) == 0 && len(remove) == 0 {
+			return errors.New(cause)
+		}
+		if _, err := s.changeRoster(req.OCS, remove, add); err != nil {
+			log.Error(s.ServerIdentity(), "couldn't restore the old roster:", err)
+			return errors.New(cause + ", and couldn't restore the old roster: " +
+				err.Error())
+		}
+		return errors.New(cause)
+	}
+
+	// The old and the new nodes take part in the protocol.
+	all := append([]*network.ServerIdentity{}, oldRoster.List...)
+	all = append(all, add...)
+	tree := onet.NewRoster(all).GenerateNaryTreeWithRoot(len(all), s.ServerIdentity())
+	pi, err := s.CreateProtocol(protocol.NameReshare, tree)
+	if err != nil {
+		return nil, fail("couldn't create protocol: " + err.Error())
+	}
+	reshare := pi.(*protocol.Reshare)
+	reshare.Shared = shared
+	reshare.NewRoster = latest.Roster
+	reshare.NewThreshold = threshold
+	reshare.OldCommits = pp.Commits
+	reshare.VerificationData = vd
+	reshare.SetConfig(&onet.GenericConfig{Data: req.OCS})
+	if err := reshare.Start(); err != nil {
+		return nil, fail("couldn't start resharing: " + err.Error())
+	}
+	select {
+	case ok := <-reshare.Finished:
+		if !ok {
+			return nil, fail("resharing failed")
+		}
+	case <-time.After(propagationTimeout):
+		return nil, fail("resharing didn't finish in time")
+	}
+	s.storeShared(req.OCS, reshare.NewShared)
+	s.save()
+	log.Lvlf2("Reshared secret of %x to %d nodes with threshold %d", req.OCS,
+		len(latest.Roster.List), threshold)
+	return &ReshareReply{OCS: latest}, nil
+}
+
+// reshareThreshold returns the threshold of the request, or the default
+// threshold for the requested roster if none is given.
+func reshareThreshold(req *ReshareRequest) int {
+	if req.Threshold != 0 {
+		return req.Threshold
+	}
+	nodes := len(req.Roster.List)
+	return nodes - (nodes-1)/3
+}
+
+// changeRoster appends a block with the nodes in add appended to and the
+// nodes in remove taken out of the roster of the OCS-skipchain.
+func (s *Service) changeRoster(id skipchain.SkipBlockID, add, remove []*network.ServerIdentity) (*skipchain.StoreSkipBlockReply, error) {
+	data, err := protobuf.Encode(&Transaction{Timestamp: time.Now().Unix()})
+	if err != nil {
+		return nil, err
+	}
+	return s.skipchain.ProposeRosterChange(&skipchain.ProposeRosterChange{
+		SkipchainID: id,
+		Add:         add,
+		Remove:      remove,
+		Data:        data,
+	})
+}
+
+// storeShared replaces the share of the OCS-skipchain with the new share from
+// the reshare-protocol. If this node is not part of the new roster, shared
+// is nil and the old share is removed.
+func (s *Service) storeShared(id skipchain.SkipBlockID, shared *protocol.SharedSecret) {
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+	if shared == nil {
+		delete(s.Storage.Shared, string(id))
+		delete(s.Storage.Polys, string(id))
+		return
+	}
+	s.Storage.Shared[string(id)] = shared
+	s.Storage.Polys[string(id)] = &pubPoly{s.Suite().Point().Base(), shared.Commits}
+}
+
+// replayOCS stores the darcs of all blocks of the OCS-skipchain. It is used
+// when this node joins an existing OCS-skipchain.
+func (s *Service) replayOCS(id skipchain.SkipBlockID) {
+	sb := s.db().GetByID(id)
+	for sb != nil {
+		s.propagateOCSFunc(sb)
+		if len(sb.ForwardLink) == 0 {
+			break
+		}
+		sb = s.db().GetByID(sb.ForwardLink[0].To)
+	}
+}
+
+// rosterDiff returns the nodes that need to be added to and removed from
+// the old roster to get the new roster.
+func rosterDiff(oldRoster, newRoster *onet.Roster) (add, remove []*network.ServerIdentity) {
+	for _, si := range newRoster.List {
+		if i, _ := oldRoster.Search(si.ID); i < 0 {
+			add = append(add, si)
+		}
+	}
+	for _, si := range oldRoster.List {
+		if i, _ := newRoster.Search(si.ID); i < 0 {
+			remove = append(remove, si)
+		}
+	}
+	return
+}
+
 // storeSkipBlock calls directly the method of the service.
 func (s *Service) storeSkipBlock(latest *skipchain.SkipBlock, d []byte) (sb *skipchain.SkipBlock, err error) {
 	block := latest.Copy()
@@ -547,10 +736,123 @@ func (s *Service) NewProtocol(tn *onet.TreeNodeInstance, conf *onet.GenericConfi
 		ocs.Shared = shared
 		ocs.Verify = s.verifyReencryption
 		return ocs, nil
+	case protocol.NameDisclose:
+		s.saveMutex.Lock()
+		shared, ok := s.Storage.Shared[string(conf.Data)]
+		s.saveMutex.Unlock()
+		if !ok {
+			return nil, errors.New("didn't find skipchain")
+		}
+		pi, err := protocol.NewDisclose(tn)
+		if err != nil {
+			return nil, err
+		}
+		disclose := pi.(*protocol.Disclose)
+		disclose.Shared = shared
+		disclose.Verify = s.verifyDisclose
+		return disclose, nil
+	case protocol.NameReshare:
+		pi, err := protocol.NewReshare(tn)
+		if err != nil {
+			return nil, err
+		}
+		s.saveMutex.Lock()
+		_, known := s.Storage.Admins[string(conf.Data)]
+		shared := s.Storage.Shared[string(conf.Data)]
+		s.saveMutex.Unlock()
+		if !known {
+			// This node joins the OCS-skipchain and needs all darcs
+			// to verify the following requests.
+			s.replayOCS(conf.Data)
+		}
+		reshare := pi.(*protocol.Reshare)
+		reshare.Shared = shared
+		reshare.Verify = s.verifyReshare
+		go func(id skipchain.SkipBlockID) {
+			select {
+			case ok := <-reshare.Finished:
+				if !ok {
+					return
+				}
+			case <-time.After(propagationTimeout):
+				log.Error(s.ServerIdentity(), "resharing didn't finish in time")
+				return
+			}
+			s.storeShared(id, reshare.NewShared)
+			s.save()
+		}(conf.Data)
+		return reshare, nil
 	}
 	return nil, nil
 }
 
+// verifyReshare makes sure the resharing request is signed by the admin of
+// the OCS-skipchain, that the new roster and threshold are the requested ones
+// and that the new roster is the one of the latest block.
+func (s *Service) verifyReshare(root *network.ServerIdentity, sr *protocol.StartReshare) bool {
+	err := func() error {
+		_, msg, err := network.Unmarshal(sr.VerificationData, cothority.Suite)
+		if err != nil {
+			return err
+		}
+		req, ok := msg.(*ReshareRequest)
+		if !ok {
+			return errors.New("verificationData was not of type ReshareRequest")
+		}
+		s.saveMutex.Lock()
+		admin := s.Storage.Admins[string(req.OCS)]
+		s.saveMutex.Unlock()
+		if admin == nil {
+			return errors.New("don't know this OCS-skipchain")
+		}
+		if err := s.verifySignature(req.Message(), req.Signature, *admin,
+			darc.ActionEvolve, time.Now().Unix()); err != nil {
+			return errors.New("wrong signature: " + err.Error())
+		}
+		genesis := s.db().GetByID(req.OCS)
+		if genesis == nil {
+			return errors.New("didn't find OCS-skipchain")
+		}
+		latest, err := s.db().GetLatest(genesis)
+		if err != nil {
+			return errors.New("didn't find latest block: " + err.Error())
+		}
+		if sr.NewRoster == nil || len(sr.NewRoster.List) != len(latest.Roster.List) {
+			return errors.New("new roster is not the roster of the latest block")
+		}
+		for i, si := range latest.Roster.List {
+			if !si.Equal(sr.NewRoster.List[i]) {
+				return errors.New("new roster is not the roster of the latest block")
+			}
+		}
+		if add, remove := rosterDiff(&req.Roster, sr.NewRoster); len(add) > 0 || len(remove) > 0 {
+			return errors.New("new roster is not the requested roster")
+		}
+		if sr.NewThreshold != reshareThreshold(req) {
+			return errors.New("new threshold is not the requested threshold")
+		}
+		// The roster is changed right before the resharing, so the root
+		// is in the roster of the latest or of the previous block.
+		if i, _ := latest.Roster.Search(root.ID); i >= 0 {
+			return nil
+		}
+		if latest.Index > 0 {
+			previous := s.db().GetByID(latest.BackLinkIDs[0])
+			if previous != nil {
+				if i, _ := previous.Roster.Search(root.ID); i >= 0 {
+					return nil
+				}
+			}
+		}
+		return errors.New("root is not a node of the OCS-skipchain")
+	}()
+	if err != nil {
+		log.Lvl2(s.ServerIdentity(), "wrong resharing request:", err)
+		return false
+	}
+	return true
+}
+
 func (s *Service) verifyReencryption(rc *protocol.Reencrypt) bool {
 	err := func() error {
 		_, vdInt, err := network.Unmarshal(*rc.VerificationData, cothority.Suite)
@@ -572,6 +874,17 @@ func (s *Service) verifyReencryption(rc *protocol.Reencrypt) bool {
 		if o.Read == nil {
 			return errors.New("not an OCS-read block")
 		}
+		writeSB := s.db().GetByID(o.Read.DataID)
+		if writeSB == nil {
+			return errors.New("didn't find write-block")
+		}
+		w := NewOCS(writeSB.Data)
+		if w == nil || w.Write == nil {
+			return errors.New("not an OCS-write block")
+		}
+		if err := s.checkPolicy(writeSB, w.Write.Policy, o, sb.Hash); err != nil {
+			return errors.New("access policy refused: " + err.Error())
+		}
 		if verificationData.Ephemeral != nil {
 			buf, err := verificationData.Ephemeral.MarshalBinary()
 			if err != nil {
@@ -583,7 +896,8 @@ func (s *Service) verifyReencryption(rc *protocol.Reencrypt) bool {
 				&verificationData.Signature.SignaturePath.Signer) {
 				return errors.New("ephemeral key signed by wrong reader")
 			}
-			if err := verificationData.Signature.Verify(buf, darc); err != nil {
+			if err := verificationData.Signature.VerifyAt(buf, darc,
+				time.Unix(o.Timestamp, 0)); err != nil {
 				return errors.New("wrong signature on ephemeral key: " + err.Error())
 			}
 		} else {
@@ -623,31 +937,50 @@ func (s *Service) verifyOCS(newID []byte, sb *skipchain.SkipBlock) bool {
 	}
 
 	if dataOCS.Darc != nil {
-		if err := s.verifyDarc(dataOCS.Darc); err != nil {
+		if err := s.verifyDarc(dataOCS.Darc, dataOCS.Timestamp); err != nil {
 			log.Error("verification of new darc failed: " + err.Error())
 			return false
 		}
 	}
 	if dataOCS.Write != nil {
-		if err := s.verifyWrite(sb.SkipChainID(), dataOCS.Write); err != nil {
+		if err := s.verifyWrite(sb.SkipChainID(), dataOCS.Write, dataOCS.Timestamp); err != nil {
 			log.Error("verification of write request failed: " + err.Error())
 			return false
 		}
 	}
 	if dataOCS.Read != nil {
-		if err := s.verifyRead(dataOCS.Read); err != nil {
+		if err := s.verifyRead(dataOCS.Read, dataOCS.Timestamp); err != nil {
 			log.Error("verification of read request failed: " + err.Error())
 			return false
 		}
 	}
+	if dataOCS.Revocation != nil {
+		if err := s.verifyRevocation(dataOCS.Revocation, dataOCS.Timestamp); err != nil {
+			log.Error("verification of revocation failed: " + err.Error())
+			return false
+		}
+	}
+	if dataOCS.Disclosure != nil {
+		if err := s.verifyDisclosure(dataOCS.Disclosure, dataOCS.Timestamp); err != nil {
+			log.Error("verification of disclosure failed: " + err.Error())
+			return false
+		}
+	}
+	if dataOCS.DisclosedKey != nil {
+		if err := s.verifyDisclosedKey(sb.SkipChainID(), dataOCS.DisclosedKey); err != nil {
+			log.Error("verification of disclosed key failed: " + err.Error())
+			return false
+		}
+	}
 	log.Lvl3("OCS verification succeeded")
 	return true
 }
 
 // verifyRead makes sure that the read request is correctly signed from
 // a valid reader that has a path to the Readers-entry in the corresponding write
-// request.
-func (s *Service) verifyRead(read *Read) error {
+// request, and that it respects the access policy of the write request at the
+// given timestamp.
+func (s *Service) verifyRead(read *Read, timestamp int64) error {
 	// Read has to check that it's a valid reader
 	log.Lvl2("It's a read")
 
@@ -664,58 +997,157 @@ func (s *Service) verifyRead(read *Read) error {
 	if s.getDarc(readers.GetID()) == nil {
 		return errors.New("couldn't find reader-darc in database")
 	}
-	return s.verifySignature(read.DataID, read.Signature, readers, darc.User)
+	if err := s.verifySignature(read.DataID, read.Signature, readers, darc.ActionRead,
+		timestamp); err != nil {
+		return err
+	}
+	return s.checkPolicy(sbWrite, wd.Write.Policy, &Transaction{Read: read,
+		Timestamp: timestamp}, nil)
+}
+
+// checkPolicy makes sure that the read-request respects the access policy of
+// the write-block. If readID is nil, the read-request is a new one and all
+// existing read-requests are counted, else only the read-requests before
+// readID are counted.
+func (s *Service) checkPolicy(writeSB *skipchain.SkipBlock, policy *AccessPolicy,
+	read *Transaction, readID skipchain.SkipBlockID) error {
+	if policy == nil {
+		return nil
+	}
+	if err := policy.CheckTime(read.Timestamp); err != nil {
+		return err
+	}
+	if policy.MaxReads > 0 {
+		reader := &read.Read.Signature.SignaturePath.Signer
+		reads, err := s.countReads(writeSB, reader, readID)
+		if err != nil {
+			return err
+		}
+		if reads >= policy.MaxReads {
+			return fmt.Errorf("reader already did %d read-requests", reads)
+		}
+	}
+	return nil
+}
+
+// countReads returns the number of read-requests of the reader for the
+// document in writeSB, stopping at the block with the hash until.
+func (s *Service) countReads(writeSB *skipchain.SkipBlock, reader *darc.Identity,
+	until skipchain.SkipBlockID) (int, error) {
+	reads := 0
+	current := writeSB
+	for len(current.ForwardLink) > 0 {
+		current = s.db().GetByID(current.ForwardLink[0].To)
+		if current == nil {
+			return 0, errors.New("didn't find block for this forward-link")
+		}
+		if current.Hash.Equal(until) {
+			break
+		}
+		dataOCS := NewOCS(current.Data)
+		if dataOCS == nil || dataOCS.Read == nil ||
+			!dataOCS.Read.DataID.Equal(writeSB.Hash) {
+			continue
+		}
+		if dataOCS.Read.Signature.SignaturePath.Signer.Equal(reader) {
+			reads++
+		}
+	}
+	return reads, nil
 }
 
 // verifySignature handles both offline and online signatures. For offline
 // signatures, all darcs in the path must be stored in the SignaturePath.
 // For online signatures, the system will check itself if it finds a valid
-// path from the base darc to the signer.
+// path from the base darc to the signer. If the signature holds signatures
+// of other signers, all of them are verified, and together they must fulfill
+// the rule of the base darc for the action. Certificates of the signers
+// need to be valid at the timestamp of the transaction. Offline paths cannot
+// use versions of darcs that have been revoked since.
 // If the signature is valid, nil is returned. Else an error is returned,
 // indicating what went wrong.
-func (s *Service) verifySignature(msg []byte, sig darc.Signature, base darc.Darc, role darc.Role) error {
-	if sig.SignaturePath.Darcs == nil {
-		log.Lvl3("Verifying online darc")
-		signer := sig.SignaturePath.Signer
-		path := s.searchPath([]darc.Darc{base}, signer, role)
-		if path == nil {
-			return errors.New("didn't find a valid path from the write.Readers to the signer")
-		}
-		hash, err := sig.SignaturePath.SigHash(msg)
-		if err != nil {
-			return err
+func (s *Service) verifySignature(msg []byte, sig darc.Signature, base darc.Darc, action string,
+	timestamp int64) error {
+	when := time.Unix(timestamp, 0)
+	var paths []*darc.SignaturePath
+	online := sig.SignaturePath.Darcs == nil
+	for _, si := range sig.All() {
+		path := si.SignaturePath
+		if (path.Darcs == nil) != online {
+			return errors.New("cannot mix online and offline signatures")
 		}
-		if err := signer.Verify(hash, sig.Signature); err != nil {
-			return errors.New("wrong online signature: " + err.Error())
+		if online {
+			log.Lvl3("Verifying online darc")
+			found := s.searchPath([]darc.Darc{base}, path.Signer, action)
+			if found == nil {
+				return errors.New("didn't find a valid path from the base darc to the signer")
+			}
+			hash, err := path.SigHash(msg)
+			if err != nil {
+				return err
+			}
+			if err := path.Signer.VerifyAt(hash, si.Signature, when); err != nil {
+				return errors.New("wrong online signature: " + err.Error())
+			}
+			darcs := make([]*darc.Darc, len(found))
+			for i := range found {
+				darcs[i] = &found[i]
+			}
+			path.Darcs = &darcs
+		} else {
+			log.Lvl3("Verifying offline darc")
+			if err := si.VerifyAt(msg, &base, when); err != nil {
+				return errors.New("wrong offline signature: " + err.Error())
+			}
+			if err := s.checkRevoked(&path); err != nil {
+				return err
+			}
 		}
+		paths = append(paths, &path)
+	}
+	// Darcs found online have been verified when they were stored, but
+	// the evolutions of an offline path need to be verified.
+	var err error
+	if online {
+		err = darc.EvalPaths(paths, action)
 	} else {
-		log.Lvl3("Verifying offline darc")
-		if err := sig.Verify(msg, &base); err != nil {
-			return errors.New("wrong offline signature: " + err.Error())
-		}
+		err = darc.VerifyPathsAt(paths, action, when)
+	}
+	if err != nil {
+		return errors.New("signers don't fulfill the rule: " + err.Error())
 	}
 	return nil
 }
 
 // verifyWrite makes sure that the write request is correctly signed from
-// a writer that has a valid path from the admin darc in the ocs skipchain.
-func (s *Service) verifyWrite(ocs skipchain.SkipBlockID, write *Write) error {
+// a writer that has a valid path from the admin darc in the ocs skipchain
+// at the given timestamp.
+func (s *Service) verifyWrite(ocs skipchain.SkipBlockID, write *Write, timestamp int64) error {
 	if err := write.CheckProof(cothority.Suite, ocs); err != nil {
 		return errors.New("proof verification failed: " + err.Error())
 	}
-	s.saveMutex.Lock()
 	log.Lvl3("Verifying write request")
-	defer s.saveMutex.Unlock()
+	s.saveMutex.Lock()
 	admin := s.Storage.Admins[string(ocs)]
+	s.saveMutex.Unlock()
 	if admin == nil {
 		return errors.New("couldn't find admin for this chain")
 	}
-	return s.verifySignature(write.Reader.GetID(), *write.Signature, *admin, darc.User)
+	if write.Policy != nil {
+		if err := write.Policy.Check(); err != nil {
+			return errors.New("invalid access policy: " + err.Error())
+		}
+	}
+	if err := s.verifyChunks(ocs, write); err != nil {
+		return err
+	}
+	return s.verifySignature(write.SignatureMessage(), *write.Signature, *admin, darc.ActionWrite,
+		timestamp)
 }
 
 // verifyDarc makes sure that the new darc is correctly signed from a previous
-// darc if it has a Version > 0.
-func (s *Service) verifyDarc(newDarc *darc.Darc) error {
+// darc at the given timestamp if it has a Version > 0.
+func (s *Service) verifyDarc(newDarc *darc.Darc, timestamp int64) error {
 	log.Lvl3("Verifying new darc")
 	if s.getDarc(newDarc.GetID()) != nil {
 		return errors.New("cannot store darc again")
@@ -730,7 +1162,8 @@ func (s *Service) verifyDarc(newDarc *darc.Darc) error {
 		}
 		return nil
 	}
-	return s.verifySignature(newDarc.GetID(), *newDarc.Signature, *latest, darc.Owner)
+	return s.verifySignature(newDarc.GetID(), *newDarc.Signature, *latest, darc.ActionEvolve,
+		timestamp)
 }
 
 func (s *Service) addDarc(d *darc.Darc) {
@@ -780,13 +1213,13 @@ func (s *Service) printPath(path []darc.Darc) {
 // of path to the identity. It starts by first getting the latest darc-version,
 // then searching all sub-darcs.
 // If it doesn't find a matching path, it returns nil.
-func (s *Service) searchPath(path []darc.Darc, identity darc.Identity, role darc.Role) []darc.Darc {
+func (s *Service) searchPath(path []darc.Darc, identity darc.Identity, action string) []darc.Darc {
 	newpath := make([]darc.Darc, len(path))
 	copy(newpath, path)
 
-	// Any role deeper in the tree must be a user role.
-	if role == darc.Owner && len(path) > 1 {
-		role = darc.User
+	// Any darc deeper in the tree must be signed on behalf of.
+	if len(path) > 1 {
+		action = darc.ActionSign
 	}
 	d := &path[len(path)-1]
 
@@ -800,35 +1233,30 @@ func (s *Service) searchPath(path []darc.Darc, identity darc.Identity, role darc
 		}
 	}
 	s.saveMutex.Unlock()
-	log.Lvl3("role is:", role)
+	log.Lvl3("action is:", action)
 	for i, p := range newpath {
 		log.Lvlf4("newpath[%d] = %x", i, p.GetID())
 	}
 	log.Lvl3("This darc is:", newpath[len(newpath)-1].String())
 
 	// Then search for identity
-	ids := d.Users
-	if role == darc.Owner {
-		ids = d.Owners
-	}
-	if ids != nil {
-		// First search the identity
-		for _, id := range *ids {
-			if identity.Equal(id) {
-				return newpath
-			}
+	ids := d.Expression(action).Identities()
+	// First search the identity
+	for _, id := range ids {
+		if identity.Equal(id) {
+			return newpath
 		}
-		// Then search sub-darcs
-		for _, id := range *ids {
-			if id.Darc != nil {
-				d := s.getDarc(id.Darc.ID)
-				if d == nil {
-					log.Lvlf1("Got unknown darc-id in path - ignoring: %x", id.Darc.ID)
-					continue
-				}
-				if np := s.searchPath(append(newpath, *d), identity, role); np != nil {
-					return np
-				}
+	}
+	// Then search sub-darcs
+	for _, id := range ids {
+		if id.Darc != nil {
+			d := s.getDarc(id.Darc.ID)
+			if d == nil {
+				log.Lvlf1("Got unknown darc-id in path - ignoring: %x", id.Darc.ID)
+				continue
+			}
+			if np := s.searchPath(append(newpath, *d), identity, action); np != nil {
+				return np
 			}
 		}
 	}
@@ -850,6 +1278,13 @@ func (s *Service) propagateOCSFunc(sbI network.Message) {
 		log.Lvlf3("Storing new darc %x - %x", r.GetID(), r.GetBaseID())
 		s.addDarc(r)
 	}
+	if dataOCS.Revocation != nil {
+		s.addRevocation(dataOCS.Revocation)
+	}
+	s.indexBlock(sb, dataOCS)
+	if w := dataOCS.Write; w != nil && w.Supersedes != nil {
+		s.collectChunks(w.Supersedes)
+	}
 	defer s.save()
 	if sb.Index == 0 {
 		s.saveMutex.Lock()
@@ -894,6 +1329,12 @@ func (s *Service) tryLoad() error {
 		if len(s.Storage.Admins) == 0 {
 			s.Storage.Admins = map[string]*darc.Darc{}
 		}
+		if len(s.Storage.Indexes) == 0 {
+			s.Storage.Indexes = map[string]*ocsIndex{}
+		}
+		if len(s.Storage.Revoked) == 0 {
+			s.Storage.Revoked = map[string]int{}
+		}
 	}()
 	s.saveMutex.Lock()
 	defer s.saveMutex.Unlock()
@@ -920,7 +1361,9 @@ func newService(c *onet.Context) (onet.Service, error) {
 	s := &Service{
 		ServiceProcessor: onet.NewServiceProcessor(c),
 		Storage: &Storage{
-			Admins: make(map[string]*darc.Darc),
+			Admins:  make(map[string]*darc.Darc),
+			Indexes: make(map[string]*ocsIndex),
+			Revoked: make(map[string]int),
 		},
 		skipchain: c.Service(skipchain.ServiceName).(*skipchain.Service),
 	}
@@ -928,7 +1371,10 @@ func newService(c *onet.Context) (onet.Service, error) {
 		s.WriteRequest, s.ReadRequest, s.GetReadRequests,
 		s.DecryptKeyRequest, s.SharedPublic,
 		s.UpdateDarc, s.GetDarcPath,
-		s.GetLatestDarc); err != nil {
+		s.GetLatestDarc, s.Reshare, s.GetIdentityDarcs,
+		s.RevokeIdentity, s.GetRevocations,
+		s.ListWrites, s.ListReads, s.GetDataChunk, s.StoreDataChunk,
+		s.DisclosePublicly); err != nil {
 		log.Error("Couldn't register messages", err)
 		return nil, err
 	}
@@ -936,6 +1382,9 @@ func newService(c *onet.Context) (onet.Service, error) {
 	var err error
 	s.propagateOCS, err = messaging.NewPropagationFunc(c, "PropagateOCS", s.propagateOCSFunc, -1)
 	log.ErrFatal(err)
+	s.propagateChunk, err = messaging.NewPropagationFunc(c, "PropagateChunk", s.propagateChunkFunc, -1)
+	log.ErrFatal(err)
+	s.chunks, s.chunkBucket = c.GetAdditionalBucket([]byte("chunks"))
 	if err := s.tryLoad(); err != nil {
 		log.Error(err)
 		return nil, err
@@ -3,12 +3,14 @@ package service
 import (
 	"sync"
 	"testing"
+	"time"
 
-	bolt "github.com/coreos/bbolt"
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/ocs/darc"
+	"github.com/dedis/cothority/ocs/protocol"
 	"github.com/dedis/cothority/skipchain"
 	"github.com/dedis/kyber/suites"
+	"github.com/dedis/kyber/util/random"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
 	"github.com/dedis/onet/network"
@@ -76,7 +78,7 @@ func TestService_proof(t *testing.T) {
 	bucket := skipchain.ServiceName + "_skipblocks"
 	for _, s := range o.services {
 		db := s.(*Service).db()
-		require.Nil(t, db.Update(func(tx *bolt.Tx) error {
+		require.Nil(t, db.Update(func(tx skipchain.StorageTx) error {
 			return tx.Bucket([]byte(bucket)).Put(rr.SB.Hash, val)
 		}))
 	}
@@ -94,6 +96,547 @@ func TestService_proof(t *testing.T) {
 	require.Equal(t, 1, len(requests.Documents))
 }
 
+func TestService_Reshare(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	// Write a document before the resharing.
+	encKey := []byte{1, 2, 3}
+	write := NewWrite(cothority.Suite, o.sc.OCS.Hash, o.sc.X, o.readers, encKey)
+	write.Data = []byte{}
+	sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.User)
+	sig, err := darc.NewDarcSignature(write.Reader.GetID(), sigPath, o.writer)
+	require.Nil(t, err)
+	wr, err := o.service.WriteRequest(&WriteRequest{
+		OCS:       o.sc.OCS.Hash,
+		Write:     *write,
+		Signature: *sig,
+		Readers:   o.readers,
+	})
+	require.Nil(t, err)
+
+	// Remove the last node and add two new ones.
+	hosts := o.local.GenServers(2)
+	list := append([]*network.ServerIdentity{}, o.sc.OCS.Roster.List[:4]...)
+	for _, h := range hosts {
+		list = append(list, h.ServerIdentity)
+	}
+	req := &ReshareRequest{
+		OCS:       o.sc.OCS.Hash,
+		Roster:    *onet.NewRoster(list),
+		Threshold: 4,
+	}
+	ownerPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.Owner)
+	wrongSig, err := darc.NewDarcSignature([]byte("wrong"), ownerPath, o.writer)
+	require.Nil(t, err)
+	req.Signature = *wrongSig
+	_, err = o.service.Reshare(req)
+	require.NotNil(t, err)
+	adminSig, err := darc.NewDarcSignature(req.Message(), ownerPath, o.writer)
+	require.Nil(t, err)
+	req.Signature = *adminSig
+	reply, err := o.service.Reshare(req)
+	require.Nil(t, err)
+	require.Equal(t, 6, len(reply.OCS.Roster.List))
+
+	// The public key stays the same, the removed node dropped its share and
+	// the new nodes got one.
+	pub, err := o.service.SharedPublic(&SharedPublicRequest{Genesis: o.sc.OCS.Hash})
+	require.Nil(t, err)
+	require.True(t, o.sc.X.Equal(pub.X))
+	// The other nodes store their new shares in the background.
+	hasShare := func(s onet.Service) bool {
+		for i := 0; i < 10; i++ {
+			_, err := s.(*Service).SharedPublic(&SharedPublicRequest{Genesis: o.sc.OCS.Hash})
+			if err == nil {
+				return true
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		return false
+	}
+	for _, s := range o.local.GetServices(hosts, templateID) {
+		require.True(t, hasShare(s))
+	}
+	removed := o.services[4].(*Service)
+	for i := 0; i < 10; i++ {
+		_, err = removed.SharedPublic(&SharedPublicRequest{Genesis: o.sc.OCS.Hash})
+		if err != nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NotNil(t, err)
+
+	// The document written before the resharing can still be decrypted.
+	sigRead, err := darc.NewDarcSignature(wr.SB.Hash, sigPath, o.writer)
+	require.Nil(t, err)
+	rr, err := o.service.ReadRequest(&ReadRequest{
+		OCS:  o.sc.OCS.Hash,
+		Read: Read{DataID: wr.SB.Hash, Signature: *sigRead},
+	})
+	require.Nil(t, err)
+	symEnc, err := o.service.DecryptKeyRequest(&DecryptKeyRequest{
+		Read: rr.SB.Hash,
+	})
+	require.Nil(t, err)
+	priv, err := o.writer.GetPrivate()
+	require.Nil(t, err)
+	sym, err := DecodeKey(cothority.Suite, o.sc.X, write.Cs, symEnc.XhatEnc, priv)
+	require.Nil(t, err)
+	require.Equal(t, encKey, sym)
+}
+
+// A failed resharing restores the old roster, whose nodes keep their shares.
+func TestService_ReshareFailed(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	// Two nodes with a wrong share can't deal, so the old secret can't be
+	// recovered.
+	id := string(o.sc.OCS.Hash)
+	originals := make([]*protocol.SharedSecret, 2)
+	for i := range originals {
+		s := o.services[i+1].(*Service)
+		s.saveMutex.Lock()
+		originals[i] = s.Storage.Shared[id]
+		wrong := *originals[i]
+		wrong.X = cothority.Suite.Point().Pick(random.New())
+		s.Storage.Shared[id] = &wrong
+		s.saveMutex.Unlock()
+	}
+
+	hosts := o.local.GenServers(1)
+	list := append([]*network.ServerIdentity{}, o.sc.OCS.Roster.List[:4]...)
+	list = append(list, hosts[0].ServerIdentity)
+	req := &ReshareRequest{
+		OCS:    o.sc.OCS.Hash,
+		Roster: *onet.NewRoster(list),
+	}
+	ownerPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.Owner)
+	sig, err := darc.NewDarcSignature(req.Message(), ownerPath, o.writer)
+	require.Nil(t, err)
+	req.Signature = *sig
+	_, err = o.service.Reshare(req)
+	require.NotNil(t, err)
+
+	latest, err := o.service.db().GetLatest(o.service.db().GetByID(o.sc.OCS.Hash))
+	require.Nil(t, err)
+	require.Equal(t, len(o.sc.OCS.Roster.List), len(latest.Roster.List))
+	for _, si := range o.sc.OCS.Roster.List {
+		i, _ := latest.Roster.Search(si.ID)
+		require.True(t, i >= 0)
+	}
+	_, err = o.services[4].(*Service).SharedPublic(&SharedPublicRequest{Genesis: o.sc.OCS.Hash})
+	require.Nil(t, err)
+
+	// With the shares restored, documents can be decrypted by the old roster.
+	for i, shared := range originals {
+		s := o.services[i+1].(*Service)
+		s.saveMutex.Lock()
+		s.Storage.Shared[id] = shared
+		s.saveMutex.Unlock()
+	}
+	sb, err := o.storeWrite(t, o.newWrite())
+	require.Nil(t, err)
+	rr, err := o.readWrite(t, sb)
+	require.Nil(t, err)
+	_, err = o.service.DecryptKeyRequest(&DecryptKeyRequest{Read: rr.SB.Hash})
+	require.Nil(t, err)
+}
+
+// The trustees only deal their shares for a resharing to the roster and
+// threshold signed by the admin and started by a node of the OCS-skipchain.
+func TestService_VerifyReshare(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	req := &ReshareRequest{
+		OCS:       o.sc.OCS.Hash,
+		Roster:    *o.sc.OCS.Roster,
+		Threshold: 3,
+	}
+	ownerPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.Owner)
+	sig, err := darc.NewDarcSignature(req.Message(), ownerPath, o.writer)
+	require.Nil(t, err)
+	req.Signature = *sig
+	vd, err := network.Marshal(req)
+	require.Nil(t, err)
+	root := o.sc.OCS.Roster.List[0]
+	sr := &protocol.StartReshare{
+		NewRoster:        o.sc.OCS.Roster,
+		NewThreshold:     3,
+		VerificationData: vd,
+	}
+	trustee := o.services[1].(*Service)
+	require.True(t, trustee.verifyReshare(root, sr))
+
+	stranger := o.local.GenServers(1)[0].ServerIdentity
+	require.False(t, trustee.verifyReshare(stranger, sr))
+	sr.NewThreshold = 1
+	require.False(t, trustee.verifyReshare(root, sr))
+	sr.NewThreshold = 3
+	sr.NewRoster = onet.NewRoster(append([]*network.ServerIdentity{stranger},
+		o.sc.OCS.Roster.List[1:]...))
+	require.False(t, trustee.verifyReshare(root, sr))
+	sr.NewRoster = o.sc.OCS.Roster
+	sr.VerificationData = nil
+	require.False(t, trustee.verifyReshare(root, sr))
+
+	req.Threshold = 1
+	vd, err = network.Marshal(req)
+	require.Nil(t, err)
+	sr.NewThreshold = 1
+	sr.VerificationData = vd
+	require.False(t, trustee.verifyReshare(root, sr))
+}
+
+// Uses a reader-darc whose rule for reading needs two signers.
+func TestService_ReadRule(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	second := darc.NewSignerEd25519(nil, nil)
+	readers := darc.NewDarc(&[]*darc.Identity{o.writerI},
+		&[]*darc.Identity{o.writerI, second.Identity()}, nil)
+	require.Nil(t, readers.SetRule(darc.ActionRead, darc.NewExprAnd(
+		darc.NewExprIdentity(o.writerI), darc.NewExprIdentity(second.Identity()))))
+
+	write := NewWrite(cothority.Suite, o.sc.OCS.Hash, o.sc.X, readers, []byte{1, 2, 3})
+	write.Data = []byte{}
+	sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.User)
+	sig, err := darc.NewDarcSignature(write.Reader.GetID(), sigPath, o.writer)
+	require.Nil(t, err)
+	wr, err := o.service.WriteRequest(&WriteRequest{
+		OCS:       o.sc.OCS.Hash,
+		Write:     *write,
+		Signature: *sig,
+		Readers:   readers,
+	})
+	require.Nil(t, err)
+
+	readPath := darc.NewSignaturePath([]*darc.Darc{readers}, *o.writerI, darc.User)
+	sigRead, err := darc.NewDarcSignature(wr.SB.Hash, readPath, o.writer)
+	require.Nil(t, err)
+	_, err = o.service.ReadRequest(&ReadRequest{
+		OCS:  o.sc.OCS.Hash,
+		Read: Read{DataID: wr.SB.Hash, Signature: *sigRead},
+	})
+	require.NotNil(t, err)
+
+	secondPath := darc.NewSignaturePath([]*darc.Darc{readers}, *second.Identity(), darc.User)
+	sigSecond, err := darc.NewDarcSignature(wr.SB.Hash, secondPath, second)
+	require.Nil(t, err)
+	sigRead.AddSignature(sigSecond)
+	_, err = o.service.ReadRequest(&ReadRequest{
+		OCS:  o.sc.OCS.Hash,
+		Read: Read{DataID: wr.SB.Hash, Signature: *sigRead},
+	})
+	require.Nil(t, err)
+}
+
+func TestService_ReadPolicy(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	writePolicy := func(policy *AccessPolicy) *skipchain.SkipBlock {
+		write := o.newWrite()
+		write.Policy = policy
+		sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.User)
+		sig, err := darc.NewDarcSignature(write.Reader.GetID(), sigPath, o.writer)
+		require.Nil(t, err)
+		_, err = o.service.WriteRequest(&WriteRequest{
+			OCS:       o.sc.OCS.Hash,
+			Write:     *write,
+			Signature: *sig,
+		})
+		require.NotNil(t, err, "signature doesn't cover the policy")
+		sb, err := o.storeWrite(t, write)
+		require.Nil(t, err)
+		return sb
+	}
+
+	now := time.Now().Unix()
+	once := writePolicy(&AccessPolicy{NotAfter: now + 3600, MaxReads: 1})
+	rr, err := o.readWrite(t, once)
+	require.Nil(t, err)
+	_, err = o.readWrite(t, once)
+	require.NotNil(t, err)
+	_, err = o.service.DecryptKeyRequest(&DecryptKeyRequest{Read: rr.SB.Hash})
+	require.Nil(t, err)
+
+	later := writePolicy(&AccessPolicy{NotBefore: now + 3600})
+	_, err = o.readWrite(t, later)
+	require.NotNil(t, err)
+	expired := writePolicy(&AccessPolicy{NotAfter: now - 1})
+	_, err = o.readWrite(t, expired)
+	require.NotNil(t, err)
+}
+
+func TestService_RevokeIdentity(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	bad := darc.NewSignerEd25519(nil, nil)
+	admin := o.readers.Copy()
+	admin.AddUser(bad.Identity())
+	require.Nil(t, admin.SetEvolution(o.readers, nil, o.writer))
+	_, err := o.service.UpdateDarc(&UpdateDarc{OCS: o.sc.OCS.Hash, Darc: *admin})
+	require.Nil(t, err)
+	readers := darc.NewDarc(&[]*darc.Identity{o.writerI, bad.Identity()},
+		&[]*darc.Identity{bad.Identity()}, []byte("readers"))
+	_, err = o.service.UpdateDarc(&UpdateDarc{OCS: o.sc.OCS.Hash, Darc: *readers})
+	require.Nil(t, err)
+
+	write := func(path []*darc.Darc) error {
+		_, err := o.storeWriteBy(t, o.newWrite(), bad, path)
+		return err
+	}
+	require.Nil(t, write([]*darc.Darc{o.readers, admin}))
+	// Evolving a darc doesn't invalidate its older versions.
+	_, err = o.storeWrite(t, o.newWrite())
+	require.Nil(t, err)
+
+	ids, err := o.service.GetIdentityDarcs(&GetIdentityDarcs{Identity: *bad.Identity()})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(ids.Darcs))
+	revoke := func(signer *darc.Signer, darcs []*darc.Darc) error {
+		req := &RevokeIdentity{OCS: o.sc.OCS.Hash, Identity: *bad.Identity()}
+		for _, d := range darcs {
+			newDarc := d.Copy()
+			require.Nil(t, newDarc.RemoveIdentity(bad.Identity()))
+			require.Nil(t, newDarc.SetEvolution(d, nil, signer))
+			req.Darcs = append(req.Darcs, newDarc)
+		}
+		_, err := o.service.RevokeIdentity(req)
+		return err
+	}
+	require.NotNil(t, revoke(o.writer, ids.Darcs[:1]), "missing darc")
+	require.NotNil(t, revoke(bad, ids.Darcs[1:]), "signed by revoked identity")
+	require.Nil(t, revoke(o.writer, ids.Darcs))
+
+	ids, err = o.service.GetIdentityDarcs(&GetIdentityDarcs{Identity: *bad.Identity()})
+	require.Nil(t, err)
+	require.Equal(t, 0, len(ids.Darcs))
+	revs, err := o.service.GetRevocations(&GetRevocations{Start: o.sc.OCS.Hash})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(revs.Revocations))
+	require.True(t, bad.Identity().Equal(&revs.Revocations[0].Identity))
+	require.Equal(t, 2, len(revs.Revocations[0].Darcs))
+
+	// Older versions of the darcs cannot be used anymore.
+	require.NotNil(t, write([]*darc.Darc{o.readers, admin}))
+	require.NotNil(t, write([]*darc.Darc{o.readers}))
+	_, err = o.storeWrite(t, o.newWrite())
+	require.NotNil(t, err)
+}
+
+func TestService_ListWrites(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	var writes []*skipchain.SkipBlock
+	for _, extra := range []string{"doc-1", "doc-2", "img-1"} {
+		write := o.newWrite()
+		ed := []byte(extra)
+		write.ExtraData = &ed
+		sb, err := o.storeWrite(t, write)
+		require.Nil(t, err)
+		writes = append(writes, sb)
+	}
+	_, err := o.readWrite(t, writes[1])
+	require.Nil(t, err)
+
+	list := func(filter QueryFilter, cursor skipchain.SkipBlockID, count int) *ListWritesReply {
+		reply, err := o.service.ListWrites(&ListWrites{OCS: o.sc.OCS.Hash,
+			Filter: filter, Cursor: cursor, Count: count})
+		require.Nil(t, err)
+		return reply
+	}
+	require.Equal(t, 3, len(list(QueryFilter{}, nil, 0).Writes))
+	require.Equal(t, 3, len(list(QueryFilter{ReaderDarc: o.readers.GetBaseID()}, nil, 0).Writes))
+	require.Equal(t, 0, len(list(QueryFilter{ReaderDarc: darc.ID{1, 2, 3}}, nil, 0).Writes))
+	require.Equal(t, 3, len(list(QueryFilter{Writer: o.writerI}, nil, 0).Writes))
+	require.Equal(t, 0, len(list(QueryFilter{Until: 1}, nil, 0).Writes))
+	docs := list(QueryFilter{ExtraData: []byte("doc-")}, nil, 0)
+	require.Equal(t, 2, len(docs.Writes))
+	require.Nil(t, docs.Cursor)
+	require.Equal(t, []byte("doc-2"), docs.Writes[1].ExtraData)
+
+	// Paginate one write at a time.
+	first := list(QueryFilter{}, nil, 1)
+	require.Equal(t, 1, len(first.Writes))
+	require.True(t, writes[0].Hash.Equal(first.Writes[0].WriteID))
+	require.NotNil(t, first.Cursor)
+	second := list(QueryFilter{}, first.Cursor, 2)
+	require.Equal(t, 2, len(second.Writes))
+	require.True(t, writes[2].Hash.Equal(second.Writes[1].WriteID))
+	require.Nil(t, second.Cursor)
+
+	reads, err := o.service.ListReads(&ListReads{OCS: o.sc.OCS.Hash,
+		Filter: QueryFilter{ExtraData: []byte("doc-")}})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(reads.Reads))
+	require.True(t, writes[1].Hash.Equal(reads.Reads[0].DataID))
+	reads, err = o.service.ListReads(&ListReads{OCS: o.sc.OCS.Hash,
+		Filter: QueryFilter{ExtraData: []byte("img-")}})
+	require.Nil(t, err)
+	require.Equal(t, 0, len(reads.Reads))
+}
+
+func TestService_Chunks(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	write := func(data []byte, supersedes skipchain.SkipBlockID) (*skipchain.SkipBlock, error) {
+		write := o.newWrite()
+		write.Supersedes = supersedes
+		chunks, hashes := SplitChunks(data)
+		write.Chunks = hashes
+		sb, err := o.storeWrite(t, write)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			_, err := o.service.StoreDataChunk(&StoreDataChunk{DataID: sb.Hash, Chunk: chunk})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return sb, nil
+	}
+	getChunks := func(sb *skipchain.SkipBlock, service *Service) ([]byte, error) {
+		var data []byte
+		for i := range NewOCS(sb.Data).Write.Chunks {
+			reply, err := service.GetDataChunk(&GetDataChunk{DataID: sb.Hash, Index: i})
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, reply.Chunk...)
+		}
+		return data, nil
+	}
+
+	data := make([]byte, 2*ChunkSize+10)
+	random.Bytes(data, random.New())
+	first, err := write(data, nil)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(NewOCS(first.Data).Write.Chunks))
+	for _, s := range o.services {
+		got, err := getChunks(first, s.(*Service))
+		require.Nil(t, err)
+		require.Equal(t, data, got)
+	}
+	_, err = o.service.GetDataChunk(&GetDataChunk{DataID: first.Hash, Index: 3})
+	require.NotNil(t, err)
+
+	// The signature covers the hashes of the chunks.
+	write2 := o.newWrite()
+	_, write2.Chunks = SplitChunks([]byte{1})
+	sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.User)
+	sig, err := darc.NewDarcSignature(write2.SignatureMessage(), sigPath, o.writer)
+	require.Nil(t, err)
+	_, write2.Chunks = SplitChunks([]byte{2})
+	_, err = o.service.WriteRequest(&WriteRequest{OCS: o.sc.OCS.Hash, Write: *write2,
+		Signature: *sig})
+	require.NotNil(t, err)
+
+	// Chunks that don't belong to a stored write are refused.
+	sb2, err := o.storeWrite(t, write2)
+	require.Nil(t, err)
+	_, err = o.service.StoreDataChunk(&StoreDataChunk{DataID: sb2.Hash, Chunk: []byte{1}})
+	require.NotNil(t, err)
+	_, err = o.service.StoreDataChunk(&StoreDataChunk{DataID: first.Hash, Chunk: []byte{2}})
+	require.NotNil(t, err)
+	_, err = o.service.loadChunk(chunkHash([]byte{1}))
+	require.NotNil(t, err)
+	o.service.propagateChunkFunc(&DataChunk{DataID: first.Hash, Data: []byte{1}})
+	_, err = o.service.loadChunk(chunkHash([]byte{1}))
+	require.NotNil(t, err)
+	_, err = o.service.StoreDataChunk(&StoreDataChunk{DataID: sb2.Hash, Chunk: []byte{2}})
+	require.Nil(t, err)
+
+	// Superseding removes the chunks that are not used anymore.
+	newData := append([]byte{}, data[:ChunkSize]...)
+	newData = append(newData, 1, 2, 3)
+	second, err := write(newData, first.Hash)
+	require.Nil(t, err)
+	_, err = getChunks(first, o.service)
+	require.NotNil(t, err)
+	got, err := getChunks(second, o.service)
+	require.Nil(t, err)
+	require.Equal(t, newData, got)
+	_, err = o.service.loadChunk(NewOCS(first.Data).Write.Chunks[1])
+	require.NotNil(t, err)
+	_, err = o.service.loadChunk(NewOCS(first.Data).Write.Chunks[0])
+	require.Nil(t, err)
+}
+
+func TestService_DisclosePublicly(t *testing.T) {
+	o := createOCS(t)
+	defer o.local.CloseAll()
+
+	writePolicy := func(policy *AccessPolicy) *skipchain.SkipBlock {
+		write := o.newWrite()
+		write.Policy = policy
+		sb, err := o.storeWrite(t, write)
+		require.Nil(t, err)
+		return sb
+	}
+	disclose := func(sb *skipchain.SkipBlock, signer *darc.Signer) (*DisclosePubliclyReply, error) {
+		sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *signer.Identity(), darc.User)
+		sig, err := darc.NewDarcSignature(DiscloseMessage(sb.Hash), sigPath, signer)
+		require.Nil(t, err)
+		return o.service.DisclosePublicly(&DisclosePublicly{
+			OCS:        o.sc.OCS.Hash,
+			Disclosure: Disclosure{DataID: sb.Hash, Signature: *sig},
+		})
+	}
+
+	embargo := writePolicy(&AccessPolicy{NotBefore: time.Now().Unix() + 3600})
+	_, err := disclose(embargo, o.writer)
+	require.NotNil(t, err)
+
+	doc := writePolicy(nil)
+	_, err = disclose(doc, darc.NewSignerEd25519(nil, nil))
+	require.NotNil(t, err)
+	reply, err := disclose(doc, o.writer)
+	require.Nil(t, err)
+
+	// Recover the key from the blocks only.
+	key := NewOCS(reply.SB.Data).DisclosedKey
+	require.NotNil(t, key)
+	sym, err := RecoverDisclosedKey(NewOCS(doc.Data).Write, key)
+	require.Nil(t, err)
+	require.Equal(t, []byte{1, 2, 3}, sym)
+
+	// Disclosing again returns the stored shares.
+	again, err := disclose(doc, o.writer)
+	require.Nil(t, err)
+	require.True(t, again.SB.Hash.Equal(reply.SB.Hash))
+
+	// A disclosure without shares, e.g. after a failed decryption, is reused.
+	pending := writePolicy(nil)
+	sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writer.Identity(), darc.User)
+	sig, err := darc.NewDarcSignature(DiscloseMessage(pending.Hash), sigPath, o.writer)
+	require.Nil(t, err)
+	disclosureSB, err := o.service.addTransaction(pending, &Transaction{
+		Disclosure: &Disclosure{DataID: pending.Hash, Signature: *sig},
+		Timestamp:  time.Now().Unix(),
+	})
+	require.Nil(t, err)
+	reply, err = disclose(pending, o.writer)
+	require.Nil(t, err)
+	require.Equal(t, disclosureSB.Index+1, reply.SB.Index)
+	require.True(t, NewOCS(reply.SB.Data).DisclosedKey.DisclosureID.Equal(disclosureSB.Hash))
+
+	// A wrong share cannot be used to recover the key.
+	key.Shares[0].Ui = cothority.Suite.Point().Add(key.Shares[0].Ui,
+		cothority.Suite.Point().Base())
+	_, err = RecoverDisclosedKey(NewOCS(doc.Data).Write, key)
+	require.NotNil(t, err)
+}
+
 func TestService_GetDarcPath(t *testing.T) {
 	o := createOCS(t)
 	defer o.local.CloseAll()
@@ -316,3 +859,46 @@ func createOCS(t *testing.T) *ocsStruct {
 	require.Nil(t, err)
 	return o
 }
+
+// newWrite returns a write of the symmetric key {1, 2, 3} for o.readers
+// without data.
+func (o *ocsStruct) newWrite() *Write {
+	write := NewWrite(cothority.Suite, o.sc.OCS.Hash, o.sc.X, o.readers, []byte{1, 2, 3})
+	write.Data = []byte{}
+	return write
+}
+
+// storeWrite signs the write with o.writer and stores it.
+func (o *ocsStruct) storeWrite(t *testing.T, write *Write) (*skipchain.SkipBlock, error) {
+	return o.storeWriteBy(t, write, o.writer, []*darc.Darc{o.readers})
+}
+
+// storeWriteBy signs the write with the signer, using the darcs in path,
+// and stores it.
+func (o *ocsStruct) storeWriteBy(t *testing.T, write *Write, signer *darc.Signer,
+	path []*darc.Darc) (*skipchain.SkipBlock, error) {
+	sigPath := darc.NewSignaturePath(path, *signer.Identity(), darc.User)
+	sig, err := darc.NewDarcSignature(write.SignatureMessage(), sigPath, signer)
+	require.Nil(t, err)
+	wr, err := o.service.WriteRequest(&WriteRequest{
+		OCS:       o.sc.OCS.Hash,
+		Write:     *write,
+		Signature: *sig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wr.SB, nil
+}
+
+// readWrite asks to read the write stored in sb with a signature of
+// o.writer.
+func (o *ocsStruct) readWrite(t *testing.T, sb *skipchain.SkipBlock) (*ReadReply, error) {
+	sigPath := darc.NewSignaturePath([]*darc.Darc{o.readers}, *o.writerI, darc.User)
+	sig, err := darc.NewDarcSignature(sb.Hash, sigPath, o.writer)
+	require.Nil(t, err)
+	return o.service.ReadRequest(&ReadRequest{
+		OCS:  o.sc.OCS.Hash,
+		Read: Read{DataID: sb.Hash, Signature: *sig},
+	})
+}
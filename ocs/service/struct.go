@@ -6,11 +6,14 @@ This holds the messages used to communicate with the service over the network.
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/dedis/cothority"
 	"github.com/dedis/cothority/ocs/darc"
+	"github.com/dedis/cothority/ocs/protocol"
 	"github.com/dedis/cothority/skipchain"
 	"github.com/dedis/kyber"
 	"github.com/dedis/kyber/suites"
@@ -29,13 +32,22 @@ type suite interface {
 // We need to register all messages so the network knows how to handle them.
 func init() {
 	network.RegisterMessages(
-		Transaction{}, Write{}, Read{},
+		Transaction{}, Write{}, Read{}, AccessPolicy{},
 		CreateSkipchainsRequest{}, CreateSkipchainsReply{},
 		WriteRequest{}, WriteReply{},
 		ReadRequest{}, ReadReply{},
 		SharedPublicRequest{}, SharedPublicReply{},
 		DecryptKeyRequest{}, DecryptKeyReply{},
-		GetReadRequests{}, GetReadRequestsReply{})
+		GetReadRequests{}, GetReadRequestsReply{},
+		ReshareRequest{}, ReshareReply{},
+		GetIdentityDarcs{}, GetIdentityDarcsReply{},
+		RevokeIdentity{}, RevokeIdentityReply{},
+		GetRevocations{}, GetRevocationsReply{},
+		ListWrites{}, ListWritesReply{},
+		ListReads{}, ListReadsReply{},
+		DataChunk{}, GetDataChunk{}, GetDataChunkReply{},
+		StoreDataChunk{}, StoreDataChunkReply{},
+		DisclosePublicly{}, DisclosePubliclyReply{})
 }
 
 // ServiceName is used for registration on the onet.
@@ -97,6 +109,10 @@ func (dw *Transaction) String() string {
 	if dw.Read != nil {
 		str += fmt.Sprintf("Read: %+v read data %x\n", dw.Read.Signature.SignaturePath.Signer, dw.Read.DataID)
 	}
+	if dw.Revocation != nil {
+		str += fmt.Sprintf("Revocation: %s from %d darcs\n", dw.Revocation.Identity.String(),
+			len(dw.Revocation.Darcs))
+	}
 	return str
 }
 
@@ -185,6 +201,85 @@ func (wr *Write) CheckProof(suite suite, scid skipchain.SkipBlockID) error {
 	return errors.New("recreated proof is not equal to stored proof")
 }
 
+// SignatureMessage returns the message the writer has to sign for this
+// write-request.
+func (wr *Write) SignatureMessage() []byte {
+	return ChunksMessage(&wr.Reader, wr.Policy, wr.Supersedes, wr.Chunks)
+}
+
+// WriteMessage returns the message a writer signs for a write-request with
+// the given reader-darc and access policy: the ID of the reader-darc,
+// followed by the hash of the policy if there is one.
+func WriteMessage(reader *darc.Darc, policy *AccessPolicy) []byte {
+	msg := []byte(reader.GetID())
+	if policy != nil {
+		msg = append(msg, policy.Hash()...)
+	}
+	return msg
+}
+
+// SupersedeMessage returns the message a writer signs for a write-request
+// that replaces an older write: the WriteMessage followed by the ID of the
+// older write. If supersedes is nil, it is the same as WriteMessage.
+func SupersedeMessage(reader *darc.Darc, policy *AccessPolicy, supersedes skipchain.SkipBlockID) []byte {
+	return append(WriteMessage(reader, policy), supersedes...)
+}
+
+// ChunksMessage returns the message a writer signs for a write-request with
+// off-chain data: the SupersedeMessage followed by "chunks" and the hashes
+// of the chunks, as returned by SplitChunks. If there are no chunks, it is
+// the same as SupersedeMessage.
+func ChunksMessage(reader *darc.Darc, policy *AccessPolicy, supersedes skipchain.SkipBlockID,
+	hashes [][]byte) []byte {
+	msg := SupersedeMessage(reader, policy, supersedes)
+	if len(hashes) == 0 {
+		return msg
+	}
+	msg = append(msg, []byte("chunks")...)
+	for _, h := range hashes {
+		msg = append(msg, h...)
+	}
+	return msg
+}
+
+// DiscloseMessage returns the message that needs to be signed to disclose
+// the document with the given id to the public.
+func DiscloseMessage(dataID skipchain.SkipBlockID) []byte {
+	return append([]byte("disclose"), dataID...)
+}
+
+// Hash returns the sha256 of the fields of the policy.
+func (p *AccessPolicy) Hash() []byte {
+	h := sha256.New()
+	for _, i := range []int64{p.NotBefore, p.NotAfter, int64(p.MaxReads)} {
+		binary.Write(h, binary.LittleEndian, i)
+	}
+	return h.Sum(nil)
+}
+
+// Check returns an error if the policy can never be fulfilled.
+func (p *AccessPolicy) Check() error {
+	if p.MaxReads < 0 {
+		return errors.New("negative number of reads")
+	}
+	if p.NotAfter != 0 && p.NotAfter < p.NotBefore {
+		return errors.New("policy ends before it starts")
+	}
+	return nil
+}
+
+// CheckTime returns an error if the unix timestamp is outside of the time
+// window of the policy.
+func (p *AccessPolicy) CheckTime(timestamp int64) error {
+	if p.NotBefore != 0 && timestamp < p.NotBefore {
+		return fmt.Errorf("read-request at %d is before %d", timestamp, p.NotBefore)
+	}
+	if p.NotAfter != 0 && timestamp > p.NotAfter {
+		return fmt.Errorf("read-request at %d is after %d", timestamp, p.NotAfter)
+	}
+	return nil
+}
+
 // DecodeKey can be used by the reader of an onchain-secret to convert the
 // re-encrypted secret back to a symmetric key that can be used later to
 // decode the document.
@@ -262,6 +357,45 @@ type Transaction struct {
 	Meta *[]byte
 	// Unix timestamp to record the transaction creation time
 	Timestamp int64
+	// Revocation removes an identity from all darcs
+	Revocation *Revocation
+	// Disclosure approves the public disclosure of a document
+	Disclosure *Disclosure
+	// DisclosedKey holds the decryption shares of a disclosed document
+	DisclosedKey *DisclosedKey
+}
+
+// Revocation removes an identity from all darcs stored in the OCS-service.
+// Darcs holds a new version of every latest darc that contained the
+// identity, signed by an owner other than the revoked identity.
+type Revocation struct {
+	// Identity is the revoked identity
+	Identity darc.Identity
+	// Darcs are the new versions of the darcs without the identity
+	Darcs []*darc.Darc
+}
+
+// Disclosure approves the public disclosure of a document. The signature is
+// on DiscloseMessage(DataID) and must fulfill the ocs:disclose rule of the
+// reader-darc of the write.
+type Disclosure struct {
+	// DataID is the document-id of the disclosed document
+	DataID skipchain.SkipBlockID
+	// Signature approves the disclosure
+	Signature darc.Signature
+}
+
+// DisclosedKey holds the verified decryption shares of the symmetric key of
+// a disclosed document, together with the public commitments of the shared
+// secret, so that anyone can verify the shares and recover the key.
+type DisclosedKey struct {
+	// DisclosureID is the id of the block holding the disclosure
+	DisclosureID skipchain.SkipBlockID
+	// Commits are the commitments of the public polynomial of the shared
+	// secret
+	Commits []kyber.Point
+	// Shares are the decryption shares with their DLEQ-proofs
+	Shares []*protocol.DecryptShare
 }
 
 // Write stores the data and the encrypted secret
@@ -291,6 +425,26 @@ type Write struct {
 	// skipchain. For backwards-compatibility, this is an optional field.
 	// But for every new write-request, it must be set.
 	Signature *darc.Signature
+	// Policy restricts when and how often the document can be read. If it
+	// is nil, every valid reader can read the document at any time.
+	Policy *AccessPolicy
+	// Chunks holds the sha256-hashes of the chunks of the encrypted data,
+	// if the data is stored off-chain. Data must be empty in this case.
+	Chunks [][]byte
+	// Supersedes is the ID of an older write of the same writer that is
+	// replaced by this write. The chunks of the older write are removed.
+	Supersedes skipchain.SkipBlockID
+}
+
+// AccessPolicy restricts the read-requests of a document. Zero values mean
+// that there is no restriction.
+type AccessPolicy struct {
+	// NotBefore is the earliest unix timestamp of a read-request.
+	NotBefore int64
+	// NotAfter is the latest unix timestamp of a read-request.
+	NotAfter int64
+	// MaxReads is the maximum number of read-requests per reader.
+	MaxReads int
 }
 
 // Read stores a read-request which is the secret encrypted under the
@@ -311,6 +465,28 @@ type ReadDoc struct {
 	DataID skipchain.SkipBlockID
 }
 
+// WriteDoc represents one write-request in the index of the service.
+type WriteDoc struct {
+	WriteID    skipchain.SkipBlockID
+	Index      int
+	ReaderDarc darc.ID
+	Writer     darc.Identity
+	ExtraData  []byte
+	Timestamp  int64
+	// Chunks are the hashes of the off-chain chunks of the write
+	Chunks [][]byte
+	// SupersededBy is the ID of the write replacing this write, or nil
+	SupersededBy skipchain.SkipBlockID
+}
+
+// RevocationDoc represents one revocation of an identity.
+type RevocationDoc struct {
+	Identity     darc.Identity
+	RevocationID skipchain.SkipBlockID
+	Darcs        []darc.ID
+	Timestamp    int64
+}
+
 // ***
 // Requests and replies to/from the service
 // ***
@@ -446,3 +622,161 @@ type GetLatestDarc struct {
 type GetLatestDarcReply struct {
 	Darcs *[]*darc.Darc
 }
+
+// ReshareRequest asks the leader of the OCS-skipchain to move the shared
+// secret to a new roster and threshold. A Threshold of 0 uses the default
+// threshold for the size of the roster. The Signature must be from an
+// owner of the admin-darc on the message returned by Message.
+type ReshareRequest struct {
+	OCS       skipchain.SkipBlockID
+	Roster    onet.Roster
+	Threshold int
+	Signature darc.Signature
+}
+
+// Message returns the message that needs to be signed by an owner of the
+// admin-darc.
+func (rr *ReshareRequest) Message() []byte {
+	msg := append([]byte("reshare:"), rr.OCS...)
+	for _, si := range rr.Roster.List {
+		msg = append(msg, si.ID[:]...)
+	}
+	return append(msg, []byte(strconv.Itoa(rr.Threshold))...)
+}
+
+// ReshareReply returns the latest block of the OCS-skipchain, which holds
+// the new roster.
+type ReshareReply struct {
+	OCS *skipchain.SkipBlock
+}
+
+// GetIdentityDarcs asks for the latest version of all darcs that hold the
+// identity.
+type GetIdentityDarcs struct {
+	Identity darc.Identity
+}
+
+// GetIdentityDarcsReply returns the latest version of all darcs that hold
+// the identity.
+type GetIdentityDarcsReply struct {
+	Darcs []*darc.Darc
+}
+
+// RevokeIdentity asks to remove the identity from all darcs. Darcs must hold
+// a new version of every darc returned by GetIdentityDarcs, without the
+// identity and signed by another owner.
+type RevokeIdentity struct {
+	OCS      skipchain.SkipBlockID
+	Identity darc.Identity
+	Darcs    []*darc.Darc
+}
+
+// RevokeIdentityReply returns the block holding the revocation.
+type RevokeIdentityReply struct {
+	SB *skipchain.SkipBlock
+}
+
+// GetRevocations asks for a list of revocations, starting at the block
+// Start. If Count is 0, all revocations are returned.
+type GetRevocations struct {
+	Start skipchain.SkipBlockID
+	Count int
+}
+
+// GetRevocationsReply returns the revocations.
+type GetRevocationsReply struct {
+	Revocations []*RevocationDoc
+}
+
+// QueryFilter restricts the documents returned by ListWrites and ListReads.
+// Empty fields match all documents.
+type QueryFilter struct {
+	// ReaderDarc is the base ID of the reader-darc of the write
+	ReaderDarc darc.ID
+	// Writer is the identity that signed the write
+	Writer *darc.Identity
+	// Since is the earliest timestamp of the transaction
+	Since int64
+	// Until is the latest timestamp of the transaction
+	Until int64
+	// ExtraData is a prefix of the ExtraData of the write
+	ExtraData []byte
+}
+
+// ListWrites asks for the write-requests of an OCS-skipchain that match the
+// filter, in the order of the skipchain. The list starts after the write
+// given in Cursor, or at the beginning if Cursor is nil. At most Count
+// writes are returned, or all if Count is 0.
+type ListWrites struct {
+	OCS    skipchain.SkipBlockID
+	Filter QueryFilter
+	Cursor skipchain.SkipBlockID
+	Count  int
+}
+
+// ListWritesReply returns the matching writes. If there are more writes,
+// Cursor is set and can be used to get the next writes.
+type ListWritesReply struct {
+	Writes []*WriteDoc
+	Cursor skipchain.SkipBlockID
+}
+
+// ListReads asks for the read-requests of an OCS-skipchain. The timestamp
+// of the read must be in the range of the filter, and its write must match
+// all other fields of the filter. Cursor and Count work like in ListWrites.
+type ListReads struct {
+	OCS    skipchain.SkipBlockID
+	Filter QueryFilter
+	Cursor skipchain.SkipBlockID
+	Count  int
+}
+
+// ListReadsReply returns the matching reads. If there are more reads,
+// Cursor is set and can be used to get the next reads.
+type ListReadsReply struct {
+	Reads  []*ReadDoc
+	Cursor skipchain.SkipBlockID
+}
+
+// DataChunk is a chunk of encrypted data of the write stored in the block
+// DataID. It is sent to all trustees once the write is stored.
+type DataChunk struct {
+	DataID skipchain.SkipBlockID
+	Data   []byte
+}
+
+// StoreDataChunk sends a chunk of the off-chain data of a write that is
+// already stored on the skipchain.
+type StoreDataChunk struct {
+	DataID skipchain.SkipBlockID
+	Chunk  []byte
+}
+
+// StoreDataChunkReply is returned once the chunk is sent to all trustees.
+type StoreDataChunkReply struct {
+}
+
+// GetDataChunk asks for a chunk of the off-chain data of a write.
+type GetDataChunk struct {
+	DataID skipchain.SkipBlockID
+	Index  int
+}
+
+// GetDataChunkReply returns the chunk.
+type GetDataChunkReply struct {
+	Chunk []byte
+}
+
+// DisclosePublicly asks the OCS-skipchain to disclose a document to the
+// public. Once the disclosure is stored, the nodes run a threshold
+// decryption of the symmetric key of the document.
+type DisclosePublicly struct {
+	OCS        skipchain.SkipBlockID
+	Disclosure Disclosure
+}
+
+// DisclosePubliclyReply returns the block holding the decryption shares of
+// the symmetric key.
+type DisclosePubliclyReply struct {
+	SB *skipchain.SkipBlock
+}
@@ -21,9 +21,36 @@ main(){
     #test Write
     #test Read
     test SCRead
+    test Export
+    test Darc
     stopTest
 }
 
+testDarc(){
+    setupOCS
+    testFail runCl 1 manage create public.toml $READER1_PRIV
+    runGrepSed "Admin-darc" "s/.* //" runCl 1 manage create --admin $READER1_PRIV public.toml
+    DARC=$SED
+    testGrep $READER1_PUB runCl 1 darc show $DARC
+    testFail runCl 1 darc add-user $DARC $READER2_PUB $READER2_PRIV
+    testOK runCl 1 darc add-user $DARC $READER2_PUB $READER1_PRIV
+    testGrep "Version: 1" runCl 1 darc show $DARC
+    testGrep $READER2_PUB runCl 1 darc show $DARC
+    testOK runCl 1 darc rm-user $DARC $READER2_PUB $READER1_PRIV
+    testNGrep $READER2_PUB runCl 1 darc show $DARC
+    testFail runCl 1 darc rm-user $DARC $READER2_PUB $READER1_PRIV
+    testOK runCl 1 darc evolve --desc new --owner $READER2_PUB $DARC $READER1_PRIV
+    testGrep "Description: new" runCl 1 darc show $DARC
+    testOK runCl 1 darc evolve --rm-owner $READER1_PUB $DARC $READER2_PRIV
+    testFail runCl 1 darc evolve $DARC $READER1_PRIV
+}
+
+testExport(){
+    setupOCS
+    testGrep $SID runCl 1 manage export
+    testOK runCl 1 manage export --qr
+}
+
 testSCRead(){
 	setupOCS
 	testGrep last runCl 1 skipchain
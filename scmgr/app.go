@@ -405,6 +405,116 @@ func scPrint(c *cli.Context) error {
 	return nil
 }
 
+// Asks all conodes of a skipchain for evidence of forks
+func scForks(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("Please give a skipchain-id")
+	}
+	cfg := getConfigOrFail(c)
+	sb, err := cfg.Db.GetFuzzy(c.Args().First())
+	if err != nil {
+		return err
+	}
+	if sb == nil {
+		return errors.New("didn't find this skipchain")
+	}
+	guc, err := skipchain.NewClient().GetUpdateChain(sb.Roster, sb.SkipChainID())
+	if err != nil {
+		return err
+	}
+	roster := sb.Roster
+	if len(guc.Update) > 0 {
+		roster = guc.Update[len(guc.Update)-1].Roster
+	}
+	var forks int
+	for _, si := range roster.List {
+		evidence, err := skipchain.NewClient().GetForkEvidence(si, sb.SkipChainID())
+		if err != nil {
+			log.Warnf("Couldn't get evidence from %s: %s", si.Address, err)
+			continue
+		}
+		for _, fe := range evidence {
+			forks++
+			log.Infof("Fork at index %d reported by %s:", fe.Index, fe.Reporter.Address)
+			log.Infof("  Block %x -> %x", fe.Links[0].From, fe.Links[0].To)
+			log.Infof("  Block %x -> %x", fe.Links[1].From, fe.Links[1].To)
+			for _, culprit := range fe.Culprits {
+				log.Infof("  Signed both: %s", culprit)
+			}
+		}
+	}
+	if forks == 0 {
+		log.Infof("No forks reported for skipchain %x", sb.SkipChainID())
+	}
+	return nil
+}
+
+// Exports all blocks of a skipchain to an archive
+func scExport(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("Please give a skipchain-id")
+	}
+	cfg := getConfigOrFail(c)
+	sb, err := cfg.Db.GetFuzzy(c.Args().First())
+	if err != nil {
+		return err
+	}
+	if sb == nil {
+		return errors.New("didn't find this skipchain")
+	}
+	guc, err := skipchain.NewClient().GetUpdateChain(sb.Roster, sb.SkipChainID())
+	if err != nil {
+		return err
+	}
+	roster := sb.Roster
+	if len(guc.Update) > 0 {
+		roster = guc.Update[len(guc.Update)-1].Roster
+	}
+	var archive []byte
+	for _, si := range roster.List {
+		archive, err = skipchain.NewClient().ExportSkipchain(si, sb.SkipChainID())
+		if err == nil {
+			break
+		}
+		log.Warnf("Couldn't export from %s: %s", si.Address, err)
+	}
+	if archive == nil {
+		return errors.New("no conode could export the skipchain")
+	}
+	if out := c.String("output"); out != "" {
+		if err := ioutil.WriteFile(out, archive, 0660); err != nil {
+			return err
+		}
+		log.Infof("Exported skipchain %x to %s", sb.SkipChainID(), out)
+		return nil
+	}
+	_, err = os.Stdout.Write(archive)
+	return err
+}
+
+// Imports all blocks of an archive into a linked conode
+func scImport(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return errors.New("please give: archive-file ip:port")
+	}
+	cfg := getConfigOrFail(c)
+	archive, err := ioutil.ReadFile(c.Args().First())
+	if err != nil {
+		return err
+	}
+	link, err := findLinkFromAddress(cfg, c.Args().Get(1))
+	if err != nil {
+		return errors.New("couldn't parse node-address or not linked yet: " + err.Error())
+	}
+	reply, err := skipchain.NewClient().ImportSkipchain(link.Conode, link.Private, archive)
+	if err != nil {
+		return errors.New("couldn't import archive: " + err.Error())
+	}
+	cfg.Db.Store(reply.Latest)
+	log.Infof("Imported skipchain %x up to block %d", reply.GenesisID, reply.Latest.Index)
+	return cfg.save(c)
+}
+
 // Joins a given skipchain
 func dnsFetch(c *cli.Context) error {
 	if c.NArg() != 2 {
@@ -713,7 +823,7 @@ func loadConfig(c *cli.Context) (*config, error) {
 		return nil, err
 	}
 	cfg.Db = skipchain.NewSkipBlockDB(db, bucketName)
-	err = cfg.Db.View(func(tx *bolt.Tx) error {
+	err = cfg.Db.View(func(tx skipchain.StorageTx) error {
 		b := tx.Bucket([]byte("config"))
 		v := b.Get([]byte("values"))
 		if v != nil {
@@ -739,7 +849,7 @@ func (cfg *config) save(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	err = cfg.Db.Update(func(tx *bolt.Tx) error {
+	err = cfg.Db.Update(func(tx skipchain.StorageTx) error {
 		b := tx.Bucket([]byte("config"))
 		err := b.Put([]byte("values"), buf)
 		return err
@@ -149,6 +149,33 @@ func getCommands() cli.Commands {
 						},
 					},
 				},
+				{
+					Name:      "forks",
+					Usage:     "ask all conodes of a skipchain for evidence of forks",
+					Aliases:   []string{"f"},
+					ArgsUsage: "skipchain-id",
+					Action:    scForks,
+				},
+				{
+					Name:      "export",
+					Usage:     "write all blocks of a skipchain to an archive",
+					Aliases:   []string{"e"},
+					ArgsUsage: "skipchain-id",
+					Action:    scExport,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "file to write the archive to, default is stdout",
+						},
+					},
+				},
+				{
+					Name:      "import",
+					Usage:     "verify and store all blocks of an archive on a linked conode",
+					Aliases:   []string{"i"},
+					ArgsUsage: "archive-file ip:port",
+					Action:    scImport,
+				},
 			},
 		},
 
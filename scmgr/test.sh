@@ -17,6 +17,8 @@ main(){
 	test Create
 	test Join
 	test Add
+	test Forks
+	test ExportImport
 	test Index
 	test Fetch
 	test Link
@@ -192,6 +194,28 @@ testAdd(){
 	testOK runSc skipchain block add --roster public.toml $ID
 }
 
+testForks(){
+	startCl
+	setupGenesis
+	testFail runSc skipchain forks 1234
+	testOK runSc skipchain forks $ID
+	testGrep "No forks" runSc skipchain forks $ID
+}
+
+testExportImport(){
+	startCl
+	setupGenesis
+	testOK runSc skipchain block add --data "block 1" $ID
+	testFail runSc skipchain export 1234
+	testOK runSc skipchain export -o archive.bin $ID
+	testOK [ -s archive.bin ]
+	testFail runSc skipchain import archive.bin localhost:2002
+	testOK runSc link add co1/private.toml
+	testFail runSc skipchain import public.toml localhost:2002
+	testGrep "up to block 1" runSc skipchain import archive.bin localhost:2002
+	rm -f archive.bin
+}
+
 setupFour(){
 	rm -f public.toml
 	for n in $( seq 4 ); do
@@ -1,9 +1,11 @@
 package skipchain
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/dedis/cothority"
 	status "github.com/dedis/cothority/status/service"
@@ -188,6 +190,7 @@ func (c *Client) GetUpdateChain(roster *onet.Roster, latest SkipBlockID) (reply
 	const retries = 3
 
 	reply = &GetUpdateChainReply{}
+	redirected := false
 	for {
 		r2 := &GetUpdateChainReply{}
 
@@ -198,7 +201,7 @@ func (c *Client) GetUpdateChain(roster *onet.Roster, latest SkipBlockID) (reply
 			// To handle the case where len(perm) < retries.
 			which := i % len(perm)
 			err = c.SendProtobuf(roster.List[perm[which]], &GetUpdateChain{LatestID: latest}, r2)
-			if err == nil && len(r2.Update) != 0 {
+			if err == nil && (len(r2.Update) != 0 || r2.Pruned != nil) {
 				break
 			}
 		}
@@ -206,6 +209,18 @@ func (c *Client) GetUpdateChain(roster *onet.Roster, latest SkipBlockID) (reply
 			return nil, fmt.Errorf("too many retries; last error: %v", err)
 		}
 
+		// If the conode pruned the blocks we need and didn't make any
+		// progress, continue with the archive nodes.
+		if r2.Pruned != nil && len(r2.Update) <= 1 {
+			if redirected || len(r2.Pruned.Archive) == 0 {
+				return nil, errors.New("skipchain has been pruned and no archive node has the missing blocks")
+			}
+			redirected = true
+			roster = onet.NewRoster(r2.Pruned.Archive)
+			continue
+		}
+		redirected = false
+
 		// Does this chain start where we expect it to?
 		if !r2.Update[0].Hash.Equal(latest) {
 			return nil, errors.New("first returned block does not match requested hash")
@@ -246,6 +261,9 @@ func (c *Client) GetUpdateChain(roster *onet.Roster, latest SkipBlockID) (reply
 		// to continue following the chain.
 		latest = last.Hash
 		roster = last.Roster
+		if r2.Pruned != nil && len(r2.Pruned.Archive) > 0 {
+			roster = onet.NewRoster(r2.Pruned.Archive)
+		}
 	}
 }
 
@@ -276,6 +294,43 @@ func (c *Client) GetSingleBlockByIndex(roster *onet.Roster, genesis SkipBlockID,
 	return
 }
 
+// GetProof returns the shortest chain of blocks going from the genesis-block
+// to the block with the given id. The proof is not verified - the caller has
+// to call Proof.Verify with the genesis-id it trusts.
+func (c *Client) GetProof(roster *onet.Roster, id SkipBlockID) (Proof, error) {
+	reply := &GetProofReply{}
+	err := c.SendProtobuf(roster.RandomServerIdentity(), &GetProof{ID: id}, reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Proof, nil
+}
+
+// StreamSkipBlocks subscribes to the skipchain given by genesis and calls
+// f for every stored block, starting at index from, and then for every new
+// block and every block that gets a new forward-link. It returns when f
+// returns false or when the connection is closed. To resume after a
+// disconnection, call it again with the index of the last received block.
+func (c *Client) StreamSkipBlocks(si *network.ServerIdentity, genesis SkipBlockID,
+	from int, f func(*SkipBlock) bool) error {
+	conn, err := c.Stream(si, &StreamSkipBlocks{GenesisID: genesis, FromIndex: from})
+	if err != nil {
+		return err
+	}
+	for {
+		reply := &StreamSkipBlocksReply{}
+		if err := conn.ReadMessage(reply); err != nil {
+			return err
+		}
+		if err := reply.Block.VerifyForwardSignatures(); err != nil {
+			return err
+		}
+		if !f(reply.Block) {
+			return nil
+		}
+	}
+}
+
 // CreateLinkPrivate asks the conode to create a link by sending a public
 // key of the client, signed by the private key of the conode. The reasoning is
 // that an administrator should well be able to copy the private.toml-file from
@@ -373,6 +428,153 @@ func (c *Client) DelFollow(si *network.ServerIdentity, clientPriv kyber.Scalar,
 	return c.SendProtobuf(si, &DelFollow{SkipchainID: scid, Signature: sig}, nil)
 }
 
+// SetPruning asks the conode to only keep the latest keep blocks of the
+// skipchain, besides the genesis-block. Requests for older blocks are
+// redirected to the archive nodes. If keep is 0, pruning is disabled for
+// this skipchain.
+func (c *Client) SetPruning(si *network.ServerIdentity, clientPriv kyber.Scalar,
+	scid SkipBlockID, keep int, archive []*network.ServerIdentity) error {
+	req := &SetPruning{
+		SkipchainID: scid,
+		Keep:        keep,
+		Archive:     archive,
+	}
+	sig, err := schnorr.Sign(cothority.Suite, clientPriv, req.message())
+	if err != nil {
+		return err
+	}
+	req.Signature = sig
+	return c.SendProtobuf(si, req, &EmptyReply{})
+}
+
+// ProposeRosterChange asks the leader of the latest block to add the conodes
+// in add to the roster of the skipchain and to remove the conodes in remove.
+// The new block holds data. The change is refused if latest is not the
+// latest block of the skipchain anymore. If priv is not nil, the request is
+// signed with it. The reply holds the new block.
+func (c *Client) ProposeRosterChange(latest *SkipBlock, add, remove []*network.ServerIdentity,
+	data []byte, priv kyber.Scalar) (*StoreSkipBlockReply, error) {
+	req := &ProposeRosterChange{
+		SkipchainID: latest.SkipChainID(),
+		Latest:      latest.Hash,
+		Add:         add,
+		Remove:      remove,
+		Data:        data,
+	}
+	if priv != nil {
+		sig, err := schnorr.Sign(cothority.Suite, priv, req.message())
+		if err != nil {
+			return nil, err
+		}
+		req.Signature = &sig
+	}
+	reply := &StoreSkipBlockReply{}
+	err := c.SendProtobuf(latest.Roster.Get(0), req, reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// StoreBatchedPayload sends the payload to the leader of the latest block,
+// which stores it together with the payloads of other clients in the next
+// block of the skipchain. The payload is refused if latest is not the latest
+// block of the skipchain anymore. If priv is not nil, the request is signed
+// with it. The returned receipt is verified to hold the payload.
+func (c *Client) StoreBatchedPayload(latest *SkipBlock, payload []byte,
+	priv kyber.Scalar) (*PayloadReceipt, error) {
+	req := &StoreBatchedPayload{
+		SkipchainID: latest.SkipChainID(),
+		Latest:      latest.Hash,
+		Payload:     payload,
+	}
+	if priv != nil {
+		sig, err := schnorr.Sign(cothority.Suite, priv, req.message())
+		if err != nil {
+			return nil, err
+		}
+		req.Signature = &sig
+	}
+	reply := &StoreBatchedPayloadReply{}
+	err := c.SendProtobuf(latest.Roster.Get(0), req, reply)
+	if err != nil {
+		return nil, err
+	}
+	if err := reply.Receipt.Verify(payload); err != nil {
+		return nil, errors.New("got invalid receipt: " + err.Error())
+	}
+	return reply.Receipt, nil
+}
+
+// SetBatching asks the conode to collect the payloads sent with
+// StoreBatchedPayload during interval before storing them in a new block of
+// the skipchain. An interval of 0 disables batching.
+func (c *Client) SetBatching(si *network.ServerIdentity, clientPriv kyber.Scalar,
+	scid SkipBlockID, interval time.Duration) error {
+	req := &SetBatching{
+		SkipchainID: scid,
+		IntervalMS:  int(interval / time.Millisecond),
+	}
+	sig, err := schnorr.Sign(cothority.Suite, clientPriv, req.message())
+	if err != nil {
+		return err
+	}
+	req.Signature = sig
+	return c.SendProtobuf(si, req, &EmptyReply{})
+}
+
+// GetForkEvidence returns all evidence of forks of the skipchain that the
+// conode detected. If scid is empty, the evidence of all skipchains is
+// returned. Every evidence is verified before it is returned.
+func (c *Client) GetForkEvidence(si *network.ServerIdentity, scid SkipBlockID) ([]*ForkEvidence, error) {
+	reply := &GetForkEvidenceReply{}
+	err := c.SendProtobuf(si, &GetForkEvidence{SkipchainID: scid}, reply)
+	if err != nil {
+		return nil, err
+	}
+	for _, fe := range reply.Evidence {
+		if err := fe.Verify(); err != nil {
+			return nil, errors.New("got invalid evidence: " + err.Error())
+		}
+	}
+	return reply.Evidence, nil
+}
+
+// ExportSkipchain returns an archive of all blocks of the skipchain. The
+// archive is verified before it is returned.
+func (c *Client) ExportSkipchain(si *network.ServerIdentity, scid SkipBlockID) ([]byte, error) {
+	reply := &ExportSkipchainReply{}
+	err := c.SendProtobuf(si, &ExportSkipchain{SkipchainID: scid}, reply)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := ReadArchive(bytes.NewReader(reply.Archive))
+	if err != nil {
+		return nil, errors.New("got invalid archive: " + err.Error())
+	}
+	if !blocks[0].Hash.Equal(scid) {
+		return nil, errors.New("got archive of another skipchain")
+	}
+	return reply.Archive, nil
+}
+
+// ImportSkipchain sends an archive to the conode, which verifies and stores
+// all blocks. The clientPriv must be linked to the conode.
+func (c *Client) ImportSkipchain(si *network.ServerIdentity, clientPriv kyber.Scalar,
+	archive []byte) (*ImportSkipchainReply, error) {
+	req := &ImportSkipchain{Archive: archive}
+	sig, err := schnorr.Sign(cothority.Suite, clientPriv, req.message())
+	if err != nil {
+		return nil, err
+	}
+	req.Signature = sig
+	reply := &ImportSkipchainReply{}
+	if err := c.SendProtobuf(si, req, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 // ListFollow returns the list of latest skipblock of all skipchains that are followed
 // for authentication purposes.
 func (c *Client) ListFollow(si *network.ServerIdentity, clientPriv kyber.Scalar) (*ListFollowReply, error) {
@@ -0,0 +1,247 @@
+package skipchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
+	"github.com/dedis/protobuf"
+)
+
+/*
+This file holds the archive format used to export and import complete
+skipchains. An archive is a sequence of records, each one made of a 4-byte
+big-endian length followed by that many bytes of protobuf. The first record
+is an ArchiveHeader, followed by one record per SkipBlock, sorted by index.
+*/
+
+// ArchiveVersion is the version of the archives written by WriteArchive.
+const ArchiveVersion = 1
+
+// maxArchiveRecord is the maximum size of one record in an archive.
+const maxArchiveRecord = 64 * 1024 * 1024
+
+// ArchiveHeader is the first record of an archive.
+type ArchiveHeader struct {
+	// Version of the archive format.
+	Version int
+	// GenesisID of the archived skipchain.
+	GenesisID SkipBlockID
+	// Length is the number of blocks in the archive.
+	Length int
+}
+
+// WriteArchive writes all blocks of a skipchain, sorted by index and
+// starting with the genesis-block, as an archive to w.
+func WriteArchive(w io.Writer, blocks []*SkipBlock) error {
+	if len(blocks) == 0 || blocks[0].Index != 0 {
+		return errors.New("archive needs to start with the genesis-block")
+	}
+	if err := writeRecord(w, &ArchiveHeader{
+		Version:   ArchiveVersion,
+		GenesisID: blocks[0].Hash,
+		Length:    len(blocks),
+	}); err != nil {
+		return err
+	}
+	for _, sb := range blocks {
+		if err := writeRecord(w, sb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadArchive reads the blocks of an archive and verifies that they form a
+// valid skipchain using VerifyChain.
+func ReadArchive(r io.Reader) ([]*SkipBlock, error) {
+	header := &ArchiveHeader{}
+	if err := readRecord(r, header); err != nil {
+		return nil, errors.New("couldn't read header: " + err.Error())
+	}
+	if header.Version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", header.Version)
+	}
+	if header.Length <= 0 {
+		return nil, errors.New("empty archive")
+	}
+	var blocks []*SkipBlock
+	for i := 0; i < header.Length; i++ {
+		sb := &SkipBlock{}
+		if err := readRecord(r, sb); err != nil {
+			return nil, fmt.Errorf("couldn't read block %d: %s", i, err)
+		}
+		blocks = append(blocks, sb)
+	}
+	if !blocks[0].Hash.Equal(header.GenesisID) {
+		return nil, errors.New("archive holds another skipchain")
+	}
+	if err := VerifyChain(blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// VerifyChain makes sure that the blocks, sorted by index, form a complete
+// skipchain starting at the genesis-block: every hash is correct, every
+// forward-link is correctly signed by the roster of its block, and every
+// block is the target of the level-0 forward-link of the previous block.
+func VerifyChain(blocks []*SkipBlock) error {
+	if len(blocks) == 0 {
+		return errors.New("no blocks")
+	}
+	byID := make(map[string]*SkipBlock)
+	for i, sb := range blocks {
+		if sb.SkipBlockFix == nil || sb.Roster == nil {
+			return fmt.Errorf("block %d is incomplete", i)
+		}
+		if !sb.Hash.Equal(sb.CalculateHash()) {
+			return fmt.Errorf("wrong hash of block %d", i)
+		}
+		if sb.Index != i {
+			return fmt.Errorf("block %d has index %d", i, sb.Index)
+		}
+		if i > 0 && !sb.GenesisID.Equal(blocks[0].Hash) {
+			return fmt.Errorf("block %d belongs to another skipchain", i)
+		}
+		if err := sb.VerifyForwardSignatures(); err != nil {
+			return fmt.Errorf("block %d: %s", i, err)
+		}
+		for _, fl := range sb.ForwardLink {
+			if !fl.From.Equal(sb.Hash) {
+				return fmt.Errorf("forward-link of block %d starts at another block", i)
+			}
+		}
+		if i > 0 {
+			prev := blocks[i-1]
+			if len(prev.ForwardLink) == 0 || !prev.ForwardLink[0].To.Equal(sb.Hash) {
+				return fmt.Errorf("block %d is not linked from the previous block", i)
+			}
+			if len(sb.BackLinkIDs) == 0 || !sb.BackLinkIDs[0].Equal(prev.Hash) {
+				return fmt.Errorf("block %d doesn't link back to the previous block", i)
+			}
+		}
+		byID[string(sb.Hash)] = sb
+	}
+	// The higher forward-links must point to blocks of the archive and
+	// announce the correct roster.
+	for i, sb := range blocks {
+		for _, fl := range sb.ForwardLink {
+			to, ok := byID[string(fl.To)]
+			if !ok {
+				if sb.Index == len(blocks)-1 {
+					// The latest block might already point to a
+					// block that is not yet in the archive.
+					continue
+				}
+				return fmt.Errorf("forward-link of block %d points outside of archive", i)
+			}
+			if fl.NewRoster != nil && !fl.NewRoster.ID.Equal(to.Roster.ID) {
+				return fmt.Errorf("forward-link of block %d has wrong roster", i)
+			}
+		}
+	}
+	return nil
+}
+
+func writeRecord(w io.Writer, msg interface{}) error {
+	buf, err := protobuf.Encode(msg)
+	if err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(buf)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+func readRecord(r io.Reader, msg interface{}) error {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return err
+	}
+	l := binary.BigEndian.Uint32(length[:])
+	if l > maxArchiveRecord {
+		return errors.New("record too big")
+	}
+	buf := make([]byte, l)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	return protobuf.DecodeWithConstructors(buf, msg,
+		network.DefaultConstructors(cothority.Suite))
+}
+
+// exportChain returns all blocks of the skipchain, sorted by index.
+func (s *Service) exportChain(genesisID SkipBlockID) ([]*SkipBlock, error) {
+	sb := s.db.GetByID(genesisID)
+	if sb == nil || sb.Index != 0 {
+		return nil, errors.New("No such genesis-block")
+	}
+	if snap := s.db.GetSnapshot(genesisID); snap != nil {
+		return nil, snap.errPruned()
+	}
+	blocks := []*SkipBlock{sb}
+	for len(sb.ForwardLink) > 0 {
+		sb = s.db.GetByID(sb.ForwardLink[0].To)
+		if sb == nil {
+			return nil, errors.New("missing block")
+		}
+		blocks = append(blocks, sb)
+	}
+	return blocks, nil
+}
+
+// ExportSkipchain returns an archive of all blocks of the skipchain.
+func (s *Service) ExportSkipchain(req *ExportSkipchain) (*ExportSkipchainReply, error) {
+	blocks, err := s.exportChain(req.SkipchainID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, blocks); err != nil {
+		return nil, err
+	}
+	return &ExportSkipchainReply{Archive: buf.Bytes()}, nil
+}
+
+// ImportSkipchain verifies all blocks of the archive and stores them. As
+// an import can add whole skipchains, it is refused if no client is linked
+// with this conode.
+func (s *Service) ImportSkipchain(req *ImportSkipchain) (*ImportSkipchainReply, error) {
+	s.storageMutex.Lock()
+	linked := len(s.Storage.Clients) > 0
+	s.storageMutex.Unlock()
+	if !linked {
+		return nil, errors.New("cannot import without a linked client")
+	}
+	if !s.verifySigs(req.message(), req.Signature) {
+		return nil, errors.New("wrong signature of unknown signer")
+	}
+	blocks, err := ReadArchive(bytes.NewReader(req.Archive))
+	if err != nil {
+		return nil, err
+	}
+	genesisID := blocks[0].Hash
+	s.chains.lock(genesisID)
+	defer s.chains.unlock(genesisID)
+	for _, sb := range blocks {
+		if err := s.storeBlock(sb); err != nil {
+			return nil, fmt.Errorf("couldn't store block %d: %s", sb.Index, err)
+		}
+	}
+	log.Lvlf2("%s: imported %d blocks of skipchain %x", s.ServerIdentity(),
+		len(blocks), genesisID)
+	return &ImportSkipchainReply{
+		GenesisID: genesisID,
+		Latest:    blocks[len(blocks)-1],
+	}, nil
+}
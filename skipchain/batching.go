@@ -0,0 +1,279 @@
+package skipchain
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dedis/cothority"
+	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
+)
+
+/*
+This file holds the batching of payloads. Instead of running one BFT round
+per block, the leader collects the payloads of all clients during the
+batching interval and stores all of them in one block.
+*/
+
+// maxBatchPayloads is the maximum number of payloads in one block. If more
+// payloads arrive during the batching interval, the block is created at once.
+const maxBatchPayloads = 1000
+
+// BatchData is stored in the Data field of a block created from a batch.
+type BatchData struct {
+	Payloads [][]byte
+}
+
+// PayloadReceipt proves that a payload has been stored in a block. To prove
+// that the block is part of the skipchain, use GetProof with the ID of the
+// block.
+type PayloadReceipt struct {
+	// Block holding the payload.
+	Block *SkipBlock
+	// Position of the payload in the BatchData of the block.
+	Position int
+}
+
+// Verify makes sure that the block of the receipt is correctly hashed and
+// holds the payload at the given position.
+func (pr *PayloadReceipt) Verify(payload []byte) error {
+	if pr.Block == nil {
+		return errors.New("receipt without block")
+	}
+	if !pr.Block.Hash.Equal(pr.Block.CalculateHash()) {
+		return errors.New("wrong hash of block")
+	}
+	bd, err := GetBatchData(pr.Block)
+	if err != nil {
+		return err
+	}
+	if pr.Position < 0 || pr.Position >= len(bd.Payloads) {
+		return errors.New("position out of range")
+	}
+	if !bytes.Equal(bd.Payloads[pr.Position], payload) {
+		return errors.New("block holds another payload at this position")
+	}
+	return nil
+}
+
+// GetBatchData returns the payloads stored in the block.
+func GetBatchData(sb *SkipBlock) (*BatchData, error) {
+	_, msg, err := network.Unmarshal(sb.Data, cothority.Suite)
+	if err != nil {
+		return nil, err
+	}
+	bd, ok := msg.(*BatchData)
+	if !ok {
+		return nil, errors.New("block doesn't hold a batch")
+	}
+	return bd, nil
+}
+
+// batchPool holds the pending batches of all skipchains.
+type batchPool struct {
+	sync.Mutex
+	// the key is the skipchain-id as a string
+	pending map[string]*pendingBatch
+}
+
+// pendingBatch collects payloads until the block is created.
+type pendingBatch struct {
+	payloads [][]byte
+	// latest holds for every payload the latest block the client knew,
+	// or nil if the client didn't give it.
+	latest  []SkipBlockID
+	waiters []chan batchResult
+	timer   *time.Timer
+}
+
+// batchResult is sent to every client waiting for its payload to be stored.
+type batchResult struct {
+	receipt *PayloadReceipt
+	err     error
+}
+
+// add appends the payload to the pending batch of the skipchain, creating it
+// if necessary. The returned channel receives the result once the block has
+// been created. The batch is committed after interval, or at once if it is
+// full. A payload that is already pending for the same latest block is
+// refused, so that a request cannot be replayed during the interval.
+func (bp *batchPool) add(id, latest SkipBlockID, payload []byte, interval time.Duration,
+	commit func(SkipBlockID)) chan batchResult {
+	bp.Lock()
+	defer bp.Unlock()
+	if bp.pending == nil {
+		bp.pending = make(map[string]*pendingBatch)
+	}
+	pb := bp.pending[string(id)]
+	if pb == nil {
+		pb = &pendingBatch{}
+		pb.timer = time.AfterFunc(interval, func() { commit(id) })
+		bp.pending[string(id)] = pb
+	}
+	c := make(chan batchResult, 1)
+	for i, p := range pb.payloads {
+		if pb.latest[i].Equal(latest) && bytes.Equal(p, payload) {
+			c <- batchResult{err: errors.New("payload is already pending")}
+			return c
+		}
+	}
+	pb.payloads = append(pb.payloads, payload)
+	pb.latest = append(pb.latest, latest)
+	pb.waiters = append(pb.waiters, c)
+	if len(pb.payloads) >= maxBatchPayloads && pb.timer.Stop() {
+		go commit(id)
+	}
+	return c
+}
+
+// take removes the pending batch of the skipchain and returns it.
+func (bp *batchPool) take(id SkipBlockID) *pendingBatch {
+	bp.Lock()
+	defer bp.Unlock()
+	pb := bp.pending[string(id)]
+	delete(bp.pending, string(id))
+	return pb
+}
+
+// StoreBatchedPayload adds the payload to the pending batch of the skipchain
+// and returns once the batch has been stored in a new block. Batching needs
+// to be enabled for the skipchain with SetBatching, and only the leader can
+// handle this request.
+func (s *Service) StoreBatchedPayload(req *StoreBatchedPayload) (*StoreBatchedPayloadReply, error) {
+	if len(s.Storage.Clients) > 0 {
+		if req.Signature == nil {
+			return nil, errors.New(
+				"cannot store payload without authentication")
+		}
+		if len(req.Latest) == 0 {
+			return nil, errors.New(
+				"authenticated payloads need the latest block")
+		}
+		if !s.authenticate(req.message(), *req.Signature) {
+			return nil, errors.New(
+				"wrong signature for this skipchain")
+		}
+	}
+	interval := s.batchInterval(req.SkipchainID)
+	if interval == 0 {
+		return nil, errors.New("batching is not enabled for this skipchain")
+	}
+	genesis := s.db.GetByID(req.SkipchainID)
+	if genesis == nil || genesis.Index != 0 {
+		return nil, errors.New("No such genesis-block")
+	}
+	latest := s.findLatest(genesis)
+	if len(req.Latest) > 0 && !req.Latest.Equal(latest.Hash) {
+		return nil, errors.New("the given block is not the latest block anymore")
+	}
+	if !s.ServerIdentity().Equal(latest.Roster.Get(0)) {
+		return nil, errors.New("only leader is allowed to add blocks")
+	}
+
+	res := <-s.batches.add(req.SkipchainID, req.Latest, req.Payload, interval, s.commitBatch)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &StoreBatchedPayloadReply{Receipt: res.receipt}, nil
+}
+
+// SetBatching enables, updates or disables batching for a skipchain.
+func (s *Service) SetBatching(sb *SetBatching) (*EmptyReply, error) {
+	if sb.IntervalMS < 0 {
+		return nil, errors.New("cannot have a negative interval")
+	}
+	if !s.verifySigs(sb.message(), sb.Signature) {
+		return nil, errors.New("wrong signature of unknown signer")
+	}
+	if s.db.GetByID(sb.SkipchainID) == nil {
+		return nil, errors.New("unknown skipchain")
+	}
+
+	s.storageMutex.Lock()
+	var batching []BatchConfig
+	for _, bc := range s.Storage.Batching {
+		if !bc.SkipchainID.Equal(sb.SkipchainID) {
+			batching = append(batching, bc)
+		}
+	}
+	if sb.IntervalMS > 0 {
+		batching = append(batching, BatchConfig{
+			SkipchainID: sb.SkipchainID,
+			IntervalMS:  sb.IntervalMS,
+		})
+	}
+	s.Storage.Batching = batching
+	s.storageMutex.Unlock()
+	s.save()
+	return &EmptyReply{}, nil
+}
+
+// batchInterval returns the batching interval of the skipchain, or 0 if
+// batching is disabled.
+func (s *Service) batchInterval(id SkipBlockID) time.Duration {
+	s.storageMutex.Lock()
+	defer s.storageMutex.Unlock()
+	for _, bc := range s.Storage.Batching {
+		if bc.SkipchainID.Equal(id) {
+			return time.Duration(bc.IntervalMS) * time.Millisecond
+		}
+	}
+	return 0
+}
+
+// commitBatch stores all pending payloads of the skipchain in a new block and
+// sends the receipts to the waiting clients. Payloads given for a block that
+// is not the latest anymore are refused.
+func (s *Service) commitBatch(id SkipBlockID) {
+	pb := s.batches.take(id)
+	if pb == nil {
+		return
+	}
+	genesis := s.db.GetByID(id)
+	if genesis == nil {
+		for _, c := range pb.waiters {
+			c <- batchResult{err: errors.New("No such genesis-block")}
+		}
+		return
+	}
+	latest := s.findLatest(genesis)
+	var payloads [][]byte
+	positions := make([]int, len(pb.payloads))
+	for i, p := range pb.payloads {
+		if len(pb.latest[i]) > 0 && !pb.latest[i].Equal(latest.Hash) {
+			positions[i] = -1
+			continue
+		}
+		positions[i] = len(payloads)
+		payloads = append(payloads, p)
+	}
+	var reply *StoreSkipBlockReply
+	var err error
+	if len(payloads) > 0 {
+		reply, err = func() (*StoreSkipBlockReply, error) {
+			data, err := network.Marshal(&BatchData{Payloads: payloads})
+			if err != nil {
+				return nil, err
+			}
+			prop := NewSkipBlock()
+			prop.Roster = latest.Roster
+			prop.Data = data
+			return s.storeSkipBlock(&StoreSkipBlock{TargetSkipChainID: id, NewBlock: prop})
+		}()
+		if err != nil {
+			log.Error(s.ServerIdentity(), "couldn't store batch:", err)
+		}
+	}
+	for i, c := range pb.waiters {
+		switch {
+		case positions[i] < 0:
+			c <- batchResult{err: errors.New("the given block is not the latest block anymore")}
+		case err != nil:
+			c <- batchResult{err: errors.New("couldn't store batch: " + err.Error())}
+		default:
+			c <- batchResult{receipt: &PayloadReceipt{Block: reply.Latest, Position: positions[i]}}
+		}
+	}
+}
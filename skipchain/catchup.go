@@ -0,0 +1,168 @@
+package skipchain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dedis/onet/log"
+)
+
+/*
+This file holds the background process that keeps the skipchains of a conode
+up to date, even if it has been offline while new blocks have been added.
+*/
+
+// CatchupInterval is the time between two catch-up runs of the skipchain
+// service. In every run, all skipchains where this conode is part of the
+// roster of the latest block are compared with the other conodes of the
+// roster, and missing blocks are fetched. A value of 0 disables the periodic
+// catch-up. It needs to be set before the service is started.
+var CatchupInterval = 10 * time.Minute
+
+// catchupStartDelay is the time to wait after startup before the first
+// catch-up, so that the conode is ready to communicate.
+const catchupStartDelay = 5 * time.Second
+
+// catchupBatchSize is the number of blocks fetched at once.
+const catchupBatchSize = 10
+
+// startCatchup schedules the first catch-up. If the conode already stores
+// skipchains, it is done right after startup, else only after
+// CatchupInterval.
+func (s *Service) startCatchup() {
+	ids, err := s.db.GetSkipchainIDs()
+	if err != nil {
+		log.Error(err)
+	}
+	if len(ids) > 0 {
+		s.scheduleCatchup(catchupStartDelay)
+	} else {
+		s.scheduleCatchup(CatchupInterval)
+	}
+}
+
+// scheduleCatchup runs catchUpAll after the given delay and then every
+// CatchupInterval. A timer is used instead of a goroutine, so that nothing
+// is running between two catch-ups. The timer is kept in the service, so
+// that Close can stop it.
+func (s *Service) scheduleCatchup(delay time.Duration) {
+	if delay <= 0 || CatchupInterval <= 0 {
+		return
+	}
+	s.catchupMutex.Lock()
+	defer s.catchupMutex.Unlock()
+	if s.closed {
+		return
+	}
+	s.catchupTimer = time.AfterFunc(delay, func() {
+		s.catchUpAll()
+		s.scheduleCatchup(CatchupInterval)
+	})
+}
+
+// Close stops the periodic catch-up of the service. A running catch-up
+// stops after the current skipchain, and no new one is scheduled. The
+// storage of the skipblocks is closed, unless it is the bolt database of the
+// conode, which is closed by the conode. It needs to be called before the
+// conode shuts down.
+func (s *Service) Close() error {
+	s.catchupMutex.Lock()
+	defer s.catchupMutex.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.catchupTimer != nil {
+		s.catchupTimer.Stop()
+	}
+	if s.config.Storage == "" || s.config.Storage == StorageBolt {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// isClosed returns true once Close has been called.
+func (s *Service) isClosed() bool {
+	s.catchupMutex.Lock()
+	defer s.catchupMutex.Unlock()
+	return s.closed
+}
+
+// catchUpAll fetches the missing blocks of all skipchains where this conode
+// is part of the roster of the latest block.
+func (s *Service) catchUpAll() {
+	ids, err := s.db.GetSkipchainIDs()
+	if err != nil {
+		log.Error(s.ServerIdentity(), "couldn't list skipchains:", err)
+		return
+	}
+	for _, id := range ids {
+		if s.isClosed() {
+			return
+		}
+		genesis := s.db.GetByID(id)
+		if genesis == nil {
+			continue
+		}
+		latest := s.findLatest(genesis)
+		if i, _ := latest.Roster.Search(s.ServerIdentity().ID); i < 0 {
+			continue
+		}
+		n, err := s.catchUp(latest)
+		if err != nil {
+			log.Lvlf2("%s: couldn't catch up skipchain %x: %s", s.ServerIdentity(),
+				id, err)
+		}
+		if n > 0 {
+			log.Lvlf2("%s: fetched %d blocks of skipchain %x", s.ServerIdentity(), n, id)
+		}
+	}
+}
+
+// catchUp asks the roster of latest for the blocks following latest, until
+// no new blocks are returned. Every block is verified to be the target of the
+// collectively signed forward-link of the block before it. It returns the
+// number of new blocks stored.
+func (s *Service) catchUp(latest *SkipBlock) (int, error) {
+	var stored int
+	for {
+		blocks, err := s.getBlocks(latest.Roster, latest.Hash, catchupBatchSize, false)
+		if err != nil {
+			return stored, err
+		}
+		if len(blocks) == 0 || !blocks[0].Hash.Equal(latest.Hash) {
+			return stored, errors.New("got wrong blocks")
+		}
+		// The first block is our latest one, maybe with new forward-links.
+		prev := latest
+		for _, sb := range blocks {
+			if !sb.Hash.Equal(sb.CalculateHash()) {
+				return stored, errors.New("got block with wrong hash")
+			}
+			if sb != blocks[0] {
+				if len(prev.ForwardLink) == 0 || !prev.ForwardLink[0].To.Equal(sb.Hash) {
+					return stored, errors.New("got block that is not linked")
+				}
+			}
+			if err := sb.VerifyForwardSignatures(); err != nil {
+				return stored, err
+			}
+			known := s.db.GetByID(sb.Hash) != nil
+			if err := s.storeBlock(sb); err != nil {
+				return stored, err
+			}
+			if !known {
+				stored++
+			}
+			prev = sb
+		}
+		s.prune(blocks)
+		if len(prev.ForwardLink) == 0 {
+			return stored, nil
+		}
+		if len(blocks) == 1 {
+			return stored, errors.New("roster doesn't have the next block")
+		}
+		latest = prev
+	}
+}
@@ -1,6 +1,9 @@
 package skipchain
 
 import (
+	"crypto/sha256"
+	"strconv"
+
 	"github.com/dedis/kyber"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/network"
@@ -17,6 +20,12 @@ func init() {
 		&GetUpdateChainReply{},
 		// Request updated block
 		&GetSingleBlock{},
+		// Request a proof of inclusion of a block
+		&GetProof{},
+		&GetProofReply{},
+		// Subscribe to new blocks
+		&StreamSkipBlocks{},
+		&StreamSkipBlocksReply{},
 		// Fetch all skipchains
 		&GetAllSkipchains{},
 		&GetAllSkipchainsReply{},
@@ -36,6 +45,23 @@ func init() {
 		&DelFollow{},
 		// EmptyReply for calls that only return errors
 		&EmptyReply{},
+		// Configure pruning of a skipchain
+		&SetPruning{},
+		// Add or remove conodes from the roster of a skipchain
+		&ProposeRosterChange{},
+		// Request evidence of forks
+		&GetForkEvidence{},
+		&GetForkEvidenceReply{},
+		// Add a payload to the next batch
+		&StoreBatchedPayload{},
+		&StoreBatchedPayloadReply{},
+		// Configure batching of a skipchain
+		&SetBatching{},
+		// Export and import complete skipchains
+		&ExportSkipchain{},
+		&ExportSkipchainReply{},
+		&ImportSkipchain{},
+		&ImportSkipchainReply{},
 		// Lists all skipchains we follow
 		&ListFollow{},
 		// Returns the genesis-blocks of all skipchains we follow
@@ -48,6 +74,11 @@ func init() {
 		// - Data structures
 		&SkipBlockFix{},
 		&SkipBlock{},
+		&Snapshot{},
+		&ForkEvidence{},
+		&BatchData{},
+		&PayloadReceipt{},
+		&ArchiveHeader{},
 		// Own service
 		&Service{},
 		// - Protocol messages
@@ -56,6 +87,8 @@ func init() {
 		&ProtoExtendRosterReply{},
 		&ProtoGetBlocks{},
 		&ProtoGetBlocksReply{},
+		&ProtoJoinChain{},
+		&ProtoJoinChainReply{},
 	)
 }
 
@@ -91,6 +124,9 @@ type GetUpdateChain struct {
 // starting from the SkipBlock the client sent
 type GetUpdateChainReply struct {
 	Update []*SkipBlock
+	// Pruned is set if the next blocks have been pruned on this conode. The
+	// caller can continue with the archive nodes of the snapshot.
+	Pruned *Snapshot
 }
 
 // GetAllSkipchains - returns all known last blocks of skipchains.
@@ -102,6 +138,48 @@ type GetAllSkipchainsReply struct {
 	SkipChains []*SkipBlock
 }
 
+// GetForkEvidence asks for all evidence of forks of the given skipchain that
+// the conode detected. If SkipchainID is empty, the evidence of all
+// skipchains is returned.
+type GetForkEvidence struct {
+	SkipchainID SkipBlockID
+}
+
+// GetForkEvidenceReply returns the evidence of forks.
+type GetForkEvidenceReply struct {
+	Evidence []*ForkEvidence
+}
+
+// StoreBatchedPayload asks the leader of a skipchain to include the payload
+// in the next block. The leader collects the payloads of all clients during
+// the batching interval of the skipchain and then creates one block holding
+// all of them. If Latest is given, the payload is refused if Latest is not
+// the latest block of the skipchain anymore when the batch is stored. If the
+// conode has clients, Latest is mandatory, so that a signed request cannot be
+// replayed, and the Signature has to be on "batch:" + the SkipchainID +
+// "latest:" + Latest + "payload:" + the Payload.
+type StoreBatchedPayload struct {
+	SkipchainID SkipBlockID
+	Latest      SkipBlockID
+	Payload     []byte
+	Signature   *[]byte
+}
+
+// message returns the message that needs to be signed by the client.
+func (sbp *StoreBatchedPayload) message() []byte {
+	msg := append([]byte("batch:"), sbp.SkipchainID...)
+	msg = append(msg, []byte("latest:")...)
+	msg = append(msg, sbp.Latest...)
+	msg = append(msg, []byte("payload:")...)
+	return append(msg, sbp.Payload...)
+}
+
+// StoreBatchedPayloadReply returns the proof that the payload has been
+// included in a block.
+type StoreBatchedPayloadReply struct {
+	Receipt *PayloadReceipt
+}
+
 // Internal calls
 
 // PropagateSkipBlocks sends a newly signed SkipBlock to all members of
@@ -135,6 +213,32 @@ type GetSingleBlockByIndex struct {
 	Index   int
 }
 
+// GetProof asks for the shortest chain of blocks going from the genesis-block
+// to the block with the given ID.
+type GetProof struct {
+	ID SkipBlockID
+}
+
+// GetProofReply returns the proof that the requested block is part of its
+// skipchain.
+type GetProofReply struct {
+	Proof Proof
+}
+
+// StreamSkipBlocks subscribes to all blocks of the skipchain given by
+// GenesisID, starting at FromIndex. New blocks and blocks with new
+// forward-links are sent as soon as they are stored.
+type StreamSkipBlocks struct {
+	GenesisID SkipBlockID
+	FromIndex int
+}
+
+// StreamSkipBlocksReply holds one block of the skipchain. The same block can
+// be sent more than once, with more forward-links.
+type StreamSkipBlocksReply struct {
+	Block *SkipBlock
+}
+
 // Internal calls
 
 // GetBlock asks for an updated block, in case for a conode that is not
@@ -213,6 +317,30 @@ type ProtoStructGetBlocksReply struct {
 	ProtoGetBlocksReply
 }
 
+// ProtoJoinChain asks a conode that joins the roster of a skipchain to fetch
+// all blocks up to Latest, which holds the previous roster.
+type ProtoJoinChain struct {
+	Latest SkipBlock
+}
+
+// ProtoStructJoinChain embeds the treenode.
+type ProtoStructJoinChain struct {
+	*onet.TreeNode
+	ProtoJoinChain
+}
+
+// ProtoJoinChainReply is a signature on the ID of the latest block, or nil
+// if the conode couldn't fetch the skipchain.
+type ProtoJoinChainReply struct {
+	Signature *[]byte
+}
+
+// ProtoStructJoinChainReply embeds the treenode.
+type ProtoStructJoinChainReply struct {
+	*onet.TreeNode
+	ProtoJoinChainReply
+}
+
 // CreateLinkPrivate asks to store the given public key in the list of administrative
 // clients.
 type CreateLinkPrivate struct {
@@ -283,6 +411,110 @@ type DelFollow struct {
 	Signature   []byte
 }
 
+// SetPruning configures how many blocks of a skipchain are kept. Older blocks
+// are removed, except for the genesis-block, and requests for them are
+// redirected to the Archive nodes. A Keep of 0 disables pruning for that
+// skipchain. The Signature is on "pruning:" + the SkipchainID + the Keep as
+// a decimal string + the IDs of all archive nodes.
+type SetPruning struct {
+	SkipchainID SkipBlockID
+	Keep        int
+	Archive     []*network.ServerIdentity
+	Signature   []byte
+}
+
+// message returns the message that needs to be signed by the client.
+func (sp *SetPruning) message() []byte {
+	msg := append([]byte("pruning:"), sp.SkipchainID...)
+	msg = append(msg, []byte(strconv.Itoa(sp.Keep))...)
+	for _, si := range sp.Archive {
+		msg = append(msg, si.ID[:]...)
+	}
+	return msg
+}
+
+// SetBatching enables batching of payloads for a skipchain on the leader.
+// All payloads received during IntervalMS milliseconds are stored in one
+// block. An IntervalMS of 0 disables batching. The Signature is on
+// "batching:" + the SkipchainID + the IntervalMS as a decimal string.
+type SetBatching struct {
+	SkipchainID SkipBlockID
+	IntervalMS  int
+	Signature   []byte
+}
+
+// message returns the message that needs to be signed by the client.
+func (sb *SetBatching) message() []byte {
+	msg := append([]byte("batching:"), sb.SkipchainID...)
+	return append(msg, []byte(strconv.Itoa(sb.IntervalMS))...)
+}
+
+// ExportSkipchain asks for an archive of all blocks of a skipchain. The
+// skipchain must not be pruned.
+type ExportSkipchain struct {
+	SkipchainID SkipBlockID
+}
+
+// ExportSkipchainReply holds the archive as written by WriteArchive.
+type ExportSkipchainReply struct {
+	Archive []byte
+}
+
+// ImportSkipchain asks the conode to verify and store all blocks of an
+// archive. The Signature is on "import:" + the sha256-hash of the Archive.
+type ImportSkipchain struct {
+	Archive   []byte
+	Signature []byte
+}
+
+// message returns the message that needs to be signed by the client.
+func (is *ImportSkipchain) message() []byte {
+	h := sha256.Sum256(is.Archive)
+	return append([]byte("import:"), h[:]...)
+}
+
+// ImportSkipchainReply returns the genesis-id and the latest block of the
+// imported skipchain.
+type ImportSkipchainReply struct {
+	GenesisID SkipBlockID
+	Latest    *SkipBlock
+}
+
+// ProposeRosterChange asks the leader of a skipchain to create a new block
+// where the conodes in Add are appended to the roster and the conodes in
+// Remove are taken out of it. The leader cannot be removed. The new block
+// holds Data. If Latest is given, the change is refused if Latest is not the
+// latest block of the skipchain anymore. If the conode has clients, Latest
+// is mandatory, so that a signed request cannot be replayed, and the
+// Signature has to be on "rosterchange:" + the SkipchainID + "latest:" +
+// Latest + "add:" + the IDs of the added conodes + "remove:" + the IDs of
+// the removed conodes + "data:" + Data.
+type ProposeRosterChange struct {
+	SkipchainID SkipBlockID
+	Latest      SkipBlockID
+	Add         []*network.ServerIdentity
+	Remove      []*network.ServerIdentity
+	Data        []byte
+	Signature   *[]byte
+}
+
+// message returns the message that needs to be signed by the client.
+func (prc *ProposeRosterChange) message() []byte {
+	msg := append([]byte("rosterchange:"), prc.SkipchainID...)
+	msg = append(msg, []byte("latest:")...)
+	msg = append(msg, prc.Latest...)
+	msg = append(msg, []byte("add:")...)
+	for _, si := range prc.Add {
+		msg = append(msg, si.ID[:]...)
+	}
+	msg = append(msg, []byte("remove:")...)
+	for _, si := range prc.Remove {
+		msg = append(msg, si.ID[:]...)
+	}
+	msg = append(msg, []byte("data:")...)
+	return append(msg, prc.Data...)
+}
+
 // ListFollow returns all followed lists all skipchains we follow.
 // The signature has to be on the following message:
 // "listfollow:" + the public key of the conode
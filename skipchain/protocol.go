@@ -30,9 +30,14 @@ const ProtocolExtendRoster = "scExtendRoster"
 // ProtocolGetBlocks asks a remote node for some blocks.
 const ProtocolGetBlocks = "scGetBlocks"
 
+// ProtocolJoinChain asks the nodes joining or leaving the roster of a
+// skipchain to fetch all its blocks.
+const ProtocolJoinChain = "scJoinChain"
+
 func init() {
 	onet.GlobalProtocolRegister(ProtocolExtendRoster, NewProtocolExtendRoster)
 	onet.GlobalProtocolRegister(ProtocolGetBlocks, NewProtocolGetBlocks)
+	onet.GlobalProtocolRegister(ProtocolJoinChain, NewProtocolJoinChain)
 }
 
 // ExtendRoster is used for different communications in the skipchain-service.
@@ -63,6 +68,21 @@ type GetBlocks struct {
 	DB             *SkipBlockDB
 }
 
+// JoinChain is used by the leader to make sure that all joining nodes hold
+// the skipchain before they are added to the roster, and that all leaving
+// nodes hold the block that removed them.
+type JoinChain struct {
+	*onet.TreeNodeInstance
+
+	JoinChain      *ProtoJoinChain
+	JoinChainReply chan []ProtoExtendSignature
+	// Sync fetches all blocks up to the given block.
+	Sync      func(latest *SkipBlock) error
+	sigs      []ProtoExtendSignature
+	sigsMutex sync.Mutex
+	finished  bool
+}
+
 // NewProtocolExtendRoster prepares for a protocol that checks if a roster can
 // be extended.
 func NewProtocolExtendRoster(n *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
@@ -85,6 +105,16 @@ func NewProtocolGetBlocks(n *onet.TreeNodeInstance) (onet.ProtocolInstance, erro
 	return t, t.RegisterHandlers(t.HandleGetBlocks, t.HandleGetBlocksReply)
 }
 
+// NewProtocolJoinChain prepares for a protocol that transfers a skipchain to
+// the joining nodes.
+func NewProtocolJoinChain(n *onet.TreeNodeInstance) (onet.ProtocolInstance, error) {
+	t := &JoinChain{
+		TreeNodeInstance: n,
+		JoinChainReply:   make(chan []ProtoExtendSignature, 1),
+	}
+	return t, t.RegisterHandlers(t.HandleJoinChain, t.HandleJoinChainReply)
+}
+
 // Start sends the extend roster request to all of the children.
 func (p *ExtendRoster) Start() error {
 	log.Lvl3("Starting Protocol ExtendRoster")
@@ -101,6 +131,17 @@ func (p *GetBlocks) Start() error {
 	return p.SendToChildren(p.GetBlocks)
 }
 
+// Start sends the join request to all of the children.
+func (p *JoinChain) Start() error {
+	log.Lvl3("Starting Protocol JoinChain")
+	if errs := p.SendToChildrenInParallel(p.JoinChain); len(errs) > 0 {
+		p.JoinChainReply <- []ProtoExtendSignature{}
+		p.Done()
+		return fmt.Errorf("Send to children failed: %v", errs)
+	}
+	return nil
+}
+
 // HandleExtendRoster uses the stored followers to decide if we want to accept
 // to be part of the new roster.
 func (p *ExtendRoster) HandleExtendRoster(msg ProtoStructExtendRoster) error {
@@ -272,3 +313,52 @@ func (p *GetBlocks) HandleGetBlocksReply(msg ProtoStructGetBlocksReply) error {
 
 	return nil
 }
+
+// HandleJoinChain fetches the skipchain and signs the ID of the latest block
+// if all blocks are available.
+func (p *JoinChain) HandleJoinChain(msg ProtoStructJoinChain) error {
+	defer p.Done()
+
+	if p.Sync == nil {
+		return p.SendToParent(&ProtoJoinChainReply{})
+	}
+	if !msg.ServerIdentity.Equal(msg.Latest.Roster.Get(0)) {
+		log.Error(p.ServerIdentity(), "join request from a node that is not the leader")
+		return p.SendToParent(&ProtoJoinChainReply{})
+	}
+	if err := p.Sync(&msg.Latest); err != nil {
+		log.Error(p.ServerIdentity(), "couldn't fetch skipchain:", err)
+		return p.SendToParent(&ProtoJoinChainReply{})
+	}
+	sig, err := schnorr.Sign(cothority.Suite, p.Private(), msg.Latest.Hash)
+	if err != nil {
+		log.Error("couldn't sign latest block")
+		return p.SendToParent(&ProtoJoinChainReply{})
+	}
+	return p.SendToParent(&ProtoJoinChainReply{Signature: &sig})
+}
+
+// HandleJoinChainReply collects the signatures of the joining nodes. As soon
+// as one node fails, the protocol stops and returns an empty slice.
+func (p *JoinChain) HandleJoinChainReply(r ProtoStructJoinChainReply) error {
+	p.sigsMutex.Lock()
+	defer p.sigsMutex.Unlock()
+	if p.finished {
+		return nil
+	}
+	if r.Signature == nil || schnorr.Verify(cothority.Suite, r.ServerIdentity.Public,
+		p.JoinChain.Latest.Hash, *r.Signature) != nil {
+		log.Lvl2(r.ServerIdentity, "failed to join the skipchain")
+		p.finished = true
+		p.JoinChainReply <- []ProtoExtendSignature{}
+		p.Done()
+		return nil
+	}
+	p.sigs = append(p.sigs, ProtoExtendSignature{SI: r.ServerIdentity.ID, Signature: *r.Signature})
+	if len(p.sigs) == len(p.Children()) {
+		p.finished = true
+		p.JoinChainReply <- p.sigs
+		p.Done()
+	}
+	return nil
+}
@@ -45,9 +45,33 @@ func init() {
 	network.RegisterMessages(&Storage{})
 }
 
+// Config holds the configuration of the service, which is read from the
+// Skipchain section of the conode configuration.
+type Config struct {
+	// Storage is the backend for the skipblocks: StorageBolt, StorageMemory
+	// or StorageLevelDB. An empty string is the same as StorageBolt.
+	Storage string
+}
+
+// RegisterService replaces the service with one using the given
+// configuration. It needs to be called before the conode is started.
+func RegisterService(cfg Config) error {
+	if err := checkBackend(cfg.Storage); err != nil {
+		return err
+	}
+	if err := onet.UnregisterService(ServiceName); err != nil {
+		return err
+	}
+	_, err := onet.RegisterNewService(ServiceName, func(c *onet.Context) (onet.Service, error) {
+		return newService(c, cfg)
+	})
+	return err
+}
+
 // Service handles adding new SkipBlocks
 type Service struct {
 	*onet.ServiceProcessor
+	config                  Config
 	db                      *SkipBlockDB
 	propagate               messaging.PropagationFunc
 	verifiers               map[VerifierID]SkipBlockVerifier
@@ -58,6 +82,12 @@ type Service struct {
 	chains                  chainLocker
 	verifyNewBlockBuffer    sync.Map
 	verifyFollowBlockBuffer sync.Map
+	streaming               streamingManager
+	batches                 batchPool
+	// catchupMutex protects catchupTimer and closed.
+	catchupMutex sync.Mutex
+	catchupTimer *time.Timer
+	closed       bool
 }
 
 type chainLocker struct {
@@ -133,6 +163,26 @@ type Storage struct {
 	// to this service. Once a client is linked to a service, only blocks signed
 	// by this client will be allowed.
 	Clients []kyber.Point
+	// Pruning holds all skipchains where old blocks are removed.
+	Pruning []PruneConfig
+	// Batching holds all skipchains where payloads are collected into
+	// blocks.
+	Batching []BatchConfig
+}
+
+// BatchConfig defines how long payloads for a skipchain are collected
+// before they are stored in a new block.
+type BatchConfig struct {
+	SkipchainID SkipBlockID
+	IntervalMS  int
+}
+
+// PruneConfig defines how many blocks of a skipchain are kept and where
+// clients are redirected for older blocks.
+type PruneConfig struct {
+	SkipchainID SkipBlockID
+	Keep        int
+	Archive     []*network.ServerIdentity
 }
 
 // StoreSkipBlock stores a new skipblock in the system. This can be either a
@@ -150,12 +200,6 @@ type Storage struct {
 // If TargetSkipChainID is an empty slice, the service will create a new
 // skipchain and store the given block as genesis-block.
 func (s *Service) StoreSkipBlock(psbd *StoreSkipBlock) (*StoreSkipBlockReply, error) {
-	// Initial checks on the proposed block.
-	prop := psbd.NewBlock
-	if !s.ServerIdentity().Equal(prop.Roster.Get(0)) {
-		return nil, errors.New(
-			"only leader is allowed to add blocks")
-	}
 	if len(s.Storage.Clients) > 0 {
 		if psbd.Signature == nil {
 			return nil, errors.New(
@@ -166,6 +210,18 @@ func (s *Service) StoreSkipBlock(psbd *StoreSkipBlock) (*StoreSkipBlockReply, er
 				"wrong signature for this skipchain")
 		}
 	}
+	return s.storeSkipBlock(psbd)
+}
+
+// storeSkipBlock does the work of StoreSkipBlock once the request has been
+// authenticated.
+func (s *Service) storeSkipBlock(psbd *StoreSkipBlock) (*StoreSkipBlockReply, error) {
+	// Initial checks on the proposed block.
+	prop := psbd.NewBlock
+	if !s.ServerIdentity().Equal(prop.Roster.Get(0)) {
+		return nil, errors.New(
+			"only leader is allowed to add blocks")
+	}
 	var prev *SkipBlock
 	var changed []*SkipBlock
 
@@ -251,7 +307,7 @@ func (s *Service) StoreSkipBlock(psbd *StoreSkipBlock) (*StoreSkipBlockReply, er
 		if needSync {
 			latest := s.findLatest(prev)
 			log.Lvlf2("Catching up chain %x from index %v", prev.SkipChainID(), latest.Index)
-			err := s.syncChain(latest.Roster, latest.Hash)
+			err := s.syncChain(latest.Roster, latest.Hash, true)
 			if err != nil {
 				return nil, errors.New("failed to catch up with error: " + err.Error())
 			}
@@ -350,17 +406,205 @@ func (s *Service) StoreSkipBlock(psbd *StoreSkipBlock) (*StoreSkipBlockReply, er
 	return reply, nil
 }
 
+// ProposeRosterChange creates a new block on the skipchain where the
+// requested conodes are added to or removed from the roster of the latest
+// block. Before the block is created, every joining conode is asked whether
+// its follow-policies accept the skipchain, and has to fetch all blocks of
+// the skipchain. Only if all joining conodes acknowledged, the new block is
+// signed by the current roster. Finally the leaving conodes are asked to
+// fetch the new block, so that they know they left the roster.
+//
+// Only the leader of the skipchain can handle this request, and it cannot
+// remove itself.
+func (s *Service) ProposeRosterChange(prc *ProposeRosterChange) (*StoreSkipBlockReply, error) {
+	if len(s.Storage.Clients) > 0 {
+		if prc.Signature == nil {
+			return nil, errors.New(
+				"cannot change roster without authentication")
+		}
+		if len(prc.Latest) == 0 {
+			return nil, errors.New(
+				"authenticated roster changes need the latest block")
+		}
+		if !s.authenticate(prc.message(), *prc.Signature) {
+			return nil, errors.New(
+				"wrong signature for this skipchain")
+		}
+	}
+	genesis := s.db.GetByID(prc.SkipchainID)
+	if genesis == nil {
+		return nil, errors.New("unknown skipchain")
+	}
+	latest, err := s.db.GetLatest(genesis)
+	if err != nil {
+		return nil, err
+	}
+	if len(prc.Latest) > 0 && !prc.Latest.Equal(latest.Hash) {
+		return nil, errors.New("the given block is not the latest block anymore")
+	}
+	if !s.ServerIdentity().Equal(latest.Roster.Get(0)) {
+		return nil, errors.New("only leader is allowed to change the roster")
+	}
+	roster, joining, leaving, err := changeRoster(latest.Roster, prc.Add, prc.Remove)
+	if err != nil {
+		return nil, err
+	}
+
+	prop := NewSkipBlock()
+	prop.Roster = roster
+	prop.Data = prc.Data
+	prop.GenesisID = latest.SkipChainID()
+	prop.Index = latest.Index + 1
+	if len(joining) > 0 {
+		if err := s.willNodesJoin(prop, joining); err != nil {
+			return nil, err
+		}
+		if err := s.transferChain(latest, joining); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := s.storeSkipBlock(&StoreSkipBlock{
+		TargetSkipChainID: latest.Hash,
+		NewBlock:          prop,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(leaving) > 0 {
+		// The roster is already changed, and a conode is often removed
+		// because it is down, so a failing leave is not an error.
+		if err := s.transferChain(reply.Latest, leaving); err != nil {
+			log.Lvl2(s.ServerIdentity(), "leaving nodes didn't confirm:", err)
+		}
+	}
+	return reply, nil
+}
+
+// changeRoster returns a new roster with the conodes in add appended and the
+// conodes in remove taken out. It also returns the conodes that are new in
+// the roster and the ones that left it. The leader cannot be removed.
+func changeRoster(ro *onet.Roster, add, remove []*network.ServerIdentity) (roster *onet.Roster,
+	joining, leaving []*network.ServerIdentity, err error) {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil, nil, errors.New("no change in the roster")
+	}
+	list := append([]*network.ServerIdentity{}, ro.List...)
+	for _, si := range remove {
+		i, _ := onet.NewRoster(list).Search(si.ID)
+		if i < 0 {
+			return nil, nil, nil, fmt.Errorf("%s is not in the roster", si)
+		}
+		if i == 0 {
+			return nil, nil, nil, errors.New("cannot remove the leader")
+		}
+		list = append(list[:i], list[i+1:]...)
+	}
+	for _, si := range add {
+		if i, _ := onet.NewRoster(list).Search(si.ID); i >= 0 {
+			return nil, nil, nil, fmt.Errorf("%s is already in the roster", si)
+		}
+		list = append(list, si)
+	}
+	roster = onet.NewRoster(list)
+	for _, si := range list {
+		if i, _ := ro.Search(si.ID); i < 0 {
+			joining = append(joining, si)
+		}
+	}
+	for _, si := range ro.List {
+		if i, _ := roster.Search(si.ID); i < 0 {
+			leaving = append(leaving, si)
+		}
+	}
+	return roster, joining, leaving, nil
+}
+
+// willNodesJoin asks all joining nodes whether their follow-policies accept
+// the new block. Contrary to willNodesAcceptBlock, every joining node has to
+// accept.
+func (s *Service) willNodesJoin(block *SkipBlock, joining []*network.ServerIdentity) error {
+	ro := onet.NewRoster(append([]*network.ServerIdentity{s.ServerIdentity()}, joining...))
+	pi, err := s.CreateProtocol(ProtocolExtendRoster, ro.GenerateStar())
+	if err != nil {
+		return err
+	}
+	pisc := pi.(*ExtendRoster)
+	pisc.ExtendRoster = &ProtoExtendRoster{Block: *block}
+	if err := pisc.Start(); err != nil {
+		return err
+	}
+	select {
+	case sigs := <-pisc.ExtendRosterReply:
+		if len(sigs) != len(joining) {
+			return errors.New("node refused to join the roster")
+		}
+	case <-time.After(s.propTimeout):
+		return errors.New("timeout waiting for the joining nodes to accept")
+	}
+	return nil
+}
+
+// transferChain makes sure that all nodes fetched the skipchain up to
+// latest. It is used for the nodes joining the roster, and for the nodes
+// leaving it with the block of the new roster.
+func (s *Service) transferChain(latest *SkipBlock, nodes []*network.ServerIdentity) error {
+	ro := onet.NewRoster(append([]*network.ServerIdentity{s.ServerIdentity()}, nodes...))
+	pi, err := s.CreateProtocol(ProtocolJoinChain, ro.GenerateStar())
+	if err != nil {
+		return err
+	}
+	pisc := pi.(*JoinChain)
+	pisc.JoinChain = &ProtoJoinChain{Latest: *latest}
+	if err := pisc.Start(); err != nil {
+		return err
+	}
+	select {
+	case sigs := <-pisc.JoinChainReply:
+		if len(sigs) != len(nodes) {
+			return errors.New("node couldn't fetch the skipchain")
+		}
+	case <-time.After(s.propTimeout * 4):
+		return errors.New("timeout waiting for the nodes to fetch the skipchain")
+	}
+	return nil
+}
+
+// syncToBlock fetches all blocks of the skipchain up to latest from the
+// roster of latest. It is called on a node that joins or leaves the roster.
+func (s *Service) syncToBlock(latest *SkipBlock) error {
+	start := latest.SkipChainID()
+	if known := s.db.GetLatestByGenesis(start); known != nil {
+		start = known.Hash
+	}
+	if err := s.syncChain(latest.Roster, start, false); err != nil {
+		return err
+	}
+	if s.db.GetByID(latest.Hash) == nil {
+		return errors.New("didn't get latest block")
+	}
+	return nil
+}
+
 // GetUpdateChain returns a slice of SkipBlocks which describe the part of the
 // skipchain from the latest block the caller knows to the latest
 // SkipBlock we know. The last block in the returned slice of blocks is
 // not guaranteed to have no forward links. It is up to the caller
 // to continue following forward links with the new roster if necessary.
+//
+// The reply follows the highest forward-link of every block, because the
+// caller verifies every link and the back-link of the next block. So it
+// doesn't use the index by height: the path only holds a logarithmic number
+// of blocks, and every one of them is fetched by its ID.
 func (s *Service) GetUpdateChain(guc *GetUpdateChain) (network.Message, error) {
 	block := s.db.GetByID(guc.LatestID)
 	if block == nil {
+		if snap := s.db.GetPruned(guc.LatestID); snap != nil {
+			return &GetUpdateChainReply{Pruned: snap}, nil
+		}
 		return nil, errors.New("Couldn't find latest skipblock")
 	}
 
+	reply := &GetUpdateChainReply{}
 	blocks := []*SkipBlock{block.Copy()}
 	log.Lvlf3("Starting to search chain at %s", s.Context.ServerIdentity())
 	for block.GetForwardLen() > 0 {
@@ -372,6 +616,9 @@ func (s *Service) GetUpdateChain(guc *GetUpdateChain) (network.Message, error) {
 			// this chain. The caller will be responsible
 			// to issue a new GetUpdateChain with the
 			// latest Roster to keep traversing.
+			// If the block has been pruned, the caller is
+			// redirected to the archive nodes.
+			reply.Pruned = s.db.GetPruned(link.To)
 			break
 		} else {
 			if i, _ := next.Roster.Search(s.ServerIdentity().ID); i < 0 {
@@ -384,13 +631,17 @@ func (s *Service) GetUpdateChain(guc *GetUpdateChain) (network.Message, error) {
 		blocks = append(blocks, next.Copy())
 	}
 	log.Lvl3("Found", len(blocks), "blocks")
-	reply := &GetUpdateChainReply{Update: blocks}
+	reply.Update = blocks
 
 	return reply, nil
 }
 
 // Search the local DB starting at bl and finding the latest block we know.
 func (s *Service) findLatest(bl *SkipBlock) *SkipBlock {
+	if indexed := s.db.GetLatestByGenesis(bl.SkipChainID()); indexed != nil &&
+		indexed.Index > bl.Index {
+		bl = indexed
+	}
 	for {
 		if len(bl.ForwardLink) == 0 {
 			return bl
@@ -398,18 +649,28 @@ func (s *Service) findLatest(bl *SkipBlock) *SkipBlock {
 		next := bl.ForwardLink[len(bl.ForwardLink)-1].To
 		nextBl := s.db.GetByID(next)
 		if nextBl == nil {
-			return bl
+			snap := s.db.GetPruned(next)
+			if snap == nil || bl.Index >= snap.Index {
+				return bl
+			}
+			nextBl = s.db.GetByID(snap.BlockID)
+			if nextBl == nil {
+				return bl
+			}
 		}
 		bl = nextBl
 	}
 }
 
 // syncChain communicates with conodes in the Roster via getBlocks
-// in order traverse the chain and save the blocks locally.
-func (s *Service) syncChain(roster *onet.Roster, latest SkipBlockID) error {
+// in order traverse the chain and save the blocks locally. If skipping
+// is false, all blocks are fetched, else only the blocks on the path
+// of the highest forward-links. The fetched blocks are checked for forks
+// like all other blocks before they are stored.
+func (s *Service) syncChain(roster *onet.Roster, latest SkipBlockID, skipping bool) error {
 	// loop on getBlocks, fetching 10 at a time
 	for {
-		blocks, err := s.getBlocks(roster, latest, 10)
+		blocks, err := s.getBlocks(roster, latest, 10, skipping)
 		if err != nil {
 			return err
 		}
@@ -418,7 +679,9 @@ func (s *Service) syncChain(roster *onet.Roster, latest SkipBlockID) error {
 			if err := sb.VerifyForwardSignatures(); err != nil {
 				return err
 			}
-			s.db.Store(sb)
+			if err := s.storeBlock(sb); err != nil {
+				return err
+			}
 			if len(sb.ForwardLink) == 0 {
 				return nil
 			}
@@ -431,7 +694,7 @@ func (s *Service) syncChain(roster *onet.Roster, latest SkipBlockID) error {
 // skiplist forward from id. It contacts a random subgroup of some of the nodes
 // in the roster, in order to find an answer, even in the case that a few
 // nodes in the network are down.
-func (s *Service) getBlocks(roster *onet.Roster, id SkipBlockID, n int) ([]*SkipBlock, error) {
+func (s *Service) getBlocks(roster *onet.Roster, id SkipBlockID, n int, skipping bool) ([]*SkipBlock, error) {
 	subCount := (len(roster.List)-1)/3 + 1
 	r := roster.RandomSubset(s.ServerIdentity(), subCount)
 	tr := r.GenerateStar()
@@ -444,10 +707,10 @@ func (s *Service) getBlocks(roster *onet.Roster, id SkipBlockID, n int) ([]*Skip
 	pisc.GetBlocks = &ProtoGetBlocks{
 		SBID:     id,
 		Count:    n,
-		Skipping: true,
+		Skipping: skipping,
 	}
 	if err := pi.Start(); err != nil {
-		log.ErrFatal(err)
+		return nil, err
 	}
 	select {
 	case result := <-pisc.GetBlocksReply:
@@ -469,7 +732,7 @@ func (s *Service) getLastBlock(roster *onet.Roster, latest SkipBlockID) (*SkipBl
 	}
 	// loop on getBlocks, fetching 10 at a time
 	for {
-		blocks, err := s.getBlocks(roster, latest, 10)
+		blocks, err := s.getBlocks(roster, latest, 10, true)
 		if err != nil {
 			return nil, err
 		}
@@ -491,6 +754,9 @@ func (s *Service) getLastBlock(roster *onet.Roster, latest SkipBlockID) (*SkipBl
 func (s *Service) GetSingleBlock(id *GetSingleBlock) (*SkipBlock, error) {
 	sb := s.db.GetByID(id.ID)
 	if sb == nil {
+		if snap := s.db.GetPruned(id.ID); snap != nil {
+			return nil, snap.errPruned()
+		}
 		return nil, errors.New(
 			"No such block")
 
@@ -510,6 +776,22 @@ func (s *Service) GetSingleBlockByIndex(id *GetSingleBlockByIndex) (*SkipBlock,
 	if sb.Index == id.Index {
 		return sb, nil
 	}
+	if snap := s.db.GetSnapshot(id.Genesis); snap != nil {
+		if id.Index < snap.Index {
+			return nil, snap.errPruned()
+		}
+		sb = s.db.GetByID(snap.BlockID)
+		if sb == nil {
+			return nil, errors.New("didn't find snapshot block")
+		}
+		if sb.Index == id.Index {
+			return sb, nil
+		}
+	}
+	if indexed := s.db.GetByIndex(id.Genesis, id.Index); indexed != nil {
+		return indexed, nil
+	}
+	// Blocks stored by older versions might not be indexed.
 	for len(sb.ForwardLink) > 0 {
 		sb = s.db.GetByID(sb.ForwardLink[0].To)
 		if sb == nil {
@@ -524,6 +806,43 @@ func (s *Service) GetSingleBlockByIndex(id *GetSingleBlockByIndex) (*SkipBlock,
 
 }
 
+// GetProof returns the shortest chain of blocks going from the genesis-block
+// to the requested block. At every step the highest forward-link that doesn't
+// jump over the requested block is followed.
+func (s *Service) GetProof(req *GetProof) (*GetProofReply, error) {
+	target := s.db.GetByID(req.ID)
+	if target == nil {
+		if snap := s.db.GetPruned(req.ID); snap != nil {
+			return nil, snap.errPruned()
+		}
+		return nil, errors.New("No such block")
+	}
+	sb := s.db.GetByID(target.SkipChainID())
+	if sb == nil {
+		return nil, errors.New("No such genesis-block")
+	}
+	proof := Proof{sb}
+	for !sb.Hash.Equal(target.Hash) {
+		var next *SkipBlock
+		for i := len(sb.ForwardLink) - 1; i >= 0; i-- {
+			next = s.db.GetByID(sb.ForwardLink[i].To)
+			if next != nil && next.Index <= target.Index {
+				break
+			}
+			next = nil
+		}
+		if next == nil {
+			if snap := s.db.GetSnapshot(target.SkipChainID()); snap != nil {
+				return nil, snap.errPruned()
+			}
+			return nil, fmt.Errorf("didn't find a forward link from block %d", sb.Index)
+		}
+		sb = next
+		proof = append(proof, sb)
+	}
+	return &GetProofReply{Proof: proof}, nil
+}
+
 // GetAllSkipchains returns a list of all known skipchains
 func (s *Service) GetAllSkipchains(id *GetAllSkipchains) (*GetAllSkipchainsReply, error) {
 	// Write all known skipblocks to a map, thus removing double blocks.
@@ -717,6 +1036,39 @@ func (s *Service) DelFollow(del *DelFollow) (*EmptyReply, error) {
 	return &EmptyReply{}, nil
 }
 
+// SetPruning adds, updates or removes the pruning configuration of a
+// skipchain. The blocks are pruned as soon as new blocks arrive.
+func (s *Service) SetPruning(sp *SetPruning) (*EmptyReply, error) {
+	if sp.Keep < 0 {
+		return nil, errors.New("cannot keep a negative number of blocks")
+	}
+	if !s.verifySigs(sp.message(), sp.Signature) {
+		return nil, errors.New("wrong signature of unknown signer")
+	}
+	if s.db.GetByID(sp.SkipchainID) == nil {
+		return nil, errors.New("unknown skipchain")
+	}
+
+	s.storageMutex.Lock()
+	var pruning []PruneConfig
+	for _, pc := range s.Storage.Pruning {
+		if !pc.SkipchainID.Equal(sp.SkipchainID) {
+			pruning = append(pruning, pc)
+		}
+	}
+	if sp.Keep > 0 {
+		pruning = append(pruning, PruneConfig{
+			SkipchainID: sp.SkipchainID,
+			Keep:        sp.Keep,
+			Archive:     sp.Archive,
+		})
+	}
+	s.Storage.Pruning = pruning
+	s.storageMutex.Unlock()
+	s.save()
+	return &EmptyReply{}, nil
+}
+
 // ListFollow returns the skipchain-ids that are followed
 func (s *Service) ListFollow(list *ListFollow) (*ListFollowReply, error) {
 	reply := &ListFollowReply{}
@@ -763,6 +1115,13 @@ func (s *Service) NewProtocol(ti *onet.TreeNodeInstance, conf *onet.GenericConfi
 			pigu.DB = s.db
 		}
 	}
+	if ti.ProtocolName() == ProtocolJoinChain {
+		pi, err = NewProtocolJoinChain(ti)
+		if err == nil {
+			pijc := pi.(*JoinChain)
+			pijc.Sync = s.syncToBlock
+		}
+	}
 	return
 }
 
@@ -867,7 +1226,7 @@ func (s *Service) bftForwardLinkLevel0(msg, data []byte) bool {
 			return false
 		}
 		log.Lvl3(s.ServerIdentity(), "Didn't find src-skipblock, trying to sync")
-		if err := s.syncChain(fs.Newest.Roster, fs.Previous); err != nil {
+		if err := s.syncChain(fs.Newest.Roster, fs.Previous, true); err != nil {
 			log.Error("failed to sync skipchain", err)
 			return false
 		}
@@ -1092,7 +1451,85 @@ func (s *Service) propagateSkipBlock(msg network.Message) {
 			log.Lvlf2("%s: block is not friendly: %x", s.ServerIdentity(), sb.Hash)
 			return
 		}
-		s.db.Store(sb)
+		if err := s.storeBlock(sb); err != nil {
+			log.Error(s.ServerIdentity(), err)
+			return
+		}
+	}
+	s.prune(sbs.SkipBlocks)
+}
+
+// storeBlock stores the block and sends it to all subscribers of its
+// skipchain. If the block conflicts with a stored block, the stored block is
+// kept, the evidence of the fork is stored and an error is returned. Blocks
+// that can't be checked for forks aren't stored either.
+func (s *Service) storeBlock(sb *SkipBlock) error {
+	fe, err := s.db.FindFork(sb)
+	if err != nil {
+		return err
+	}
+	if fe != nil {
+		if err := s.reportFork(fe); err != nil {
+			return err
+		}
+		return fmt.Errorf("block %x forks skipchain %x at index %d",
+			sb.Hash, fe.GenesisID, fe.Index)
+	}
+	if s.db.Store(sb) == nil {
+		return fmt.Errorf("couldn't store block %x", sb.Hash)
+	}
+	if s.streaming.isSubscribed(sb.SkipChainID()) {
+		if stored := s.db.GetByID(sb.Hash); stored != nil {
+			s.streaming.notify(stored)
+		}
+	}
+	return nil
+}
+
+// reportFork signs and stores the evidence of a fork.
+func (s *Service) reportFork(fe *ForkEvidence) error {
+	log.Errorf("%s: detected fork of skipchain %x at index %d, signed by %v",
+		s.ServerIdentity(), fe.GenesisID, fe.Index, fe.Culprits)
+	fe.Reporter = s.ServerIdentity()
+	fe.Time = time.Now().UnixNano()
+	sig, err := schnorr.Sign(cothority.Suite, s.ServerIdentity().GetPrivate(), fe.Hash())
+	if err != nil {
+		return err
+	}
+	fe.Signature = sig
+	if err := s.db.StoreForkEvidence(fe); err != nil {
+		return errors.New("couldn't store fork evidence: " + err.Error())
+	}
+	return nil
+}
+
+// GetForkEvidence returns all evidence of forks of the requested skipchain
+// that this conode detected.
+func (s *Service) GetForkEvidence(req *GetForkEvidence) (*GetForkEvidenceReply, error) {
+	evidence, err := s.db.GetForkEvidence(req.SkipchainID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetForkEvidenceReply{Evidence: evidence}, nil
+}
+
+// prune removes old blocks from all configured skipchains that got new
+// blocks.
+func (s *Service) prune(sbs []*SkipBlock) {
+	s.storageMutex.Lock()
+	pruning := make([]PruneConfig, len(s.Storage.Pruning))
+	copy(pruning, s.Storage.Pruning)
+	s.storageMutex.Unlock()
+
+	for _, pc := range pruning {
+		for _, sb := range sbs {
+			if sb.SkipChainID().Equal(pc.SkipchainID) {
+				if _, err := s.db.Prune(pc.SkipchainID, pc.Keep, pc.Archive); err != nil {
+					log.Error(s.ServerIdentity(), "couldn't prune skipchain:", err)
+				}
+				break
+			}
+		}
 	}
 }
 
@@ -1276,10 +1713,23 @@ func sliceToArr(msg []byte) [32]byte {
 }
 
 func newSkipchainService(c *onet.Context) (onet.Service, error) {
+	return newService(c, Config{})
+}
+
+func newService(c *onet.Context, cfg Config) (onet.Service, error) {
 	db, bucket := c.GetAdditionalBucket([]byte("skipblocks"))
+	st, err := openStorage(db, cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	sbdb, err := NewSkipBlockDBFromStorage(st, bucket)
+	if err != nil {
+		return nil, err
+	}
 	s := &Service{
 		ServiceProcessor: onet.NewServiceProcessor(c),
-		db:               NewSkipBlockDB(db, bucket),
+		config:           cfg,
+		db:               sbdb,
 		Storage:          &Storage{},
 		verifiers:        map[VerifierID]SkipBlockVerifier{},
 		propTimeout:      defaultPropagateTimeout,
@@ -1289,9 +1739,12 @@ func newSkipchainService(c *onet.Context) (onet.Service, error) {
 		return nil, err
 	}
 	log.ErrFatal(s.RegisterHandlers(s.StoreSkipBlock, s.GetUpdateChain,
-		s.GetSingleBlock, s.GetSingleBlockByIndex, s.GetAllSkipchains,
+		s.GetSingleBlock, s.GetSingleBlockByIndex, s.GetProof, s.GetAllSkipchains,
 		s.CreateLinkPrivate, s.Unlink, s.AddFollow, s.ListFollow,
-		s.DelFollow, s.Listlink))
+		s.DelFollow, s.Listlink, s.SetPruning, s.ProposeRosterChange,
+		s.GetForkEvidence, s.StoreBatchedPayload, s.SetBatching,
+		s.ExportSkipchain, s.ImportSkipchain))
+	log.ErrFatal(s.RegisterStreamingHandler(s.StreamSkipBlocks))
 	s.ServiceProcessor.RegisterStatusReporter("Skipblock", s.db)
 
 	if err := s.registerVerification(VerifyBase, s.verifyFuncBase); err != nil {
@@ -1307,7 +1760,6 @@ func newSkipchainService(c *onet.Context) (onet.Service, error) {
 		return nil, err
 	}
 
-	var err error
 	s.propagate, err = messaging.NewPropagationFunc(c, "SkipchainPropagate", s.propagateSkipBlock, -1)
 	if err != nil {
 		return nil, err
@@ -1322,6 +1774,7 @@ func newSkipchainService(c *onet.Context) (onet.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.startCatchup()
 
 	return s, nil
 }
@@ -9,9 +9,10 @@ import (
 	"testing"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	"github.com/dedis/cothority"
+	"github.com/dedis/cothority/byzcoinx"
 	"github.com/dedis/kyber"
+	"github.com/dedis/kyber/sign/cosi"
 	"github.com/dedis/kyber/sign/schnorr"
 	"github.com/dedis/kyber/util/key"
 	"github.com/dedis/onet"
@@ -246,6 +247,393 @@ func TestService_MultiLevel(t *testing.T) {
 	}
 }
 
+func TestService_GetProof(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	_, el, genService := local.MakeSRS(cothority.Suite, 3, skipchainSID)
+	service := genService.(*Service)
+
+	sbRoot, err := makeGenesisRosterArgs(service, el, nil, VerificationNone, 2, 3)
+	log.ErrFatal(err)
+	blocks := []*SkipBlock{sbRoot}
+	for sbi := 1; sbi <= 8; sbi++ {
+		sb := NewSkipBlock()
+		sb.Roster = el
+		psbr, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+		blocks = append(blocks, psbr.Latest)
+	}
+
+	for _, sb := range blocks {
+		reply, err := service.GetProof(&GetProof{ID: sb.Hash})
+		require.Nil(t, err)
+		require.Nil(t, reply.Proof.Verify(sbRoot.Hash))
+		require.True(t, reply.Proof.Target().Equal(sb))
+	}
+
+	// The proof for the 8th block should use the higher-level forward-links:
+	// 0 -> 4 -> 8
+	reply, err := service.GetProof(&GetProof{ID: blocks[8].Hash})
+	require.Nil(t, err)
+	proof := reply.Proof
+	require.Equal(t, 3, len(proof))
+	require.Equal(t, 4, proof[1].Index)
+
+	require.NotNil(t, proof.Verify(blocks[1].Hash))
+	require.NotNil(t, Proof{}.Verify(sbRoot.Hash))
+	require.NotNil(t, Proof{sbRoot, blocks[8]}.Verify(sbRoot.Hash))
+
+	proof[1].Data = []byte{1}
+	require.NotNil(t, proof.Verify(sbRoot.Hash))
+	proof[1].Data = blocks[4].Data
+	require.Nil(t, proof.Verify(sbRoot.Hash))
+	proof[0].ForwardLink[2].Signature.Sig[0] ^= 0xff
+	require.NotNil(t, proof.Verify(sbRoot.Hash))
+
+	_, err = service.GetProof(&GetProof{ID: SkipBlockID{1, 2, 3}})
+	require.NotNil(t, err)
+}
+
+func TestService_Prune(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	servers, el, genService := local.MakeSRS(cothority.Suite, 3, skipchainSID)
+	service := genService.(*Service)
+	services := make([]*Service, len(servers))
+	for i, s := range local.GetServices(servers, skipchainSID) {
+		services[i] = s.(*Service)
+	}
+
+	sbRoot, err := makeGenesisRoster(service, el)
+	log.ErrFatal(err)
+	archive := []*network.ServerIdentity{el.List[0]}
+	for _, s := range services[1:] {
+		_, err := s.SetPruning(&SetPruning{SkipchainID: sbRoot.Hash, Keep: 2,
+			Archive: archive})
+		require.Nil(t, err)
+	}
+	_, err = service.SetPruning(&SetPruning{SkipchainID: sbRoot.Hash, Keep: -1})
+	require.NotNil(t, err)
+	_, err = service.SetPruning(&SetPruning{SkipchainID: SkipBlockID{1}, Keep: 2})
+	require.NotNil(t, err)
+
+	blocks := []*SkipBlock{sbRoot}
+	for sbi := 1; sbi <= 6; sbi++ {
+		sb := NewSkipBlock()
+		sb.Roster = el
+		psbr, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+		blocks = append(blocks, psbr.Latest)
+	}
+
+	// The leader is an archive node and keeps everything.
+	sb, err := service.GetSingleBlockByIndex(&GetSingleBlockByIndex{Genesis: sbRoot.Hash, Index: 1})
+	require.Nil(t, err)
+	require.True(t, sb.Equal(blocks[1]))
+
+	for _, s := range services[1:] {
+		snap := s.db.GetSnapshot(sbRoot.Hash)
+		require.NotNil(t, snap)
+		require.Equal(t, 4, snap.Index)
+		require.Nil(t, snap.Verify(el))
+		require.NotNil(t, snap.Verify(onet.NewRoster(el.List[1:])))
+		require.NotNil(t, snap.Verify(nil))
+		links := snap.Links
+		snap.Links = links[1:]
+		require.NotNil(t, snap.Verify(el))
+		snap.Links = links
+
+		_, err := s.GetSingleBlockByIndex(&GetSingleBlockByIndex{Genesis: sbRoot.Hash, Index: 1})
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "pruned")
+		_, err = s.GetSingleBlock(&GetSingleBlock{ID: blocks[2].Hash})
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "pruned")
+		sb, err := s.GetSingleBlockByIndex(&GetSingleBlockByIndex{Genesis: sbRoot.Hash, Index: 5})
+		require.Nil(t, err)
+		require.True(t, sb.Equal(blocks[5]))
+
+		reply, err := s.GetUpdateChain(&GetUpdateChain{LatestID: sbRoot.Hash})
+		require.Nil(t, err)
+		guc := reply.(*GetUpdateChainReply)
+		require.NotNil(t, guc.Pruned)
+		require.True(t, guc.Pruned.Archive[0].Equal(el.List[0]))
+		reply, err = s.GetUpdateChain(&GetUpdateChain{LatestID: blocks[3].Hash})
+		require.Nil(t, err)
+		require.Equal(t, 0, len(reply.(*GetUpdateChainReply).Update))
+		reply, err = s.GetUpdateChain(&GetUpdateChain{LatestID: blocks[4].Hash})
+		require.Nil(t, err)
+		require.Equal(t, 3, len(reply.(*GetUpdateChainReply).Update))
+	}
+
+	// Disable pruning again.
+	_, err = services[1].SetPruning(&SetPruning{SkipchainID: sbRoot.Hash, Keep: 0})
+	require.Nil(t, err)
+	require.Equal(t, 0, len(services[1].Storage.Pruning))
+}
+
+func TestService_StreamSkipBlocks(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	_, el, genService := local.MakeSRS(cothority.Suite, 3, skipchainSID)
+	service := genService.(*Service)
+
+	sbRoot, err := makeGenesisRoster(service, el)
+	log.ErrFatal(err)
+	addBlock := func() *SkipBlock {
+		sb := NewSkipBlock()
+		sb.Roster = el
+		psbr, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+		return psbr.Latest
+	}
+	receive := func(out chan *StreamSkipBlocksReply) *SkipBlock {
+		select {
+		case reply := <-out:
+			return reply.Block
+		case <-time.After(5 * time.Second):
+			t.Fatal("didn't receive block")
+		}
+		return nil
+	}
+	sb1 := addBlock()
+
+	_, _, err = service.StreamSkipBlocks(&StreamSkipBlocks{GenesisID: sb1.Hash})
+	require.NotNil(t, err)
+	_, _, err = service.StreamSkipBlocks(&StreamSkipBlocks{GenesisID: sbRoot.Hash, FromIndex: -1})
+	require.NotNil(t, err)
+
+	// Resume from index 1 and wait for the following block and the new
+	// forward-link of block 1.
+	out, stop, err := service.StreamSkipBlocks(&StreamSkipBlocks{GenesisID: sbRoot.Hash, FromIndex: 1})
+	require.Nil(t, err)
+	require.True(t, receive(out).Equal(sb1))
+	sb2 := addBlock()
+	var gotBlock, gotLink bool
+	for !gotBlock || !gotLink {
+		sb := receive(out)
+		require.True(t, sb.Index >= 1)
+		if sb.Equal(sb2) {
+			gotBlock = true
+		}
+		if sb.Equal(sb1) && len(sb.ForwardLink) > 0 {
+			require.True(t, sb.ForwardLink[0].To.Equal(sb2.Hash))
+			gotLink = true
+		}
+	}
+
+	stop <- true
+	for range out {
+	}
+	require.False(t, service.streaming.isSubscribed(sbRoot.Hash))
+
+	// Starting after the latest block only sends new blocks.
+	out, stop, err = service.StreamSkipBlocks(&StreamSkipBlocks{GenesisID: sbRoot.Hash, FromIndex: 3})
+	require.Nil(t, err)
+	sb3 := addBlock()
+	for sb := receive(out); !sb.Equal(sb3); sb = receive(out) {
+		require.True(t, sb.Index >= 3)
+	}
+	close(stop)
+	for range out {
+	}
+}
+
+func TestService_ProposeRosterChange(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	servers, ro, _ := local.MakeSRS(cothority.Suite, 4, skipchainSID)
+	services := make([]*Service, len(servers))
+	for i, s := range local.GetServices(servers, skipchainSID) {
+		services[i] = s.(*Service)
+	}
+	service := services[0]
+	el := onet.NewRoster(ro.List[:3])
+
+	sbRoot, err := makeGenesisRosterArgs(service, el, nil, VerificationNone, 2, 3)
+	log.ErrFatal(err)
+	for i := 0; i < 2; i++ {
+		sb := NewSkipBlock()
+		sb.Roster = el
+		_, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+	}
+
+	// Only the leader can change the roster, and it cannot remove itself.
+	_, err = services[1].ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash,
+		Add: ro.List[3:]})
+	require.NotNil(t, err)
+	_, err = service.ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash,
+		Remove: ro.List[:1]})
+	require.NotNil(t, err)
+	_, err = service.ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash,
+		Add: ro.List[1:2]})
+	require.NotNil(t, err)
+	_, err = service.ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash})
+	require.NotNil(t, err)
+
+	// The new node only follows another skipchain.
+	services[3].Storage.FollowIDs = []SkipBlockID{{0}}
+	_, err = service.ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash,
+		Add: ro.List[3:]})
+	require.NotNil(t, err)
+	require.Equal(t, 0, services[3].db.Length())
+
+	// A request for an older block is refused.
+	services[3].Storage.FollowIDs = []SkipBlockID{sbRoot.Hash}
+	_, err = service.ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash,
+		Latest: sbRoot.Hash, Add: ro.List[3:]})
+	require.NotNil(t, err)
+
+	reply, err := service.ProposeRosterChange(&ProposeRosterChange{SkipchainID: sbRoot.Hash,
+		Add: ro.List[3:], Remove: ro.List[2:3], Data: []byte("new roster")})
+	require.Nil(t, err)
+	latest := reply.Latest
+	require.Equal(t, 3, latest.Index)
+	require.Equal(t, []byte("new roster"), latest.Data)
+	require.Equal(t, 3, len(latest.Roster.List))
+	i, _ := latest.Roster.Search(ro.List[3].ID)
+	require.Equal(t, 2, i)
+	i, _ = latest.Roster.Search(ro.List[2].ID)
+	require.Equal(t, -1, i)
+
+	// The new node got all blocks, not only the ones on the highest links.
+	for index := 0; index <= 3; index++ {
+		sb, err := services[3].GetSingleBlockByIndex(&GetSingleBlockByIndex{
+			Genesis: sbRoot.Hash, Index: index})
+		require.Nil(t, err)
+		require.Equal(t, index, sb.Index)
+	}
+	// The leaving node knows it left.
+	require.NotNil(t, services[2].db.GetByID(latest.Hash))
+
+	// The new roster is able to add blocks.
+	sb := NewSkipBlock()
+	sb.Roster = latest.Roster
+	psbr, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: latest.Hash, NewBlock: sb})
+	require.Nil(t, err)
+	require.Equal(t, 4, psbr.Latest.Index)
+
+	// With a linked client, the request needs to be signed for the latest
+	// block and the signature covers the data.
+	kp := key.NewKeyPair(cothority.Suite)
+	service.Storage.Clients = []kyber.Point{kp.Public}
+	prc := &ProposeRosterChange{SkipchainID: sbRoot.Hash, Remove: ro.List[3:]}
+	sig, err := schnorr.Sign(cothority.Suite, kp.Private, prc.message())
+	require.Nil(t, err)
+	prc.Signature = &sig
+	_, err = service.ProposeRosterChange(prc)
+	require.NotNil(t, err)
+	prc.Latest = psbr.Latest.Hash
+	sig, err = schnorr.Sign(cothority.Suite, kp.Private, prc.message())
+	require.Nil(t, err)
+	prc.Data = []byte("other data")
+	_, err = service.ProposeRosterChange(prc)
+	require.NotNil(t, err)
+	prc.Data = nil
+	reply, err = service.ProposeRosterChange(prc)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(reply.Latest.Roster.List))
+}
+
+func TestService_StoreBatchedPayload(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	servers, el, genService := local.MakeSRS(cothority.Suite, 3, skipchainSID)
+	service := genService.(*Service)
+
+	sbRoot, err := makeGenesisRoster(service, el)
+	log.ErrFatal(err)
+	_, err = service.StoreBatchedPayload(&StoreBatchedPayload{SkipchainID: sbRoot.Hash,
+		Payload: []byte("disabled")})
+	require.NotNil(t, err)
+
+	_, err = service.SetBatching(&SetBatching{SkipchainID: sbRoot.Hash, IntervalMS: -1})
+	require.NotNil(t, err)
+	_, err = service.SetBatching(&SetBatching{SkipchainID: sbRoot.Hash, IntervalMS: 200})
+	require.Nil(t, err)
+	follower := local.GetServices(servers, skipchainSID)[1].(*Service)
+	_, err = follower.SetBatching(&SetBatching{SkipchainID: sbRoot.Hash, IntervalMS: 200})
+	require.Nil(t, err)
+	_, err = follower.StoreBatchedPayload(&StoreBatchedPayload{SkipchainID: sbRoot.Hash,
+		Payload: []byte("not leader")})
+	require.NotNil(t, err)
+
+	// All payloads sent during the interval end up in the same block.
+	n := 5
+	receipts := make([]*PayloadReceipt, n)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := service.StoreBatchedPayload(&StoreBatchedPayload{
+				SkipchainID: sbRoot.Hash,
+				Payload:     []byte{byte(i)},
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			receipts[i] = reply.Receipt
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+	positions := make(map[int]bool)
+	for i, r := range receipts {
+		require.Nil(t, r.Verify([]byte{byte(i)}))
+		require.NotNil(t, r.Verify([]byte("other")))
+		require.True(t, r.Block.Equal(receipts[0].Block))
+		positions[r.Position] = true
+	}
+	require.Equal(t, n, len(positions))
+	require.Equal(t, 1, receipts[0].Block.Index)
+	bd, err := GetBatchData(receipts[0].Block)
+	require.Nil(t, err)
+	require.Equal(t, n, len(bd.Payloads))
+
+	// The next batch goes into a new block.
+	reply, err := service.StoreBatchedPayload(&StoreBatchedPayload{SkipchainID: sbRoot.Hash,
+		Payload: []byte("next")})
+	require.Nil(t, err)
+	require.Equal(t, 2, reply.Receipt.Block.Index)
+
+	// A payload bound to the latest block cannot be replayed.
+	req := &StoreBatchedPayload{SkipchainID: sbRoot.Hash,
+		Latest: reply.Receipt.Block.Hash, Payload: []byte("bound")}
+	reply, err = service.StoreBatchedPayload(req)
+	require.Nil(t, err)
+	require.Equal(t, 3, reply.Receipt.Block.Index)
+	_, err = service.StoreBatchedPayload(req)
+	require.NotNil(t, err)
+
+	// The same payload is only pending once for the same latest block.
+	latest := reply.Receipt.Block.Hash
+	first := service.batches.add(sbRoot.Hash, latest, []byte("twice"), time.Hour,
+		func(SkipBlockID) {})
+	second := service.batches.add(sbRoot.Hash, latest, []byte("twice"), time.Hour,
+		func(SkipBlockID) {})
+	require.NotNil(t, (<-second).err)
+	require.Equal(t, 0, len(first))
+	pb := service.batches.take(sbRoot.Hash)
+	pb.timer.Stop()
+	require.Equal(t, 1, len(pb.payloads))
+
+	_, err = service.SetBatching(&SetBatching{SkipchainID: sbRoot.Hash})
+	require.Nil(t, err)
+	require.Equal(t, 0, len(service.Storage.Batching))
+}
+
 func TestService_Verification(t *testing.T) {
 	local := onet.NewLocalTest(cothority.Suite)
 	defer waitPropagationFinished(t, local)
@@ -1045,6 +1433,227 @@ func TestService_LeaderCatchup(t *testing.T) {
 	}
 }
 
+func TestService_CatchUp(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	servers, el, genService := local.MakeSRS(cothority.Suite, 3, skipchainSID)
+	service := genService.(*Service)
+	follower := local.GetServices(servers, skipchainSID)[2].(*Service)
+
+	sbRoot, err := makeGenesisRosterArgs(service, el, nil, VerificationNone, 2, 3)
+	log.ErrFatal(err)
+	var blocks []*SkipBlock
+	for i := 0; i < 25; i++ {
+		sb := NewSkipBlock()
+		sb.Roster = el
+		psbr, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+		blocks = append(blocks, psbr.Latest)
+	}
+
+	// Nothing to fetch if the follower is up to date.
+	n, err := follower.catchUp(follower.findLatest(sbRoot))
+	require.Nil(t, err)
+	require.Equal(t, 0, n)
+
+	// Simulate a follower that has been offline since block 3.
+	nukeBlocksFrom(t, follower.db, blocks[3].Hash)
+	require.Nil(t, follower.db.GetByID(blocks[24].Hash))
+	follower.catchUpAll()
+	for _, sb := range blocks {
+		require.NotNil(t, follower.db.GetByID(sb.Hash))
+	}
+	latest, err := follower.db.GetLatest(sbRoot)
+	require.Nil(t, err)
+	require.True(t, latest.Equal(blocks[24]))
+
+	// A closed service stops its timer and doesn't catch up anymore.
+	require.Nil(t, follower.Close())
+	require.False(t, follower.catchupTimer.Stop())
+	follower.scheduleCatchup(time.Millisecond)
+	require.False(t, follower.catchupTimer.Stop())
+	nukeBlocksFrom(t, follower.db, blocks[20].Hash)
+	follower.catchUpAll()
+	require.Nil(t, follower.db.GetByID(blocks[24].Hash))
+}
+
+func TestService_ForkEvidence(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	servers, el, genService := local.MakeSRS(cothority.Suite, 3, skipchainSID)
+	service := genService.(*Service)
+	follower := local.GetServices(servers, skipchainSID)[1].(*Service)
+
+	sbRoot, err := makeGenesisRoster(service, el)
+	log.ErrFatal(err)
+	var blocks []*SkipBlock
+	for i := 0; i < 2; i++ {
+		sb := NewSkipBlock()
+		sb.Roster = el
+		psbr, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+		blocks = append(blocks, psbr.Latest)
+	}
+	block1 := follower.db.GetByID(blocks[0].Hash)
+
+	// Without fork, no evidence.
+	fe, err := follower.db.FindFork(block1)
+	require.Nil(t, err)
+	require.Nil(t, fe)
+
+	// The roster also signs a different block 2.
+	fake := blocks[1].Copy()
+	fake.Data = []byte("fork")
+	fake.updateHash()
+	fl := NewForwardLink(block1, fake)
+	var privates []kyber.Scalar
+	for _, s := range servers {
+		privates = append(privates, local.GetPrivate(s))
+	}
+	fl.Signature = signCollectively(t, el, privates, fl.Hash())
+	forked := block1.Copy()
+	forked.ForwardLink = []*ForwardLink{fl}
+	_, err = follower.db.FindFork(fake)
+	require.NotNil(t, err)
+	require.NotNil(t, follower.storeBlock(fake))
+	require.Nil(t, follower.db.GetByID(fake.Hash))
+
+	// The forked block is refused and the stored block is kept.
+	require.NotNil(t, follower.storeBlock(forked))
+	require.True(t, follower.db.GetByID(block1.Hash).ForwardLink[0].To.Equal(blocks[1].Hash))
+	evidence, err := follower.GetForkEvidence(&GetForkEvidence{SkipchainID: sbRoot.Hash})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(evidence.Evidence))
+	fe = evidence.Evidence[0]
+	require.Nil(t, fe.Verify())
+	require.Equal(t, 2, fe.Index)
+	require.Equal(t, 3, len(fe.Culprits))
+	require.True(t, fe.Reporter.Equal(follower.ServerIdentity()))
+
+	// The same fork is only stored once.
+	require.NotNil(t, follower.storeBlock(forked))
+	evidence, err = follower.GetForkEvidence(&GetForkEvidence{})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(evidence.Evidence))
+	evidence, err = follower.GetForkEvidence(&GetForkEvidence{SkipchainID: SkipBlockID{1}})
+	require.Nil(t, err)
+	require.Equal(t, 0, len(evidence.Evidence))
+
+	// Tampering with the evidence is detected.
+	fe.Culprits = fe.Culprits[1:]
+	require.NotNil(t, fe.Verify())
+}
+
+func TestService_ExportImport(t *testing.T) {
+	local := onet.NewLocalTest(cothority.Suite)
+	defer waitPropagationFinished(t, local)
+	defer local.CloseAll()
+	servers, el, genService := local.MakeSRS(cothority.Suite, 4, skipchainSID)
+	service := genService.(*Service)
+	importer := local.GetServices(servers, skipchainSID)[3].(*Service)
+	ro := onet.NewRoster(el.List[:3])
+
+	sbRoot, err := makeGenesisRosterArgs(service, ro, nil, VerificationNone, 2, 3)
+	log.ErrFatal(err)
+	for i := 0; i < 5; i++ {
+		sb := NewSkipBlock()
+		sb.Roster = ro
+		sb.Data = []byte{byte(i)}
+		_, err := service.StoreSkipBlock(&StoreSkipBlock{TargetSkipChainID: sbRoot.Hash, NewBlock: sb})
+		log.ErrFatal(err)
+	}
+
+	reply, err := service.ExportSkipchain(&ExportSkipchain{SkipchainID: sbRoot.Hash})
+	require.Nil(t, err)
+	blocks, err := ReadArchive(bytes.NewReader(reply.Archive))
+	require.Nil(t, err)
+	require.Equal(t, 6, len(blocks))
+	_, err = service.ExportSkipchain(&ExportSkipchain{SkipchainID: blocks[1].Hash})
+	require.NotNil(t, err)
+
+	// An unlinked conode refuses all imports.
+	kp := key.NewKeyPair(cothority.Suite)
+	req := &ImportSkipchain{Archive: reply.Archive}
+	req.Signature, err = schnorr.Sign(cothority.Suite, kp.Private, req.message())
+	require.Nil(t, err)
+	_, err = importer.ImportSkipchain(req)
+	require.NotNil(t, err)
+	require.Nil(t, importer.db.GetByID(sbRoot.Hash))
+
+	// Only linked clients can import.
+	importer.Storage.Clients = []kyber.Point{kp.Public}
+	_, err = importer.ImportSkipchain(&ImportSkipchain{Archive: reply.Archive})
+	require.NotNil(t, err)
+
+	// A tampered block is rejected and nothing is stored.
+	tampered := make([]*SkipBlock, len(blocks))
+	copy(tampered, blocks)
+	tampered[3] = blocks[3].Copy()
+	tampered[3].Data = []byte("tampered")
+	tampered[3].updateHash()
+	var buf bytes.Buffer
+	require.Nil(t, WriteArchive(&buf, tampered))
+	bad := &ImportSkipchain{Archive: buf.Bytes()}
+	bad.Signature, err = schnorr.Sign(cothority.Suite, kp.Private, bad.message())
+	require.Nil(t, err)
+	_, err = importer.ImportSkipchain(bad)
+	require.NotNil(t, err)
+	require.Nil(t, importer.db.GetByID(sbRoot.Hash))
+
+	// A truncated archive is rejected.
+	_, err = ReadArchive(bytes.NewReader(reply.Archive[:len(reply.Archive)-1]))
+	require.NotNil(t, err)
+
+	ir, err := importer.ImportSkipchain(req)
+	require.Nil(t, err)
+	require.True(t, ir.GenesisID.Equal(sbRoot.Hash))
+	require.Equal(t, 5, ir.Latest.Index)
+	for _, sb := range blocks {
+		stored := importer.db.GetByID(sb.Hash)
+		require.NotNil(t, stored)
+		require.Equal(t, len(sb.ForwardLink), len(stored.ForwardLink))
+	}
+	latest := importer.db.GetLatestByGenesis(sbRoot.Hash)
+	require.NotNil(t, latest)
+	require.True(t, latest.Hash.Equal(blocks[5].Hash))
+}
+
+// signCollectively returns a collective signature of all privates on msg.
+func signCollectively(t *testing.T, ro *onet.Roster, privates []kyber.Scalar,
+	msg []byte) byzcoinx.FinalSignature {
+	mask, err := cosi.NewMask(cothority.Suite, ro.Publics(), nil)
+	require.Nil(t, err)
+	var secrets []kyber.Scalar
+	var commitments []kyber.Point
+	var masks [][]byte
+	for i := range privates {
+		v, V := cosi.Commit(cothority.Suite)
+		secrets = append(secrets, v)
+		commitments = append(commitments, V)
+		require.Nil(t, mask.SetBit(i, true))
+	}
+	for range privates {
+		masks = append(masks, mask.Mask())
+	}
+	V, _, err := cosi.AggregateCommitments(cothority.Suite, commitments, masks)
+	require.Nil(t, err)
+	c, err := cosi.Challenge(cothority.Suite, V, mask.AggregatePublic, msg)
+	require.Nil(t, err)
+	var responses []kyber.Scalar
+	for i, priv := range privates {
+		r, err := cosi.Response(cothority.Suite, priv, secrets[i], c)
+		require.Nil(t, err)
+		responses = append(responses, r)
+	}
+	r, err := cosi.AggregateResponses(cothority.Suite, responses)
+	require.Nil(t, err)
+	sig, err := cosi.Sign(cothority.Suite, V, r, mask)
+	require.Nil(t, err)
+	return byzcoinx.FinalSignature{Msg: msg, Sig: sig}
+}
+
 func nukeBlocksFrom(t *testing.T, db *SkipBlockDB, where SkipBlockID) {
 	for {
 		// Get to find forward links.
@@ -1055,7 +1664,7 @@ func nukeBlocksFrom(t *testing.T, db *SkipBlockDB, where SkipBlockID) {
 
 		// nuke it
 		log.Lvl2("nuking block", sb.Index)
-		err := db.Update(func(tx *bolt.Tx) error {
+		err := db.Update(func(tx StorageTx) error {
 			err := tx.Bucket([]byte(db.bucketName)).Delete(where)
 			if err != nil {
 				log.Fatal("delete error", err)
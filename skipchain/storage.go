@@ -0,0 +1,300 @@
+package skipchain
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	bolt "github.com/coreos/bbolt"
+)
+
+/*
+This file holds the storage backends that can be used by SkipBlockDB. All
+backends offer transactional buckets of key/value pairs, like bolt does.
+*/
+
+const (
+	// StorageBolt stores the skipblocks in the bolt database of the conode.
+	StorageBolt = "bolt"
+	// StorageMemory keeps the skipblocks in memory only. It is useful for
+	// tests and ephemeral deployments, as all blocks are lost on restart.
+	StorageMemory = "memory"
+	// StorageLevelDB stores the skipblocks in a LevelDB database next to
+	// the bolt database of the conode. It has a better write throughput
+	// than bolt.
+	StorageLevelDB = "leveldb"
+)
+
+// SkipBlockStorage is a transactional key/value store with buckets.
+type SkipBlockStorage interface {
+	// View runs f in a read-only transaction.
+	View(f func(tx StorageTx) error) error
+	// Update runs f in a read-write transaction. If f returns an error,
+	// none of its changes are stored.
+	Update(f func(tx StorageTx) error) error
+	// Close releases all resources of the storage.
+	Close() error
+}
+
+// StorageTx is a transaction of a SkipBlockStorage.
+type StorageTx interface {
+	// Bucket returns the bucket with the given name, or nil if it doesn't
+	// exist.
+	Bucket(name []byte) StorageBucket
+	// CreateBucketIfNotExists returns the bucket with the given name and
+	// creates it if necessary. It returns an error in a read-only
+	// transaction.
+	CreateBucketIfNotExists(name []byte) (StorageBucket, error)
+}
+
+// StorageBucket holds key/value pairs. The returned values are only valid
+// during the transaction.
+type StorageBucket interface {
+	// Get returns the value of the key, or nil if it doesn't exist.
+	Get(key []byte) []byte
+	// Put stores the value under the key.
+	Put(key, value []byte) error
+	// Delete removes the key. It is not an error if the key doesn't exist.
+	Delete(key []byte) error
+	// ForEach calls f for all key/value pairs, sorted by key. If f returns
+	// an error, the iteration stops and the error is returned.
+	ForEach(f func(k, v []byte) error) error
+	// Stats returns the number of keys and the number of bytes used by
+	// the bucket.
+	Stats() (keys, bytes int)
+}
+
+// checkBackend returns an error if the backend is unknown. An empty string
+// is the same as StorageBolt.
+func checkBackend(backend string) error {
+	switch backend {
+	case "", StorageBolt, StorageMemory, StorageLevelDB:
+		return nil
+	default:
+		return errors.New("unknown storage backend: " + backend)
+	}
+}
+
+// openStorage returns the storage of the given backend. The bolt database of
+// the conode is used by the bolt backend and to find the directory of the
+// LevelDB backend.
+func openStorage(db *bolt.DB, backend string) (SkipBlockStorage, error) {
+	if err := checkBackend(backend); err != nil {
+		return nil, err
+	}
+	switch backend {
+	case StorageMemory:
+		return NewMemoryStorage(), nil
+	case StorageLevelDB:
+		return NewLevelDBStorage(db.Path() + "_skipblocks")
+	default:
+		return NewBoltStorage(db), nil
+	}
+}
+
+// boltStorage wraps a bolt database.
+type boltStorage struct {
+	db *bolt.DB
+}
+
+// NewBoltStorage returns a storage using the given bolt database.
+func NewBoltStorage(db *bolt.DB) SkipBlockStorage {
+	return &boltStorage{db: db}
+}
+
+func (bs *boltStorage) View(f func(tx StorageTx) error) error {
+	return bs.db.View(func(tx *bolt.Tx) error {
+		return f(&boltTx{tx})
+	})
+}
+
+func (bs *boltStorage) Update(f func(tx StorageTx) error) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return f(&boltTx{tx})
+	})
+}
+
+func (bs *boltStorage) Close() error {
+	return bs.db.Close()
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (btx *boltTx) Bucket(name []byte) StorageBucket {
+	b := btx.tx.Bucket(name)
+	if b == nil {
+		return nil
+	}
+	return &boltBucket{b}
+}
+
+func (btx *boltTx) CreateBucketIfNotExists(name []byte) (StorageBucket, error) {
+	b, err := btx.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return &boltBucket{b}, nil
+}
+
+type boltBucket struct {
+	b *bolt.Bucket
+}
+
+func (bb *boltBucket) Get(key []byte) []byte {
+	return bb.b.Get(key)
+}
+
+func (bb *boltBucket) Put(key, value []byte) error {
+	return bb.b.Put(key, value)
+}
+
+func (bb *boltBucket) Delete(key []byte) error {
+	return bb.b.Delete(key)
+}
+
+func (bb *boltBucket) ForEach(f func(k, v []byte) error) error {
+	return bb.b.ForEach(f)
+}
+
+func (bb *boltBucket) Stats() (int, int) {
+	s := bb.b.Stats()
+	return s.KeyN, s.BranchInuse + s.LeafInuse
+}
+
+// memoryStorage keeps all buckets in maps. Changes of a transaction are
+// collected and only applied once the transaction succeeds.
+type memoryStorage struct {
+	sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+// NewMemoryStorage returns an empty storage that is only held in memory.
+func NewMemoryStorage() SkipBlockStorage {
+	return &memoryStorage{buckets: make(map[string]map[string][]byte)}
+}
+
+func (ms *memoryStorage) View(f func(tx StorageTx) error) error {
+	ms.RLock()
+	defer ms.RUnlock()
+	return f(&memoryTx{storage: ms})
+}
+
+func (ms *memoryStorage) Update(f func(tx StorageTx) error) error {
+	ms.Lock()
+	defer ms.Unlock()
+	tx := &memoryTx{
+		storage:  ms,
+		writable: true,
+		created:  make(map[string]bool),
+		changes:  make(map[string]map[string][]byte),
+	}
+	if err := f(tx); err != nil {
+		return err
+	}
+	for name := range tx.created {
+		ms.buckets[name] = make(map[string][]byte)
+	}
+	for name, changes := range tx.changes {
+		for k, v := range changes {
+			if v == nil {
+				delete(ms.buckets[name], k)
+			} else {
+				ms.buckets[name][k] = v
+			}
+		}
+	}
+	return nil
+}
+
+func (ms *memoryStorage) Close() error {
+	return nil
+}
+
+type memoryTx struct {
+	storage  *memoryStorage
+	writable bool
+	// created holds the buckets created in this transaction.
+	created map[string]bool
+	// changes holds the new values per bucket, a nil value is a deletion.
+	changes map[string]map[string][]byte
+}
+
+func (mtx *memoryTx) Bucket(name []byte) StorageBucket {
+	if _, ok := mtx.storage.buckets[string(name)]; !ok && !mtx.created[string(name)] {
+		return nil
+	}
+	return &memoryBucket{tx: mtx, name: string(name)}
+}
+
+func (mtx *memoryTx) CreateBucketIfNotExists(name []byte) (StorageBucket, error) {
+	if !mtx.writable {
+		return nil, errors.New("read-only transaction")
+	}
+	if _, ok := mtx.storage.buckets[string(name)]; !ok {
+		mtx.created[string(name)] = true
+	}
+	return &memoryBucket{tx: mtx, name: string(name)}, nil
+}
+
+type memoryBucket struct {
+	tx   *memoryTx
+	name string
+}
+
+func (mb *memoryBucket) Get(key []byte) []byte {
+	if v, ok := mb.tx.changes[mb.name][string(key)]; ok {
+		return v
+	}
+	return mb.tx.storage.buckets[mb.name][string(key)]
+}
+
+func (mb *memoryBucket) Put(key, value []byte) error {
+	return mb.set(key, append([]byte{}, value...))
+}
+
+func (mb *memoryBucket) Delete(key []byte) error {
+	return mb.set(key, nil)
+}
+
+func (mb *memoryBucket) set(key, value []byte) error {
+	if !mb.tx.writable {
+		return errors.New("read-only transaction")
+	}
+	if mb.tx.changes[mb.name] == nil {
+		mb.tx.changes[mb.name] = make(map[string][]byte)
+	}
+	mb.tx.changes[mb.name][string(key)] = value
+	return nil
+}
+
+func (mb *memoryBucket) ForEach(f func(k, v []byte) error) error {
+	var keys []string
+	for k := range mb.tx.storage.buckets[mb.name] {
+		if _, ok := mb.tx.changes[mb.name][k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for k, v := range mb.tx.changes[mb.name] {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := f([]byte(k), mb.Get([]byte(k))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mb *memoryBucket) Stats() (keys, bytes int) {
+	mb.ForEach(func(k, v []byte) error {
+		keys++
+		bytes += len(k) + len(v)
+		return nil
+	})
+	return
+}
@@ -0,0 +1,165 @@
+package skipchain
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/onet/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LevelDB has no buckets, so the keys are prefixed. A bucket is marked as
+// existing by the key levelDBBucketMark|name, and its values are stored
+// under levelDBBucketData|len(name)|name|key.
+const (
+	levelDBBucketMark = byte(0)
+	levelDBBucketData = byte(1)
+)
+
+// levelDBStorage stores the buckets in a LevelDB database.
+type levelDBStorage struct {
+	db *leveldb.DB
+}
+
+// NewLevelDBStorage opens or creates a LevelDB database in the directory
+// given by path.
+func NewLevelDBStorage(path string) (SkipBlockStorage, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &levelDBStorage{db: db}, nil
+}
+
+func (ls *levelDBStorage) View(f func(tx StorageTx) error) error {
+	snap, err := ls.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+	return f(&levelDBTx{reader: snap})
+}
+
+func (ls *levelDBStorage) Update(f func(tx StorageTx) error) error {
+	tr, err := ls.db.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	if err := f(&levelDBTx{reader: tr, writer: tr}); err != nil {
+		tr.Discard()
+		return err
+	}
+	return tr.Commit()
+}
+
+func (ls *levelDBStorage) Close() error {
+	return ls.db.Close()
+}
+
+// levelDBReader is implemented by snapshots and transactions.
+type levelDBReader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	Has(key []byte, ro *opt.ReadOptions) (bool, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+// levelDBWriter is implemented by transactions.
+type levelDBWriter interface {
+	Put(key, value []byte, wo *opt.WriteOptions) error
+	Delete(key []byte, wo *opt.WriteOptions) error
+}
+
+type levelDBTx struct {
+	reader levelDBReader
+	// writer is nil for read-only transactions.
+	writer levelDBWriter
+}
+
+func (ltx *levelDBTx) Bucket(name []byte) StorageBucket {
+	ok, err := ltx.reader.Has(levelDBMarkKey(name), nil)
+	if err != nil {
+		log.Error(err)
+	}
+	if !ok {
+		return nil
+	}
+	return newLevelDBBucket(ltx, name)
+}
+
+func (ltx *levelDBTx) CreateBucketIfNotExists(name []byte) (StorageBucket, error) {
+	if ltx.writer == nil {
+		return nil, errors.New("read-only transaction")
+	}
+	if err := ltx.writer.Put(levelDBMarkKey(name), []byte{}, nil); err != nil {
+		return nil, err
+	}
+	return newLevelDBBucket(ltx, name), nil
+}
+
+func levelDBMarkKey(name []byte) []byte {
+	return append([]byte{levelDBBucketMark}, name...)
+}
+
+type levelDBBucket struct {
+	tx     *levelDBTx
+	prefix []byte
+}
+
+func newLevelDBBucket(tx *levelDBTx, name []byte) *levelDBBucket {
+	prefix := make([]byte, 5, 5+len(name))
+	prefix[0] = levelDBBucketData
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(name)))
+	return &levelDBBucket{tx: tx, prefix: append(prefix, name...)}
+}
+
+func (lb *levelDBBucket) key(key []byte) []byte {
+	return append(append([]byte{}, lb.prefix...), key...)
+}
+
+func (lb *levelDBBucket) Get(key []byte) []byte {
+	val, err := lb.tx.reader.Get(lb.key(key), nil)
+	if err != nil {
+		if err != leveldb.ErrNotFound {
+			log.Error(err)
+		}
+		return nil
+	}
+	return val
+}
+
+func (lb *levelDBBucket) Put(key, value []byte) error {
+	if lb.tx.writer == nil {
+		return errors.New("read-only transaction")
+	}
+	return lb.tx.writer.Put(lb.key(key), value, nil)
+}
+
+func (lb *levelDBBucket) Delete(key []byte) error {
+	if lb.tx.writer == nil {
+		return errors.New("read-only transaction")
+	}
+	return lb.tx.writer.Delete(lb.key(key), nil)
+}
+
+func (lb *levelDBBucket) ForEach(f func(k, v []byte) error) error {
+	it := lb.tx.reader.NewIterator(util.BytesPrefix(lb.prefix), nil)
+	defer it.Release()
+	for it.Next() {
+		if err := f(it.Key()[len(lb.prefix):], it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+func (lb *levelDBBucket) Stats() (keys, bytes int) {
+	lb.ForEach(func(k, v []byte) error {
+		keys++
+		bytes += len(k) + len(v)
+		return nil
+	})
+	return
+}
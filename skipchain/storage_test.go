@@ -0,0 +1,134 @@
+package skipchain
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_Bolt(t *testing.T) {
+	f, err := ioutil.TempFile("", "storage-test")
+	require.Nil(t, err)
+	fname := f.Name()
+	require.Nil(t, f.Close())
+	defer os.Remove(fname)
+	db, err := bolt.Open(fname, 0600, nil)
+	require.Nil(t, err)
+
+	testStorage(t, NewBoltStorage(db))
+}
+
+func TestStorage_Memory(t *testing.T) {
+	testStorage(t, NewMemoryStorage())
+}
+
+func TestStorage_LevelDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	st, err := NewLevelDBStorage(dir)
+	require.Nil(t, err)
+
+	testStorage(t, st)
+}
+
+func TestStorage_Config(t *testing.T) {
+	for _, backend := range []string{"", StorageBolt, StorageMemory, StorageLevelDB} {
+		require.Nil(t, checkBackend(backend))
+	}
+	require.NotNil(t, checkBackend("sqlite"))
+	require.NotNil(t, RegisterService(Config{Storage: "sqlite"}))
+}
+
+func TestStorage_SkipBlockDB(t *testing.T) {
+	db, err := NewSkipBlockDBFromStorage(NewMemoryStorage(), []byte("skipblock-test"))
+	require.Nil(t, err)
+	defer db.Close()
+
+	sb0 := NewSkipBlock()
+	sb0.Data = []byte{0}
+	sb0.Hash = []byte{1, 2, 3, 6, 5}
+	sb1 := NewSkipBlock()
+	sb1.Data = []byte{1}
+	sb1.Hash = []byte{2, 3, 4, 1, 5}
+	require.NotNil(t, db.Store(sb0))
+	require.NotNil(t, db.Store(sb1))
+	require.Equal(t, 2, db.Length())
+	require.Equal(t, sb1.Data, db.GetByID(sb1.Hash).Data)
+	require.Nil(t, db.GetByID([]byte{1}))
+	require.Equal(t, "2", db.GetStatus().Field["Blocks"])
+
+	sb, err := db.GetFuzzy("0203")
+	require.Nil(t, err)
+	require.Equal(t, sb1.Data, sb.Data)
+	sb, err = db.GetFuzzy("0605")
+	require.Nil(t, err)
+	require.Equal(t, sb0.Data, sb.Data)
+
+	all, err := db.GetSkipchains()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(all))
+}
+
+func testStorage(t *testing.T, st SkipBlockStorage) {
+	defer st.Close()
+	name := []byte("bucket")
+
+	// Read-only transactions cannot create buckets.
+	require.Nil(t, st.View(func(tx StorageTx) error {
+		require.Nil(t, tx.Bucket(name))
+		_, err := tx.CreateBucketIfNotExists(name)
+		require.NotNil(t, err)
+		return nil
+	}))
+
+	require.Nil(t, st.Update(func(tx StorageTx) error {
+		b, err := tx.CreateBucketIfNotExists(name)
+		require.Nil(t, err)
+		require.Nil(t, b.Put([]byte("b"), []byte("2")))
+		require.Nil(t, b.Put([]byte("a"), []byte("1")))
+		require.Nil(t, b.Put([]byte("c"), []byte("3")))
+		require.Equal(t, []byte("1"), b.Get([]byte("a")))
+		return nil
+	}))
+
+	// A failing transaction must not change anything.
+	errRollback := errors.New("rollback")
+	require.Equal(t, errRollback, st.Update(func(tx StorageTx) error {
+		b := tx.Bucket(name)
+		require.Nil(t, b.Put([]byte("d"), []byte("4")))
+		require.Nil(t, b.Delete([]byte("a")))
+		require.Nil(t, b.Get([]byte("a")))
+		_, err := tx.CreateBucketIfNotExists([]byte("other"))
+		require.Nil(t, err)
+		return errRollback
+	}))
+
+	require.Nil(t, st.Update(func(tx StorageTx) error {
+		return tx.Bucket(name).Delete([]byte("b"))
+	}))
+
+	require.Nil(t, st.View(func(tx StorageTx) error {
+		require.Nil(t, tx.Bucket([]byte("other")))
+		b := tx.Bucket(name)
+		require.NotNil(t, b)
+		require.NotNil(t, b.Put([]byte("e"), []byte("5")))
+		require.Nil(t, b.Get([]byte("b")))
+		require.Nil(t, b.Get([]byte("d")))
+		var keys, values []string
+		require.Nil(t, b.ForEach(func(k, v []byte) error {
+			keys = append(keys, string(k))
+			values = append(values, string(v))
+			return nil
+		}))
+		require.Equal(t, []string{"a", "c"}, keys)
+		require.Equal(t, []string{"1", "3"}, values)
+		n, _ := b.Stats()
+		require.Equal(t, 2, n)
+		return nil
+	}))
+}
@@ -0,0 +1,155 @@
+package skipchain
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/dedis/onet/log"
+)
+
+/*
+This file holds the subscription service that pushes new and updated
+skipblocks to clients.
+*/
+
+// streamBufferSize is the number of blocks that can be waiting for a slow
+// subscriber. If more blocks arrive, the subscription is closed and the
+// client has to resume from the last index it received.
+const streamBufferSize = 128
+
+// streamingManager keeps track of all subscribers to the skipchains.
+type streamingManager struct {
+	sync.Mutex
+	// the key is the skipchain-id as a string
+	subscribers map[string][]chan *SkipBlock
+}
+
+// subscribe returns a channel where all new and updated blocks of the given
+// skipchain are sent to.
+func (sm *streamingManager) subscribe(id SkipBlockID) chan *SkipBlock {
+	sm.Lock()
+	defer sm.Unlock()
+	if sm.subscribers == nil {
+		sm.subscribers = make(map[string][]chan *SkipBlock)
+	}
+	c := make(chan *SkipBlock, streamBufferSize)
+	sm.subscribers[string(id)] = append(sm.subscribers[string(id)], c)
+	return c
+}
+
+// unsubscribe removes the channel from the subscribers and closes it. It is
+// safe to call it for a channel that has already been removed.
+func (sm *streamingManager) unsubscribe(id SkipBlockID, c chan *SkipBlock) {
+	sm.Lock()
+	defer sm.Unlock()
+	sm.remove(string(id), c)
+}
+
+// remove must be called with the lock held.
+func (sm *streamingManager) remove(key string, c chan *SkipBlock) {
+	subs := sm.subscribers[key]
+	for i, sub := range subs {
+		if sub == c {
+			close(c)
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(sm.subscribers, key)
+	} else {
+		sm.subscribers[key] = subs
+	}
+}
+
+// isSubscribed returns true if at least one client subscribed to the
+// skipchain.
+func (sm *streamingManager) isSubscribed(id SkipBlockID) bool {
+	sm.Lock()
+	defer sm.Unlock()
+	return len(sm.subscribers[string(id)]) > 0
+}
+
+// notify sends the block to all subscribers of its skipchain. Subscribers
+// that cannot keep up are removed.
+func (sm *streamingManager) notify(sb *SkipBlock) {
+	sm.Lock()
+	defer sm.Unlock()
+	key := string(sb.SkipChainID())
+	for _, c := range append([]chan *SkipBlock{}, sm.subscribers[key]...) {
+		select {
+		case c <- sb.Copy():
+		default:
+			log.Lvl2("subscriber is too slow, closing subscription")
+			sm.remove(key, c)
+		}
+	}
+}
+
+// StreamSkipBlocks sends all blocks of the skipchain, starting at
+// FromIndex, to the client. Afterwards every new block and every block that
+// gets a new forward-link is sent as soon as it is stored. The stream stays
+// open until the client closes it or cannot keep up with the new blocks, in
+// which case it has to resume from the index of the last received block.
+func (s *Service) StreamSkipBlocks(req *StreamSkipBlocks) (chan *StreamSkipBlocksReply, chan bool, error) {
+	genesis := s.db.GetByID(req.GenesisID)
+	if genesis == nil || genesis.Index != 0 {
+		return nil, nil, errors.New("No such genesis-block")
+	}
+	if req.FromIndex < 0 {
+		return nil, nil, errors.New("Can't have an index < 0")
+	}
+	// Subscribe before looking up the stored blocks, so that no block gets
+	// lost. The client might receive some blocks twice.
+	live := s.streaming.subscribe(req.GenesisID)
+
+	var first *SkipBlock
+	latest, err := s.db.GetLatest(genesis)
+	if err == nil && req.FromIndex <= latest.Index {
+		first, err = s.GetSingleBlockByIndex(&GetSingleBlockByIndex{
+			Genesis: req.GenesisID,
+			Index:   req.FromIndex,
+		})
+	}
+	if err != nil {
+		s.streaming.unsubscribe(req.GenesisID, live)
+		return nil, nil, err
+	}
+
+	out := make(chan *StreamSkipBlocksReply)
+	stop := make(chan bool, 1)
+	go func() {
+		defer close(out)
+		defer s.streaming.unsubscribe(req.GenesisID, live)
+		for sb := first; sb != nil; {
+			select {
+			case out <- &StreamSkipBlocksReply{Block: sb}:
+			case <-stop:
+				return
+			}
+			if len(sb.ForwardLink) == 0 {
+				break
+			}
+			sb = s.db.GetByID(sb.ForwardLink[0].To)
+		}
+		for {
+			select {
+			case sb, ok := <-live:
+				if !ok {
+					return
+				}
+				if sb.Index < req.FromIndex {
+					continue
+				}
+				select {
+				case out <- &StreamSkipBlocksReply{Block: sb}:
+				case <-stop:
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return out, stop, nil
+}
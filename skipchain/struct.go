@@ -15,6 +15,7 @@ import (
 	"github.com/dedis/cothority/byzcoinx"
 	"github.com/dedis/kyber"
 	"github.com/dedis/kyber/sign/cosi"
+	"github.com/dedis/kyber/sign/schnorr"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
 	"github.com/dedis/onet/network"
@@ -525,31 +526,304 @@ func (fl *ForwardLink) Verify(suite cosi.Suite, pubs []kyber.Point) error {
 		cosi.NewThresholdPolicy(len(pubs)-t))
 }
 
+// Proof is a list of blocks going from the genesis-block to a target block,
+// where every block is linked to the next one by one of its forward-links.
+// It can be verified offline, without contacting any conode.
+type Proof []*SkipBlock
+
+// Target returns the last block of the proof, or nil if the proof is empty.
+func (p Proof) Target() *SkipBlock {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[len(p)-1]
+}
+
+// Verify makes sure that the proof starts at the genesis-block given by
+// genesisID and that every block links to the next one with a valid
+// forward-link. If a forward-link changes the roster, the new roster must be
+// the one of the next block. It returns nil if the proof is correct.
+func (p Proof) Verify(genesisID SkipBlockID) error {
+	if len(p) == 0 {
+		return errors.New("empty proof")
+	}
+	for i, sb := range p {
+		if sb == nil || sb.SkipBlockFix == nil || sb.Roster == nil {
+			return fmt.Errorf("incomplete block at position %d", i)
+		}
+		if !sb.CalculateHash().Equal(sb.Hash) {
+			return fmt.Errorf("wrong hash for block at position %d", i)
+		}
+	}
+	if p[0].Index != 0 || !p[0].Hash.Equal(genesisID) {
+		return errors.New("proof doesn't start with the genesis-block")
+	}
+	for i, sb := range p[1:] {
+		prev := p[i]
+		if !sb.GenesisID.Equal(genesisID) {
+			return fmt.Errorf("block %d is not part of this skipchain", sb.Index)
+		}
+		if sb.Index <= prev.Index {
+			return fmt.Errorf("block %d doesn't follow block %d", sb.Index, prev.Index)
+		}
+		var link *ForwardLink
+		for _, fl := range prev.ForwardLink {
+			if fl.To.Equal(sb.Hash) {
+				link = fl
+				break
+			}
+		}
+		if link == nil {
+			return fmt.Errorf("no forward-link from block %d to block %d",
+				prev.Index, sb.Index)
+		}
+		if !link.From.Equal(prev.Hash) {
+			return fmt.Errorf("forward-link of block %d has wrong origin", prev.Index)
+		}
+		if err := link.Verify(cothority.Suite, prev.Roster.Publics()); err != nil {
+			return fmt.Errorf("wrong forward-link from block %d to block %d: %s",
+				prev.Index, sb.Index, err.Error())
+		}
+		if link.NewRoster != nil {
+			if !link.NewRoster.ID.Equal(sb.Roster.ID) {
+				return fmt.Errorf("roster of block %d doesn't match the forward-link",
+					sb.Index)
+			}
+		} else if !prev.Roster.ID.Equal(sb.Roster.ID) {
+			return fmt.Errorf("roster changed in block %d without being signed",
+				sb.Index)
+		}
+	}
+	return nil
+}
+
+// Snapshot is stored for every pruned skipchain. It points to the first block
+// after the genesis-block that is still stored, and holds the collectively
+// signed forward-links going from the genesis-block to it, so that the
+// snapshot block can be verified without the pruned history.
+type Snapshot struct {
+	// GenesisID is the ID of the pruned skipchain.
+	GenesisID SkipBlockID
+	// BlockID is the ID of the snapshot block.
+	BlockID SkipBlockID
+	// Index of the snapshot block.
+	Index int
+	// Links go from the genesis-block to the snapshot block. Every link
+	// starts where the previous one points to, and uses the highest level
+	// available.
+	Links []*ForwardLink
+	// Archive is a list of conodes that still hold the full history.
+	Archive []*network.ServerIdentity
+}
+
+// Verify makes sure that the forward-links of the snapshot go from the
+// genesis-block to the snapshot block, and that each of them is signed by the
+// roster of its block. The roster of the genesis-block needs to be trusted,
+// the following rosters are taken from the forward-links.
+func (snap *Snapshot) Verify(genesis *onet.Roster) error {
+	if len(snap.Links) == 0 {
+		return errors.New("snapshot without forward-links")
+	}
+	if genesis == nil {
+		return errors.New("missing roster of the genesis-block")
+	}
+	roster := genesis
+	from := snap.GenesisID
+	for i, fl := range snap.Links {
+		if fl == nil || !fl.From.Equal(from) {
+			return fmt.Errorf("forward-link %d doesn't follow the previous one", i)
+		}
+		if err := fl.Verify(cothority.Suite, roster.Publics()); err != nil {
+			return fmt.Errorf("forward-link %d: %s", i, err)
+		}
+		if fl.NewRoster != nil {
+			roster = fl.NewRoster
+		}
+		from = fl.To
+	}
+	if !from.Equal(snap.BlockID) {
+		return errors.New("forward-links don't point to snapshot block")
+	}
+	return nil
+}
+
+// errPruned returns the error sent to clients asking for a pruned block.
+func (snap *Snapshot) errPruned() error {
+	return fmt.Errorf("pruned: blocks before index %d are only available on archive nodes %v",
+		snap.Index, snap.Archive)
+}
+
+// ForkEvidence proves that two different blocks have been collectively
+// signed as the successor of the same block at the same height. This can only
+// happen if at least a third of the roster misbehaved.
+type ForkEvidence struct {
+	// GenesisID is the ID of the forked skipchain.
+	GenesisID SkipBlockID
+	// Index of the conflicting blocks.
+	Index int
+	// Roster of the block both forward-links start from.
+	Roster *onet.Roster
+	// Links are the two conflicting forward-links.
+	Links [2]*ForwardLink
+	// Culprits are the members of Roster that signed both forward-links.
+	Culprits []*network.ServerIdentity
+	// Reporter is the conode that detected the fork.
+	Reporter *network.ServerIdentity
+	// Signature of the reporter on Hash.
+	Signature []byte
+	// Time when the fork has been detected, in unix nanoseconds.
+	Time int64
+}
+
+// Hash returns the hash of the conflicting forward-links. It is the same for
+// all conodes that detect the same fork.
+func (fe *ForkEvidence) Hash() []byte {
+	h := sha256.New()
+	h.Write(fe.GenesisID)
+	for _, fl := range fe.Links {
+		h.Write(fl.From)
+		h.Write(fl.To)
+		h.Write(fl.Signature.Sig)
+	}
+	return h.Sum(nil)
+}
+
+// Verify makes sure that both forward-links are correctly signed by the
+// roster, start at the same block and point to different blocks, that the
+// culprits signed both of them, and that the reporter signed the evidence.
+func (fe *ForkEvidence) Verify() error {
+	if fe.Roster == nil || fe.Links[0] == nil || fe.Links[1] == nil {
+		return errors.New("incomplete evidence")
+	}
+	if !fe.Links[0].From.Equal(fe.Links[1].From) {
+		return errors.New("forward-links start at different blocks")
+	}
+	if fe.Links[0].To.Equal(fe.Links[1].To) {
+		return errors.New("forward-links point to the same block")
+	}
+	for _, fl := range fe.Links {
+		if err := fl.Verify(cothority.Suite, fe.Roster.Publics()); err != nil {
+			return err
+		}
+	}
+	culprits, err := commonSigners(fe.Roster, fe.Links[0], fe.Links[1])
+	if err != nil {
+		return err
+	}
+	if len(culprits) != len(fe.Culprits) {
+		return errors.New("wrong list of culprits")
+	}
+	for i, si := range culprits {
+		if !si.Equal(fe.Culprits[i]) {
+			return errors.New("wrong list of culprits")
+		}
+	}
+	if fe.Reporter == nil {
+		return errors.New("evidence without reporter")
+	}
+	return schnorr.Verify(cothority.Suite, fe.Reporter.Public, fe.Hash(), fe.Signature)
+}
+
+// signers returns the members of the roster that took part in the
+// collective signature of the forward-link.
+func signers(ro *onet.Roster, fl *ForwardLink) ([]*network.ServerIdentity, error) {
+	lenRes := cothority.Suite.PointLen() + cothority.Suite.ScalarLen()
+	if len(fl.Signature.Sig) < lenRes {
+		return nil, errors.New("signature too short")
+	}
+	mask, err := cosi.NewMask(cothority.Suite, ro.Publics(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := mask.SetMask(fl.Signature.Sig[lenRes:]); err != nil {
+		return nil, err
+	}
+	var sis []*network.ServerIdentity
+	for i, si := range ro.List {
+		if enabled, err := mask.IndexEnabled(i); err == nil && enabled {
+			sis = append(sis, si)
+		}
+	}
+	return sis, nil
+}
+
+// commonSigners returns the members of the roster that signed both
+// forward-links.
+func commonSigners(ro *onet.Roster, fl1, fl2 *ForwardLink) ([]*network.ServerIdentity, error) {
+	s1, err := signers(ro, fl1)
+	if err != nil {
+		return nil, err
+	}
+	s2, err := signers(ro, fl2)
+	if err != nil {
+		return nil, err
+	}
+	var common []*network.ServerIdentity
+	for _, si := range s1 {
+		for _, si2 := range s2 {
+			if si.Equal(si2) {
+				common = append(common, si)
+				break
+			}
+		}
+	}
+	return common, nil
+}
+
 // SkipBlockDB holds the database to the skipblocks.
 // This is used for verification, so that all links can be followed.
-// It is a wrapper to embed bolt.DB.
+// It is a wrapper to embed a SkipBlockStorage.
 type SkipBlockDB struct {
-	*bolt.DB
+	SkipBlockStorage
 	bucketName []byte
 }
 
-// NewSkipBlockDB returns an initialized SkipBlockDB structure.
+// NewSkipBlockDB returns an initialized SkipBlockDB structure using the
+// given bolt database, which must already hold the bucket.
 func NewSkipBlockDB(db *bolt.DB, bn []byte) *SkipBlockDB {
 	return &SkipBlockDB{
-		DB:         db,
-		bucketName: bn,
+		SkipBlockStorage: NewBoltStorage(db),
+		bucketName:       bn,
 	}
 }
 
+// NewSkipBlockDBFromStorage returns an initialized SkipBlockDB structure
+// using the given storage. The bucket is created if it doesn't exist, and
+// the secondary indexes are built if the blocks have been stored without
+// them.
+func NewSkipBlockDBFromStorage(st SkipBlockStorage, bn []byte) (*SkipBlockDB, error) {
+	db := &SkipBlockDB{
+		SkipBlockStorage: st,
+		bucketName:       bn,
+	}
+	var missingIndex bool
+	err := st.Update(func(tx StorageTx) error {
+		b, err := tx.CreateBucketIfNotExists(bn)
+		if err != nil {
+			return err
+		}
+		keys, _ := b.Stats()
+		missingIndex = keys > 0 && tx.Bucket(db.latestBucketName()) == nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if missingIndex {
+		log.Lvl1("Building indexes of the skipblocks")
+		if err := db.BuildIndexes(); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
 // GetStatus is a function that returns the status report of the db.
 func (db *SkipBlockDB) GetStatus() *onet.Status {
 	out := make(map[string]string)
-	db.DB.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(db.bucketName))
-		s := b.Stats()
-		out["Blocks"] = strconv.Itoa(s.KeyN)
-
-		total := s.BranchInuse + s.LeafInuse
+	db.View(func(tx StorageTx) error {
+		keys, total := tx.Bucket([]byte(db.bucketName)).Stats()
+		out["Blocks"] = strconv.Itoa(keys)
 		out["Bytes"] = strconv.Itoa(total)
 		return nil
 	})
@@ -559,7 +833,7 @@ func (db *SkipBlockDB) GetStatus() *onet.Status {
 // GetByID returns a new copy of the skip-block or nil if it doesn't exist
 func (db *SkipBlockDB) GetByID(sbID SkipBlockID) *SkipBlock {
 	var result *SkipBlock
-	err := db.View(func(tx *bolt.Tx) error {
+	err := db.View(func(tx StorageTx) error {
 		sb, err := db.getFromTx(tx, sbID)
 		if err != nil {
 			return err
@@ -577,7 +851,7 @@ func (db *SkipBlockDB) GetByID(sbID SkipBlockID) *SkipBlock {
 // Store stores the given SkipBlock in the service-list
 func (db *SkipBlockDB) Store(sb *SkipBlock) SkipBlockID {
 	var result SkipBlockID
-	err := db.Update(func(tx *bolt.Tx) error {
+	err := db.Update(func(tx StorageTx) error {
 		sbOld, err := db.getFromTx(tx, sb.Hash)
 		if err != nil {
 			return errors.New("failed to get skipblock with error: " + err.Error())
@@ -605,6 +879,9 @@ func (db *SkipBlockDB) Store(sb *SkipBlock) SkipBlockID {
 			if err != nil {
 				return err
 			}
+			if err := db.indexToTx(tx, sb); err != nil {
+				return err
+			}
 		}
 		result = sb.Hash
 		return nil
@@ -621,9 +898,8 @@ func (db *SkipBlockDB) Store(sb *SkipBlock) SkipBlockID {
 // Length returns how many skip blocks there are in this SkipBlockDB.
 func (db *SkipBlockDB) Length() int {
 	var i int
-	db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(db.bucketName))
-		i = b.Stats().KeyN
+	db.View(func(tx StorageTx) error {
+		i, _ = tx.Bucket([]byte(db.bucketName)).Stats()
 		return nil
 	})
 	return i
@@ -720,12 +996,26 @@ func (db *SkipBlockDB) GetLatest(sb *SkipBlock) (*SkipBlock, error) {
 		return nil, errors.New("got nil skipblock")
 	}
 	latest := sb
-	// TODO this can be optimised by using multiple bucket.Get in a single transaction
+	// Jump to the latest indexed block, the forward-links are only followed
+	// for blocks that are not indexed.
+	if indexed := db.GetLatestByGenesis(sb.SkipChainID()); indexed != nil &&
+		indexed.Index > latest.Index {
+		latest = indexed
+	}
 	for latest.GetForwardLen() > 0 {
-		latest = db.GetByID(latest.GetForward(latest.GetForwardLen() - 1).To)
-		if latest == nil {
-			return nil, errors.New("missing block")
+		next := db.GetByID(latest.GetForward(latest.GetForwardLen() - 1).To)
+		if next == nil {
+			// If the chain has been pruned, continue with the snapshot.
+			snap := db.GetSnapshot(latest.SkipChainID())
+			if snap == nil || latest.Index >= snap.Index {
+				return nil, errors.New("missing block")
+			}
+			next = db.GetByID(snap.BlockID)
+			if next == nil {
+				return nil, errors.New("missing snapshot block")
+			}
 		}
+		latest = next
 	}
 	return latest, nil
 }
@@ -748,26 +1038,30 @@ func (db *SkipBlockDB) GetFuzzy(id string) (*SkipBlock, error) {
 	}
 
 	var sb *SkipBlock
-	db.View(func(tx *bolt.Tx) error {
-		c := tx.Bucket([]byte(db.bucketName)).Cursor()
-		for k, v := c.First(); k != nil; k, v = c.Next() {
-			if bytes.HasPrefix(k, match) {
-				_, msg, err := network.Unmarshal(v, cothority.Suite)
-				if err != nil {
-					return errors.New("Unmarshal failed with error: " + err.Error())
+	// errFound stops the iteration over the bucket.
+	errFound := errors.New("found")
+	db.View(func(tx StorageTx) error {
+		b := tx.Bucket([]byte(db.bucketName))
+		for _, matches := range []func(k []byte) bool{
+			func(k []byte) bool { return bytes.HasPrefix(k, match) },
+			func(k []byte) bool { return bytes.HasSuffix(k, match) },
+		} {
+			err := b.ForEach(func(k, v []byte) error {
+				if !matches(k) {
+					return nil
 				}
-				sb = msg.(*SkipBlock).Copy()
-				return nil
-			}
-		}
-		for k, v := c.First(); k != nil; k, v = c.Next() {
-			if bytes.HasSuffix(k, match) {
 				_, msg, err := network.Unmarshal(v, cothority.Suite)
 				if err != nil {
 					return errors.New("Unmarshal failed with error: " + err.Error())
 				}
 				sb = msg.(*SkipBlock).Copy()
-				return nil
+				return errFound
+			})
+			if err != nil {
+				if err == errFound {
+					return nil
+				}
+				return err
 			}
 		}
 		return nil
@@ -780,10 +1074,484 @@ func (db *SkipBlockDB) GetSkipchains() (map[string]*SkipBlock, error) {
 	return db.getAll()
 }
 
+// Prune removes old blocks of the skipchain given by genesisID, so that at
+// least keep blocks before the latest one are still stored. The genesis-block
+// is never removed. The oldest kept block is called the snapshot block and
+// always has the maximum height, so that all backlinks of future blocks can
+// still be followed. The IDs of the removed blocks are remembered, so that
+// requests for them can be redirected to the archive nodes.
+//
+// It returns the snapshot of the skipchain, or nil if nothing has been
+// pruned so far.
+func (db *SkipBlockDB) Prune(genesisID SkipBlockID, keep int,
+	archive []*network.ServerIdentity) (*Snapshot, error) {
+	if keep < 1 {
+		return nil, errors.New("need to keep at least one block")
+	}
+	var snap *Snapshot
+	err := db.Update(func(tx StorageTx) error {
+		bSnap, err := tx.CreateBucketIfNotExists(db.snapshotBucketName())
+		if err != nil {
+			return err
+		}
+		bPruned, err := tx.CreateBucketIfNotExists(db.prunedBucketName())
+		if err != nil {
+			return err
+		}
+		genesis, err := db.getFromTx(tx, genesisID)
+		if err != nil {
+			return err
+		}
+		if genesis == nil || genesis.Index != 0 {
+			return errors.New("didn't find genesis-block")
+		}
+		snap, err = getSnapshotFromBucket(bSnap, genesisID)
+		if err != nil {
+			return err
+		}
+
+		// Find the latest block, starting from the snapshot if there is one.
+		start := genesis
+		if snap != nil {
+			start, err = db.getFromTx(tx, snap.BlockID)
+			if err != nil {
+				return err
+			}
+			if start == nil {
+				return errors.New("missing snapshot block")
+			}
+		}
+		latest := start
+		for len(latest.ForwardLink) > 0 {
+			latest, err = db.getFromTx(tx, latest.ForwardLink[0].To)
+			if err != nil {
+				return err
+			}
+			if latest == nil {
+				return errors.New("missing block")
+			}
+		}
+
+		// The snapshot block needs to have the maximum height. Once the
+		// step is bigger than the latest index, nothing can be pruned, so
+		// the multiplication stops before it overflows.
+		step := 1
+		for h := 1; h < genesis.MaximumHeight && step <= latest.Index; h++ {
+			step *= genesis.BaseHeight
+		}
+		index := (latest.Index - keep) / step * step
+		if index <= start.Index {
+			return nil
+		}
+
+		var links []*ForwardLink
+		if snap != nil {
+			links = snap.Links
+		}
+		path, err := db.linksFromTx(tx, start, index)
+		if err != nil {
+			return err
+		}
+		links = append(links, path...)
+
+		sb := start
+		for sb.Index < index {
+			if sb.Index > 0 {
+				if err := tx.Bucket(db.bucketName).Delete(sb.Hash); err != nil {
+					return err
+				}
+				if err := bPruned.Put(sb.Hash, genesisID); err != nil {
+					return err
+				}
+				if err := db.unindexFromTx(tx, sb); err != nil {
+					return err
+				}
+			}
+			if len(sb.ForwardLink) == 0 {
+				return errors.New("missing forward-link")
+			}
+			sb, err = db.getFromTx(tx, sb.ForwardLink[0].To)
+			if err != nil {
+				return err
+			}
+			if sb == nil {
+				return errors.New("missing block")
+			}
+		}
+
+		snap = &Snapshot{
+			GenesisID: genesisID,
+			BlockID:   sb.Hash,
+			Index:     sb.Index,
+			Links:     links,
+			Archive:   archive,
+		}
+		val, err := network.Marshal(snap)
+		if err != nil {
+			return err
+		}
+		return bSnap.Put(genesisID, val)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// linksFromTx returns the forward-links going from the block to the block at
+// the given index. At every block, the highest forward-link that doesn't go
+// beyond the index is taken.
+// The caller must ensure that this function is called from within a valid transaction.
+func (db *SkipBlockDB) linksFromTx(tx StorageTx, from *SkipBlock, index int) ([]*ForwardLink, error) {
+	var links []*ForwardLink
+	sb := from
+	for sb.Index < index {
+		var next *SkipBlock
+		for h := len(sb.ForwardLink) - 1; h >= 0; h-- {
+			to, err := db.getFromTx(tx, sb.ForwardLink[h].To)
+			if err != nil {
+				return nil, err
+			}
+			if to != nil && to.Index <= index {
+				links = append(links, sb.ForwardLink[h])
+				next = to
+				break
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("no forward-link from block %d to block %d", sb.Index, index)
+		}
+		sb = next
+	}
+	return links, nil
+}
+
+// GetSnapshot returns the snapshot of the given skipchain, or nil if it has
+// never been pruned.
+func (db *SkipBlockDB) GetSnapshot(genesisID SkipBlockID) *Snapshot {
+	var snap *Snapshot
+	err := db.View(func(tx StorageTx) error {
+		b := tx.Bucket(db.snapshotBucketName())
+		if b == nil {
+			return nil
+		}
+		var err error
+		snap, err = getSnapshotFromBucket(b, genesisID)
+		return err
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return snap
+}
+
+// GetPruned returns the snapshot of the skipchain if the block with the given
+// ID has been pruned, else nil is returned.
+func (db *SkipBlockDB) GetPruned(sbID SkipBlockID) *Snapshot {
+	var genesisID SkipBlockID
+	db.View(func(tx StorageTx) error {
+		b := tx.Bucket(db.prunedBucketName())
+		if b == nil {
+			return nil
+		}
+		if v := b.Get(sbID); v != nil {
+			genesisID = append(SkipBlockID{}, v...)
+		}
+		return nil
+	})
+	if genesisID.IsNull() {
+		return nil
+	}
+	return db.GetSnapshot(genesisID)
+}
+
+// GetByIndex returns the block of the skipchain with the given index, or nil
+// if it is not in the index.
+func (db *SkipBlockDB) GetByIndex(genesisID SkipBlockID, index int) *SkipBlock {
+	return db.getIndexed(db.indexBucketName(), indexKey(genesisID, index))
+}
+
+// GetLatestByGenesis returns the block with the highest index of the given
+// skipchain, or nil if the skipchain is not in the index.
+func (db *SkipBlockDB) GetLatestByGenesis(genesisID SkipBlockID) *SkipBlock {
+	return db.getIndexed(db.latestBucketName(), genesisID)
+}
+
+// FindFork compares the forward-links of sb with the ones of the stored copy
+// of sb. If they point to different blocks at the same height and both are
+// correctly signed, a ForkEvidence without reporter is returned. If sb is not
+// stored yet, but another block with the same index and back-link is, an
+// error is returned, as there is no signature proving the conflict yet.
+func (db *SkipBlockDB) FindFork(sb *SkipBlock) (*ForkEvidence, error) {
+	stored := db.GetByID(sb.Hash)
+	if stored == nil {
+		other := db.GetByIndex(sb.SkipChainID(), sb.Index)
+		if other != nil && !other.Hash.Equal(sb.Hash) &&
+			len(other.BackLinkIDs) > 0 && len(sb.BackLinkIDs) > 0 &&
+			other.BackLinkIDs[0].Equal(sb.BackLinkIDs[0]) {
+			return nil, fmt.Errorf("conflicting block %x at index %d", sb.Hash, sb.Index)
+		}
+		return nil, nil
+	}
+	for i, fl := range sb.ForwardLink {
+		if i >= len(stored.ForwardLink) {
+			break
+		}
+		old := stored.ForwardLink[i]
+		if old.To.Equal(fl.To) {
+			continue
+		}
+		if fl.Verify(cothority.Suite, stored.Roster.Publics()) != nil ||
+			old.Verify(cothority.Suite, stored.Roster.Publics()) != nil {
+			continue
+		}
+		culprits, err := commonSigners(stored.Roster, old, fl)
+		if err != nil {
+			return nil, err
+		}
+		next := db.GetByID(old.To)
+		index := stored.Index + 1
+		if next != nil {
+			index = next.Index
+		}
+		return &ForkEvidence{
+			GenesisID: stored.SkipChainID(),
+			Index:     index,
+			Roster:    stored.Roster,
+			Links:     [2]*ForwardLink{old.Copy(), fl.Copy()},
+			Culprits:  culprits,
+		}, nil
+	}
+	return nil, nil
+}
+
+// StoreForkEvidence stores the evidence. Evidence for the same fork is only
+// stored once.
+func (db *SkipBlockDB) StoreForkEvidence(fe *ForkEvidence) error {
+	val, err := network.Marshal(fe)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(tx StorageTx) error {
+		b, err := tx.CreateBucketIfNotExists(db.forkBucketName())
+		if err != nil {
+			return err
+		}
+		key := append(append([]byte{}, fe.GenesisID...), fe.Hash()...)
+		if b.Get(key) != nil {
+			return nil
+		}
+		return b.Put(key, val)
+	})
+}
+
+// GetForkEvidence returns all evidence for forks of the given skipchain. If
+// genesisID is empty, the evidence of all skipchains is returned.
+func (db *SkipBlockDB) GetForkEvidence(genesisID SkipBlockID) ([]*ForkEvidence, error) {
+	var evidence []*ForkEvidence
+	err := db.View(func(tx StorageTx) error {
+		b := tx.Bucket(db.forkBucketName())
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			if !bytes.HasPrefix(k, genesisID) {
+				return nil
+			}
+			_, msg, err := network.Unmarshal(v, cothority.Suite)
+			if err != nil {
+				return err
+			}
+			fe, ok := msg.(*ForkEvidence)
+			if !ok {
+				return errors.New("wrong type in fork bucket")
+			}
+			evidence = append(evidence, fe)
+			return nil
+		})
+	})
+	return evidence, err
+}
+
+// GetSkipchainIDs returns the IDs of all skipchains in the index.
+func (db *SkipBlockDB) GetSkipchainIDs() ([]SkipBlockID, error) {
+	var ids []SkipBlockID
+	err := db.View(func(tx StorageTx) error {
+		b := tx.Bucket(db.latestBucketName())
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			ids = append(ids, append(SkipBlockID{}, k...))
+			return nil
+		})
+	})
+	return ids, err
+}
+
+// GetByDataHash returns the latest stored block whose Data has the given
+// sha256 hash, or nil if there is no such block.
+func (db *SkipBlockDB) GetByDataHash(hash []byte) *SkipBlock {
+	return db.getIndexed(db.dataBucketName(), hash)
+}
+
+// getIndexed looks up the block ID stored under key in the given index
+// bucket and returns the corresponding block.
+func (db *SkipBlockDB) getIndexed(bucket, key []byte) *SkipBlock {
+	var result *SkipBlock
+	err := db.View(func(tx StorageTx) error {
+		b := tx.Bucket(bucket)
+		if b == nil {
+			return nil
+		}
+		id := b.Get(key)
+		if id == nil {
+			return nil
+		}
+		var err error
+		result, err = db.getFromTx(tx, id)
+		return err
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return result
+}
+
+// BuildIndexes creates the secondary indexes for all stored blocks. It only
+// needs to be called for databases that have been created before the indexes
+// were introduced, as Store keeps them up to date.
+func (db *SkipBlockDB) BuildIndexes() error {
+	return db.Update(func(tx StorageTx) error {
+		return tx.Bucket(db.bucketName).ForEach(func(k, v []byte) error {
+			_, msg, err := network.Unmarshal(v, cothority.Suite)
+			if err != nil {
+				return err
+			}
+			sb, ok := msg.(*SkipBlock)
+			if !ok {
+				return errors.New("wrong type in skipblock bucket")
+			}
+			return db.indexToTx(tx, sb)
+		})
+	})
+}
+
+// indexToTx adds the skipblock to the secondary indexes. The latest block of
+// the skipchain is only replaced if sb has a higher index.
+// The caller must ensure that this function is called from within a valid
+// read-write transaction.
+func (db *SkipBlockDB) indexToTx(tx StorageTx, sb *SkipBlock) error {
+	bIndex, err := tx.CreateBucketIfNotExists(db.indexBucketName())
+	if err != nil {
+		return err
+	}
+	bLatest, err := tx.CreateBucketIfNotExists(db.latestBucketName())
+	if err != nil {
+		return err
+	}
+	bData, err := tx.CreateBucketIfNotExists(db.dataBucketName())
+	if err != nil {
+		return err
+	}
+	genesisID := sb.SkipChainID()
+	if err := bIndex.Put(indexKey(genesisID, sb.Index), sb.Hash); err != nil {
+		return err
+	}
+	dataHash := sha256.Sum256(sb.Data)
+	if err := bData.Put(dataHash[:], sb.Hash); err != nil {
+		return err
+	}
+	if id := bLatest.Get(genesisID); id != nil {
+		latest, err := db.getFromTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if latest != nil && latest.Index >= sb.Index {
+			return nil
+		}
+	}
+	return bLatest.Put(genesisID, sb.Hash)
+}
+
+// unindexFromTx removes a pruned skipblock from the secondary indexes. The
+// latest block of a skipchain is never pruned.
+// The caller must ensure that this function is called from within a valid
+// read-write transaction.
+func (db *SkipBlockDB) unindexFromTx(tx StorageTx, sb *SkipBlock) error {
+	if b := tx.Bucket(db.indexBucketName()); b != nil {
+		key := indexKey(sb.SkipChainID(), sb.Index)
+		if id := b.Get(key); id != nil && bytes.Equal(id, sb.Hash) {
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	if b := tx.Bucket(db.dataBucketName()); b != nil {
+		dataHash := sha256.Sum256(sb.Data)
+		if id := b.Get(dataHash[:]); id != nil && bytes.Equal(id, sb.Hash) {
+			if err := b.Delete(dataHash[:]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// indexKey returns the key of a block in the index bucket: the genesis-ID
+// followed by the big-endian index, so that the blocks of a skipchain are
+// sorted by index.
+func indexKey(genesisID SkipBlockID, index int) []byte {
+	key := make([]byte, len(genesisID)+8)
+	copy(key, genesisID)
+	binary.BigEndian.PutUint64(key[len(genesisID):], uint64(index))
+	return key
+}
+
+func (db *SkipBlockDB) indexBucketName() []byte {
+	return append(append([]byte{}, db.bucketName...), []byte("-index")...)
+}
+
+func (db *SkipBlockDB) latestBucketName() []byte {
+	return append(append([]byte{}, db.bucketName...), []byte("-latest")...)
+}
+
+func (db *SkipBlockDB) dataBucketName() []byte {
+	return append(append([]byte{}, db.bucketName...), []byte("-data")...)
+}
+
+func (db *SkipBlockDB) forkBucketName() []byte {
+	return append(append([]byte{}, db.bucketName...), []byte("-forks")...)
+}
+
+func (db *SkipBlockDB) snapshotBucketName() []byte {
+	return append(append([]byte{}, db.bucketName...), []byte("-snapshots")...)
+}
+
+func (db *SkipBlockDB) prunedBucketName() []byte {
+	return append(append([]byte{}, db.bucketName...), []byte("-pruned")...)
+}
+
+func getSnapshotFromBucket(b StorageBucket, genesisID SkipBlockID) (*Snapshot, error) {
+	val := b.Get(genesisID)
+	if val == nil {
+		return nil, nil
+	}
+	_, msg, err := network.Unmarshal(val, cothority.Suite)
+	if err != nil {
+		return nil, err
+	}
+	snap, ok := msg.(*Snapshot)
+	if !ok {
+		return nil, errors.New("wrong type in snapshot bucket")
+	}
+	return snap, nil
+}
+
 // storeToTx stores the skipblock into the database.
 // An error is returned on failure.
 // The caller must ensure that this function is called from within a valid transaction.
-func (db *SkipBlockDB) storeToTx(tx *bolt.Tx, sb *SkipBlock) error {
+func (db *SkipBlockDB) storeToTx(tx StorageTx, sb *SkipBlock) error {
 	key := sb.Hash
 	val, err := network.Marshal(sb)
 	if err != nil {
@@ -796,7 +1564,7 @@ func (db *SkipBlockDB) storeToTx(tx *bolt.Tx, sb *SkipBlock) error {
 // nil is returned if the key does not exist.
 // An error is thrown if marshalling fails.
 // The caller must ensure that this function is called from within a valid transaction.
-func (db *SkipBlockDB) getFromTx(tx *bolt.Tx, sbID SkipBlockID) (*SkipBlock, error) {
+func (db *SkipBlockDB) getFromTx(tx StorageTx, sbID SkipBlockID) (*SkipBlock, error) {
 	val := tx.Bucket([]byte(db.bucketName)).Get(sbID)
 	if val == nil {
 		return nil, nil
@@ -816,7 +1584,7 @@ func (db *SkipBlockDB) getFromTx(tx *bolt.Tx, sbID SkipBlockID) (*SkipBlock, err
 // database that is consistent at the time of the function call.
 func (db *SkipBlockDB) getAll() (map[string]*SkipBlock, error) {
 	data := map[string]*SkipBlock{}
-	err := db.View(func(tx *bolt.Tx) error {
+	err := db.View(func(tx StorageTx) error {
 		b := tx.Bucket([]byte(db.bucketName))
 		return b.ForEach(func(k, v []byte) error {
 			_, sbMsg, err := network.Unmarshal(v, cothority.Suite)
@@ -2,6 +2,7 @@ package skipchain
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/dedis/cothority"
 	"github.com/dedis/onet"
 	"github.com/dedis/onet/log"
+	"github.com/dedis/onet/network"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -152,7 +154,7 @@ func TestSkipBlock_GetFuzzy(t *testing.T) {
 	sb1.Data = []byte{1}
 	sb1.Hash = []byte{2, 3, 4, 1, 5}
 
-	db.Update(func(tx *bolt.Tx) error {
+	db.Update(func(tx StorageTx) error {
 		err := db.storeToTx(tx, sb0)
 		require.Nil(t, err)
 
@@ -198,6 +200,190 @@ func TestSkipBlock_GetFuzzy(t *testing.T) {
 	require.Equal(t, sb.Data[0], sb0.Data[0])
 }
 
+func TestSkipBlockDB_Prune(t *testing.T) {
+	l := onet.NewTCPTest(cothority.Suite)
+	servers, roster, _ := l.GenTree(3, true)
+	defer l.CloseAll()
+
+	db, fname := setupSkipBlockDB(t)
+	defer db.Close()
+	defer os.Remove(fname)
+
+	var blocks []*SkipBlock
+	for i := 0; i < 10; i++ {
+		sb := NewSkipBlock()
+		sb.Index = i
+		sb.BaseHeight = 2
+		sb.MaximumHeight = 2
+		sb.Data = []byte{byte(i)}
+		sb.Roster = roster
+		if i > 0 {
+			sb.GenesisID = blocks[0].Hash
+			sb.BackLinkIDs = []SkipBlockID{blocks[i-1].Hash}
+		}
+		sb.updateHash()
+		blocks = append(blocks, sb)
+	}
+	for i, sb := range blocks {
+		if i < len(blocks)-1 {
+			sb.ForwardLink = []*ForwardLink{NewForwardLink(sb, blocks[i+1])}
+		}
+		require.NotNil(t, db.Store(sb))
+	}
+
+	_, err := db.Prune(blocks[0].Hash, 0, nil)
+	require.NotNil(t, err)
+	_, err = db.Prune(blocks[1].Hash, 3, nil)
+	require.NotNil(t, err)
+	require.Nil(t, db.GetSnapshot(blocks[0].Hash))
+
+	// Nothing to prune if all blocks are kept.
+	snap, err := db.Prune(blocks[0].Hash, 9, nil)
+	require.Nil(t, err)
+	require.Nil(t, snap)
+
+	// The snapshot needs to have the maximum height, so index 6 and not 7.
+	archive := servers[0].ServerIdentity
+	snap, err = db.Prune(blocks[0].Hash, 3, []*network.ServerIdentity{archive})
+	require.Nil(t, err)
+	require.NotNil(t, snap)
+	require.Equal(t, 6, snap.Index)
+	require.True(t, snap.BlockID.Equal(blocks[6].Hash))
+	require.Equal(t, 6, len(snap.Links))
+	require.True(t, snap.Links[0].From.Equal(blocks[0].Hash))
+	require.True(t, snap.Links[5].To.Equal(blocks[6].Hash))
+	require.True(t, snap.Archive[0].Equal(archive))
+	require.Equal(t, 5, db.Length())
+	require.NotNil(t, db.GetByID(blocks[0].Hash))
+	for _, sb := range blocks[1:6] {
+		require.Nil(t, db.GetByID(sb.Hash))
+		pruned := db.GetPruned(sb.Hash)
+		require.NotNil(t, pruned)
+		require.Equal(t, 6, pruned.Index)
+	}
+	for _, sb := range blocks[6:] {
+		require.NotNil(t, db.GetByID(sb.Hash))
+		require.Nil(t, db.GetPruned(sb.Hash))
+	}
+
+	latest, err := db.GetLatest(blocks[0])
+	require.Nil(t, err)
+	require.True(t, latest.Equal(blocks[9]))
+
+	// Pruning again doesn't change anything.
+	snap, err = db.Prune(blocks[0].Hash, 3, nil)
+	require.Nil(t, err)
+	require.Equal(t, 6, snap.Index)
+	require.Equal(t, 5, db.Length())
+
+	// Pruning more removes the old snapshot block.
+	snap, err = db.Prune(blocks[0].Hash, 1, nil)
+	require.Nil(t, err)
+	require.Equal(t, 8, snap.Index)
+	require.Equal(t, 8, len(snap.Links))
+	require.True(t, snap.Links[7].To.Equal(blocks[8].Hash))
+	require.Nil(t, db.GetByID(blocks[6].Hash))
+	require.Equal(t, 8, db.GetPruned(blocks[2].Hash).Index)
+	require.Equal(t, 3, db.Length())
+	require.Nil(t, db.GetByIndex(blocks[0].Hash, 6))
+	require.True(t, db.GetByIndex(blocks[0].Hash, 8).Equal(blocks[8]))
+}
+
+// With a big maximum height, no block has the maximum height and nothing is
+// pruned.
+func TestSkipBlockDB_PruneHeight(t *testing.T) {
+	l := onet.NewTCPTest(cothority.Suite)
+	_, roster, _ := l.GenTree(3, true)
+	defer l.CloseAll()
+
+	db, fname := setupSkipBlockDB(t)
+	defer db.Close()
+	defer os.Remove(fname)
+
+	var blocks []*SkipBlock
+	for i := 0; i < 5; i++ {
+		sb := NewSkipBlock()
+		sb.Index = i
+		sb.BaseHeight = 16
+		sb.MaximumHeight = 32
+		sb.Data = []byte{byte(i)}
+		sb.Roster = roster
+		if i > 0 {
+			sb.GenesisID = blocks[0].Hash
+			sb.BackLinkIDs = []SkipBlockID{blocks[i-1].Hash}
+		}
+		sb.updateHash()
+		blocks = append(blocks, sb)
+	}
+	for i, sb := range blocks {
+		if i < len(blocks)-1 {
+			sb.ForwardLink = []*ForwardLink{NewForwardLink(sb, blocks[i+1])}
+		}
+		require.NotNil(t, db.Store(sb))
+	}
+
+	snap, err := db.Prune(blocks[0].Hash, 1, nil)
+	require.Nil(t, err)
+	require.Nil(t, snap)
+	require.Equal(t, 5, db.Length())
+}
+
+func TestSkipBlockDB_Indexes(t *testing.T) {
+	l := onet.NewTCPTest(cothority.Suite)
+	_, roster, _ := l.GenTree(3, true)
+	defer l.CloseAll()
+
+	db, fname := setupSkipBlockDB(t)
+	defer db.Close()
+	defer os.Remove(fname)
+
+	var blocks []*SkipBlock
+	for i := 0; i < 5; i++ {
+		sb := NewSkipBlock()
+		sb.Index = i
+		sb.Data = []byte{byte(i)}
+		sb.Roster = roster
+		if i > 0 {
+			sb.GenesisID = blocks[0].Hash
+			sb.BackLinkIDs = []SkipBlockID{blocks[i-1].Hash}
+		}
+		sb.updateHash()
+		blocks = append(blocks, sb)
+	}
+	for i, sb := range blocks {
+		if i < len(blocks)-1 {
+			sb.ForwardLink = []*ForwardLink{NewForwardLink(sb, blocks[i+1])}
+		}
+	}
+	genesis := blocks[0].Hash
+	require.Nil(t, db.GetLatestByGenesis(genesis))
+
+	// Storing the blocks out of order must not change the latest block.
+	for _, i := range []int{0, 1, 3, 2} {
+		require.NotNil(t, db.Store(blocks[i]))
+		require.True(t, db.GetByIndex(genesis, i).Equal(blocks[i]))
+	}
+	require.True(t, db.GetLatestByGenesis(genesis).Equal(blocks[3]))
+	require.Nil(t, db.GetByIndex(genesis, 4))
+	require.Nil(t, db.GetByIndex(blocks[1].Hash, 1))
+
+	hash := sha256.Sum256(blocks[2].Data)
+	require.True(t, db.GetByDataHash(hash[:]).Equal(blocks[2]))
+
+	require.NotNil(t, db.Store(blocks[4]))
+	latest, err := db.GetLatest(blocks[1])
+	require.Nil(t, err)
+	require.True(t, latest.Equal(blocks[4]))
+
+	// Rebuilding the indexes gives the same result.
+	require.Nil(t, db.Update(func(tx StorageTx) error {
+		return tx.Bucket(db.latestBucketName()).Delete(genesis)
+	}))
+	require.Nil(t, db.GetLatestByGenesis(genesis))
+	require.Nil(t, db.BuildIndexes())
+	require.True(t, db.GetLatestByGenesis(genesis).Equal(blocks[4]))
+}
+
 // setupSkipBlockDB initialises a database with a bucket called 'skipblock-test' inside.
 // The caller is responsible to close and remove the database file after using it.
 func setupSkipBlockDB(t *testing.T) (*SkipBlockDB, string) {
@@ -215,5 +401,5 @@ func setupSkipBlockDB(t *testing.T) (*SkipBlockDB, string) {
 	})
 	require.Nil(t, err)
 
-	return &SkipBlockDB{db, []byte("skipblock-test")}, fname
+	return NewSkipBlockDB(db, []byte("skipblock-test")), fname
 }